@@ -266,6 +266,15 @@ func (o *Options) Config() (*binderappconfig.Config, error) {
 		}
 	}
 
+	// Set up the identity of the binder shard if sharding is enabled.
+	if sharding := c.BinderConfig.Sharding; sharding != nil && sharding.Enable && len(sharding.Identity) == 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get hostname: %v", err)
+		}
+		sharding.Identity = hostname + "_" + string(uuid.NewUUID())
+	}
+
 	c.Client = client
 
 	c.InformerFactory = cmdutil.NewInformerFactory(client, 0)
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	goruntime "runtime"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -33,11 +34,15 @@ import (
 	godelbinderconfig "github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/controller"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
 	routeutil "github.com/kubewharf/godel-scheduler/pkg/util/route"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 	"github.com/kubewharf/godel-scheduler/pkg/version/verflag"
 
+	"k8s.io/apimachinery/pkg/util/clock"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
@@ -152,6 +157,22 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 		return err
 	}
 
+	binderOptions := []binder.Option{
+		binder.WithPluginsAndConfigs(cc.BinderConfig.Profile),
+		binder.WithSchedulerNameProfiles(cc.BinderConfig.SchedulerNameProfiles),
+		binder.WithPreemptionBudget(cc.BinderConfig.PreemptionBudget),
+	}
+	var shardManager *shard.Manager
+	if sharding := cc.BinderConfig.Sharding; sharding != nil && sharding.Enable {
+		shardManager = shard.NewManager(cc.Client, clock.RealClock{}, *cc.BinderConfig.SchedulerName, sharding.Identity, shard.Config{
+			LeaseNamespace:     sharding.LeaseNamespace,
+			LeaseGroupLabelKey: binder.ShardLeaseGroupLabelKey,
+			LeaseDuration:      time.Duration(sharding.LeaseDurationSeconds) * time.Second,
+			RenewInterval:      time.Duration(sharding.RenewIntervalSeconds) * time.Second,
+		})
+		binderOptions = append(binderOptions, binder.WithShardManager(shardManager, binder.NewShardKeyFunc(sharding.ShardingKey)))
+	}
+
 	binder, err := binder.New(
 		cc.Client,
 		cc.GodelCrdClient,
@@ -162,11 +183,25 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 		eventRecorder,
 		cc.BinderConfig.SchedulerName,
 		cc.BinderConfig.VolumeBindingTimeoutSeconds,
-		binder.WithPluginsAndConfigs(cc.BinderConfig.Profile),
+		binderOptions...,
 	)
 	if err != nil {
 		return err
 	}
+	// Join the shards before the informers are started, so that the pods are dispatched to the shards
+	// with the up-to-date membership from the beginning.
+	if shardManager != nil {
+		if err := shardManager.Join(ctx); err != nil {
+			return fmt.Errorf("couldn't join binder shards: %v", err)
+		}
+		klog.InfoS("Joined binder shards", "identity", shardManager.Identity(), "members", shardManager.Members().List())
+		go func() {
+			if err := shardManager.Run(ctx); err != nil {
+				klog.ErrorS(err, "Lost binder shard lease")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+		}()
+	}
 
 	// Prepare the event broadcaster.
 	cc.EventBroadcaster.StartRecordingToSink(ctx.Done())
@@ -191,9 +226,31 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 			return fmt.Errorf("failed to start metrics server: %v", err)
 		}
 	}
+	// Start up the handoff server receiving the placements from schedulers directly.
+	if handoffConfig := cc.BinderConfig.Handoff; handoffConfig != nil && handoffConfig.Enable {
+		tlsConfig, err := handoff.NewServerTLSConfig(handoffConfig.CertFile, handoffConfig.KeyFile, handoffConfig.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to start handoff server: %v", err)
+		}
+		// Only the schedulers presenting an allowed client certificate can hand off placements.
+		handoffMux := http.NewServeMux()
+		handoffMux.Handle(handoff.Path, handoff.WithAuthorization(binder.HandoffHandler(), sets.NewString(handoffConfig.AllowedClientNames...)))
+		handoffServer := &http.Server{Addr: handoffConfig.BindAddress, Handler: handoffMux, TLSConfig: tlsConfig}
+		go func() {
+			<-ctx.Done()
+			handoffServer.Close()
+		}()
+		go func() {
+			if err := handoffServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				klog.ErrorS(err, "Failed to serve handoff server", "address", handoffConfig.BindAddress)
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+		}()
+	}
 
 	// Start podGroup Controllers
 	pgInformer := cc.GodelCrdInformerFactory.Scheduling().V1alpha1().PodGroups()
+	nodeLister := cc.InformerFactory.Core().V1().Nodes().Lister()
 
 	// Start all informers.
 	cc.GodelCrdInformerFactory.Start(ctx.Done())
@@ -207,6 +264,15 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 	cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
 	cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced)
 
+	runControllers := func(ctx context.Context) {
+		controller.SetupPodGroupController(ctx, cc.Client, cc.GodelCrdClient, pgInformer)
+		if gpuDefragmentation := cc.BinderConfig.GPUDefragmentation; gpuDefragmentation != nil && gpuDefragmentation.Enable {
+			controller.SetupGPUDefragmentationController(ctx, cc.Client, binder.BinderCache, nodeLister, gpuDefragmentation)
+		}
+		if hotspotAvoidance := cc.BinderConfig.HotspotAvoidance; hotspotAvoidance != nil && hotspotAvoidance.Enable {
+			controller.SetupHotspotAvoidanceController(ctx, cc.Client, binder.BinderCache, nodeLister, hotspotAvoidance)
+		}
+	}
 	run := func(ctx context.Context) {
 		// Register the tracer when the leader is elected.
 		closer := tracing.NewTracer(
@@ -214,10 +280,22 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 			cc.BinderConfig.Tracer)
 		defer closer.Close()
 
-		controller.SetupPodGroupController(ctx, cc.Client, cc.GodelCrdClient, pgInformer)
+		runControllers(ctx)
 		binder.Run(ctx)
 	}
 
+	// Sharded binders are all active and bind the pods of their own shards, only the controllers
+	// are run by the leader.
+	if shardManager != nil {
+		closer := tracing.NewTracer(ComponentName, cc.BinderConfig.Tracer)
+		defer closer.Close()
+		go binder.Run(ctx)
+		run = func(ctx context.Context) {
+			runControllers(ctx)
+			<-ctx.Done()
+		}
+	}
+
 	// If leader election is enabled, runCommand via LeaderElector until done and exit.
 	if cc.LeaderElection != nil {
 		cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
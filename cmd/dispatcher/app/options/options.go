@@ -21,6 +21,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
@@ -46,6 +47,7 @@ import (
 	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config/validation"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 const DefaultLeaderElectionName = "dispatcher"
@@ -122,6 +124,17 @@ func (o *Options) Flags() (nfs cliflag.NamedFlagSets) {
 	fs.Float32Var(&o.DispatcherConfig.ClientConnection.QPS, "kube-api-qps", o.DispatcherConfig.ClientConnection.QPS, "QPS to use while talking with kubernetes apiserver. This parameter is ignored if a config file is specified in --config.")
 	fs.Int32Var(&o.DispatcherConfig.ClientConnection.Burst, "kube-api-burst", o.DispatcherConfig.ClientConnection.Burst, "burst to use while talking with kubernetes apiserver. This parameter is ignored if a config file is specified in --config.")
 	fs.StringVar(o.DispatcherConfig.SchedulerName, "scheduler-name", *o.DispatcherConfig.SchedulerName, "components will deal with pods that pod.Spec.SchedulerName is equal to scheduler-name / is default-scheduler or empty.")
+	fs.StringSliceVar(&o.DispatcherConfig.ProfileSchedulerNames, "profile-scheduler-names", o.DispatcherConfig.ProfileSchedulerNames, "the scheduler names of the schedulerName profiles, pods with these names are dispatched as well as the ones with scheduler-name.")
+	fs.StringVar(&o.DispatcherConfig.NodePartitionType, "node-partition-type", o.DispatcherConfig.NodePartitionType, "the type of node partition, Physical or Logical. In Physical mode, pods are only dispatched to the schedulers owning nodes that match their node selector and node affinity.")
+	fs.Float64Var(&o.DispatcherConfig.TenantAdmission.QPS, "tenant-admission-qps", o.DispatcherConfig.TenantAdmission.QPS, "the number of pods dispatched per second for each namespace, 0 means no limit. Pods with annotation "+podutil.SkipTenantAdmissionAnnotationKey+"=true are not limited.")
+	fs.IntVar(&o.DispatcherConfig.TenantAdmission.Burst, "tenant-admission-burst", o.DispatcherConfig.TenantAdmission.Burst, "the max number of pods dispatched at once for each namespace.")
+	fs.Var(&priorityBandsValue{bands: &o.DispatcherConfig.TenantAdmission.PriorityBands}, "tenant-admission-priority-bands", "the admission rate of each priority band for each namespace, in the form of 'minPriority=qps:burst,...', e.g. '0=10:20,1000=100:200'.")
+	fs.BoolVar(&o.DispatcherConfig.Sharding.Enable, "sharding", o.DispatcherConfig.Sharding.Enable, "run multiple active dispatchers, each of which dispatches the pending pods of its own shard.")
+	fs.StringVar(&o.DispatcherConfig.Sharding.ShardingKey, "sharding-key", o.DispatcherConfig.Sharding.ShardingKey, "the key deciding the shard of a pending pod, Namespace or PodGroup.")
+	fs.StringVar(&o.DispatcherConfig.Sharding.Identity, "sharding-identity", o.DispatcherConfig.Sharding.Identity, "the identity of this dispatcher in the shard membership, defaulting to the hostname with a random suffix.")
+	fs.StringVar(&o.DispatcherConfig.Sharding.LeaseNamespace, "sharding-lease-namespace", o.DispatcherConfig.Sharding.LeaseNamespace, "the namespace of the shard membership leases, defaulting to the namespace of leader election.")
+	fs.Int64Var(&o.DispatcherConfig.Sharding.LeaseDurationSeconds, "sharding-lease-duration-seconds", o.DispatcherConfig.Sharding.LeaseDurationSeconds, "the duration after which a dispatcher which stops renewing its lease loses its shard.")
+	fs.Int64Var(&o.DispatcherConfig.Sharding.RenewIntervalSeconds, "sharding-renew-interval-seconds", o.DispatcherConfig.Sharding.RenewIntervalSeconds, "the interval of renewing the lease and refreshing the shard membership.")
 
 	o.CombinedInsecureServing.AddFlags(nfs.FlagSet("insecure serving"))
 	o.DispatcherConfig.Tracer.AddFlags(nfs.FlagSet("tracer"))
@@ -175,6 +188,20 @@ func (o *Options) Config() (*dispatcherappconfig.Config, error) {
 		}
 	}
 
+	// Set up the identity and the lease namespace of the dispatcher shard if sharding is enabled.
+	if sharding := c.DispatcherConfig.Sharding; sharding != nil && sharding.Enable {
+		if len(sharding.Identity) == 0 {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return nil, fmt.Errorf("unable to get hostname: %v", err)
+			}
+			sharding.Identity = hostname + "_" + string(uuid.NewUUID())
+		}
+		if len(sharding.LeaseNamespace) == 0 {
+			sharding.LeaseNamespace = c.DispatcherConfig.LeaderElection.ResourceNamespace
+		}
+	}
+
 	c.Client = client
 	c.InformerFactory = cmdutil.NewInformerFactory(client, 0)
 	c.GodelCrdClient = godelCrdClient
@@ -281,3 +308,55 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 
 	return client, leaderElectionClient, eventClient, godelCrdClient, nil
 }
+
+// priorityBandsValue parses the priority bands of tenant admission in the form of 'minPriority=qps:burst,...'.
+type priorityBandsValue struct {
+	bands *[]dispatcherconfig.PriorityBandAdmission
+}
+
+func (v *priorityBandsValue) String() string {
+	if v.bands == nil {
+		return ""
+	}
+	items := make([]string, 0, len(*v.bands))
+	for _, band := range *v.bands {
+		items = append(items, fmt.Sprintf("%d=%v:%d", band.MinPriority, band.QPS, band.Burst))
+	}
+	return strings.Join(items, ",")
+}
+
+func (v *priorityBandsValue) Set(value string) error {
+	var bands []dispatcherconfig.PriorityBandAdmission
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		priority, rate, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("invalid priority band %q, expected 'minPriority=qps:burst'", item)
+		}
+		qps, burst, ok := strings.Cut(rate, ":")
+		if !ok {
+			return fmt.Errorf("invalid priority band %q, expected 'minPriority=qps:burst'", item)
+		}
+		var band dispatcherconfig.PriorityBandAdmission
+		minPriority, err := strconv.ParseInt(priority, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid min priority in %q: %v", item, err)
+		}
+		band.MinPriority = int32(minPriority)
+		if band.QPS, err = strconv.ParseFloat(qps, 64); err != nil {
+			return fmt.Errorf("invalid qps in %q: %v", item, err)
+		}
+		if band.Burst, err = strconv.Atoi(burst); err != nil {
+			return fmt.Errorf("invalid burst in %q: %v", item, err)
+		}
+		bands = append(bands, band)
+	}
+	*v.bands = bands
+	return nil
+}
+
+func (v *priorityBandsValue) Type() string {
+	return "priorityBands"
+}
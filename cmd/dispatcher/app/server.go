@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	goruntime "runtime"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -33,9 +34,11 @@ import (
 	godeldispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
 	routeutil "github.com/kubewharf/godel-scheduler/pkg/util/route"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 	"github.com/kubewharf/godel-scheduler/pkg/version/verflag"
 
+	"k8s.io/apimachinery/pkg/util/clock"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
@@ -133,6 +136,18 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		return err
 	}
 
+	var shardManager *shard.Manager
+	var shardKeyFunc dispatcher.ShardKeyFunc
+	if sharding := cc.DispatcherConfig.Sharding; sharding != nil && sharding.Enable {
+		shardManager = shard.NewManager(cc.Client, clock.RealClock{}, *cc.DispatcherConfig.SchedulerName, sharding.Identity, shard.Config{
+			LeaseNamespace:     sharding.LeaseNamespace,
+			LeaseGroupLabelKey: dispatcher.ShardLeaseGroupLabelKey,
+			LeaseDuration:      time.Duration(sharding.LeaseDurationSeconds) * time.Second,
+			RenewInterval:      time.Duration(sharding.RenewIntervalSeconds) * time.Second,
+		})
+		shardKeyFunc = dispatcher.NewShardKeyFunc(sharding.ShardingKey)
+	}
+
 	dispatcher := dispatcher.New(
 		ctx.Done(),
 		cc.Client,
@@ -144,6 +159,11 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		cc.GodelCrdInformerFactory.Scheduling().V1alpha1().PodGroups(),
 		cc.InformerFactory.Scheduling().V1().PriorityClasses(),
 		*cc.DispatcherConfig.SchedulerName,
+		cc.DispatcherConfig.ProfileSchedulerNames,
+		cc.DispatcherConfig.NodePartitionType,
+		cc.DispatcherConfig.TenantAdmission,
+		shardManager,
+		shardKeyFunc,
 		getEventRecorder(&cc),
 	)
 
@@ -171,6 +191,21 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		}
 	}
 
+	// Join the shards before the informers are started, so that the pending pods are dispatched by the shards
+	// with the up-to-date membership from the beginning.
+	if shardManager != nil {
+		if err := shardManager.Join(ctx); err != nil {
+			return fmt.Errorf("couldn't join dispatcher shards: %v", err)
+		}
+		klog.InfoS("Joined dispatcher shards", "identity", shardManager.Identity(), "members", shardManager.Members().List())
+		go func() {
+			if err := shardManager.Run(ctx); err != nil {
+				klog.ErrorS(err, "Lost dispatcher shard lease")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+		}()
+	}
+
 	cc.InformerFactory.Start(ctx.Done())
 	cc.InformerFactory.WaitForCacheSync(ctx.Done())
 	cc.GodelCrdInformerFactory.Start(ctx.Done())
@@ -188,6 +223,18 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		<-ctx.Done()
 	}
 
+	// Sharded dispatchers are all active and dispatch the pending pods of their own shards, only the
+	// reconciler and the node shuffler are run by the leader.
+	if shardManager != nil {
+		closer := tracing.NewTracer(ComponentName, cc.DispatcherConfig.Tracer)
+		defer closer.Close()
+		dispatcher.RunShard(ctx)
+		run = func(ctx context.Context) {
+			dispatcher.RunLeader(ctx)
+			<-ctx.Done()
+		}
+	}
+
 	// If leader election is enabled, runCommand via LeaderElector until done and exit.
 	if cc.LeaderElection != nil {
 		cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
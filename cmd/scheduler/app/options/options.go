@@ -197,6 +197,7 @@ func (o *Options) addSchedulerConfigFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.ComponentConfig.GodelSchedulerName, "godel-scheduler-name", o.ComponentConfig.GodelSchedulerName, "godel scheduler name, to register scheduler crd.")
 	fs.StringVar(o.ComponentConfig.SchedulerName, "scheduler-name", *o.ComponentConfig.SchedulerName, "components will deal with pods that pod.Spec.SchedulerName is equal to scheduler-name / is default-scheduler or empty. This parameter overrides the value defined in config file, which is specified in --config.")
 	fs.StringVar(o.ComponentConfig.SubClusterKey, "sub-cluster-key", *o.ComponentConfig.SubClusterKey, "the key to determine a sub cluster. This parameter overrides the value defined in config file, which is specified in --config.")
+	fs.StringVar(&o.ComponentConfig.NodePartitionType, "node-partition-type", o.ComponentConfig.NodePartitionType, "the type of node partition, Physical or Logical. In Physical mode, the scheduler only sees and places pods on the nodes of its own partition. This parameter overrides the value defined in config file, which is specified in --config.")
 }
 
 // ApplyTo applies the scheduler options to the given scheduler app configuration.
@@ -287,6 +288,9 @@ func (o *Options) ApplyTo(c *schedulerappconfig.Config) error {
 			if *o.ComponentConfig.SubClusterKey != godelschedulerconfig.DefaultSubClusterKey {
 				toUse.SubClusterKey = o.ComponentConfig.SubClusterKey
 			}
+			if o.ComponentConfig.NodePartitionType != defaultsconfig.DefaultNodePartitionType {
+				toUse.NodePartitionType = o.ComponentConfig.NodePartitionType
+			}
 		}
 		// 5. Godel Profiles (Default)
 		{
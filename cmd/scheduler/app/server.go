@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	goruntime "runtime"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -41,6 +42,7 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericapiserver "k8s.io/apiserver/pkg/server"
 	genericfilters "k8s.io/apiserver/pkg/server/filters"
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/server/mux"
@@ -145,18 +147,25 @@ func runCommand(cmd *cobra.Command, opts *options.Options, args []string) error
 		return fmt.Errorf("unable to register configz: %s", err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// The context is done on SIGTERM or SIGINT, and the scheduler drains before exiting.
+	ctx, cancel := context.WithCancel(genericapiserver.SetupSignalContext())
 	defer cancel()
 
 	return Run(ctx, cc)
 }
 
+// Run runs the scheduler until ctx is done, then drains the scheduler before returning.
 func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 	err := cc.ComponentConfig.Tracer.Validate()
 	if err != nil {
 		return err
 	}
 
+	// runCtx outlives ctx until the scheduler is drained, so that the in-flight units are finished with the
+	// workflows and informers still running.
+	runCtx, stopRunning := context.WithCancel(context.Background())
+	defer stopRunning()
+
 	eventRecorder := getEventRecorder(&cc)
 
 	// Create the scheduler.
@@ -168,19 +177,22 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 		cc.InformerFactory,
 		cc.GodelCrdInformerFactory,
 		cc.KatalystCrdInformerFactory,
-		ctx.Done(),
+		runCtx.Done(),
 		eventRecorder,
 		godelscheduler.WithDefaultProfile(cc.ComponentConfig.DefaultProfile),
 		godelscheduler.WithSubClusterProfiles(cc.ComponentConfig.SubClusterProfiles),
+		godelscheduler.WithSchedulerNameProfiles(cc.ComponentConfig.SchedulerNameProfiles),
 		godelscheduler.WithRenewInterval(cc.ComponentConfig.SchedulerRenewIntervalSeconds),
 		godelscheduler.WithSubClusterKey(*cc.ComponentConfig.SubClusterKey),
+		godelscheduler.WithNodePartitionType(cc.ComponentConfig.NodePartitionType),
+		godelscheduler.WithBinderHandoff(cc.ComponentConfig.BinderHandoff),
 	)
 	if err != nil {
 		return err
 	}
 
 	// Prepare the event broadcaster.
-	cc.EventBroadcaster.StartRecordingToSink(ctx.Done())
+	cc.EventBroadcaster.StartRecordingToSink(runCtx.Done())
 
 	// Setup healthz checks.
 	var checks []healthz.HealthChecker
@@ -192,31 +204,31 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 	if cc.InsecureServing != nil {
 		separateMetrics := cc.InsecureMetricsServing != nil
 		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, separateMetrics, checks...), nil, nil)
-		if err := cc.InsecureServing.Serve(handler, 0, ctx.Done()); err != nil {
+		if err := cc.InsecureServing.Serve(handler, 0, runCtx.Done()); err != nil {
 			return fmt.Errorf("failed to start healthz server: %v", err)
 		}
 	}
 	if cc.InsecureMetricsServing != nil {
 		handler := buildHandlerChain(newMetricsHandler(&cc.ComponentConfig), nil, nil)
-		if err := cc.InsecureMetricsServing.Serve(handler, 0, ctx.Done()); err != nil {
+		if err := cc.InsecureMetricsServing.Serve(handler, 0, runCtx.Done()); err != nil {
 			return fmt.Errorf("failed to start metrics server: %v", err)
 		}
 	}
 	if cc.SecureServing != nil {
 		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, false, checks...), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
 		// TODO: handle stoppedCh returned by c.SecureServing.Serve
-		if _, _, err := cc.SecureServing.Serve(handler, 0, ctx.Done()); err != nil {
+		if _, _, err := cc.SecureServing.Serve(handler, 0, runCtx.Done()); err != nil {
 			// fail early for secure handlers, removing the old error loop from above
 			return fmt.Errorf("failed to start secure server: %v", err)
 		}
 	}
 
 	// Start all informers.
-	cc.InformerFactory.Start(ctx.Done())
-	cc.GodelCrdInformerFactory.Start(ctx.Done())
-	cc.KatalystCrdInformerFactory.Start(ctx.Done())
+	cc.InformerFactory.Start(runCtx.Done())
+	cc.GodelCrdInformerFactory.Start(runCtx.Done())
+	cc.KatalystCrdInformerFactory.Start(runCtx.Done())
 
-	// Wait for all caches to sync before scheduling.
+	// Wait for all caches to sync before scheduling, unless asked to terminate in the meantime.
 	cc.InformerFactory.WaitForCacheSync(ctx.Done())
 	cc.GodelCrdInformerFactory.WaitForCacheSync(ctx.Done())
 	cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
@@ -231,18 +243,33 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 		// Start the scheduler.
 		sched.Run(ctx)
 	}
+	drainTimeout := time.Duration(cc.ComponentConfig.SchedulerDrainTimeoutSeconds) * time.Second
 
 	// If leader election is enabled, runCommand via LeaderElector until done and exit.
 	if cc.LeaderElection != nil {
+		// Only the leader drains, the standby shares the same Scheduler with it.
+		leading, drained := make(chan struct{}), make(chan struct{})
+		go func() {
+			<-ctx.Done()
+			select {
+			case <-leading:
+				sched.Drain(drainTimeout, stopRunning)
+			default:
+				stopRunning()
+			}
+			close(drained)
+		}()
 		cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
+				close(leading)
 				run(ctx)
 			},
 			OnStoppedLeading: func() {
 				select {
 				case <-ctx.Done():
-					// We were asked to terminate. Exit 0.
+					// We were asked to terminate. Exit 0 once drained.
 					klog.InfoS("Requested to terminate. Exiting")
+					<-drained
 					klog.FlushAndExit(klog.ExitFlushTimeout, 0)
 				default:
 					// We lost the lock.
@@ -256,13 +283,15 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 			return fmt.Errorf("couldn't create leader elector: %v", err)
 		}
 
-		leaderElector.Run(ctx)
+		leaderElector.Run(runCtx)
 
 		return fmt.Errorf("lost lease")
 	}
 
-	// Leader election is disabled, so runCommand inline until done.
-	run(ctx)
+	// Leader election is disabled, so runCommand until done.
+	go run(runCtx)
+	<-ctx.Done()
+	sched.Drain(drainTimeout, stopRunning)
 	return fmt.Errorf("finished without leader elect")
 }
 
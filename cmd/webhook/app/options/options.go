@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	cliflag "k8s.io/component-base/cli/flag"
+
+	frameworkconfig "github.com/kubewharf/godel-scheduler/pkg/framework/config"
+)
+
+const (
+	// DefaultBindAddress is the default address for the webhook server to serve on.
+	DefaultBindAddress = "0.0.0.0:9443"
+)
+
+// Options are the options of the admission webhook of the Godel scheduling system.
+type Options struct {
+	Master     string
+	Kubeconfig string
+
+	// BindAddress is the IP address and port for the webhook server to serve on.
+	BindAddress string
+	// TLSCertFile and TLSPrivateKeyFile are the serving certificate and key of the webhook server.
+	TLSCertFile       string
+	TLSPrivateKeyFile string
+
+	// SchedulerNames are the spec.schedulerName of the pods admitted, the other pods are ignored.
+	SchedulerNames []string
+	// CheckPodGroups rejects the pods referring to PodGroups which don't exist.
+	CheckPodGroups bool
+	// DefaultPodAnnotations sets the resource type and launcher annotations on the pods without them.
+	DefaultPodAnnotations bool
+}
+
+// NewOptions returns default webhook options.
+func NewOptions() *Options {
+	return &Options{
+		BindAddress:    DefaultBindAddress,
+		SchedulerNames: []string{frameworkconfig.DefaultSchedulerName},
+		CheckPodGroups: true,
+	}
+}
+
+// Flags returns flags for the webhook by section name.
+func (o *Options) Flags() (nfs cliflag.NamedFlagSets) {
+	fs := nfs.FlagSet("misc")
+	fs.StringVar(&o.Master, "master", o.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to kubeconfig file with authorization and master location information.")
+
+	fs = nfs.FlagSet("serving")
+	fs.StringVar(&o.BindAddress, "bind-address", o.BindAddress, "The IP address and port for the webhook server to serve on.")
+	fs.StringVar(&o.TLSCertFile, "tls-cert-file", o.TLSCertFile, "File containing the x509 certificate for HTTPS.")
+	fs.StringVar(&o.TLSPrivateKeyFile, "tls-private-key-file", o.TLSPrivateKeyFile, "File containing the x509 private key matching --tls-cert-file.")
+
+	fs = nfs.FlagSet("admission")
+	fs.StringSliceVar(&o.SchedulerNames, "scheduler-names", o.SchedulerNames, "The spec.schedulerName of the pods admitted by the webhook, the other pods are ignored.")
+	fs.BoolVar(&o.CheckPodGroups, "check-pod-groups", o.CheckPodGroups, "Reject the pods referring to PodGroups which don't exist.")
+	fs.BoolVar(&o.DefaultPodAnnotations, "default-pod-annotations", o.DefaultPodAnnotations, "Set the pod-resource-type and pod-launcher annotations on the pods created without them.")
+	return nfs
+}
+
+// Validate validates the options.
+func (o *Options) Validate() []error {
+	var errs []error
+	if len(o.BindAddress) == 0 {
+		errs = append(errs, fmt.Errorf("--bind-address is required"))
+	}
+	if len(o.TLSCertFile) == 0 || len(o.TLSPrivateKeyFile) == 0 {
+		errs = append(errs, fmt.Errorf("--tls-cert-file and --tls-private-key-file are required"))
+	}
+	if len(o.SchedulerNames) == 0 {
+		errs = append(errs, fmt.Errorf("--scheduler-names is required"))
+	}
+	return errs
+}
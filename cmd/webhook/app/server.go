@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	"github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/cli/globalflag"
+	"k8s.io/component-base/term"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/cmd/webhook/app/options"
+	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
+	"github.com/kubewharf/godel-scheduler/pkg/version/verflag"
+	"github.com/kubewharf/godel-scheduler/pkg/webhook"
+)
+
+const ComponentName = "webhook"
+
+// NewWebhookCommand creates the command of the admission webhook validating and defaulting Godel workloads.
+func NewWebhookCommand() *cobra.Command {
+	opts := options.NewOptions()
+	cmd := &cobra.Command{
+		Use: ComponentName,
+		Long: `The Godel admission webhook validates the Godel annotations of pods and the specs of PodGroups
+with the same parsing code as the scheduling components, and optionally defaults the pod annotations.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runCommand(cmd, opts, args); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	fs := cmd.Flags()
+	namedFlagSets := opts.Flags()
+	globalflag.AddGlobalFlags(namedFlagSets.FlagSet("global"), cmd.Name())
+	verflag.AddFlags(namedFlagSets.FlagSet("global"))
+	for _, f := range namedFlagSets.FlagSets {
+		fs.AddFlagSet(f)
+	}
+
+	usageFmt := "Usage:\n  %s\n"
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		fmt.Fprintf(cmd.OutOrStderr(), usageFmt, cmd.UseLine())
+		cliflag.PrintSections(cmd.OutOrStderr(), namedFlagSets, cols)
+		return nil
+	})
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n"+usageFmt, cmd.Long, cmd.UseLine())
+		cliflag.PrintSections(cmd.OutOrStdout(), namedFlagSets, cols)
+	})
+
+	return cmd
+}
+
+func runCommand(cmd *cobra.Command, opts *options.Options, args []string) error {
+	cmdutil.InitKlogV2WithV1Flags(cmd.Flags())
+	verflag.PrintAndExitIfRequested()
+	if len(args) != 0 {
+		fmt.Fprint(os.Stderr, "arguments are not supported\n")
+	}
+
+	if errs := opts.Validate(); len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return Run(ctx, opts)
+}
+
+// Run runs the webhook server until the context is done.
+func Run(ctx context.Context, opts *options.Options) error {
+	var pgLister v1alpha1.PodGroupLister
+	if opts.CheckPodGroups {
+		kubeConfig, err := clientcmd.BuildConfigFromFlags(opts.Master, opts.Kubeconfig)
+		if err != nil {
+			return err
+		}
+		crdClient, err := godelclient.NewForConfig(kubeConfig)
+		if err != nil {
+			return err
+		}
+		crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+		pgInformer := crdInformerFactory.Scheduling().V1alpha1().PodGroups()
+		pgLister = pgInformer.Lister()
+		pgInformer.Informer()
+
+		crdInformerFactory.Start(ctx.Done())
+		if !cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced) {
+			return fmt.Errorf("failed to sync PodGroups")
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", webhook.New(opts.SchedulerNames, pgLister, opts.DefaultPodAnnotations).Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	server := &http.Server{Addr: opts.BindAddress, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	klog.InfoS("Starting webhook server", "address", opts.BindAddress)
+	if err := server.ListenAndServeTLS(opts.TLSCertFile, opts.TLSPrivateKeyFile); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -31,6 +31,14 @@ const (
 	NamespaceSystem = "godel-system"
 
 	DefaultLeaseLock = "leases"
+
+	// NodePartitionTypePhysical means the scheduler only sees and places pods on the nodes in its own partition.
+	NodePartitionTypePhysical = "Physical"
+	// NodePartitionTypeLogical means the scheduler prefers the nodes in its own partition, and falls back to
+	// the other nodes when none of them fits.
+	NodePartitionTypeLogical = "Logical"
+	// DefaultNodePartitionType is the default node partition type of schedulers.
+	DefaultNodePartitionType = NodePartitionTypeLogical
 )
 
 var (
@@ -20,11 +20,13 @@ import (
 	"bytes"
 	"fmt"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	componentbaseconfig "k8s.io/component-base/config/v1alpha1"
 	"sigs.k8s.io/yaml"
 
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
 
@@ -61,7 +63,116 @@ type GodelBinderConfiguration struct {
 	// Tracer defines the configuration of tracer
 	Tracer *tracing.TracerConfiguration
 
+	// GPUDefragmentation defines the configuration of the GPU defragmentation controller.
+	GPUDefragmentation *GPUDefragmentationConfiguration
+
+	// HotspotAvoidance defines the configuration of the hotspot avoidance controller.
+	HotspotAvoidance *HotspotAvoidanceConfiguration
+
+	// Sharding defines the configuration of horizontally sharded binders.
+	Sharding *BinderShardingConfiguration
+
+	// Handoff defines the configuration of receiving the scheduled pods from the schedulers directly.
+	Handoff *HandoffConfiguration
+
+	// PreemptionBudget limits the victims deleted per minute by the binder, nil means no limit.
+	// It should be consistent with the args of the PreemptionBudgetChecker plugin in schedulers.
+	// The victims are accounted in the memory of the binder, so the budget can't be set when the binders are
+	// sharded, which would multiply the effective limit by the number of shards, and the victims deleted
+	// before a restart are not accounted after it.
+	PreemptionBudget *preemptionbudget.Budget
+
 	Profile *GodelBinderProfile `json:"profile"`
+
+	// SchedulerNameProfiles are the profiles for the pods whose spec.schedulerName is not SchedulerName
+	// but should be served by the same scheduling system. Each profile is keyed by its SchedulerName,
+	// and the plugins and plugin configs not set are inherited from Profile.
+	SchedulerNameProfiles []GodelBinderProfile `json:"schedulerNameProfiles,omitempty"`
+}
+
+// GPUDefragmentationConfiguration configures the controller which consolidates scattered
+// free GPUs into whole nodes by migrating low-priority restartable pods.
+type GPUDefragmentationConfiguration struct {
+	// Enable indicates whether the GPU defragmentation controller should be started.
+	Enable bool
+	// DryRun makes the controller compute and report migration plans without evicting any pod.
+	DryRun bool
+	// ResourceName is the name of the GPU resource, defaulting to nvidia.com/gpu.
+	ResourceName string
+	// SyncPeriodSeconds is the interval between two rounds of fragmentation analysis.
+	SyncPeriodSeconds int64
+	// MaxVictimPriority is the highest priority of pods that are allowed to be migrated.
+	MaxVictimPriority int32
+	// MaxMigrationsPerRound limits the number of pods migrated in one round.
+	MaxMigrationsPerRound int32
+	// MaxMigrationsPerMinute limits the migration rate across rounds.
+	MaxMigrationsPerMinute int32
+}
+
+// BinderShardingConfiguration configures multiple active binders, each of which
+// binds the units of its own shard.
+type BinderShardingConfiguration struct {
+	// Enable indicates whether the binders are sharded. Leader election is then only used by the controllers.
+	Enable bool
+	// ShardingKey decides the shard of a unit, one of SchedulerName and NodeName.
+	ShardingKey string
+	// Identity is the identity of this binder in the shard membership, defaulting to the hostname with a random suffix.
+	Identity string
+	// LeaseNamespace is the namespace of the shard membership leases, defaulting to the namespace of leader election.
+	LeaseNamespace string
+	// LeaseDurationSeconds is the duration after which a binder which stops renewing its lease loses its shard.
+	LeaseDurationSeconds int64
+	// RenewIntervalSeconds is the interval of renewing the lease and refreshing the shard membership.
+	RenewIntervalSeconds int64
+}
+
+// HandoffConfiguration configures the server receiving the placements handed off by the schedulers directly,
+// instead of watching them being persisted through the pod annotations.
+type HandoffConfiguration struct {
+	// Enable indicates whether the handoff server should be started.
+	Enable bool
+	// BindAddress is the IP address and port for the handoff server to serve on, defaulting to 0.0.0.0:10452.
+	BindAddress string
+	// CertFile and KeyFile are the serving certificate and key of the handoff server.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the CA bundle verifying the client certificates of the schedulers. The handoff
+	// server only accepts the connections presenting a client certificate signed by this CA.
+	ClientCAFile string
+	// AllowedClientNames are the common names of the client certificates allowed to hand off placements,
+	// i.e. the identities of the schedulers.
+	AllowedClientNames []string
+}
+
+// HotspotAvoidanceConfiguration configures the controller which moves best-effort pods away from the nodes
+// whose usage exceeds the LoadAware usage thresholds.
+type HotspotAvoidanceConfiguration struct {
+	// Enable indicates whether the hotspot avoidance controller should be started.
+	Enable bool
+	// DryRun makes the controller compute and report evictions without evicting any pod.
+	DryRun bool
+	// SyncPeriodSeconds is the interval between two rounds of hotspot detection.
+	SyncPeriodSeconds int64
+	// UsageThresholds are the usage percentages of allocatable resources above which a node is overloaded,
+	// they should be consistent with the UsageThresholds of the LoadAware plugin in schedulers.
+	UsageThresholds map[v1.ResourceName]int64
+	// NodeMetricExpirationSeconds indicates the node metrics older than it are not trusted.
+	NodeMetricExpirationSeconds int64
+	// Estimator is the LoadAware estimator used to estimate the usage of the best-effort pods, which decides
+	// the pods to evict and how much usage the evictions release. Defaults to the default estimator.
+	Estimator string
+	// EstimatedScalingFactors are the factors applied to the requests when estimating the usage of pods.
+	EstimatedScalingFactors map[v1.ResourceName]int64
+	// HistoricalUsagePercentile is the percentile of the workload historical usage used by historicalUsageEstimator.
+	HistoricalUsagePercentile int64
+	// MaxVictimPriority is the highest priority of pods that are allowed to be evicted.
+	MaxVictimPriority int32
+	// MaxEvictionsPerNode limits the number of pods evicted from one node in one round.
+	MaxEvictionsPerNode int32
+	// MaxEvictionsPerRound limits the number of pods evicted in one round.
+	MaxEvictionsPerRound int32
+	// MaxEvictionsPerMinute limits the eviction rate across rounds.
+	MaxEvictionsPerMinute int32
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -70,6 +181,10 @@ type GodelBinderConfiguration struct {
 type GodelBinderProfile struct {
 	metav1.TypeMeta `json:",inline"`
 
+	// SchedulerName associates the profile to the pods with the same spec.schedulerName.
+	// It is only used by SchedulerNameProfiles.
+	SchedulerName string `json:"schedulerName,omitempty"`
+
 	Plugins *Plugins `json:"plugins"`
 
 	// PluginConfigs is an optional set of custom plugin arguments for each plugin.
@@ -132,6 +247,11 @@ func (in *GodelBinderConfiguration) DecodeNestedObjects(d runtime.Decoder) error
 	if err := decodeProfile(in.Profile); err != nil {
 		return err
 	}
+	for i := range in.SchedulerNameProfiles {
+		if err := decodeProfile(&in.SchedulerNameProfiles[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -159,6 +279,11 @@ func (in *GodelBinderConfiguration) EncodeNestedObjects(e runtime.Encoder) error
 	if err := encodeProfile(in.Profile); err != nil {
 		return err
 	}
+	for i := range in.SchedulerNameProfiles {
+		if err := encodeProfile(&in.SchedulerNameProfiles[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
 
@@ -37,6 +38,34 @@ const (
 
 	DefaultReservationTimeOutSeconds = 60
 
+	// DefaultGPUDefragmentationSyncPeriodSeconds is the default interval between two rounds of GPU defragmentation
+	DefaultGPUDefragmentationSyncPeriodSeconds = 300
+	// DefaultGPUDefragmentationMaxMigrationsPerRound is the default number of pods migrated in one round
+	DefaultGPUDefragmentationMaxMigrationsPerRound = 10
+	// DefaultGPUDefragmentationMaxMigrationsPerMinute is the default migration rate of GPU defragmentation
+	DefaultGPUDefragmentationMaxMigrationsPerMinute = 10
+
+	// DefaultHotspotAvoidanceSyncPeriodSeconds is the default interval between two rounds of hotspot avoidance
+	DefaultHotspotAvoidanceSyncPeriodSeconds = 60
+	// DefaultHotspotAvoidanceNodeMetricExpirationSeconds is the default expiration of node metrics used by hotspot avoidance
+	DefaultHotspotAvoidanceNodeMetricExpirationSeconds = 180
+	// DefaultHotspotAvoidanceMaxEvictionsPerNode is the default number of pods evicted from one node in one round
+	DefaultHotspotAvoidanceMaxEvictionsPerNode = 2
+	// DefaultHotspotAvoidanceMaxEvictionsPerRound is the default number of pods evicted in one round
+	DefaultHotspotAvoidanceMaxEvictionsPerRound = 10
+	// DefaultHotspotAvoidanceMaxEvictionsPerMinute is the default eviction rate of hotspot avoidance
+	DefaultHotspotAvoidanceMaxEvictionsPerMinute = 10
+
+	// BinderShardingBySchedulerName assigns all the units of a scheduler, i.e. of its node partition, to the same shard
+	BinderShardingBySchedulerName = "SchedulerName"
+	// DefaultBinderShardingLeaseDurationSeconds is the default duration of the shard membership leases
+	DefaultBinderShardingLeaseDurationSeconds = 15
+	// DefaultBinderShardingRenewIntervalSeconds is the default interval of renewing the shard membership leases
+	DefaultBinderShardingRenewIntervalSeconds = 5
+
+	// DefaultHandoffBindAddress is the default address of the handoff server
+	DefaultHandoffBindAddress = "0.0.0.0:10452"
+
 	BinderDefaultLockObjectName = "godel-binder"
 )
 
@@ -131,4 +160,62 @@ func SetDefaults_GodelBinderConfiguration(cfg *GodelBinderConfiguration) {
 	}
 
 	cfg.VolumeBindingTimeoutSeconds = VolumeBindingTimeoutSeconds
+
+	if cfg.GPUDefragmentation == nil {
+		cfg.GPUDefragmentation = &GPUDefragmentationConfiguration{}
+	}
+	if len(cfg.GPUDefragmentation.ResourceName) == 0 {
+		cfg.GPUDefragmentation.ResourceName = string(util.ResourceGPU)
+	}
+	if cfg.GPUDefragmentation.SyncPeriodSeconds == 0 {
+		cfg.GPUDefragmentation.SyncPeriodSeconds = DefaultGPUDefragmentationSyncPeriodSeconds
+	}
+	if cfg.GPUDefragmentation.MaxMigrationsPerRound == 0 {
+		cfg.GPUDefragmentation.MaxMigrationsPerRound = DefaultGPUDefragmentationMaxMigrationsPerRound
+	}
+	if cfg.GPUDefragmentation.MaxMigrationsPerMinute == 0 {
+		cfg.GPUDefragmentation.MaxMigrationsPerMinute = DefaultGPUDefragmentationMaxMigrationsPerMinute
+	}
+
+	if cfg.HotspotAvoidance == nil {
+		cfg.HotspotAvoidance = &HotspotAvoidanceConfiguration{}
+	}
+	if cfg.HotspotAvoidance.SyncPeriodSeconds == 0 {
+		cfg.HotspotAvoidance.SyncPeriodSeconds = DefaultHotspotAvoidanceSyncPeriodSeconds
+	}
+	if cfg.HotspotAvoidance.NodeMetricExpirationSeconds == 0 {
+		cfg.HotspotAvoidance.NodeMetricExpirationSeconds = DefaultHotspotAvoidanceNodeMetricExpirationSeconds
+	}
+	if cfg.HotspotAvoidance.MaxEvictionsPerNode == 0 {
+		cfg.HotspotAvoidance.MaxEvictionsPerNode = DefaultHotspotAvoidanceMaxEvictionsPerNode
+	}
+	if cfg.HotspotAvoidance.MaxEvictionsPerRound == 0 {
+		cfg.HotspotAvoidance.MaxEvictionsPerRound = DefaultHotspotAvoidanceMaxEvictionsPerRound
+	}
+	if cfg.HotspotAvoidance.MaxEvictionsPerMinute == 0 {
+		cfg.HotspotAvoidance.MaxEvictionsPerMinute = DefaultHotspotAvoidanceMaxEvictionsPerMinute
+	}
+
+	if cfg.Sharding == nil {
+		cfg.Sharding = &BinderShardingConfiguration{}
+	}
+	if len(cfg.Sharding.ShardingKey) == 0 {
+		cfg.Sharding.ShardingKey = BinderShardingBySchedulerName
+	}
+	if len(cfg.Sharding.LeaseNamespace) == 0 {
+		cfg.Sharding.LeaseNamespace = cfg.LeaderElection.ResourceNamespace
+	}
+	if cfg.Sharding.LeaseDurationSeconds == 0 {
+		cfg.Sharding.LeaseDurationSeconds = DefaultBinderShardingLeaseDurationSeconds
+	}
+	if cfg.Sharding.RenewIntervalSeconds == 0 {
+		cfg.Sharding.RenewIntervalSeconds = DefaultBinderShardingRenewIntervalSeconds
+	}
+
+	if cfg.Handoff == nil {
+		cfg.Handoff = &HandoffConfiguration{}
+	}
+	if len(cfg.Handoff.BindAddress) == 0 {
+		cfg.Handoff.BindAddress = DefaultHandoffBindAddress
+	}
 }
@@ -25,7 +25,9 @@ import (
 	unsafe "unsafe"
 
 	config "github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	preemptionbudget "github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 	tracing "github.com/kubewharf/godel-scheduler/pkg/util/tracing"
+	v1 "k8s.io/api/core/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -37,6 +39,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*BinderShardingConfiguration)(nil), (*config.BinderShardingConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_BinderShardingConfiguration_To_config_BinderShardingConfiguration(a.(*BinderShardingConfiguration), b.(*config.BinderShardingConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.BinderShardingConfiguration)(nil), (*BinderShardingConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_BinderShardingConfiguration_To_v1beta1_BinderShardingConfiguration(a.(*config.BinderShardingConfiguration), b.(*BinderShardingConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GPUDefragmentationConfiguration)(nil), (*config.GPUDefragmentationConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GPUDefragmentationConfiguration_To_config_GPUDefragmentationConfiguration(a.(*GPUDefragmentationConfiguration), b.(*config.GPUDefragmentationConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GPUDefragmentationConfiguration)(nil), (*GPUDefragmentationConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GPUDefragmentationConfiguration_To_v1beta1_GPUDefragmentationConfiguration(a.(*config.GPUDefragmentationConfiguration), b.(*GPUDefragmentationConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GodelBinderConfiguration)(nil), (*config.GodelBinderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_GodelBinderConfiguration_To_config_GodelBinderConfiguration(a.(*GodelBinderConfiguration), b.(*config.GodelBinderConfiguration), scope)
 	}); err != nil {
@@ -57,6 +79,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HandoffConfiguration)(nil), (*config.HandoffConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HandoffConfiguration_To_config_HandoffConfiguration(a.(*HandoffConfiguration), b.(*config.HandoffConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.HandoffConfiguration)(nil), (*HandoffConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_HandoffConfiguration_To_v1beta1_HandoffConfiguration(a.(*config.HandoffConfiguration), b.(*HandoffConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HotspotAvoidanceConfiguration)(nil), (*config.HotspotAvoidanceConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HotspotAvoidanceConfiguration_To_config_HotspotAvoidanceConfiguration(a.(*HotspotAvoidanceConfiguration), b.(*config.HotspotAvoidanceConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.HotspotAvoidanceConfiguration)(nil), (*HotspotAvoidanceConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_HotspotAvoidanceConfiguration_To_v1beta1_HotspotAvoidanceConfiguration(a.(*config.HotspotAvoidanceConfiguration), b.(*HotspotAvoidanceConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Plugin)(nil), (*config.Plugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Plugin_To_config_Plugin(a.(*Plugin), b.(*config.Plugin), scope)
 	}); err != nil {
@@ -110,6 +152,68 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1beta1_BinderShardingConfiguration_To_config_BinderShardingConfiguration(in *BinderShardingConfiguration, out *config.BinderShardingConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.ShardingKey = in.ShardingKey
+	out.Identity = in.Identity
+	out.LeaseNamespace = in.LeaseNamespace
+	out.LeaseDurationSeconds = in.LeaseDurationSeconds
+	out.RenewIntervalSeconds = in.RenewIntervalSeconds
+	return nil
+}
+
+// Convert_v1beta1_BinderShardingConfiguration_To_config_BinderShardingConfiguration is an autogenerated conversion function.
+func Convert_v1beta1_BinderShardingConfiguration_To_config_BinderShardingConfiguration(in *BinderShardingConfiguration, out *config.BinderShardingConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta1_BinderShardingConfiguration_To_config_BinderShardingConfiguration(in, out, s)
+}
+
+func autoConvert_config_BinderShardingConfiguration_To_v1beta1_BinderShardingConfiguration(in *config.BinderShardingConfiguration, out *BinderShardingConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.ShardingKey = in.ShardingKey
+	out.Identity = in.Identity
+	out.LeaseNamespace = in.LeaseNamespace
+	out.LeaseDurationSeconds = in.LeaseDurationSeconds
+	out.RenewIntervalSeconds = in.RenewIntervalSeconds
+	return nil
+}
+
+// Convert_config_BinderShardingConfiguration_To_v1beta1_BinderShardingConfiguration is an autogenerated conversion function.
+func Convert_config_BinderShardingConfiguration_To_v1beta1_BinderShardingConfiguration(in *config.BinderShardingConfiguration, out *BinderShardingConfiguration, s conversion.Scope) error {
+	return autoConvert_config_BinderShardingConfiguration_To_v1beta1_BinderShardingConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta1_GPUDefragmentationConfiguration_To_config_GPUDefragmentationConfiguration(in *GPUDefragmentationConfiguration, out *config.GPUDefragmentationConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.DryRun = in.DryRun
+	out.ResourceName = in.ResourceName
+	out.SyncPeriodSeconds = in.SyncPeriodSeconds
+	out.MaxVictimPriority = in.MaxVictimPriority
+	out.MaxMigrationsPerRound = in.MaxMigrationsPerRound
+	out.MaxMigrationsPerMinute = in.MaxMigrationsPerMinute
+	return nil
+}
+
+// Convert_v1beta1_GPUDefragmentationConfiguration_To_config_GPUDefragmentationConfiguration is an autogenerated conversion function.
+func Convert_v1beta1_GPUDefragmentationConfiguration_To_config_GPUDefragmentationConfiguration(in *GPUDefragmentationConfiguration, out *config.GPUDefragmentationConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta1_GPUDefragmentationConfiguration_To_config_GPUDefragmentationConfiguration(in, out, s)
+}
+
+func autoConvert_config_GPUDefragmentationConfiguration_To_v1beta1_GPUDefragmentationConfiguration(in *config.GPUDefragmentationConfiguration, out *GPUDefragmentationConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.DryRun = in.DryRun
+	out.ResourceName = in.ResourceName
+	out.SyncPeriodSeconds = in.SyncPeriodSeconds
+	out.MaxVictimPriority = in.MaxVictimPriority
+	out.MaxMigrationsPerRound = in.MaxMigrationsPerRound
+	out.MaxMigrationsPerMinute = in.MaxMigrationsPerMinute
+	return nil
+}
+
+// Convert_config_GPUDefragmentationConfiguration_To_v1beta1_GPUDefragmentationConfiguration is an autogenerated conversion function.
+func Convert_config_GPUDefragmentationConfiguration_To_v1beta1_GPUDefragmentationConfiguration(in *config.GPUDefragmentationConfiguration, out *GPUDefragmentationConfiguration, s conversion.Scope) error {
+	return autoConvert_config_GPUDefragmentationConfiguration_To_v1beta1_GPUDefragmentationConfiguration(in, out, s)
+}
+
 func autoConvert_v1beta1_GodelBinderConfiguration_To_config_GodelBinderConfiguration(in *GodelBinderConfiguration, out *config.GodelBinderConfiguration, s conversion.Scope) error {
 	out.DebuggingConfiguration = in.DebuggingConfiguration
 	out.ClientConnection = in.ClientConnection
@@ -119,7 +223,13 @@ func autoConvert_v1beta1_GodelBinderConfiguration_To_config_GodelBinderConfigura
 	out.MetricsBindAddress = in.MetricsBindAddress
 	out.VolumeBindingTimeoutSeconds = in.VolumeBindingTimeoutSeconds
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
+	out.GPUDefragmentation = (*config.GPUDefragmentationConfiguration)(unsafe.Pointer(in.GPUDefragmentation))
+	out.HotspotAvoidance = (*config.HotspotAvoidanceConfiguration)(unsafe.Pointer(in.HotspotAvoidance))
+	out.Sharding = (*config.BinderShardingConfiguration)(unsafe.Pointer(in.Sharding))
+	out.Handoff = (*config.HandoffConfiguration)(unsafe.Pointer(in.Handoff))
+	out.PreemptionBudget = (*preemptionbudget.Budget)(unsafe.Pointer(in.PreemptionBudget))
 	out.Profile = (*config.GodelBinderProfile)(unsafe.Pointer(in.Profile))
+	out.SchedulerNameProfiles = *(*[]config.GodelBinderProfile)(unsafe.Pointer(&in.SchedulerNameProfiles))
 	return nil
 }
 
@@ -137,7 +247,13 @@ func autoConvert_config_GodelBinderConfiguration_To_v1beta1_GodelBinderConfigura
 	out.MetricsBindAddress = in.MetricsBindAddress
 	out.VolumeBindingTimeoutSeconds = in.VolumeBindingTimeoutSeconds
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
+	out.GPUDefragmentation = (*GPUDefragmentationConfiguration)(unsafe.Pointer(in.GPUDefragmentation))
+	out.HotspotAvoidance = (*HotspotAvoidanceConfiguration)(unsafe.Pointer(in.HotspotAvoidance))
+	out.Sharding = (*BinderShardingConfiguration)(unsafe.Pointer(in.Sharding))
+	out.Handoff = (*HandoffConfiguration)(unsafe.Pointer(in.Handoff))
+	out.PreemptionBudget = (*preemptionbudget.Budget)(unsafe.Pointer(in.PreemptionBudget))
 	out.Profile = (*GodelBinderProfile)(unsafe.Pointer(in.Profile))
+	out.SchedulerNameProfiles = *(*[]GodelBinderProfile)(unsafe.Pointer(&in.SchedulerNameProfiles))
 	return nil
 }
 
@@ -147,6 +263,7 @@ func Convert_config_GodelBinderConfiguration_To_v1beta1_GodelBinderConfiguration
 }
 
 func autoConvert_v1beta1_GodelBinderProfile_To_config_GodelBinderProfile(in *GodelBinderProfile, out *config.GodelBinderProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
 	out.Plugins = (*config.Plugins)(unsafe.Pointer(in.Plugins))
 	out.PreemptionPluginConfigs = *(*[]config.PluginConfig)(unsafe.Pointer(&in.PreemptionPluginConfigs))
 	out.PluginConfigs = *(*[]config.PluginConfig)(unsafe.Pointer(&in.PluginConfigs))
@@ -159,6 +276,7 @@ func Convert_v1beta1_GodelBinderProfile_To_config_GodelBinderProfile(in *GodelBi
 }
 
 func autoConvert_config_GodelBinderProfile_To_v1beta1_GodelBinderProfile(in *config.GodelBinderProfile, out *GodelBinderProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
 	out.Plugins = (*Plugins)(unsafe.Pointer(in.Plugins))
 	out.PreemptionPluginConfigs = *(*[]PluginConfig)(unsafe.Pointer(&in.PreemptionPluginConfigs))
 	out.PluginConfigs = *(*[]PluginConfig)(unsafe.Pointer(&in.PluginConfigs))
@@ -170,6 +288,78 @@ func Convert_config_GodelBinderProfile_To_v1beta1_GodelBinderProfile(in *config.
 	return autoConvert_config_GodelBinderProfile_To_v1beta1_GodelBinderProfile(in, out, s)
 }
 
+func autoConvert_v1beta1_HandoffConfiguration_To_config_HandoffConfiguration(in *HandoffConfiguration, out *config.HandoffConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.BindAddress = in.BindAddress
+	out.CertFile = in.CertFile
+	out.KeyFile = in.KeyFile
+	out.ClientCAFile = in.ClientCAFile
+	out.AllowedClientNames = *(*[]string)(unsafe.Pointer(&in.AllowedClientNames))
+	return nil
+}
+
+// Convert_v1beta1_HandoffConfiguration_To_config_HandoffConfiguration is an autogenerated conversion function.
+func Convert_v1beta1_HandoffConfiguration_To_config_HandoffConfiguration(in *HandoffConfiguration, out *config.HandoffConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta1_HandoffConfiguration_To_config_HandoffConfiguration(in, out, s)
+}
+
+func autoConvert_config_HandoffConfiguration_To_v1beta1_HandoffConfiguration(in *config.HandoffConfiguration, out *HandoffConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.BindAddress = in.BindAddress
+	out.CertFile = in.CertFile
+	out.KeyFile = in.KeyFile
+	out.ClientCAFile = in.ClientCAFile
+	out.AllowedClientNames = *(*[]string)(unsafe.Pointer(&in.AllowedClientNames))
+	return nil
+}
+
+// Convert_config_HandoffConfiguration_To_v1beta1_HandoffConfiguration is an autogenerated conversion function.
+func Convert_config_HandoffConfiguration_To_v1beta1_HandoffConfiguration(in *config.HandoffConfiguration, out *HandoffConfiguration, s conversion.Scope) error {
+	return autoConvert_config_HandoffConfiguration_To_v1beta1_HandoffConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta1_HotspotAvoidanceConfiguration_To_config_HotspotAvoidanceConfiguration(in *HotspotAvoidanceConfiguration, out *config.HotspotAvoidanceConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.DryRun = in.DryRun
+	out.SyncPeriodSeconds = in.SyncPeriodSeconds
+	out.UsageThresholds = *(*map[v1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
+	out.NodeMetricExpirationSeconds = in.NodeMetricExpirationSeconds
+	out.Estimator = in.Estimator
+	out.EstimatedScalingFactors = *(*map[v1.ResourceName]int64)(unsafe.Pointer(&in.EstimatedScalingFactors))
+	out.HistoricalUsagePercentile = in.HistoricalUsagePercentile
+	out.MaxVictimPriority = in.MaxVictimPriority
+	out.MaxEvictionsPerNode = in.MaxEvictionsPerNode
+	out.MaxEvictionsPerRound = in.MaxEvictionsPerRound
+	out.MaxEvictionsPerMinute = in.MaxEvictionsPerMinute
+	return nil
+}
+
+// Convert_v1beta1_HotspotAvoidanceConfiguration_To_config_HotspotAvoidanceConfiguration is an autogenerated conversion function.
+func Convert_v1beta1_HotspotAvoidanceConfiguration_To_config_HotspotAvoidanceConfiguration(in *HotspotAvoidanceConfiguration, out *config.HotspotAvoidanceConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta1_HotspotAvoidanceConfiguration_To_config_HotspotAvoidanceConfiguration(in, out, s)
+}
+
+func autoConvert_config_HotspotAvoidanceConfiguration_To_v1beta1_HotspotAvoidanceConfiguration(in *config.HotspotAvoidanceConfiguration, out *HotspotAvoidanceConfiguration, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.DryRun = in.DryRun
+	out.SyncPeriodSeconds = in.SyncPeriodSeconds
+	out.UsageThresholds = *(*map[v1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
+	out.NodeMetricExpirationSeconds = in.NodeMetricExpirationSeconds
+	out.Estimator = in.Estimator
+	out.EstimatedScalingFactors = *(*map[v1.ResourceName]int64)(unsafe.Pointer(&in.EstimatedScalingFactors))
+	out.HistoricalUsagePercentile = in.HistoricalUsagePercentile
+	out.MaxVictimPriority = in.MaxVictimPriority
+	out.MaxEvictionsPerNode = in.MaxEvictionsPerNode
+	out.MaxEvictionsPerRound = in.MaxEvictionsPerRound
+	out.MaxEvictionsPerMinute = in.MaxEvictionsPerMinute
+	return nil
+}
+
+// Convert_config_HotspotAvoidanceConfiguration_To_v1beta1_HotspotAvoidanceConfiguration is an autogenerated conversion function.
+func Convert_config_HotspotAvoidanceConfiguration_To_v1beta1_HotspotAvoidanceConfiguration(in *config.HotspotAvoidanceConfiguration, out *HotspotAvoidanceConfiguration, s conversion.Scope) error {
+	return autoConvert_config_HotspotAvoidanceConfiguration_To_v1beta1_HotspotAvoidanceConfiguration(in, out, s)
+}
+
 func autoConvert_v1beta1_Plugin_To_config_Plugin(in *Plugin, out *config.Plugin, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Weight = in.Weight
@@ -22,9 +22,42 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BinderShardingConfiguration) DeepCopyInto(out *BinderShardingConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BinderShardingConfiguration.
+func (in *BinderShardingConfiguration) DeepCopy() *BinderShardingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BinderShardingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUDefragmentationConfiguration) DeepCopyInto(out *GPUDefragmentationConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDefragmentationConfiguration.
+func (in *GPUDefragmentationConfiguration) DeepCopy() *GPUDefragmentationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUDefragmentationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GodelBinderConfiguration) DeepCopyInto(out *GodelBinderConfiguration) {
 	*out = *in
@@ -41,11 +74,42 @@ func (in *GodelBinderConfiguration) DeepCopyInto(out *GodelBinderConfiguration)
 		in, out := &in.Tracer, &out.Tracer
 		*out = (*in).DeepCopy()
 	}
+	if in.GPUDefragmentation != nil {
+		in, out := &in.GPUDefragmentation, &out.GPUDefragmentation
+		*out = new(GPUDefragmentationConfiguration)
+		**out = **in
+	}
+	if in.HotspotAvoidance != nil {
+		in, out := &in.HotspotAvoidance, &out.HotspotAvoidance
+		*out = new(HotspotAvoidanceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sharding != nil {
+		in, out := &in.Sharding, &out.Sharding
+		*out = new(BinderShardingConfiguration)
+		**out = **in
+	}
+	if in.Handoff != nil {
+		in, out := &in.Handoff, &out.Handoff
+		*out = new(HandoffConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreemptionBudget != nil {
+		in, out := &in.PreemptionBudget, &out.PreemptionBudget
+		*out = (*in).DeepCopy()
+	}
 	if in.Profile != nil {
 		in, out := &in.Profile, &out.Profile
 		*out = new(GodelBinderProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SchedulerNameProfiles != nil {
+		in, out := &in.SchedulerNameProfiles, &out.SchedulerNameProfiles
+		*out = make([]GodelBinderProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -111,6 +175,57 @@ func (in *GodelBinderProfile) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HandoffConfiguration) DeepCopyInto(out *HandoffConfiguration) {
+	*out = *in
+	if in.AllowedClientNames != nil {
+		in, out := &in.AllowedClientNames, &out.AllowedClientNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HandoffConfiguration.
+func (in *HandoffConfiguration) DeepCopy() *HandoffConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HandoffConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HotspotAvoidanceConfiguration) DeepCopyInto(out *HotspotAvoidanceConfiguration) {
+	*out = *in
+	if in.UsageThresholds != nil {
+		in, out := &in.UsageThresholds, &out.UsageThresholds
+		*out = make(map[v1.ResourceName]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EstimatedScalingFactors != nil {
+		in, out := &in.EstimatedScalingFactors, &out.EstimatedScalingFactors
+		*out = make(map[v1.ResourceName]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HotspotAvoidanceConfiguration.
+func (in *HotspotAvoidanceConfiguration) DeepCopy() *HotspotAvoidanceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HotspotAvoidanceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Plugin) DeepCopyInto(out *Plugin) {
 	*out = *in
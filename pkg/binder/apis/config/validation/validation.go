@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -46,5 +47,154 @@ func ValidateGodelBinderConfiguration(cc *config.GodelBinderConfiguration) field
 			cc.VolumeBindingTimeoutSeconds, "must be greater than 0"))
 	}
 
+	if cc.GPUDefragmentation != nil && cc.GPUDefragmentation.Enable {
+		errs = append(errs, ValidateGPUDefragmentationConfiguration(cc.GPUDefragmentation, field.NewPath("gpuDefragmentation"))...)
+	}
+
+	if cc.HotspotAvoidance != nil && cc.HotspotAvoidance.Enable {
+		errs = append(errs, ValidateHotspotAvoidanceConfiguration(cc.HotspotAvoidance, field.NewPath("hotspotAvoidance"))...)
+	}
+
+	if cc.Sharding != nil && cc.Sharding.Enable {
+		errs = append(errs, ValidateBinderShardingConfiguration(cc.Sharding, field.NewPath("sharding"))...)
+	}
+
+	if cc.Handoff != nil && cc.Handoff.Enable {
+		errs = append(errs, ValidateHandoffConfiguration(cc.Handoff, field.NewPath("handoff"))...)
+	}
+
+	errs = append(errs, cc.PreemptionBudget.Validate(field.NewPath("preemptionBudget"))...)
+	if cc.PreemptionBudget != nil && cc.Sharding != nil && cc.Sharding.Enable {
+		// every shard would account the victims on its own and admit up to the whole budget
+		errs = append(errs, field.Forbidden(field.NewPath("preemptionBudget"), "can't be set when sharding is enabled"))
+	}
+
+	schedulerNames := make(map[string]bool, len(cc.SchedulerNameProfiles))
+	for i, profile := range cc.SchedulerNameProfiles {
+		fldPath := field.NewPath("schedulerNameProfiles").Index(i).Child("schedulerName")
+		switch {
+		case len(profile.SchedulerName) == 0:
+			errs = append(errs, field.Required(fldPath, ""))
+		case cc.SchedulerName != nil && profile.SchedulerName == *cc.SchedulerName:
+			errs = append(errs, field.Invalid(fldPath, profile.SchedulerName, "must be different from schedulerName"))
+		case schedulerNames[profile.SchedulerName]:
+			errs = append(errs, field.Duplicate(fldPath, profile.SchedulerName))
+		}
+		schedulerNames[profile.SchedulerName] = true
+	}
+
+	return errs
+}
+
+// ValidateGPUDefragmentationConfiguration validates the configuration of the GPU defragmentation controller.
+func ValidateGPUDefragmentationConfiguration(cc *config.GPUDefragmentationConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(cc.ResourceName) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("resourceName"), "must not be empty"))
+	}
+	if cc.SyncPeriodSeconds <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("syncPeriodSeconds"),
+			cc.SyncPeriodSeconds, "must be greater than 0"))
+	}
+	if cc.MaxMigrationsPerRound <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxMigrationsPerRound"),
+			cc.MaxMigrationsPerRound, "must be greater than 0"))
+	}
+	if cc.MaxMigrationsPerMinute <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxMigrationsPerMinute"),
+			cc.MaxMigrationsPerMinute, "must be greater than 0"))
+	}
+	return errs
+}
+
+// ValidateHotspotAvoidanceConfiguration validates the configuration of the hotspot avoidance controller.
+func ValidateHotspotAvoidanceConfiguration(cc *config.HotspotAvoidanceConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(cc.UsageThresholds) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("usageThresholds"), "must not be empty"))
+	}
+	for resourceName, threshold := range cc.UsageThresholds {
+		if resourceName != v1.ResourceCPU && resourceName != v1.ResourceMemory {
+			errs = append(errs, field.NotSupported(fldPath.Child("usageThresholds").Key(string(resourceName)),
+				resourceName, []string{string(v1.ResourceCPU), string(v1.ResourceMemory)}))
+		}
+		if threshold <= 0 || threshold > 100 {
+			errs = append(errs, field.Invalid(fldPath.Child("usageThresholds").Key(string(resourceName)),
+				threshold, "must be in the range (0, 100]"))
+		}
+	}
+	if cc.SyncPeriodSeconds <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("syncPeriodSeconds"),
+			cc.SyncPeriodSeconds, "must be greater than 0"))
+	}
+	if cc.NodeMetricExpirationSeconds <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("nodeMetricExpirationSeconds"),
+			cc.NodeMetricExpirationSeconds, "must be greater than 0"))
+	}
+	for resourceName, factor := range cc.EstimatedScalingFactors {
+		if factor <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("estimatedScalingFactors").Key(string(resourceName)),
+				factor, "must be greater than 0"))
+		}
+	}
+	if cc.HistoricalUsagePercentile < 0 || cc.HistoricalUsagePercentile > 100 {
+		errs = append(errs, field.Invalid(fldPath.Child("historicalUsagePercentile"),
+			cc.HistoricalUsagePercentile, "must be in the range [0, 100]"))
+	}
+	if cc.MaxEvictionsPerNode <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxEvictionsPerNode"),
+			cc.MaxEvictionsPerNode, "must be greater than 0"))
+	}
+	if cc.MaxEvictionsPerRound <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxEvictionsPerRound"),
+			cc.MaxEvictionsPerRound, "must be greater than 0"))
+	}
+	if cc.MaxEvictionsPerMinute <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxEvictionsPerMinute"),
+			cc.MaxEvictionsPerMinute, "must be greater than 0"))
+	}
+	return errs
+}
+
+// ValidateBinderShardingConfiguration validates the configuration of sharded binders.
+func ValidateBinderShardingConfiguration(cc *config.BinderShardingConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if cc.ShardingKey != config.BinderShardingBySchedulerName && cc.ShardingKey != config.BinderShardingByNodeName {
+		errs = append(errs, field.NotSupported(fldPath.Child("shardingKey"), cc.ShardingKey,
+			[]string{config.BinderShardingBySchedulerName, config.BinderShardingByNodeName}))
+	}
+	if len(cc.LeaseNamespace) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("leaseNamespace"), "must not be empty"))
+	}
+	if cc.RenewIntervalSeconds <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("renewIntervalSeconds"),
+			cc.RenewIntervalSeconds, "must be greater than 0"))
+	}
+	if cc.LeaseDurationSeconds <= cc.RenewIntervalSeconds {
+		errs = append(errs, field.Invalid(fldPath.Child("leaseDurationSeconds"),
+			cc.LeaseDurationSeconds, "must be greater than renewIntervalSeconds"))
+	}
+	return errs
+}
+
+// ValidateHandoffConfiguration ensures the handoff server is only served through mutual TLS, since the handed
+// off placements, including the victims to be preempted, are applied as if they were persisted by the schedulers.
+func ValidateHandoffConfiguration(cc *config.HandoffConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, msg := range validation.IsValidSocketAddr(cc.BindAddress) {
+		errs = append(errs, field.Invalid(fldPath.Child("bindAddress"), cc.BindAddress, msg))
+	}
+	if len(cc.CertFile) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("certFile"), "must not be empty"))
+	}
+	if len(cc.KeyFile) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("keyFile"), "must not be empty"))
+	}
+	if len(cc.ClientCAFile) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("clientCAFile"), "must not be empty"))
+	}
+	if len(cc.AllowedClientNames) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("allowedClientNames"), "must not be empty"))
+	}
 	return errs
 }
@@ -771,6 +771,18 @@ func (cache *binderCache) GetNode(nodename string) (framework.NodeInfo, error) {
 	return nInfo, nil
 }
 
+func (cache *binderCache) CloneNode(nodename string) (framework.NodeInfo, error) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	nInfo, ok := cache.nodeInfoMap[nodename]
+	if !ok {
+		return nil, fmt.Errorf("node %s does not exist in cache", nodename)
+	}
+
+	return nInfo.Clone(), nil
+}
+
 // AddPodGroup add pod group object into binder cache
 func (cache *binderCache) AddPodGroup(podGroup *schedulingv1a1.PodGroup) error {
 	cache.mu.Lock()
@@ -132,6 +132,14 @@ func (c *Cache) GetNode(nodename string) (framework.NodeInfo, error) {
 	return c.GetNodeFunc(nodename)
 }
 
+func (c *Cache) CloneNode(nodename string) (framework.NodeInfo, error) {
+	nodeInfo, err := c.GetNodeFunc(nodename)
+	if err != nil || nodeInfo == nil {
+		return nodeInfo, err
+	}
+	return nodeInfo.Clone(), nil
+}
+
 func (c *Cache) GetPodGroupPods(podGroupName string) []*v1.Pod {
 	return nil
 }
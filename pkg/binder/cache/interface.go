@@ -88,6 +88,9 @@ type BinderCache interface {
 	// check is pod is marked to delete
 	IsPodMarkedToDelete(pod *v1.Pod) (bool, error)
 	GetNode(nodename string) (framework.NodeInfo, error)
+	// CloneNode returns a clone of the NodeInfo taken under the cache lock, which is safe to read
+	// while the cache keeps being updated.
+	CloneNode(nodename string) (framework.NodeInfo, error)
 	GetPodGroupPods(podGroupName string) []*v1.Pod
 	GetPodGroupInfo(podGroupName string) (*schedulingv1a1.PodGroup, error)
 	GetUnitStatus(string) binderutils.UnitStatus
@@ -101,5 +104,6 @@ type BinderCache interface {
 	// ForgetPod removes an assumed pod from cache.
 	ForgetPod(pod *v1.Pod) error
 
+	// GetPDBItems returns the copies of the PDBItems, which are safe to read while the cache keeps being updated.
 	GetPDBItems() []framework.PDBItem
 }
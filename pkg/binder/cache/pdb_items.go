@@ -31,7 +31,7 @@ type pdbItem struct {
 }
 
 func (item *pdbItem) Clone() framework.PDBItem {
-	return nil
+	return &pdbItem{pdb: item.pdb, pdbSelector: item.pdbSelector}
 }
 
 func (item *pdbItem) GetPDB() *policy.PodDisruptionBudget {
@@ -76,7 +76,7 @@ func (cache *binderCache) GetPDBItems() []framework.PDBItem {
 
 	var items []framework.PDBItem
 	for _, item := range cache.pdbItems {
-		items = append(items, item)
+		items = append(items, item.Clone())
 	}
 	return items
 }
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const evictionRetryAttempts = 3
+
+// evictionRateLimiter limits the number of evictions performed in the latest minute.
+type evictionRateLimiter struct {
+	mu sync.Mutex
+	// history records the timestamps of the evictions in the latest minute.
+	history []time.Time
+	limit   int
+	now     func() time.Time
+}
+
+func newEvictionRateLimiter(limitPerMinute int32, now func() time.Time) *evictionRateLimiter {
+	return &evictionRateLimiter{limit: int(limitPerMinute), now: now}
+}
+
+// allow reports whether the given number of evictions can be performed without exceeding the limit,
+// and records them if so. The evictions are admitted as a whole.
+func (l *evictionRateLimiter) allow(count int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var kept []time.Time
+	for _, t := range l.history {
+		if now.Sub(t) < time.Minute {
+			kept = append(kept, t)
+		}
+	}
+	l.history = kept
+
+	if len(l.history)+count > l.limit {
+		return false
+	}
+	for i := 0; i < count; i++ {
+		l.history = append(l.history, now)
+	}
+	return true
+}
+
+// evictPod evicts the pod through the Eviction API, so that the PodDisruptionBudgets are enforced by
+// the apiserver even if they have changed since the pod was picked.
+func evictPod(client kubernetes.Interface, pod *v1.Pod) error {
+	eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name}}
+	var disallowed error
+	err := util.Retry(evictionRetryAttempts, time.Second, func() error {
+		err := client.PolicyV1().Evictions(pod.Namespace).Evict(context.TODO(), eviction)
+		if apierrors.IsTooManyRequests(err) {
+			// The eviction is disallowed by the PodDisruptionBudgets, retrying right away doesn't help.
+			disallowed = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return disallowed
+}
+
+// restartable checks whether the pod is a low-priority pod which will be recreated by its controller
+// after being evicted.
+func restartable(podInfo *framework.PodInfo, maxVictimPriority int32, now time.Time, isPodMarkedToDelete func(pod *v1.Pod) (bool, error)) bool {
+	pod := podInfo.Pod
+	if pod.DeletionTimestamp != nil || podInfo.PodPriority > int64(maxVictimPriority) {
+		return false
+	}
+	// Gang members are not restartable one by one.
+	if len(podInfo.PodGroupName) > 0 {
+		return false
+	}
+	if podInfo.PodPreemptionInfo.CanBePreempted == -1 || podutil.PodHasDaemonSetOwnerReference(pod) {
+		return false
+	}
+	// Bare pods won't be recreated after being evicted.
+	if metav1.GetControllerOf(pod) == nil {
+		return false
+	}
+	if podInfo.PodPreemptionInfo.ProtectionDurationExist && podInfo.PodPreemptionInfo.StartTime != nil {
+		protectedUntil := podInfo.PodPreemptionInfo.StartTime.Add(time.Duration(podInfo.PodPreemptionInfo.ProtectionDuration) * time.Second)
+		if now.Before(protectedUntil) {
+			return false
+		}
+	}
+	if isPodMarkedToDelete != nil {
+		if marked, err := isPodMarkedToDelete(pod); err != nil || marked {
+			return false
+		}
+	}
+	return true
+}
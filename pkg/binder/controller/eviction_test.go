@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+)
+
+// newEvictingClientset returns a fake clientset deleting the pods evicted through the Eviction API.
+func newEvictingClientset(objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(clienttesting.CreateAction).GetObject().(metav1.Object)
+		return true, nil, client.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), eviction.GetNamespace(), eviction.GetName())
+	})
+	return client
+}
+
+func TestEvictionRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := newEvictionRateLimiter(3, func() time.Time { return now })
+
+	var results []bool
+	for _, count := range []int{2, 2, 1} {
+		results = append(results, limiter.allow(count))
+	}
+	if !reflect.DeepEqual(results, []bool{true, false, true}) {
+		t.Errorf("unexpected results: %v", results)
+	}
+
+	now = now.Add(time.Minute)
+	if !limiter.allow(3) {
+		t.Errorf("expected evictions to be allowed after a minute")
+	}
+	if len(limiter.history) != 3 || !limiter.history[0].Equal(now) {
+		t.Errorf("expected expired evictions to be dropped, got %v", limiter.history)
+	}
+}
+
+func TestEvictPod(t *testing.T) {
+	pod := testinghelper.MakePod().Namespace("default").Name("p").UID("p").Obj()
+	client := newEvictingClientset(pod)
+	if err := evictPod(client, pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "p", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the pod to be evicted, got %v", err)
+	}
+
+	// The evictions disallowed by the PodDisruptionBudgets are not retried.
+	pod = testinghelper.MakePod().Namespace("default").Name("protected").UID("protected").Obj()
+	client = newEvictingClientset(pod)
+	attempts := 0
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		return true, nil, apierrors.NewTooManyRequests("disruption budget exceeded", 0)
+	})
+	if err := evictPod(client, pod); !apierrors.IsTooManyRequests(err) {
+		t.Errorf("expected the eviction to be disallowed, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %v", attempts)
+	}
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "protected", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the pod to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	schedv1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+const (
+	// Action types of the gang failure policies, used as metric labels.
+	releaseBoundAction = "release_bound"
+	restartGangAction  = "restart_gang"
+	deleteGangAction   = "delete_gang"
+)
+
+func gangTimeoutPolicy(pg *schedv1alpha1.PodGroup) unitutil.GangTimeoutPolicy {
+	policy, err := unitutil.GetGangTimeoutPolicy(pg)
+	if err != nil {
+		klog.InfoS("Invalid gang timeout policy, fall back to the default one", "podGroupKey", unitutil.GetPodGroupKey(pg), "err", err)
+	}
+	return policy
+}
+
+func gangFailurePolicy(pg *schedv1alpha1.PodGroup) unitutil.GangFailurePolicy {
+	policy, err := unitutil.GetGangFailurePolicy(pg)
+	if err != nil {
+		klog.InfoS("Invalid gang failure policy, fall back to the default one", "podGroupKey", unitutil.GetPodGroupKey(pg), "err", err)
+	}
+	return policy
+}
+
+func gangBackoffLimit(pg *schedv1alpha1.PodGroup) int32 {
+	limit, err := unitutil.GetGangBackoffLimit(pg)
+	if err != nil {
+		klog.InfoS("Invalid gang backoff limit, fall back to the default one", "podGroupKey", unitutil.GetPodGroupKey(pg), "err", err)
+	}
+	return limit
+}
+
+// gangFailed reports whether the PodGroup has been failed by its failure policy. Unlike the Failed phase
+// written by the legacy controllers, it is a final state.
+func gangFailed(pg *schedv1alpha1.PodGroup) bool {
+	if pg.Status.Phase != schedv1alpha1.PodGroupFailed {
+		return false
+	}
+	policy := gangFailurePolicy(pg)
+	return policy == unitutil.GangFailureRestartGang || policy == unitutil.GangFailureDeleteGang
+}
+
+// gangPolicyApplies reports whether the PodGroup in a final state still needs to be handled by
+// its timeout or failure policy.
+func gangPolicyApplies(pg *schedv1alpha1.PodGroup) bool {
+	switch pg.Status.Phase {
+	case schedv1alpha1.PodGroupTimeout:
+		return gangTimeoutPolicy(pg) == unitutil.GangTimeoutReleaseBound
+	case schedv1alpha1.PodGroupScheduled:
+		return gangFailurePolicy(pg) != unitutil.GangFailureIgnore
+	case schedv1alpha1.PodGroupFailed:
+		return gangFailed(pg)
+	}
+	return false
+}
+
+// activeMembers filters out the members that are being deleted, which have left the gang already.
+func activeMembers(pods []*v1.Pod) []*v1.Pod {
+	active := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			active = append(active, pod)
+		}
+	}
+	return active
+}
+
+func podFinished(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+// applyGangPolicy enforces the timeout or failure policy of the PodGroup in a final state,
+// returns whether the PodGroup should be re-enqueued.
+func (ctrl *PodGroupController) applyGangPolicy(pg *schedv1alpha1.PodGroup) bool {
+	key := unitutil.GetPodGroupKey(pg)
+	pods, err := GetAllPods(ctrl.podLister, pg.Namespace, pg.Name)
+	if err != nil {
+		klog.InfoS("Failed to list pods for podGroup", "podGroupKey", key, "err", err)
+		return true
+	}
+	pods = activeMembers(pods)
+
+	switch pg.Status.Phase {
+	case schedv1alpha1.PodGroupTimeout:
+		var bound []*v1.Pod
+		for _, pod := range pods {
+			if podutil.BoundPod(pod) && !podFinished(pod) {
+				bound = append(bound, pod)
+			}
+		}
+		return ctrl.deleteMembers(pg, bound, releaseBoundAction, "PodGroup timed out")
+	case schedv1alpha1.PodGroupScheduled:
+		var failed []string
+		for _, pod := range pods {
+			if pod.Status.Phase == v1.PodFailed {
+				failed = append(failed, pod.Name)
+			}
+		}
+		if len(failed) == 0 {
+			return false
+		}
+		reason := fmt.Sprintf("members %v failed", failed)
+		if gangFailurePolicy(pg) == unitutil.GangFailureRestartGang {
+			restarts, limit := unitutil.GetGangRestartCount(pg), gangBackoffLimit(pg)
+			if restarts < limit {
+				return ctrl.restartGang(pg, pods, restarts+1, limit, reason)
+			}
+			reason = fmt.Sprintf("%s and backoff limit %d was reached", reason, limit)
+		}
+		return ctrl.failGang(pg, pods, reason)
+	case schedv1alpha1.PodGroupFailed:
+		// The members may be left over if the deletion failed or they were recreated afterwards.
+		return ctrl.deleteMembers(pg, pods, deleteGangAction, "PodGroup failed")
+	}
+	return false
+}
+
+// restartGang moves the PodGroup back to Pending and deletes all members, so that the gang is
+// scheduled again once the members are recreated by their owner.
+func (ctrl *PodGroupController) restartGang(pg *schedv1alpha1.PodGroup, pods []*v1.Pod, restarts, limit int32, reason string) bool {
+	pgCopy := pg.DeepCopy()
+	if pgCopy.Annotations == nil {
+		pgCopy.Annotations = make(map[string]string)
+	}
+	pgCopy.Annotations[unitutil.GangRestartCountAnnotationKey] = strconv.Itoa(int(restarts))
+	pgCopy.Annotations[unitutil.GangRestartTimestampAnnotationKey] = time.Now().Format(time.RFC3339)
+	// Release the final operation lock so that the restarted gang could be scheduled or timed out again.
+	delete(pgCopy.Annotations, unitutil.PodGroupFinalOpLock)
+	pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
+	pgCopy.Status.ScheduleStartTime = nil
+	updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupPending, fmt.Sprintf("Restart gang (%d/%d) since %s", restarts, limit, reason))
+
+	if _, err := ctrl.updatePodGroup(pg, pgCopy, reason); err != nil {
+		metrics.GangFailurePolicyActionsInc(restartGangAction, metrics.FailureResult)
+		return true
+	}
+	return ctrl.deleteMembers(pg, pods, restartGangAction, fmt.Sprintf("gang restarted (%d/%d) since %s", restarts, limit, reason))
+}
+
+// failGang moves the PodGroup to Failed, which is a final state, and deletes all members.
+func (ctrl *PodGroupController) failGang(pg *schedv1alpha1.PodGroup, pods []*v1.Pod, reason string) bool {
+	pgCopy := pg.DeepCopy()
+	pgCopy.Status.Phase = schedv1alpha1.PodGroupFailed
+	updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupFailed, fmt.Sprintf("Delete gang since %s", reason))
+
+	if _, err := ctrl.updatePodGroup(pg, pgCopy, reason); err != nil {
+		metrics.GangFailurePolicyActionsInc(deleteGangAction, metrics.FailureResult)
+		return true
+	}
+	return ctrl.deleteMembers(pg, pods, deleteGangAction, fmt.Sprintf("gang failed since %s", reason))
+}
+
+// deleteMembers deletes the given members of the PodGroup, returns whether the PodGroup should be re-enqueued.
+func (ctrl *PodGroupController) deleteMembers(pg *schedv1alpha1.PodGroup, pods []*v1.Pod, action, reason string) bool {
+	if len(pods) == 0 {
+		return false
+	}
+	key := unitutil.GetPodGroupKey(pg)
+	var deleted, failed int
+	for _, pod := range pods {
+		if err := util.DeletePod(ctrl.client, pod); err != nil && !apierrs.IsNotFound(err) {
+			klog.InfoS("Failed to delete PodGroup member", "podGroupKey", key, "pod", podutil.GetPodKey(pod), "action", action, "err", err)
+			failed++
+			continue
+		}
+		deleted++
+		metrics.GangFailurePolicyDeletedPodsInc(action)
+	}
+	klog.V(4).InfoS("Deleted PodGroup members", "podGroupKey", key, "action", action, "reason", reason, "deleted", deleted, "failed", failed)
+	if ctrl.eventRecorder != nil {
+		ctrl.eventRecorder.Eventf(pg, v1.EventTypeWarning, EventReason, "Deleted %d members since %s", deleted, reason)
+	}
+	if failed > 0 {
+		metrics.GangFailurePolicyActionsInc(action, metrics.FailureResult)
+		return true
+	}
+	metrics.GangFailurePolicyActionsInc(action, metrics.SuccessResult)
+	return false
+}
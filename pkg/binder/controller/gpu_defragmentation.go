@@ -0,0 +1,542 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/preempting/pdbchecker"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	GPUDefragmentationEventReason = "GPUDefragmentation"
+
+	// gpuDefragmentationReservationTTLSeconds is how long the resources on the target nodes are reserved
+	// for the migrated pods to be recreated and scheduled.
+	gpuDefragmentationReservationTTLSeconds int64 = 300
+)
+
+// GPUDefragmentationCache is the subset of the binder cache used by GPUDefragmentationController.
+type GPUDefragmentationCache interface {
+	CloneNode(nodename string) (framework.NodeInfo, error)
+	GetPDBItems() []framework.PDBItem
+	IsPodMarkedToDelete(pod *v1.Pod) (bool, error)
+}
+
+// GPUDefragmentationController periodically looks for nodes whose GPUs are partially allocated,
+// and migrates low-priority restartable pods away from some of them so that their GPUs are
+// fully released for whole-GPU and multi-GPU workloads.
+// Pods are migrated by evicting them after reserving their resources on the target nodes for their
+// controllers, the controllers are expected to recreate them and the scheduler will place them onto
+// the reserved nodes.
+type GPUDefragmentationController struct {
+	client        kubernetes.Interface
+	cache         GPUDefragmentationCache
+	nodeLister    corelister.NodeLister
+	eventRecorder record.EventRecorder
+	args          *config.GPUDefragmentationConfiguration
+
+	// limiter admits the migrations of a node as a whole, since migrating part of them doesn't release
+	// the GPUs of the node.
+	limiter *evictionRateLimiter
+	now     func() time.Time
+}
+
+// SetupGPUDefragmentationController creates a GPUDefragmentationController and runs it until ctx is done.
+func SetupGPUDefragmentationController(
+	ctx context.Context,
+	client kubernetes.Interface,
+	cache GPUDefragmentationCache,
+	nodeLister corelister.NodeLister,
+	args *config.GPUDefragmentationConfiguration,
+) {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
+
+	ctrl := NewGPUDefragmentationController(client, cache, nodeLister,
+		broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "GPUDefragmentationController"}), args)
+	go ctrl.Run(ctx.Done())
+}
+
+// NewGPUDefragmentationController returns a new *GPUDefragmentationController.
+func NewGPUDefragmentationController(
+	client kubernetes.Interface,
+	cache GPUDefragmentationCache,
+	nodeLister corelister.NodeLister,
+	eventRecorder record.EventRecorder,
+	args *config.GPUDefragmentationConfiguration,
+) *GPUDefragmentationController {
+	return &GPUDefragmentationController{
+		client:        client,
+		cache:         cache,
+		nodeLister:    nodeLister,
+		eventRecorder: eventRecorder,
+		args:          args,
+		limiter:       newEvictionRateLimiter(args.MaxMigrationsPerMinute, time.Now),
+		now:           time.Now,
+	}
+}
+
+// Run starts the defragmentation loop.
+func (ctrl *GPUDefragmentationController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting GPU defragmentation controller", "resourceName", ctrl.args.ResourceName, "dryRun", ctrl.args.DryRun)
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation) {
+		klog.InfoS("WARN: the reservations on the target nodes are not honored since the ResourceReservation feature is disabled, " +
+			"the migrated pods may be scheduled elsewhere")
+	}
+	defer klog.InfoS("Shutting GPU defragmentation controller")
+
+	wait.Until(ctrl.Defragment, time.Duration(ctrl.args.SyncPeriodSeconds)*time.Second, stopCh)
+}
+
+// Defragment runs one round of GPU defragmentation.
+func (ctrl *GPUDefragmentationController) Defragment() {
+	nodes, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list nodes for GPU defragmentation", "err", err)
+		return
+	}
+	// Plan on the clones, since the cache keeps being updated by the binder.
+	nodeInfos := make([]framework.NodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		nodeInfo, err := ctrl.cache.CloneNode(node.Name)
+		if err != nil || nodeInfo == nil || nodeInfo.GetNode() == nil {
+			continue
+		}
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+
+	planner := newDefragmentationPlanner(ctrl.args, ctrl.cache.GetPDBItems(), ctrl.cache.IsPodMarkedToDelete, ctrl.now())
+	plan := planner.plan(nodeInfos)
+	metrics.SetGPUFragmentedNodes(plan.fragmentedNodes)
+	klog.V(4).InfoS("Planned GPU defragmentation", "fragmentedNodes", plan.fragmentedNodes, "releasedNodes", len(plan.releases))
+
+	for _, release := range plan.releases {
+		if ctrl.args.DryRun {
+			for _, m := range release.migrations {
+				klog.InfoS("DRY RUN: would migrate pod to release GPUs", "pod", podutil.GetPodKey(m.pod), "sourceNode", release.nodeName, "targetNode", m.targetNode)
+				metrics.GPUDefragmentationMigrationsInc(metrics.DryRunResult)
+			}
+			metrics.GPUDefragmentationReleasedNodesAdd(metrics.DryRunResult, 1)
+			continue
+		}
+		if !ctrl.limiter.allow(len(release.migrations)) {
+			klog.V(4).InfoS("GPU defragmentation is throttled", "node", release.nodeName, "migrations", len(release.migrations))
+			metrics.GPUDefragmentationReleasedNodesAdd(metrics.ThrottledResult, 1)
+			return
+		}
+		ctrl.release(release)
+	}
+}
+
+func (ctrl *GPUDefragmentationController) release(release *nodeRelease) {
+	// Reserve the targets first, so that the resources are not taken away by the other pods before
+	// the migrated pods are recreated.
+	if err := ctrl.reserveTargets(release); err != nil {
+		klog.InfoS("Failed to reserve the target nodes for GPU defragmentation, skipped releasing the node", "node", release.nodeName, "err", err)
+		metrics.GPUDefragmentationReleasedNodesAdd(metrics.FailureResult, 1)
+		return
+	}
+	failed := false
+	for _, m := range release.migrations {
+		pod := m.pod
+		if err := evictPod(ctrl.client, pod); err != nil {
+			klog.InfoS("Failed to migrate pod for GPU defragmentation", "pod", podutil.GetPodKey(pod), "sourceNode", release.nodeName, "err", err)
+			metrics.GPUDefragmentationMigrationsInc(metrics.FailureResult)
+			failed = true
+			continue
+		}
+		klog.V(4).InfoS("Migrated pod for GPU defragmentation", "pod", podutil.GetPodKey(pod), "sourceNode", release.nodeName, "targetNode", m.targetNode)
+		metrics.GPUDefragmentationMigrationsInc(metrics.SuccessResult)
+		if ctrl.eventRecorder != nil {
+			ctrl.eventRecorder.Eventf(pod, v1.EventTypeNormal, GPUDefragmentationEventReason,
+				"Migrated from node %v to release its GPUs, expected to be rescheduled to node %v", release.nodeName, m.targetNode)
+		}
+	}
+	if failed {
+		metrics.GPUDefragmentationReleasedNodesAdd(metrics.FailureResult, 1)
+	} else {
+		metrics.GPUDefragmentationReleasedNodesAdd(metrics.SuccessResult, 1)
+	}
+}
+
+// reserveTargets reserves the resources of the migrated pods on their target nodes, the reservations are
+// owned by the controllers of the pods so that the recreated pods are able to use them.
+func (ctrl *GPUDefragmentationController) reserveTargets(release *nodeRelease) error {
+	creationTime := metav1.NewTime(ctrl.now())
+	reservations := make(map[string]map[types.UID]*framework.ResourceReservation)
+	requests := make(map[*framework.ResourceReservation]*framework.Resource)
+	for _, m := range release.migrations {
+		controllerRef := metav1.GetControllerOf(m.pod)
+		if controllerRef == nil {
+			return fmt.Errorf("pod %v has no controller", podutil.GetPodKey(m.pod))
+		}
+		if reservations[m.targetNode] == nil {
+			reservations[m.targetNode] = make(map[types.UID]*framework.ResourceReservation)
+		}
+		r, ok := reservations[m.targetNode][controllerRef.UID]
+		if !ok {
+			r = &framework.ResourceReservation{
+				Name:         fmt.Sprintf("%s-%s-%s", GPUDefragmentationEventReason, release.nodeName, controllerRef.UID),
+				Owner:        m.pod.Namespace + "/" + controllerRef.Name,
+				OwnerUID:     controllerRef.UID,
+				CreationTime: creationTime,
+				TTLSeconds:   gpuDefragmentationReservationTTLSeconds,
+			}
+			reservations[m.targetNode][controllerRef.UID] = r
+			requests[r] = &framework.Resource{}
+		}
+		requests[r].AddResource(m.requests)
+	}
+
+	for nodeName, rs := range reservations {
+		var toReserve []*framework.ResourceReservation
+		for _, r := range rs {
+			r.Resources = nonZeroResourceList(requests[r].ResourceList())
+			toReserve = append(toReserve, r)
+		}
+		if err := ctrl.reserve(nodeName, toReserve); err != nil {
+			return fmt.Errorf("failed to reserve node %v: %v", nodeName, err)
+		}
+	}
+	return nil
+}
+
+// reserve adds the reservations to the annotation of the node, the expired ones are dropped in passing.
+func (ctrl *GPUDefragmentationController) reserve(nodeName string, reservations []*framework.ResourceReservation) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := ctrl.client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		existing, err := framework.ParseResourceReservations(node)
+		if err != nil {
+			return err
+		}
+		now := ctrl.now()
+		merged := append([]*framework.ResourceReservation{}, reservations...)
+		for _, r := range existing {
+			if !r.Expired(now) {
+				merged = append(merged, r)
+			}
+		}
+		value, err := framework.FormatResourceReservations(merged)
+		if err != nil {
+			return err
+		}
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[framework.ResourceReservationAnnotationKey] = value
+		_, err = ctrl.client.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func nonZeroResourceList(list v1.ResourceList) v1.ResourceList {
+	for name, quantity := range list {
+		if quantity.IsZero() {
+			delete(list, name)
+		}
+	}
+	return list
+}
+
+// podMigration describes moving a pod to a target node.
+type podMigration struct {
+	pod        *v1.Pod
+	requests   *framework.Resource
+	targetNode string
+}
+
+// nodeRelease contains all the migrations needed to fully release the GPUs of a node.
+type nodeRelease struct {
+	nodeName   string
+	migrations []podMigration
+}
+
+type defragmentationPlan struct {
+	fragmentedNodes int
+	releases        []*nodeRelease
+}
+
+// gpuNodeState is the mutable resource view of a node used during planning.
+type gpuNodeState struct {
+	name string
+
+	allocatableGPU int64
+	freeGPU        int64
+	freeMilliCPU   int64
+	freeMemory     int64
+	// freeNumaMilliCPU is the free cpu of each numa, only set for nodes reporting numa topology.
+	freeNumaMilliCPU map[int]int64
+
+	gpuPods []*framework.PodInfo
+	// pinned means the node has received planned migrations and can't be released anymore.
+	pinned bool
+	// released means all the gpu pods of the node are planned to be migrated.
+	released bool
+}
+
+type defragmentationPlanner struct {
+	args                *config.GPUDefragmentationConfiguration
+	resourceName        v1.ResourceName
+	pdbItems            []framework.PDBItem
+	pdbsAllowed         []int32
+	isPodMarkedToDelete func(pod *v1.Pod) (bool, error)
+	now                 time.Time
+}
+
+func newDefragmentationPlanner(
+	args *config.GPUDefragmentationConfiguration,
+	pdbItems []framework.PDBItem,
+	isPodMarkedToDelete func(pod *v1.Pod) (bool, error),
+	now time.Time,
+) *defragmentationPlanner {
+	pdbsAllowed := make([]int32, len(pdbItems))
+	for i, pdbItem := range pdbItems {
+		pdbsAllowed[i] = pdbItem.GetPDB().Status.DisruptionsAllowed
+	}
+	return &defragmentationPlanner{
+		args:                args,
+		resourceName:        v1.ResourceName(args.ResourceName),
+		pdbItems:            pdbItems,
+		pdbsAllowed:         pdbsAllowed,
+		isPodMarkedToDelete: isPodMarkedToDelete,
+		now:                 now,
+	}
+}
+
+// plan computes the nodes to release and the migrations needed for each of them.
+// Nodes with the fewest used GPUs are tried first so that each released node costs as few migrations
+// as possible, and pods are only moved onto other fragmented nodes so that no new fragment is created.
+func (p *defragmentationPlanner) plan(nodeInfos []framework.NodeInfo) *defragmentationPlan {
+	var fragmented []*gpuNodeState
+	for _, nodeInfo := range nodeInfos {
+		state := p.buildNodeState(nodeInfo)
+		if state == nil {
+			continue
+		}
+		if state.freeGPU > 0 && state.freeGPU < state.allocatableGPU {
+			fragmented = append(fragmented, state)
+		}
+	}
+	plan := &defragmentationPlan{fragmentedNodes: len(fragmented)}
+
+	sort.SliceStable(fragmented, func(i, j int) bool {
+		usedI, usedJ := fragmented[i].allocatableGPU-fragmented[i].freeGPU, fragmented[j].allocatableGPU-fragmented[j].freeGPU
+		if usedI != usedJ {
+			return usedI < usedJ
+		}
+		if len(fragmented[i].gpuPods) != len(fragmented[j].gpuPods) {
+			return len(fragmented[i].gpuPods) < len(fragmented[j].gpuPods)
+		}
+		return fragmented[i].name < fragmented[j].name
+	})
+
+	budget := int(p.args.MaxMigrationsPerRound)
+	for _, source := range fragmented {
+		if source.pinned || len(source.gpuPods) > budget {
+			continue
+		}
+		if release := p.tryRelease(source, fragmented); release != nil {
+			plan.releases = append(plan.releases, release)
+			budget -= len(release.migrations)
+		}
+	}
+	return plan
+}
+
+func (p *defragmentationPlanner) buildNodeState(nodeInfo framework.NodeInfo) *gpuNodeState {
+	node := nodeInfo.GetNode()
+	if node == nil || node.Spec.Unschedulable {
+		return nil
+	}
+	allocatable, requested := nodeInfo.GetGuaranteedAllocatable(), nodeInfo.GetGuaranteedRequested()
+	if allocatable == nil || allocatable.ScalarResources[p.resourceName] <= 0 {
+		return nil
+	}
+	state := &gpuNodeState{
+		name:           node.Name,
+		allocatableGPU: allocatable.ScalarResources[p.resourceName],
+		freeGPU:        allocatable.ScalarResources[p.resourceName],
+		freeMilliCPU:   allocatable.MilliCPU,
+		freeMemory:     allocatable.Memory,
+	}
+	if requested != nil {
+		state.freeGPU -= requested.ScalarResources[p.resourceName]
+		state.freeMilliCPU -= requested.MilliCPU
+		state.freeMemory -= requested.Memory
+	}
+	if numaStatus := nodeInfo.GetNumaTopologyStatus(); numaStatus != nil && numaStatus.GetNumaNum() > 0 {
+		state.freeNumaMilliCPU = make(map[int]int64)
+		for _, numa := range numaStatus.GetNumaList() {
+			free := numaStatus.GetFreeResourcesInNuma(numa)
+			state.freeNumaMilliCPU[numa] = free.Cpu().MilliValue()
+		}
+	}
+	for _, podInfo := range nodeInfo.GetPods() {
+		if podInfo.PodResourceType == podutil.GuaranteedPod && podInfo.Res.ScalarResources[p.resourceName] > 0 {
+			state.gpuPods = append(state.gpuPods, podInfo)
+		}
+	}
+	return state
+}
+
+// tryRelease tries to move all the gpu pods of source onto the other fragmented nodes.
+// The targets are only changed if all the pods could be moved.
+func (p *defragmentationPlanner) tryRelease(source *gpuNodeState, candidates []*gpuNodeState) *nodeRelease {
+	if len(source.gpuPods) == 0 {
+		return nil
+	}
+	pdbsAllowed := make([]int32, len(p.pdbsAllowed))
+	copy(pdbsAllowed, p.pdbsAllowed)
+	for _, podInfo := range source.gpuPods {
+		if !p.movable(podInfo) {
+			return nil
+		}
+		violating, indexes := pdbchecker.CheckPodDisruptionBudgetViolation(podInfo.Pod, pdbsAllowed, p.pdbItems)
+		if violating {
+			return nil
+		}
+		for _, i := range indexes {
+			pdbsAllowed[i]--
+		}
+	}
+
+	// First fit decreasing: place the largest pods first.
+	pods := make([]*framework.PodInfo, len(source.gpuPods))
+	copy(pods, source.gpuPods)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].Res.ScalarResources[p.resourceName] > pods[j].Res.ScalarResources[p.resourceName]
+	})
+
+	type placement struct {
+		podInfo *framework.PodInfo
+		target  *gpuNodeState
+		numa    int
+	}
+	var placements []placement
+	undo := func() {
+		for _, pl := range placements {
+			p.unassign(pl.podInfo, pl.target, pl.numa)
+		}
+	}
+	for _, podInfo := range pods {
+		var best *gpuNodeState
+		bestNuma := -1
+		for _, target := range candidates {
+			if target == source || target.released {
+				continue
+			}
+			numa, fit := p.fits(podInfo, target)
+			if !fit {
+				continue
+			}
+			// Best fit: prefer the target with the least free GPUs left.
+			if best == nil || target.freeGPU < best.freeGPU {
+				best, bestNuma = target, numa
+			}
+		}
+		if best == nil {
+			undo()
+			return nil
+		}
+		p.assign(podInfo, best, bestNuma)
+		placements = append(placements, placement{podInfo: podInfo, target: best, numa: bestNuma})
+	}
+
+	release := &nodeRelease{nodeName: source.name}
+	for _, pl := range placements {
+		pl.target.pinned = true
+		release.migrations = append(release.migrations, podMigration{pod: pl.podInfo.Pod, requests: &pl.podInfo.Res, targetNode: pl.target.name})
+	}
+	source.released = true
+	// The disruptions consumed by this release are taken into account by the following ones.
+	p.pdbsAllowed = pdbsAllowed
+	return release
+}
+
+// movable checks whether the pod is a low-priority restartable pod which can be migrated.
+func (p *defragmentationPlanner) movable(podInfo *framework.PodInfo) bool {
+	return restartable(podInfo, p.args.MaxVictimPriority, p.now, p.isPodMarkedToDelete)
+}
+
+// fits checks whether the pod fits the target node, it returns the numa the pod should be bound to
+// if the pod needs exclusive numa cpus, or -1 otherwise.
+func (p *defragmentationPlanner) fits(podInfo *framework.PodInfo, target *gpuNodeState) (int, bool) {
+	if target.freeGPU < podInfo.Res.ScalarResources[p.resourceName] ||
+		target.freeMilliCPU < podInfo.Res.MilliCPU ||
+		target.freeMemory < podInfo.Res.Memory {
+		return -1, false
+	}
+	if podInfo.IsSharedCores || target.freeNumaMilliCPU == nil {
+		return -1, true
+	}
+	// Keep the numa alignment of the pods which need exclusive numa cpus.
+	numas := make([]int, 0, len(target.freeNumaMilliCPU))
+	for numa := range target.freeNumaMilliCPU {
+		numas = append(numas, numa)
+	}
+	sort.Ints(numas)
+	for _, numa := range numas {
+		if target.freeNumaMilliCPU[numa] >= podInfo.Res.MilliCPU {
+			return numa, true
+		}
+	}
+	return -1, false
+}
+
+func (p *defragmentationPlanner) assign(podInfo *framework.PodInfo, target *gpuNodeState, numa int) {
+	target.freeGPU -= podInfo.Res.ScalarResources[p.resourceName]
+	target.freeMilliCPU -= podInfo.Res.MilliCPU
+	target.freeMemory -= podInfo.Res.Memory
+	if numa >= 0 {
+		target.freeNumaMilliCPU[numa] -= podInfo.Res.MilliCPU
+	}
+}
+
+func (p *defragmentationPlanner) unassign(podInfo *framework.PodInfo, target *gpuNodeState, numa int) {
+	target.freeGPU += podInfo.Res.ScalarResources[p.resourceName]
+	target.freeMilliCPU += podInfo.Res.MilliCPU
+	target.freeMemory += podInfo.Res.Memory
+	if numa >= 0 {
+		target.freeNumaMilliCPU[numa] += podInfo.Res.MilliCPU
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	pdbstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pdb_store"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeGPUNodeInfo(name string, gpus string, pods ...*v1.Pod) framework.NodeInfo {
+	nodeInfo := framework.NewNodeInfo(pods...)
+	nodeInfo.SetNode(testinghelper.MakeNode().Name(name).Capacity(map[v1.ResourceName]string{
+		v1.ResourceCPU:    "64",
+		v1.ResourceMemory: "256Gi",
+		util.ResourceGPU:  gpus,
+	}).Obj())
+	return nodeInfo
+}
+
+func makePDBItem(pdb *policy.PodDisruptionBudget) framework.PDBItem {
+	item := pdbstore.NewPDBItemImpl(pdb)
+	selector, _ := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	item.SetPDBSelector(selector)
+	return item
+}
+
+func makeGPUPod(name, node string, gpus string, priority int32) *testinghelper.PodWrapper {
+	return testinghelper.MakePod().Namespace("default").Name(name).UID(name).Node(node).
+		Priority(priority).PriorityClassName("low").
+		ControllerRef(metav1.OwnerReference{Kind: util.OwnerTypeReplicaSet, Name: "rs", UID: "rs", APIVersion: "apps/v1", Controller: pointer.Bool(true)}).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "1Gi", util.ResourceGPU: gpus})
+}
+
+func TestGPUDefragmentationPlan(t *testing.T) {
+	args := &config.GPUDefragmentationConfiguration{
+		Enable:                 true,
+		ResourceName:           string(util.ResourceGPU),
+		MaxVictimPriority:      100,
+		MaxMigrationsPerRound:  10,
+		MaxMigrationsPerMinute: 10,
+	}
+
+	tests := []struct {
+		name                  string
+		nodeInfos             []framework.NodeInfo
+		pdbItems              []framework.PDBItem
+		maxMigrationsPerRound int32
+		expectedFragmented    int
+		expectedMigrations    map[string]map[string]string
+	}{
+		{
+			name: "consolidate single gpu pods into the busiest node",
+			nodeInfos: []framework.NodeInfo{
+				makeGPUNodeInfo("n1", "8", makeGPUPod("p1", "n1", "1", 10).Obj()),
+				makeGPUNodeInfo("n2", "8", makeGPUPod("p2", "n2", "6", 10).Obj()),
+				makeGPUNodeInfo("n3", "8"),
+			},
+			expectedFragmented: 2,
+			expectedMigrations: map[string]map[string]string{
+				"n1": {"p1": "n2"},
+			},
+		},
+		{
+			name: "high priority pods are not migrated",
+			nodeInfos: []framework.NodeInfo{
+				makeGPUNodeInfo("n1", "8", makeGPUPod("p1", "n1", "1", 1000).Obj()),
+				makeGPUNodeInfo("n2", "8", makeGPUPod("p2", "n2", "6", 10).Obj()),
+			},
+			expectedFragmented: 2,
+			expectedMigrations: map[string]map[string]string{
+				"n2": {"p2": "n1"},
+			},
+		},
+		{
+			name: "bare and gang pods are not migrated",
+			nodeInfos: []framework.NodeInfo{
+				makeGPUNodeInfo("n1", "8", testinghelper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").
+					Priority(10).PriorityClassName("low").
+					Req(map[v1.ResourceName]string{util.ResourceGPU: "1"}).Obj()),
+				makeGPUNodeInfo("n2", "8", makeGPUPod("p2", "n2", "2", 10).Annotation(podutil.PodGroupNameAnnotationKey, "pg").Obj()),
+			},
+			expectedFragmented: 2,
+			expectedMigrations: map[string]map[string]string{},
+		},
+		{
+			name: "node is not released if not all pods fit",
+			nodeInfos: []framework.NodeInfo{
+				makeGPUNodeInfo("n1", "8", makeGPUPod("p1", "n1", "1", 10).Obj(), makeGPUPod("p2", "n1", "4", 10).Obj()),
+				makeGPUNodeInfo("n2", "8", makeGPUPod("p3", "n2", "6", 10).Obj()),
+			},
+			expectedFragmented: 2,
+			expectedMigrations: map[string]map[string]string{},
+		},
+		{
+			name: "pdb violation prevents migration",
+			nodeInfos: []framework.NodeInfo{
+				makeGPUNodeInfo("n1", "8", makeGPUPod("p1", "n1", "1", 10).Label("app", "a").Obj()),
+				makeGPUNodeInfo("n2", "8", makeGPUPod("p2", "n2", "6", 10).Label("app", "a").Obj()),
+			},
+			pdbItems: []framework.PDBItem{makePDBItem(testinghelper.MakePdb().Namespace("default").Name("pdb").
+				Label("app", "a").DisruptionsAllowed(0).Obj())},
+			expectedFragmented: 2,
+			expectedMigrations: map[string]map[string]string{},
+		},
+		{
+			name: "migrations are capped per round",
+			nodeInfos: []framework.NodeInfo{
+				makeGPUNodeInfo("n1", "8", makeGPUPod("p1", "n1", "1", 10).Obj(), makeGPUPod("p2", "n1", "1", 10).Obj()),
+				makeGPUNodeInfo("n2", "8", makeGPUPod("p3", "n2", "4", 10).Obj()),
+			},
+			maxMigrationsPerRound: 1,
+			expectedFragmented:    2,
+			expectedMigrations: map[string]map[string]string{
+				"n2": {"p3": "n1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := *args
+			if tt.maxMigrationsPerRound > 0 {
+				a.MaxMigrationsPerRound = tt.maxMigrationsPerRound
+			}
+			planner := newDefragmentationPlanner(&a, tt.pdbItems, nil, time.Now())
+			plan := planner.plan(tt.nodeInfos)
+			if plan.fragmentedNodes != tt.expectedFragmented {
+				t.Errorf("expected %v fragmented nodes, got %v", tt.expectedFragmented, plan.fragmentedNodes)
+			}
+			got := map[string]map[string]string{}
+			for _, release := range plan.releases {
+				got[release.nodeName] = map[string]string{}
+				for _, m := range release.migrations {
+					got[release.nodeName][m.pod.Name] = m.targetNode
+				}
+			}
+			if !reflect.DeepEqual(tt.expectedMigrations, got) {
+				t.Errorf("expected migrations %v, got %v", tt.expectedMigrations, got)
+			}
+		})
+	}
+}
+
+func TestGPUDefragmentationRelease(t *testing.T) {
+	now := time.Now()
+	p1, p2 := makeGPUPod("p1", "n1", "1", 10).Obj(), makeGPUPod("p2", "n1", "2", 10).Obj()
+	existing := &framework.ResourceReservation{
+		Name: "existing", Owner: "default/pg", Resources: v1.ResourceList{util.ResourceGPU: resource.MustParse("1")},
+		CreationTime: metav1.NewTime(now), TTLSeconds: 60,
+	}
+	expired := &framework.ResourceReservation{
+		Name: "expired", Owner: "default/pg", Resources: v1.ResourceList{util.ResourceGPU: resource.MustParse("1")},
+		CreationTime: metav1.NewTime(now.Add(-time.Hour)), TTLSeconds: 60,
+	}
+	value, err := framework.FormatResourceReservations([]*framework.ResourceReservation{existing, expired})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n2 := testinghelper.MakeNode().Name("n2").Obj()
+	n2.Annotations = map[string]string{framework.ResourceReservationAnnotationKey: value}
+	client := newEvictingClientset(p1, p2, n2)
+	ctrl := &GPUDefragmentationController{
+		client: client,
+		args:   &config.GPUDefragmentationConfiguration{MaxMigrationsPerMinute: 10},
+		now:    func() time.Time { return now },
+	}
+
+	res1 := framework.NewResource(p1.Spec.Containers[0].Resources.Requests)
+	res2 := framework.NewResource(p2.Spec.Containers[0].Resources.Requests)
+	ctrl.release(&nodeRelease{nodeName: "n1", migrations: []podMigration{
+		{pod: p1, requests: res1, targetNode: "n2"},
+		{pod: p2, requests: res2, targetNode: "n2"},
+	}})
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), "n2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations, err := framework.ParseResourceReservations(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reservations) != 2 || reservations[1].Name != "existing" {
+		t.Fatalf("expected the reservation of the migrated pods to be added and the expired one dropped, got %v", reservations)
+	}
+	r := reservations[0]
+	if r.OwnerUID != "rs" || r.Owner != "default/rs" || r.TTLSeconds != gpuDefragmentationReservationTTLSeconds {
+		t.Errorf("unexpected reservation of the migrated pods: %+v", r)
+	}
+	if gpu := r.Resources[util.ResourceGPU]; gpu.Value() != 3 {
+		t.Errorf("expected 3 gpus to be reserved, got %v", gpu.String())
+	}
+	for _, pod := range []*v1.Pod{p1, p2} {
+		if _, err := client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+			t.Errorf("expected pod %v to be migrated, got %v", pod.Name, err)
+		}
+	}
+}
+
+func TestGPUDefragmentationReleaseWithoutReservation(t *testing.T) {
+	p1 := makeGPUPod("p1", "n1", "1", 10).Obj()
+	client := newEvictingClientset(p1)
+	ctrl := &GPUDefragmentationController{
+		client: client,
+		args:   &config.GPUDefragmentationConfiguration{MaxMigrationsPerMinute: 10},
+		now:    time.Now,
+	}
+
+	// The target node doesn't exist, so the pod is not deleted.
+	ctrl.release(&nodeRelease{nodeName: "n1", migrations: []podMigration{
+		{pod: p1, requests: framework.NewResource(p1.Spec.Containers[0].Resources.Requests), targetNode: "n2"},
+	}})
+	if _, err := client.CoreV1().Pods(p1.Namespace).Get(context.TODO(), p1.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected pod p1 to be kept if the target can't be reserved, got %v", err)
+	}
+}
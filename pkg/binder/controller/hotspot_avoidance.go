@@ -0,0 +1,401 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/metrics"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/loadaware"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/preempting/pdbchecker"
+	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/loadaware/estimator"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const HotspotAvoidanceEventReason = "HotspotAvoidance"
+
+// HotspotAvoidanceCache is the subset of the binder cache used by HotspotAvoidanceController.
+type HotspotAvoidanceCache interface {
+	CloneNode(nodename string) (framework.NodeInfo, error)
+	GetPDBItems() []framework.PDBItem
+	IsPodMarkedToDelete(pod *v1.Pod) (bool, error)
+}
+
+// HotspotAvoidanceController periodically looks for the nodes whose best-effort usage reported in CNR
+// exceeds the LoadAware usage thresholds, and evicts best-effort pods from them until the estimated usage
+// falls below the thresholds.
+// Evicted pods are expected to be recreated by their owners, and the LoadAware Filter of the scheduler,
+// configured with the same thresholds, keeps them from landing back on the hot nodes.
+type HotspotAvoidanceController struct {
+	client        kubernetes.Interface
+	cache         HotspotAvoidanceCache
+	nodeLister    corelister.NodeLister
+	eventRecorder record.EventRecorder
+	args          *config.HotspotAvoidanceConfiguration
+
+	limiter *evictionRateLimiter
+	// evictedNodes records the metric update time of the nodes when pods were evicted from them,
+	// no more pods are evicted from a node until its metrics are refreshed.
+	evictedNodes map[string]metav1.Time
+	now          func() time.Time
+}
+
+// SetupHotspotAvoidanceController creates a HotspotAvoidanceController and runs it until ctx is done.
+func SetupHotspotAvoidanceController(
+	ctx context.Context,
+	client kubernetes.Interface,
+	cache HotspotAvoidanceCache,
+	nodeLister corelister.NodeLister,
+	args *config.HotspotAvoidanceConfiguration,
+) {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
+
+	ctrl := NewHotspotAvoidanceController(client, cache, nodeLister,
+		broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "HotspotAvoidanceController"}), args)
+	go ctrl.Run(ctx.Done())
+}
+
+// NewHotspotAvoidanceController returns a new *HotspotAvoidanceController.
+func NewHotspotAvoidanceController(
+	client kubernetes.Interface,
+	cache HotspotAvoidanceCache,
+	nodeLister corelister.NodeLister,
+	eventRecorder record.EventRecorder,
+	args *config.HotspotAvoidanceConfiguration,
+) *HotspotAvoidanceController {
+	return &HotspotAvoidanceController{
+		client:        client,
+		cache:         cache,
+		nodeLister:    nodeLister,
+		eventRecorder: eventRecorder,
+		args:          args,
+		limiter:       newEvictionRateLimiter(args.MaxEvictionsPerMinute, time.Now),
+		evictedNodes:  make(map[string]metav1.Time),
+		now:           time.Now,
+	}
+}
+
+// Run starts the hotspot avoidance loop.
+func (ctrl *HotspotAvoidanceController) Run(stopCh <-chan struct{}) {
+	klog.InfoS("Starting hotspot avoidance controller", "usageThresholds", ctrl.args.UsageThresholds, "dryRun", ctrl.args.DryRun)
+	defer klog.InfoS("Shutting hotspot avoidance controller")
+
+	wait.Until(ctrl.Avoid, time.Duration(ctrl.args.SyncPeriodSeconds)*time.Second, stopCh)
+}
+
+// Avoid runs one round of hotspot avoidance.
+func (ctrl *HotspotAvoidanceController) Avoid() {
+	nodes, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list nodes for hotspot avoidance", "err", err)
+		return
+	}
+	// Plan on the clones, since the cache keeps being updated by the binder.
+	nodeInfos := make([]framework.NodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		nodeInfo, err := ctrl.cache.CloneNode(node.Name)
+		if err != nil || nodeInfo == nil || nodeInfo.GetNode() == nil {
+			continue
+		}
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+
+	planner, err := newHotspotPlanner(ctrl.args, nodeInfos, ctrl.cache.GetPDBItems(), ctrl.cache.IsPodMarkedToDelete, ctrl.now())
+	if err != nil {
+		klog.InfoS("Failed to create hotspot planner", "err", err)
+		return
+	}
+	plan := planner.plan(nodeInfos, ctrl.evictedNodes)
+	metrics.SetHotspotNodes(plan.hotspotNodes)
+	klog.V(4).InfoS("Planned hotspot avoidance", "hotspotNodes", plan.hotspotNodes, "evictingNodes", len(plan.evictions))
+
+	for _, eviction := range plan.evictions {
+		if ctrl.args.DryRun {
+			for _, pod := range eviction.pods {
+				klog.InfoS("DRY RUN: would evict pod from hotspot node", "pod", podutil.GetPodKey(pod), "node", eviction.nodeName)
+				metrics.HotspotAvoidanceEvictionsInc(metrics.DryRunResult)
+			}
+			continue
+		}
+		for _, pod := range eviction.pods {
+			if !ctrl.limiter.allow(1) {
+				klog.V(4).InfoS("Hotspot avoidance is throttled", "node", eviction.nodeName)
+				metrics.HotspotAvoidanceEvictionsInc(metrics.ThrottledResult)
+				return
+			}
+			if ctrl.evict(pod, eviction.nodeName) {
+				ctrl.evictedNodes[eviction.nodeName] = eviction.metricUpdateTime
+			}
+		}
+	}
+}
+
+// evict evicts the pod from the hotspot node and returns whether the eviction succeeded.
+func (ctrl *HotspotAvoidanceController) evict(pod *v1.Pod, nodeName string) bool {
+	if err := evictPod(ctrl.client, pod); err != nil {
+		klog.InfoS("Failed to evict pod from hotspot node", "pod", podutil.GetPodKey(pod), "node", nodeName, "err", err)
+		metrics.HotspotAvoidanceEvictionsInc(metrics.FailureResult)
+		return false
+	}
+	klog.V(4).InfoS("Evicted pod from hotspot node", "pod", podutil.GetPodKey(pod), "node", nodeName)
+	metrics.HotspotAvoidanceEvictionsInc(metrics.SuccessResult)
+	if ctrl.eventRecorder != nil {
+		ctrl.eventRecorder.Eventf(pod, v1.EventTypeNormal, HotspotAvoidanceEventReason,
+			"Evicted from node %v whose usage exceeds the thresholds", nodeName)
+	}
+	return true
+}
+
+// nodeEviction contains the pods to evict from a hotspot node.
+type nodeEviction struct {
+	nodeName         string
+	metricUpdateTime metav1.Time
+	pods             []*v1.Pod
+}
+
+type hotspotPlan struct {
+	hotspotNodes int
+	evictions    []*nodeEviction
+}
+
+type hotspotPlanner struct {
+	args                *config.HotspotAvoidanceConfiguration
+	estimator           estimator.Estimator
+	pdbItems            []framework.PDBItem
+	pdbsAllowed         []int32
+	isPodMarkedToDelete func(pod *v1.Pod) (bool, error)
+	now                 time.Time
+}
+
+func newHotspotPlanner(
+	args *config.HotspotAvoidanceConfiguration,
+	nodeInfos []framework.NodeInfo,
+	pdbItems []framework.PDBItem,
+	isPodMarkedToDelete func(pod *v1.Pod) (bool, error),
+	now time.Time,
+) (*hotspotPlanner, error) {
+	// Estimate the usage of pods the same way as the LoadAware plugin of the schedulers.
+	podEstimator, err := estimator.NewEstimator(&schedulerconfig.LoadAwareArgs{
+		Resources: []schedulerconfig.ResourceSpec{
+			{Name: string(v1.ResourceCPU), Weight: 1, ResourceType: podutil.BestEffortPod},
+			{Name: string(v1.ResourceMemory), Weight: 1, ResourceType: podutil.BestEffortPod},
+		},
+		Estimator:                   args.Estimator,
+		NodeMetricExpirationSeconds: args.NodeMetricExpirationSeconds,
+		UsageThresholds:             args.UsageThresholds,
+		EstimatedScalingFactors:     args.EstimatedScalingFactors,
+		HistoricalUsagePercentile:   args.HistoricalUsagePercentile,
+	}, newHotspotEstimatorHandle(nodeInfos))
+	if err != nil {
+		return nil, err
+	}
+	pdbsAllowed := make([]int32, len(pdbItems))
+	for i, pdbItem := range pdbItems {
+		pdbsAllowed[i] = pdbItem.GetPDB().Status.DisruptionsAllowed
+	}
+	return &hotspotPlanner{
+		args:                args,
+		estimator:           podEstimator,
+		pdbItems:            pdbItems,
+		pdbsAllowed:         pdbsAllowed,
+		isPodMarkedToDelete: isPodMarkedToDelete,
+		now:                 now,
+	}, nil
+}
+
+// plan computes the pods to evict from each hotspot node. Nodes whose metrics haven't been refreshed
+// since the last eviction are skipped, so that the effect of the last eviction is observed first.
+func (p *hotspotPlanner) plan(nodeInfos []framework.NodeInfo, evictedNodes map[string]metav1.Time) *hotspotPlan {
+	plan := &hotspotPlan{}
+	budget := int(p.args.MaxEvictionsPerRound)
+	for _, nodeInfo := range nodeInfos {
+		nodeMetricInfo := loadaware.GetNodeMetricInfo(nodeInfo.GetCNR(), podutil.BestEffortPod)
+		if nodeMetricInfo == nil {
+			continue
+		}
+		// Don't evict pods based on stale metrics.
+		if p.now.Sub(nodeMetricInfo.UpdateTime.Time) > time.Duration(p.args.NodeMetricExpirationSeconds)*time.Second {
+			continue
+		}
+		excess := loadaware.ExcessUsage(nodeMetricInfo, loadaware.GetAllocatable(nodeInfo, podutil.BestEffortPod), p.args.UsageThresholds)
+		if len(excess) == 0 {
+			continue
+		}
+		plan.hotspotNodes++
+
+		if budget <= 0 {
+			continue
+		}
+		if updateTime, ok := evictedNodes[nodeInfo.GetNodeName()]; ok {
+			if updateTime.Equal(&nodeMetricInfo.UpdateTime) {
+				continue
+			}
+			delete(evictedNodes, nodeInfo.GetNodeName())
+		}
+		pods := p.selectVictims(nodeInfo, excess, budget)
+		if len(pods) == 0 {
+			continue
+		}
+		plan.evictions = append(plan.evictions, &nodeEviction{
+			nodeName:         nodeInfo.GetNodeName(),
+			metricUpdateTime: nodeMetricInfo.UpdateTime,
+			pods:             pods,
+		})
+		budget -= len(pods)
+	}
+	return plan
+}
+
+// selectVictims picks the best-effort pods to evict from the node until the excess usage is covered.
+// Pods with lower priority are evicted first, and bigger pods are preferred among the same priority
+// so that as few pods as possible are evicted. The usage of pods is estimated by the LoadAware estimator.
+func (p *hotspotPlanner) selectVictims(nodeInfo framework.NodeInfo, excess map[v1.ResourceName]int64, budget int) []*v1.Pod {
+	var candidates []*hotspotCandidate
+	for _, podInfo := range nodeInfo.GetPods() {
+		if podInfo.PodResourceType != podutil.BestEffortPod || !p.evictable(podInfo) {
+			continue
+		}
+		usage, err := p.estimator.EstimatePod(podInfo.Pod)
+		if err != nil {
+			klog.InfoS("Failed to estimate the usage of pod", "pod", podutil.GetPodKey(podInfo.Pod), "err", err)
+			continue
+		}
+		candidates = append(candidates, &hotspotCandidate{podInfo: podInfo, usage: usage})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].podInfo.PodPriority != candidates[j].podInfo.PodPriority {
+			return candidates[i].podInfo.PodPriority < candidates[j].podInfo.PodPriority
+		}
+		if candidates[i].usage.MilliCPU != candidates[j].usage.MilliCPU {
+			return candidates[i].usage.MilliCPU > candidates[j].usage.MilliCPU
+		}
+		return candidates[i].usage.Memory > candidates[j].usage.Memory
+	})
+
+	limit := int(p.args.MaxEvictionsPerNode)
+	if limit > budget {
+		limit = budget
+	}
+	var victims []*v1.Pod
+	for _, candidate := range candidates {
+		if len(victims) >= limit || !exceeded(excess) {
+			break
+		}
+		violating, indexes := pdbchecker.CheckPodDisruptionBudgetViolation(candidate.podInfo.Pod, p.pdbsAllowed, p.pdbItems)
+		if violating {
+			continue
+		}
+		for _, i := range indexes {
+			p.pdbsAllowed[i]--
+		}
+		excess[v1.ResourceCPU] -= candidate.usage.MilliCPU
+		excess[v1.ResourceMemory] -= candidate.usage.Memory
+		victims = append(victims, candidate.podInfo.Pod)
+	}
+	return victims
+}
+
+// hotspotCandidate is a best-effort pod which can be evicted, along with its estimated usage.
+type hotspotCandidate struct {
+	podInfo *framework.PodInfo
+	usage   *framework.Resource
+}
+
+// evictable checks whether the pod is a low-priority restartable pod which can be evicted.
+func (p *hotspotPlanner) evictable(podInfo *framework.PodInfo) bool {
+	return restartable(podInfo, p.args.MaxVictimPriority, p.now, p.isPodMarkedToDelete)
+}
+
+// exceeded checks whether there is still any excess usage to cover.
+func exceeded(excess map[v1.ResourceName]int64) bool {
+	for _, v := range excess {
+		if v > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hotspotEstimatorHandle provides the load aware information to the estimator from the CNRs of the nodes.
+type hotspotEstimatorHandle struct {
+	nodeInfos map[string]framework.NodeInfo
+	// workloadProfiles are parsed on demand, since only historicalUsageEstimator needs them.
+	workloadProfiles map[string]loadaware.WorkloadUsageProfiles
+}
+
+var _ estimator.Handle = &hotspotEstimatorHandle{}
+
+func newHotspotEstimatorHandle(nodeInfos []framework.NodeInfo) *hotspotEstimatorHandle {
+	h := &hotspotEstimatorHandle{nodeInfos: make(map[string]framework.NodeInfo, len(nodeInfos))}
+	for _, nodeInfo := range nodeInfos {
+		h.nodeInfos[nodeInfo.GetNodeName()] = nodeInfo
+	}
+	return h
+}
+
+func (h *hotspotEstimatorHandle) GetLoadAwareNodeMetricInfo(nodeName string, resourceType podutil.PodResourceType) *framework.LoadAwareNodeMetricInfo {
+	nodeInfo := h.nodeInfos[nodeName]
+	if nodeInfo == nil {
+		return nil
+	}
+	return loadaware.GetNodeMetricInfo(nodeInfo.GetCNR(), resourceType)
+}
+
+// GetLoadAwareNodeUsage only returns the usage reported in CNR, the requests of the pods which are not
+// covered by the node metrics yet are not tracked by the binder.
+func (h *hotspotEstimatorHandle) GetLoadAwareNodeUsage(nodeName string, resourceType podutil.PodResourceType) *framework.LoadAwareNodeUsage {
+	nodeMetricInfo := h.GetLoadAwareNodeMetricInfo(nodeName, resourceType)
+	if nodeMetricInfo == nil {
+		return &framework.LoadAwareNodeUsage{}
+	}
+	return &framework.LoadAwareNodeUsage{
+		ProfileMilliCPU: nodeMetricInfo.ProfileMilliCPUUsage,
+		ProfileMEM:      nodeMetricInfo.ProfileMEMUsage,
+	}
+}
+
+func (h *hotspotEstimatorHandle) GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *framework.LoadAwareWorkloadUsage {
+	if h.workloadProfiles == nil {
+		h.workloadProfiles = make(map[string]loadaware.WorkloadUsageProfiles)
+		for nodeName, nodeInfo := range h.nodeInfos {
+			for key, profile := range loadaware.ParseWorkloadUsageProfiles(nodeInfo.GetCNR()) {
+				if h.workloadProfiles[key] == nil {
+					h.workloadProfiles[key] = make(loadaware.WorkloadUsageProfiles)
+				}
+				h.workloadProfiles[key][nodeName] = profile
+			}
+		}
+	}
+	return h.workloadProfiles[workloadKey].Usage(percentile)
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corelister "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/loadaware/estimator"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeHotspotNodeInfo(name string, cpuUsage string, updateTime time.Time, pods ...*v1.Pod) framework.NodeInfo {
+	nodeInfo := framework.NewNodeInfo(pods...)
+	nodeInfo.SetNode(testinghelper.MakeNode().Name(name).Capacity(map[v1.ResourceName]string{
+		v1.ResourceCPU:    "64",
+		v1.ResourceMemory: "256Gi",
+	}).Obj())
+	usage := resource.MustParse(cpuUsage)
+	nodeInfo.SetCNR(&katalystv1alpha1.CustomNodeResource{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: katalystv1alpha1.CustomNodeResourceStatus{
+			Resources: katalystv1alpha1.Resources{
+				Allocatable: &v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("10"),
+					v1.ResourceMemory: resource.MustParse("40Gi"),
+				},
+			},
+			NodeMetricStatus: &katalystv1alpha1.NodeMetricStatus{
+				UpdateTime: metav1.NewTime(updateTime),
+				GroupMetric: []katalystv1alpha1.GroupMetricInfo{
+					{
+						QoSLevel: string(util.ReclaimedCores),
+						ResourceUsage: katalystv1alpha1.ResourceUsage{
+							GenericUsage: &katalystv1alpha1.ResourceMetric{CPU: &usage},
+						},
+					},
+				},
+			},
+		},
+	})
+	return nodeInfo
+}
+
+func makeBEPod(name, node string, cpu string, priority int32) *testinghelper.PodWrapper {
+	return testinghelper.MakePod().Namespace("default").Name(name).UID(name).Node(node).
+		Priority(priority).PriorityClassName("low").
+		Annotation(util.QoSLevelKey, string(util.ReclaimedCores)).
+		ControllerRef(metav1.OwnerReference{Kind: util.OwnerTypeReplicaSet, Name: "rs", UID: "rs", APIVersion: "apps/v1", Controller: pointer.Bool(true)}).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu, v1.ResourceMemory: "1Gi"})
+}
+
+// withWorkloadUsageProfiles sets the workload usage profiles reported by the node.
+func withWorkloadUsageProfiles(nodeInfo framework.NodeInfo, profiles string) framework.NodeInfo {
+	nodeInfo.GetCNR().Annotations = map[string]string{util.WorkloadUsageProfileAnnotationKey: profiles}
+	return nodeInfo
+}
+
+// withOwner changes the ReplicaSet owning the pod.
+func withOwner(pod *v1.Pod, name string) *v1.Pod {
+	pod.OwnerReferences[0].Name, pod.OwnerReferences[0].UID = name, types.UID(name)
+	return pod
+}
+
+func TestHotspotAvoidancePlan(t *testing.T) {
+	now := time.Now()
+	fresh := now.Add(-10 * time.Second)
+	args := &config.HotspotAvoidanceConfiguration{
+		Enable:                      true,
+		UsageThresholds:             map[v1.ResourceName]int64{v1.ResourceCPU: 80},
+		NodeMetricExpirationSeconds: 60,
+		MaxVictimPriority:           100,
+		MaxEvictionsPerNode:         5,
+		MaxEvictionsPerRound:        10,
+		MaxEvictionsPerMinute:       10,
+	}
+
+	tests := []struct {
+		name                string
+		nodeInfos           []framework.NodeInfo
+		pdbItems            []framework.PDBItem
+		evictedNodes        map[string]metav1.Time
+		maxEvictionsPerNode int32
+		estimator           string
+		expectedHotspots    int
+		expectedEvictions   map[string][]string
+	}{
+		{
+			name: "evict the lowest priority and biggest pods until the usage falls below the threshold",
+			nodeInfos: []framework.NodeInfo{
+				makeHotspotNodeInfo("n1", "9", fresh,
+					makeBEPod("p1", "n1", "500m", 10).Obj(),
+					makeBEPod("p2", "n1", "500m", 5).Obj(),
+					makeBEPod("p3", "n1", "1", 5).Obj()),
+				makeHotspotNodeInfo("n2", "5", fresh, makeBEPod("p4", "n2", "1", 5).Obj()),
+			},
+			expectedHotspots: 1,
+			expectedEvictions: map[string][]string{
+				"n1": {"p3"},
+			},
+		},
+		{
+			name: "pods are ranked and evicted by the estimated usage",
+			nodeInfos: []framework.NodeInfo{
+				withWorkloadUsageProfiles(makeHotspotNodeInfo("n1", "9", fresh,
+					withOwner(makeBEPod("p1", "n1", "500m", 5).Obj(), "busy"),
+					makeBEPod("p2", "n1", "1", 5).Obj(),
+					makeBEPod("p3", "n1", "800m", 5).Obj()),
+					`{"ReplicaSet/default/busy":{"95":{"cpu":"1200m"}},"ReplicaSet/default/rs":{"95":{"cpu":"200m"}}}`),
+			},
+			estimator:        estimator.HistoricalUsageEstimatorName,
+			expectedHotspots: 1,
+			expectedEvictions: map[string][]string{
+				"n1": {"p1"},
+			},
+		},
+		{
+			name: "expired metrics are ignored",
+			nodeInfos: []framework.NodeInfo{
+				makeHotspotNodeInfo("n1", "9", now.Add(-time.Hour), makeBEPod("p1", "n1", "1", 5).Obj()),
+			},
+			expectedHotspots:  0,
+			expectedEvictions: map[string][]string{},
+		},
+		{
+			name: "protected, guaranteed and high priority pods are not evicted",
+			nodeInfos: []framework.NodeInfo{
+				makeHotspotNodeInfo("n1", "9", fresh,
+					makeBEPod("p1", "n1", "500m", 10).Obj(),
+					makeBEPod("p2", "n1", "500m", 1000).Obj(),
+					makeBEPod("p3", "n1", "1", 5).Annotation(podutil.ProtectionDurationFromPreemptionKey, "3600").
+						StartTime(metav1.NewTime(now.Add(-time.Minute))).Obj(),
+					makeBEPod("p4", "n1", "1", 5).Annotation(util.QoSLevelKey, string(util.SharedCores)).Obj()),
+			},
+			expectedHotspots: 1,
+			expectedEvictions: map[string][]string{
+				"n1": {"p1"},
+			},
+		},
+		{
+			name: "pdb violation prevents eviction",
+			nodeInfos: []framework.NodeInfo{
+				makeHotspotNodeInfo("n1", "9", fresh,
+					makeBEPod("p1", "n1", "500m", 10).Obj(),
+					makeBEPod("p2", "n1", "1", 5).Label("app", "a").Obj()),
+			},
+			pdbItems: []framework.PDBItem{makePDBItem(testinghelper.MakePdb().Namespace("default").Name("pdb").
+				Label("app", "a").DisruptionsAllowed(0).Obj())},
+			expectedHotspots: 1,
+			expectedEvictions: map[string][]string{
+				"n1": {"p1"},
+			},
+		},
+		{
+			name: "evictions are capped per node",
+			nodeInfos: []framework.NodeInfo{
+				makeHotspotNodeInfo("n1", "9", fresh,
+					makeBEPod("p1", "n1", "500m", 10).Obj(),
+					makeBEPod("p2", "n1", "500m", 5).Obj()),
+			},
+			maxEvictionsPerNode: 1,
+			expectedHotspots:    1,
+			expectedEvictions: map[string][]string{
+				"n1": {"p2"},
+			},
+		},
+		{
+			name: "nodes are skipped until their metrics are refreshed",
+			nodeInfos: []framework.NodeInfo{
+				makeHotspotNodeInfo("n1", "9", fresh, makeBEPod("p1", "n1", "1", 5).Obj()),
+				makeHotspotNodeInfo("n2", "9", fresh, makeBEPod("p2", "n2", "1", 5).Obj()),
+			},
+			evictedNodes: map[string]metav1.Time{
+				"n1": metav1.NewTime(fresh),
+				"n2": metav1.NewTime(fresh.Add(-time.Minute)),
+			},
+			expectedHotspots: 2,
+			expectedEvictions: map[string][]string{
+				"n2": {"p2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := *args
+			if tt.maxEvictionsPerNode > 0 {
+				a.MaxEvictionsPerNode = tt.maxEvictionsPerNode
+			}
+			a.Estimator = tt.estimator
+			evictedNodes := tt.evictedNodes
+			if evictedNodes == nil {
+				evictedNodes = map[string]metav1.Time{}
+			}
+			planner, err := newHotspotPlanner(&a, tt.nodeInfos, tt.pdbItems, nil, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan := planner.plan(tt.nodeInfos, evictedNodes)
+			if plan.hotspotNodes != tt.expectedHotspots {
+				t.Errorf("expected %v hotspot nodes, got %v", tt.expectedHotspots, plan.hotspotNodes)
+			}
+			got := map[string][]string{}
+			for _, eviction := range plan.evictions {
+				for _, pod := range eviction.pods {
+					got[eviction.nodeName] = append(got[eviction.nodeName], pod.Name)
+				}
+			}
+			if !reflect.DeepEqual(tt.expectedEvictions, got) {
+				t.Errorf("expected evictions %v, got %v", tt.expectedEvictions, got)
+			}
+		})
+	}
+}
+
+type fakeHotspotAvoidanceCache struct {
+	nodeInfos map[string]framework.NodeInfo
+}
+
+func (c *fakeHotspotAvoidanceCache) CloneNode(nodeName string) (framework.NodeInfo, error) {
+	return c.nodeInfos[nodeName].Clone(), nil
+}
+
+func (c *fakeHotspotAvoidanceCache) GetPDBItems() []framework.PDBItem {
+	return nil
+}
+
+func (c *fakeHotspotAvoidanceCache) IsPodMarkedToDelete(_ *v1.Pod) (bool, error) {
+	return false, nil
+}
+
+func TestHotspotAvoidanceAvoid(t *testing.T) {
+	now := time.Now()
+	fresh := now.Add(-10 * time.Second)
+	p1, p2 := makeBEPod("p1", "n1", "1", 5).Obj(), makeBEPod("p2", "n2", "1", 5).Obj()
+	n1, n2 := makeHotspotNodeInfo("n1", "9", fresh, p1), makeHotspotNodeInfo("n2", "9", fresh, p2)
+
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, nodeInfo := range []framework.NodeInfo{n1, n2} {
+		nodeIndexer.Add(nodeInfo.GetNode())
+	}
+	client := newEvictingClientset(p1, p2)
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.(clienttesting.CreateAction).GetObject().(metav1.Object).GetName() == "p2" {
+			return true, nil, apierrors.NewTooManyRequests("disruption budget exceeded", 0)
+		}
+		return false, nil, nil
+	})
+	ctrl := NewHotspotAvoidanceController(client,
+		&fakeHotspotAvoidanceCache{nodeInfos: map[string]framework.NodeInfo{"n1": n1, "n2": n2}},
+		corelister.NewNodeLister(nodeIndexer), nil, &config.HotspotAvoidanceConfiguration{
+			UsageThresholds:             map[v1.ResourceName]int64{v1.ResourceCPU: 80},
+			NodeMetricExpirationSeconds: 60,
+			MaxVictimPriority:           100,
+			MaxEvictionsPerNode:         5,
+			MaxEvictionsPerRound:        10,
+			MaxEvictionsPerMinute:       10,
+		})
+	ctrl.now = func() time.Time { return now }
+
+	ctrl.Avoid()
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "p1", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected p1 to be evicted, got %v", err)
+	}
+	// Only the nodes whose pods have been evicted wait for their metrics to be refreshed.
+	expected := map[string]metav1.Time{"n1": metav1.NewTime(fresh)}
+	if !reflect.DeepEqual(expected, ctrl.evictedNodes) {
+		t.Errorf("expected evicted nodes %v, got %v", expected, ctrl.evictedNodes)
+	}
+}
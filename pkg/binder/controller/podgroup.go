@@ -69,6 +69,7 @@ const (
 // PodGroupController is a controller that process pod groups using provided Handler interface
 type PodGroupController struct {
 	eventRecorder   record.EventRecorder
+	client          kubernetes.Interface
 	pgQueue         workqueue.RateLimitingInterface
 	pgLister        pglister.PodGroupLister
 	podLister       corelister.PodLister
@@ -90,6 +91,7 @@ func SetupPodGroupController(
 
 	ctrl := &PodGroupController{
 		eventRecorder: broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "PodGroupController"}),
+		client:        client,
 		// TODO: default controller rate limiter is not that suitable for pod group queue
 		pgQueue: workqueue.NewNamedRateLimitingQueue(
 			workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 300*time.Second),
@@ -138,7 +140,7 @@ func (ctrl *PodGroupController) Run(workers int, stopCh <-chan struct{}) {
 }
 
 func (ctrl *PodGroupController) pgAdded(pg *schedv1alpha1.PodGroup, event string) {
-	if unitutil.PodGroupFinalState(pg.Status.Phase) {
+	if (unitutil.PodGroupFinalState(pg.Status.Phase) || gangFailed(pg)) && !gangPolicyApplies(pg) {
 		return
 	}
 	if key := unitutil.GetPodGroupKey(pg); len(key) > 0 {
@@ -237,7 +239,10 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 		return true
 	}
 	// Quick check.
-	if unitutil.PodGroupFinalState(pg.Status.Phase) {
+	if unitutil.PodGroupFinalState(pg.Status.Phase) || gangFailed(pg) {
+		if gangPolicyApplies(pg) {
+			return ctrl.applyGangPolicy(pg)
+		}
 		klog.V(4).InfoS("PodGroup has already ever reached the final state, shouldn't change any more", "podGroupKey", key, "phase", pg.Status.Phase)
 		return false
 	}
@@ -253,6 +258,8 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 			klog.InfoS("Failed to list pods for podGroup", "podGroupKey", key, "err", err)
 			return true
 		}
+		// Members being deleted, e.g. by a gang restart, don't count any more.
+		pods = activeMembers(pods)
 
 		if len(pods) > 0 {
 			fillOccupiedObj(pgCopy, pods[0])
@@ -400,7 +407,7 @@ func podGroupTimeout(pgCopy *schedv1alpha1.PodGroup) bool {
 		timeoutDuration = frameworkruntime.DefaultGangTimeout
 	}
 
-	if time.Since(pgCopy.CreationTimestamp.Time) > timeoutDuration {
+	if time.Since(unitutil.GetGangStartTime(pgCopy)) > timeoutDuration {
 		klog.V(5).InfoS("Pod group timeout", "podGroupKey", unitutil.GetPodGroupKey(pgCopy), "timeout period", timeoutDuration)
 		return true
 	}
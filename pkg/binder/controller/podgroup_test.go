@@ -34,6 +34,7 @@ import (
 	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/controller"
 	podAnnotations "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
 )
 
 const (
@@ -320,6 +321,185 @@ func Test_PodGroupRun(t *testing.T) {
 	}
 }
 
+func Test_PodGroupGangPolicy(t *testing.T) {
+	ctx := context.TODO()
+	createTimeForCustomize := metav1.Time{Time: time.Now().Add(-301 * time.Second)} // custom timeout is 300s
+	durationInSeconds := int32(300)
+
+	cases := []struct {
+		name               string
+		pgName             string
+		minMember          int32
+		annotations        map[string]string
+		podNodeName        string
+		podPhase           v1.PodPhase
+		podPhaseOverride   v1.PodPhase
+		previousPhase      v1alpha1.PodGroupPhase
+		podGroupCreateTime *metav1.Time
+		desiredGroupPhase  v1alpha1.PodGroupPhase
+		desiredPods        int
+		desiredRestarts    string
+	}{
+		{
+			name:               "Timeout gang releases bound members",
+			pgName:             "pg0",
+			minMember:          3,
+			annotations:        map[string]string{unitutil.GangTimeoutPolicyAnnotationKey: string(unitutil.GangTimeoutReleaseBound)},
+			podNodeName:        "n",
+			podPhase:           v1.PodPending,
+			previousPhase:      v1alpha1.PodGroupPreScheduling,
+			podGroupCreateTime: &createTimeForCustomize,
+			desiredGroupPhase:  v1alpha1.PodGroupTimeout,
+			desiredPods:        0,
+		},
+		{
+			name:              "Scheduled gang restarts after member failure",
+			pgName:            "pg1",
+			minMember:         2,
+			annotations:       map[string]string{unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureRestartGang)},
+			podNodeName:       "n",
+			podPhase:          v1.PodRunning,
+			podPhaseOverride:  v1.PodFailed,
+			previousPhase:     v1alpha1.PodGroupScheduled,
+			desiredGroupPhase: v1alpha1.PodGroupPending,
+			desiredPods:       0,
+			desiredRestarts:   "1",
+		},
+		{
+			name:   "Scheduled gang fails after reaching backoff limit",
+			pgName: "pg2",
+			annotations: map[string]string{
+				unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureRestartGang),
+				unitutil.GangBackoffLimitAnnotationKey:  "1",
+				unitutil.GangRestartCountAnnotationKey:  "1",
+			},
+			minMember:         2,
+			podNodeName:       "n",
+			podPhase:          v1.PodRunning,
+			podPhaseOverride:  v1.PodFailed,
+			previousPhase:     v1alpha1.PodGroupScheduled,
+			desiredGroupPhase: v1alpha1.PodGroupFailed,
+			desiredPods:       0,
+			desiredRestarts:   "1",
+		},
+		{
+			name:              "Scheduled gang is deleted after member failure",
+			pgName:            "pg3",
+			minMember:         2,
+			annotations:       map[string]string{unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureDeleteGang)},
+			podNodeName:       "n",
+			podPhase:          v1.PodRunning,
+			podPhaseOverride:  v1.PodFailed,
+			previousPhase:     v1alpha1.PodGroupScheduled,
+			desiredGroupPhase: v1alpha1.PodGroupFailed,
+			desiredPods:       0,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ps := makePods([]string{"pod1", "pod2"}, c.pgName, c.podNodeName)
+			for _, pod := range ps {
+				pod.Status.Phase = c.podPhase
+			}
+			if len(c.podPhaseOverride) != 0 {
+				ps[0].Status.Phase = c.podPhaseOverride
+			}
+			kubeClient := fake.NewSimpleClientset(ps[0], ps[1])
+			pg := makePG(c.pgName, c.minMember, c.previousPhase, &durationInSeconds, c.podGroupCreateTime)
+			pg.Annotations = c.annotations
+			pgClient := pgfake.NewSimpleClientset(pg)
+
+			pgInformerFactory := crdinformers.NewSharedInformerFactory(pgClient, controller.NoResyncPeriodFunc())
+			pgInformer := pgInformerFactory.Scheduling().V1alpha1().PodGroups()
+			SetupPodGroupController(ctx, kubeClient, pgClient, pgInformer)
+			pgInformerFactory.Start(ctx.Done())
+			pgInformerFactory.WaitForCacheSync(ctx.Done())
+
+			// Failed syncs are requeued with exponential backoff, which may take a while under -race.
+			err := wait.Poll(200*time.Millisecond, wait.ForeverTestTimeout, func() (done bool, err error) {
+				newPg, err := pgClient.SchedulingV1alpha1().PodGroups("default").Get(ctx, c.pgName, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if newPg.Status.Phase != c.desiredGroupPhase {
+					return false, nil
+				}
+				if restarts := newPg.Annotations[unitutil.GangRestartCountAnnotationKey]; restarts != c.desiredRestarts {
+					return false, nil
+				}
+				pods, err := kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return false, err
+				}
+				return len(pods.Items) == c.desiredPods, nil
+			})
+			if err != nil {
+				t.Fatal("Unexpected error", err)
+			}
+		})
+	}
+}
+
+func Test_GangPolicyApplies(t *testing.T) {
+	cases := []struct {
+		name        string
+		phase       v1alpha1.PodGroupPhase
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name:  "timeout without policy",
+			phase: v1alpha1.PodGroupTimeout,
+		},
+		{
+			name:        "timeout with ReleaseBound",
+			phase:       v1alpha1.PodGroupTimeout,
+			annotations: map[string]string{unitutil.GangTimeoutPolicyAnnotationKey: string(unitutil.GangTimeoutReleaseBound)},
+			want:        true,
+		},
+		{
+			name:        "scheduled with Ignore",
+			phase:       v1alpha1.PodGroupScheduled,
+			annotations: map[string]string{unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureIgnore)},
+		},
+		{
+			name:        "scheduled with RestartGang",
+			phase:       v1alpha1.PodGroupScheduled,
+			annotations: map[string]string{unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureRestartGang)},
+			want:        true,
+		},
+		{
+			name:        "scheduled with invalid policy",
+			phase:       v1alpha1.PodGroupScheduled,
+			annotations: map[string]string{unitutil.GangFailurePolicyAnnotationKey: "Unknown"},
+		},
+		{
+			name:  "legacy failed",
+			phase: v1alpha1.PodGroupFailed,
+		},
+		{
+			name:        "failed by DeleteGang",
+			phase:       v1alpha1.PodGroupFailed,
+			annotations: map[string]string{unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureDeleteGang)},
+			want:        true,
+		},
+		{
+			name:        "pending with RestartGang",
+			phase:       v1alpha1.PodGroupPending,
+			annotations: map[string]string{unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureRestartGang)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pg := makePG("pg", 2, c.phase, nil, nil)
+			pg.Annotations = c.annotations
+			if got := gangPolicyApplies(pg); got != c.want {
+				t.Errorf("want %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
 func makePods(podNames []string, pgName string, nodeName string) []*v1.Pod {
 	pds := make([]*v1.Pod, 0)
 	trueP := true
@@ -273,6 +273,13 @@ func (binder *Binder) deletePodFromCache(obj interface{}) {
 	}
 }
 
+// assumedPodOfGodel returns true if the pod is assumed by the schedulers of SchedulerName or of the
+// schedulerName profiles.
+func (binder *Binder) assumedPodOfGodel(pod *v1.Pod) bool {
+	return podutil.AssumedPodOfGodel(pod, *binder.SchedulerName) ||
+		len(binder.profileSchedulerNames) > 0 && podutil.AssumedPodOfGodel(pod, binder.profileSchedulerNames...)
+}
+
 func (binder *Binder) addPodToBinderQueue(obj interface{}) {
 	pod, err := podutil.ConvertToPod(obj)
 	if err != nil {
@@ -280,10 +287,15 @@ func (binder *Binder) addPodToBinderQueue(obj interface{}) {
 		return
 	}
 
-	if !podutil.AssumedPodOfGodel(pod, *binder.SchedulerName) {
+	if !binder.assumedPodOfGodel(pod) {
 		klog.V(4).InfoS("Pod is not in assumed state", "pod", klog.KObj(pod))
 		return
 	}
+	if !binder.ownsPod(pod) {
+		klog.V(4).InfoS("Pod is bound by another binder shard", "pod", klog.KObj(pod))
+		binder.reservePod(pod)
+		return
+	}
 
 	podProperty := framework.ExtractPodProperty(pod)
 	parentSpanContext := tracing.GetSpanContextFromPod(pod)
@@ -316,18 +328,27 @@ func (binder *Binder) updatePodInBinderQueue(oldObj, newObj interface{}) {
 		klog.InfoS("Failed to update pod in binder queue with newObj", "err", err)
 		return
 	}
+	binder.syncHandedOffPod(newPod, false)
 
-	if !podutil.AssumedPodOfGodel(newPod, *binder.SchedulerName) {
-		if podutil.AssumedPodOfGodel(oldPod, *binder.SchedulerName) {
+	if !binder.assumedPodOfGodel(newPod) {
+		if binder.assumedPodOfGodel(oldPod) {
 			// pod is reset to another state
 			// if changed to Bound, the waiting pod is allowed, it will be added to Bound map in waitingTasksManager,
 			// if reset to Pending/Dispatched by binder, the pod is rejected or failed after being allowed,
 			// if reset to other state and the operator is not binder, the pod may be still waiting,
 			// we can always remove the pod from waiting state
 			binder.BinderQueue.Delete(oldPod)
+			if !binder.ownsPod(oldPod) {
+				// the pod of another shard is bound or reset, bound pods have been confirmed in cache already
+				binder.releasePod(oldPod)
+			}
 		}
 		return
 	}
+	if !binder.ownsPod(newPod) {
+		binder.reservePod(newPod)
+		return
+	}
 
 	parentSpanContext := tracing.GetSpanContextFromPod(newPod)
 	podProperty := framework.ExtractPodProperty(newPod)
@@ -354,8 +375,13 @@ func (binder *Binder) deletePodFromBinderQueue(obj interface{}) {
 		klog.InfoS("Failed to delete pod from binder queue", "err", err)
 		return
 	}
+	binder.syncHandedOffPod(pod, true)
 
-	if !podutil.AssumedPodOfGodel(pod, *binder.SchedulerName) {
+	if !binder.assumedPodOfGodel(pod) {
+		return
+	}
+	if !binder.ownsPod(pod) {
+		binder.releasePod(pod)
 		return
 	}
 
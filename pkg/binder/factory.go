@@ -31,6 +31,8 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/binder/apis"
 	godelcache "github.com/kubewharf/godel-scheduler/pkg/binder/cache"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/defaultbinder"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/gpushare"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/localstoragepool"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/nodeports"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/noderesources"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/nodevolumelimits"
@@ -83,6 +85,12 @@ func NewBasePlugins(victimsCheckingPlugins []*framework.VictimCheckingPluginColl
 	if utilfeature.DefaultFeatureGate.Enabled(features.NonNativeResourceSchedulingSupport) {
 		basicPlugins.CheckConflicts = append(basicPlugins.CheckConflicts, nonnativeresource.Name)
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.GPUShareScheduling) {
+		basicPlugins.CheckConflicts = append(basicPlugins.CheckConflicts, gpushare.Name)
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.LocalStoragePoolScheduling) {
+		basicPlugins.CheckConflicts = append(basicPlugins.CheckConflicts, localstoragepool.Name)
+	}
 
 	return &basicPlugins
 }
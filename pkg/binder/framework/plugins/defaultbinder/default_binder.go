@@ -53,7 +53,8 @@ func (b DefaultBinder) Name() string {
 func (b DefaultBinder) Bind(ctx context.Context, state *framework.CycleState, p *v1.Pod, nodeName string) *framework.Status {
 	klog.V(3).InfoS("Started to bind pod to node", "pod", klog.KObj(p), "nodeName", nodeName)
 	binding := &v1.Binding{
-		ObjectMeta: metav1.ObjectMeta{Namespace: p.Namespace, Name: p.Name, UID: p.UID},
+		// The annotations of the binding are set on the pod by the API server along with the node name.
+		ObjectMeta: metav1.ObjectMeta{Namespace: p.Namespace, Name: p.Name, UID: p.UID, Annotations: framework.GetBindingAnnotations(state)},
 		Target:     v1.ObjectReference{Kind: "Node", Name: nodeName},
 	}
 
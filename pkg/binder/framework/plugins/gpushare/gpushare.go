@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpushare
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/gpushare"
+)
+
+const (
+	Name = "GPUShare"
+)
+
+// GPUShare checks whether the shared GPU device assigned by scheduler is overcommitted by the pods
+// placed concurrently by other schedulers.
+type GPUShare struct{}
+
+var _ framework.CheckConflictsPlugin = &GPUShare{}
+
+func New(_ runtime.Object, _ framework.BinderFrameworkHandle) (framework.Plugin, error) {
+	return &GPUShare{}, nil
+}
+
+func (pl *GPUShare) Name() string {
+	return Name
+}
+
+func (pl *GPUShare) CheckConflicts(_ context.Context, _ *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	return gpushare.FeasibleAssignedDevice(pod, nodeInfo)
+}
@@ -18,10 +18,13 @@ package noderesources
 
 import (
 	"context"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	utils "github.com/kubewharf/godel-scheduler/pkg/plugins/noderesources"
 )
@@ -46,7 +49,7 @@ func (pl *ConflictCheck) CheckConflicts(_ context.Context, state *framework.Cycl
 	if s.Err != nil {
 		return framework.NewStatus(framework.Error, s.Err.Error())
 	}
-	insufficientResources := utils.FitsRequest(s, nodeInfo, nil, nil)
+	insufficientResources := utils.FitsRequestWithReserved(s, nodeInfo, getReservedResource(pod, nodeInfo), nil, nil)
 
 	if len(insufficientResources) != 0 {
 		// We will keep all failure reasons.
@@ -62,3 +65,11 @@ func (pl *ConflictCheck) CheckConflicts(_ context.Context, state *framework.Cycl
 func NewConflictCheck(_ runtime.Object, _ framework.BinderFrameworkHandle) (framework.Plugin, error) {
 	return &ConflictCheck{}, nil
 }
+
+// getReservedResource returns the resources reserved on the node for the units other than the pod's.
+func getReservedResource(pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Resource {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation) {
+		return nil
+	}
+	return framework.GetReservedResource(nodeInfo.GetResourceReservations(), nodeInfo, pod, time.Now())
+}
@@ -21,12 +21,16 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
 	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	v1helper "github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
@@ -461,3 +465,91 @@ func TestStorageRequests(t *testing.T) {
 		})
 	}
 }
+
+func newPodGroupResourcePod(pgName string, usage framework.Resource) *v1.Pod {
+	pod := newResourcePod(usage)
+	pod.Namespace = "default"
+	if len(pgName) > 0 {
+		pod.Annotations[podutil.PodGroupNameAnnotationKey] = pgName
+	}
+	return pod
+}
+
+func TestCheckConflictsWithReservations(t *testing.T) {
+	now := time.Now()
+	reservation := &framework.ResourceReservation{
+		Name:         "r",
+		Owner:        "default/pg",
+		Resources:    v1.ResourceList{v1.ResourceCPU: *resource.NewMilliQuantity(6, resource.DecimalSI)},
+		CreationTime: metav1.NewTime(now),
+		TTLSeconds:   60,
+	}
+	expired := *reservation
+	expired.CreationTime = metav1.NewTime(now.Add(-time.Hour))
+
+	tests := []struct {
+		name         string
+		disabled     bool
+		reservations []*framework.ResourceReservation
+		existingPods []*v1.Pod
+		pod          *v1.Pod
+		wantStatus   *framework.Status
+	}{
+		{
+			name:         "reserved resources are hidden from the pods of other units",
+			reservations: []*framework.ResourceReservation{reservation},
+			pod:          newPodGroupResourcePod("", framework.Resource{MilliCPU: 5}),
+			wantStatus:   framework.NewStatus(framework.Unschedulable, getErrReason(v1.ResourceCPU)),
+		},
+		{
+			name:         "reserved resources are available to the pods of the owner",
+			reservations: []*framework.ResourceReservation{reservation},
+			pod:          newPodGroupResourcePod("pg", framework.Resource{MilliCPU: 5}),
+		},
+		{
+			name:         "reserved resources are released as the pods of the owner are placed",
+			reservations: []*framework.ResourceReservation{reservation},
+			existingPods: []*v1.Pod{newPodGroupResourcePod("pg", framework.Resource{MilliCPU: 4})},
+			pod:          newPodGroupResourcePod("", framework.Resource{MilliCPU: 4}),
+		},
+		{
+			name:         "expired reservations are ignored",
+			reservations: []*framework.ResourceReservation{&expired},
+			pod:          newPodGroupResourcePod("", framework.Resource{MilliCPU: 5}),
+		},
+		{
+			name:         "reservations are ignored if ResourceReservation is disabled",
+			disabled:     true,
+			reservations: []*framework.ResourceReservation{reservation},
+			pod:          newPodGroupResourcePod("", framework.Resource{MilliCPU: 5}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourceReservation, !test.disabled)()
+
+			value, err := framework.FormatResourceReservations(test.reservations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			node := v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "n", Annotations: map[string]string{framework.ResourceReservationAnnotationKey: value}},
+				Status:     v1.NodeStatus{Capacity: makeResources(10, 20, 32, 5, 20, 5).Capacity, Allocatable: makeAllocatableResources(10, 20, 32, 5, 20, 5)},
+			}
+			nodeInfo := framework.NewNodeInfo(test.existingPods...)
+			nodeInfo.SetNode(&node)
+
+			p, err := NewConflictCheck(nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cycleState := framework.NewCycleState()
+			framework.SetPodResourceTypeState(podutil.GuaranteedPod, cycleState)
+
+			gotStatus := p.(framework.CheckConflictsPlugin).CheckConflicts(context.Background(), cycleState, test.pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, test.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, test.wantStatus)
+			}
+		})
+	}
+}
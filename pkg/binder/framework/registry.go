@@ -24,6 +24,8 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/defaultbinder"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/defaultpreemption"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/gpushare"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/localstoragepool"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/nodeports"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/noderesources"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/nodevolumelimits"
@@ -56,6 +58,8 @@ func NewInTreeRegistry() Registry {
 		volumebinding.Name:              volumebinding.New,
 		nodeports.Name:                  nodeports.New,
 		nonnativeresource.Name:          nonnativeresource.New,
+		gpushare.Name:                   gpushare.New,
+		localstoragepool.Name:           localstoragepool.New,
 	}
 }
 
@@ -48,6 +48,8 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/parallelize"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
 
@@ -62,6 +64,9 @@ type Binder struct {
 	// SchedulerName here is the higher level scheduler name, which is used to select pods
 	// that godel schedulers should be responsible for and filter out irrelevant pods.
 	SchedulerName *string
+	// profileSchedulerNames are the scheduler names of the schedulerName profiles, the pods with these
+	// names are bound as well as the ones with SchedulerName.
+	profileSchedulerNames []string
 	// Close this to shut down the scheduler.
 	StopEverything <-chan struct{}
 
@@ -88,6 +93,21 @@ type Binder struct {
 	podLister corelisters.PodLister
 	pgLister  v1alpha1.PodGroupLister
 
+	// shardManager decides the pods bound by this binder when multiple binders are running
+	// as shards, it is nil if the binder binds all the pods.
+	shardManager *shard.Manager
+	shardKeyFunc ShardKeyFunc
+
+	// handoffs are the pods handed off by schedulers directly, whose placements are not persisted yet.
+	handoffs *handoffStore
+
+	// preemptionBudget limits the victims deleted per minute, the victims deleted are accounted in
+	// preemptionTracker. It is nil if the victims are not limited.
+	// The tracker is kept in memory, it's only enforced across the cluster by an unsharded binder and it
+	// is reset on restart.
+	preemptionBudget  *preemptionbudget.Budget
+	preemptionTracker *preemptionbudget.Tracker
+
 	// node partition doesn't make any effect for now, remove it from binder
 	// TODO: figure out if we need this and add back if necessary
 	// it is useful for scheduler since it may affect scheduling decisions,
@@ -146,6 +166,20 @@ func New(
 
 		podLister: informerFactory.Core().V1().Pods().Lister(),
 		pgLister:  crdInformerFactory.Scheduling().V1alpha1().PodGroups().Lister(),
+
+		shardManager: options.shardManager,
+		shardKeyFunc: options.shardKeyFunc,
+
+		handoffs: newHandoffStore(),
+
+		preemptionBudget:  options.preemptionBudget,
+		preemptionTracker: preemptionbudget.NewTracker(),
+	}
+	for _, profile := range options.schedulerNameProfiles {
+		binder.profileSchedulerNames = append(binder.profileSchedulerNames, profile.SchedulerName)
+	}
+	if binder.shardManager != nil {
+		binder.shardManager.AddMembershipChangedHandler(binder.onShardMembershipChanged)
 	}
 
 	// Setup cache debugger.
@@ -242,7 +276,13 @@ func (binder *Binder) CheckAndBindUnit(ctx context.Context) bool {
 			stageDescription: "mark victims and assume tasks",
 			stageFunc:        func() error { return binder.MarkVictimsAndAssumeTasks(ctx, unitInfo) },
 		},
-		// stage5: api operations
+		// stage5: preemption budget checks for the victims to be deleted
+		{
+			stageName:        "checkPreemptionBudget",
+			stageDescription: "preemption budget checks",
+			stageFunc:        func() error { return binder.CheckPreemptionBudgetForUnit(unitInfo) },
+		},
+		// stage6: api operations
 		{
 			stageName:        "apiCall",
 			stageDescription: "delete victims and bind tasks",
@@ -306,6 +346,7 @@ func (binder *Binder) initializeTask(unitInfo *bindingUnitInfo, runningUnitInfo
 		return
 	}
 	runningUnitInfo.State = state
+	binder.setBindingAnnotations(state, queuedPod.Pod)
 
 	suggestedNode := utils.GetNodeNameFromPod(queuedPod.Pod)
 	if len(suggestedNode) == 0 {
@@ -45,6 +45,14 @@ type frameworkHandleImpl struct {
 	// VolumeBinder handles PVC/PV binding for the pod.
 	volumeBinder scheduling.GodelVolumeBinder
 
+	// defaultProfile is used to bind the pods not belonging to any schedulerName profile.
+	defaultProfile *frameworkProfile
+	// schedulerNameProfiles are keyed by the spec.schedulerName of the pods they bind.
+	schedulerNameProfiles map[string]*frameworkProfile
+}
+
+// frameworkProfile is the collection of plugins used to bind the pods of one profile.
+type frameworkProfile struct {
 	// basePlugins is the collection of all plugins supposed to run when a pod is scheduled
 	basePlugins *apis.BinderPluginCollection
 	// pluginRegistry is the collection of all enabled plugins
@@ -75,10 +83,22 @@ func NewFrameworkHandle(
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
 
+	h.defaultProfile = newFrameworkProfile(options, h)
+	h.schedulerNameProfiles = make(map[string]*frameworkProfile, len(options.schedulerNameProfiles))
+	for i := range options.schedulerNameProfiles {
+		profile := &options.schedulerNameProfiles[i]
+		h.schedulerNameProfiles[profile.SchedulerName] = newFrameworkProfile(options.profileOptions(profile), h)
+	}
+
+	return h
+}
+
+func newFrameworkProfile(options binderOptions, h framework.BinderFrameworkHandle) *frameworkProfile {
 	pluginMaps, err := binderframework.NewPluginsRegistry(binderframework.NewInTreeRegistry(), options.pluginConfigs, h)
 	if err != nil {
 		klog.ErrorS(err, "Failed to initialize GodelBinder")
@@ -94,16 +114,24 @@ func NewFrameworkHandle(
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
-	h.pluginRegistry = pluginMaps
-	h.preemptionPluginRegistry = preemptionPluginsMaps
-	h.basePlugins = NewBasePlugins(options.victimCheckingPluginSet)
+	return &frameworkProfile{
+		basePlugins:              NewBasePlugins(options.victimCheckingPluginSet),
+		pluginRegistry:           pluginMaps,
+		preemptionPluginRegistry: preemptionPluginsMaps,
+	}
+}
 
-	return h
+// profileForPod returns the schedulerName profile of the pod, or the default profile if there is none.
+func (h *frameworkHandleImpl) profileForPod(pod *v1.Pod) *frameworkProfile {
+	if profile, ok := h.schedulerNameProfiles[pod.Spec.SchedulerName]; ok {
+		return profile
+	}
+	return h.defaultProfile
 }
 
 func (h *frameworkHandleImpl) GetFrameworkForPod(pod *v1.Pod) (framework.BinderFramework, error) {
-	// TODO: construct according to pod.Annotation ?
-	f := runtime.New(h.pluginRegistry, h.preemptionPluginRegistry, h.basePlugins) //, binder.waitingTasksManager)
+	profile := h.profileForPod(pod)
+	f := runtime.New(profile.pluginRegistry, profile.preemptionPluginRegistry, profile.basePlugins) //, binder.waitingTasksManager)
 	return f, nil
 }
 
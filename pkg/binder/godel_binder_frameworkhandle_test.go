@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"testing"
+	"time"
+
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/defaultpreemption"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+)
+
+func TestGetFrameworkForPodWithSchedulerNameProfiles(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	pdbCheckerArgs := config.PluginConfig{Name: defaultpreemption.PDBCheckerName, Args: runtime.RawExtension{Raw: []byte(`{}`)}}
+	options := renderOptions(
+		WithPluginsAndConfigs(&config.GodelBinderProfile{
+			PreemptionPluginConfigs: []config.PluginConfig{pdbCheckerArgs},
+		}),
+		WithSchedulerNameProfiles([]config.GodelBinderProfile{
+			{
+				SchedulerName: "godel-batch",
+				Plugins: &config.Plugins{
+					VictimChecking: &config.VictimCheckingPluginSet{},
+				},
+			},
+		}),
+	)
+
+	client, crdClient := clientsetfake.NewSimpleClientset(), godelclientfake.NewSimpleClientset()
+	h := NewFrameworkHandle(
+		client, crdClient,
+		informers.NewSharedInformerFactory(client, 0), crdinformers.NewSharedInformerFactory(crdClient, 0),
+		options,
+		cache.New(time.Second, stopCh, ""), volumeBindingTimeoutSeconds,
+	).(*frameworkHandleImpl)
+
+	for _, tt := range []struct {
+		name                    string
+		pod                     *v1.Pod
+		expectedProfile         *frameworkProfile
+		expectedVictimCheckings int
+	}{
+		{
+			name:                    "pod of the primary scheduler name",
+			pod:                     testinghelper.MakePod().Namespace("default").Name("p").SchedulerName(testSchedulerName).Obj(),
+			expectedProfile:         h.defaultProfile,
+			expectedVictimCheckings: 1,
+		},
+		{
+			name:                    "pod of the schedulerName profile",
+			pod:                     testinghelper.MakePod().Namespace("default").Name("p").SchedulerName("godel-batch").Obj(),
+			expectedProfile:         h.schedulerNameProfiles["godel-batch"],
+			expectedVictimCheckings: 0,
+		},
+		{
+			name:                    "pod of an unknown scheduler name",
+			pod:                     testinghelper.MakePod().Namespace("default").Name("p").SchedulerName("godel-unknown").Obj(),
+			expectedProfile:         h.defaultProfile,
+			expectedVictimCheckings: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := h.profileForPod(tt.pod)
+			if profile != tt.expectedProfile {
+				t.Errorf("expected profile %p, got %p", tt.expectedProfile, profile)
+			}
+			if got := len(profile.basePlugins.VictimCheckings); got != tt.expectedVictimCheckings {
+				t.Errorf("expected %d victim checking collections, got %d", tt.expectedVictimCheckings, got)
+			}
+			// The profile inherits the preemption plugin configs of the primary profile.
+			if _, ok := profile.preemptionPluginRegistry[defaultpreemption.PDBCheckerName]; !ok {
+				t.Errorf("expected preemption plugin %s to be registered", defaultpreemption.PDBCheckerName)
+			}
+			if _, err := h.GetFrameworkForPod(tt.pod); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
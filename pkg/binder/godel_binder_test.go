@@ -1257,6 +1257,7 @@ func TestCoscheduling(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			nil,
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -1349,6 +1350,7 @@ func TestCoscheduling_Error_InvalidPodGroup(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			nil,
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -1435,6 +1437,7 @@ func TestCoscheduling_Waiting(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			nil,
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -1525,6 +1528,7 @@ func TestCoscheduling_Timeout(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			nil,
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// handedOffPod is a pod handed off by a scheduler directly, whose placement has not been persisted
+// through the pod annotations yet.
+type handedOffPod struct {
+	scheduler   string
+	token       string
+	annotations map[string]string
+}
+
+// handoffStore records the pods handed off by schedulers, keyed by pod UID.
+type handoffStore struct {
+	mu   sync.RWMutex
+	pods map[types.UID]*handedOffPod
+}
+
+func newHandoffStore() *handoffStore {
+	return &handoffStore{pods: make(map[types.UID]*handedOffPod)}
+}
+
+func (s *handoffStore) add(uid types.UID, pod *handedOffPod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pods[uid] = pod
+}
+
+func (s *handoffStore) get(uid types.UID) *handedOffPod {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pods[uid]
+}
+
+func (s *handoffStore) delete(uid types.UID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pods[uid]; !ok {
+		return false
+	}
+	delete(s.pods, uid)
+	return true
+}
+
+// HandoffHandler returns the handler receiving the placements handed off by schedulers directly. The
+// accepted pods are added to the binder queue as if their placements had been persisted, and the
+// placements are persisted along with the binding. Schedulers persist the placements themselves if
+// the pods are not bound in time, so nothing is lost if the binder restarts.
+func (binder *Binder) HandoffHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var request handoff.Request
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := handoff.Response{}
+		for i := range request.Placements {
+			if binder.acceptHandoff(request.Scheduler, &request.Placements[i]) {
+				response.Accepted = append(response.Accepted, request.Placements[i].UID)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&response); err != nil {
+			klog.InfoS("Failed to write handoff response", "scheduler", request.Scheduler, "err", err)
+		}
+	})
+}
+
+// acceptHandoff adds the pod to the binder queue with the placement applied. It returns false if the pod
+// is not dispatched to the scheduler any more, should be bound by another binder shard, or its placement
+// can't be verified.
+func (binder *Binder) acceptHandoff(scheduler string, placement *handoff.Placement) bool {
+	pod, err := binder.podLister.Pods(placement.Namespace).Get(placement.Name)
+	if err != nil || pod.UID != placement.UID || pod.DeletionTimestamp != nil {
+		return false
+	}
+	if !podutil.DispatchedPod(pod) || pod.Annotations[podutil.SchedulerAnnotationKey] != scheduler {
+		return false
+	}
+
+	assumedPod := pod.DeepCopy()
+	for key, value := range placement.Annotations {
+		assumedPod.Annotations[key] = value
+	}
+	if !binder.assumedPodOfGodel(assumedPod) || !binder.ownsPod(assumedPod) {
+		return false
+	}
+	if err := binder.verifyPlacement(assumedPod); err != nil {
+		klog.InfoS("Rejected handed off pod with unverifiable placement", "pod", klog.KObj(pod), "scheduler", scheduler, "err", err)
+		return false
+	}
+
+	binder.handoffs.add(pod.UID, &handedOffPod{
+		scheduler:   scheduler,
+		token:       placement.Annotations[podutil.HandoffTokenAnnotationKey],
+		annotations: placement.Annotations,
+	})
+	if err := binder.BinderQueue.Add(assumedPod); err != nil {
+		klog.InfoS("Failed to add handed off pod to binder queue", "pod", klog.KObj(pod), "err", err)
+		binder.handoffs.delete(pod.UID)
+		return false
+	}
+	klog.V(3).InfoS("Accepted handed off pod", "pod", klog.KObj(pod), "scheduler", scheduler)
+	return true
+}
+
+// verifyPlacement checks the handed off placement against the binder's own view of the cluster, since the
+// placement has not been persisted by the scheduler. The node must be known to the binder cache, and every
+// victim must be a pod running on the nominated node with a lower priority than the preemptor.
+func (binder *Binder) verifyPlacement(assumedPod *v1.Pod) error {
+	nodeName := utils.GetNodeNameFromPod(assumedPod)
+	if len(nodeName) == 0 {
+		return fmt.Errorf("no node found in placement")
+	}
+	if _, err := binder.BinderCache.GetNode(nodeName); err != nil {
+		return fmt.Errorf("unknown node %s: %v", nodeName, err)
+	}
+	if len(assumedPod.Annotations[podutil.NominatedNodeAnnotationKey]) == 0 {
+		return nil
+	}
+
+	nominatedNode, err := utils.GetPodNominatedNode(assumedPod)
+	if err != nil {
+		return err
+	}
+	priority := podutil.GetPodPriority(assumedPod)
+	for _, victim := range nominatedNode.VictimPods {
+		victimPod, err := binder.podLister.Pods(victim.Namespace).Get(victim.Name)
+		if err != nil {
+			return fmt.Errorf("victim %s/%s not found: %v", victim.Namespace, victim.Name, err)
+		}
+		if string(victimPod.UID) != victim.UID {
+			return fmt.Errorf("victim %s/%s has UID %s, expected %s", victim.Namespace, victim.Name, victimPod.UID, victim.UID)
+		}
+		if victimPod.Spec.NodeName != nominatedNode.NodeName {
+			return fmt.Errorf("victim %s/%s is not running on node %s", victim.Namespace, victim.Name, nominatedNode.NodeName)
+		}
+		if podutil.GetPodPriority(victimPod) >= priority {
+			return fmt.Errorf("victim %s/%s has a priority not lower than the preemptor", victim.Namespace, victim.Name)
+		}
+	}
+	return nil
+}
+
+// setBindingAnnotations makes the placement of a handed off pod persisted along with the binding.
+func (binder *Binder) setBindingAnnotations(state *framework.CycleState, pod *v1.Pod) {
+	if handedOff := binder.handoffs.get(pod.UID); handedOff != nil {
+		framework.SetBindingAnnotations(state, handedOff.annotations)
+	}
+}
+
+// syncHandedOffPod forgets the handed off pod once the latest pod is not dispatched to the scheduler any
+// more, or its placement has been rejected. The pod is removed from the binder queue unless it is bound or its placement has been persisted,
+// in which case it's handled as any other assumed pod.
+func (binder *Binder) syncHandedOffPod(pod *v1.Pod, deleted bool) {
+	handedOff := binder.handoffs.get(pod.UID)
+	if handedOff == nil {
+		return
+	}
+	if !deleted && podutil.DispatchedPod(pod) && pod.Annotations[podutil.SchedulerAnnotationKey] == handedOff.scheduler &&
+		pod.Annotations[podutil.HandoffRejectedAnnotationKey] != handedOff.token {
+		return
+	}
+	binder.handoffs.delete(pod.UID)
+	if !deleted && (podutil.BoundPod(pod) || binder.assumedPodOfGodel(pod)) {
+		return
+	}
+	if err := binder.BinderQueue.Delete(pod); err != nil {
+		klog.InfoS("Failed to remove handed off pod from binder queue", "pod", klog.KObj(pod), "err", err)
+	}
+}
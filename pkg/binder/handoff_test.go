@@ -0,0 +1,235 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/queue"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeHandoffTestPod(name, scheduler string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testNS",
+			UID:       types.UID(name),
+			Annotations: map[string]string{
+				podutil.PodStateAnnotationKey:        string(podutil.PodDispatched),
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+				podutil.SchedulerAnnotationKey:       scheduler,
+			},
+		},
+		Spec: v1.PodSpec{SchedulerName: testSchedulerName},
+	}
+}
+
+func TestHandoffHandler(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	dispatchedPod := makeHandoffTestPod("dispatched", "scheduler-0")
+	otherSchedulerPod := makeHandoffTestPod("other-scheduler", "scheduler-1")
+	client := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	for _, pod := range []*v1.Pod{dispatchedPod, otherSchedulerPod} {
+		podInformer.Informer().GetStore().Add(pod)
+	}
+
+	binderQueue := queue.NewPriorityQueue(DefaultUnitQueueSortFunc(), nil, nil)
+	binder := &Binder{
+		SchedulerName: &testSchedulerName,
+		BinderCache:   cache.New(10*time.Second, stopCh, ""),
+		BinderQueue:   binderQueue,
+		podLister:     podInformer.Lister(),
+		handoffs:      newHandoffStore(),
+	}
+	binder.BinderCache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	assumed := map[string]string{
+		podutil.PodStateAnnotationKey:    string(podutil.PodAssumed),
+		podutil.AssumedNodeAnnotationKey: "node-1",
+	}
+	request := &handoff.Request{
+		Scheduler: "scheduler-0",
+		Placements: []handoff.Placement{
+			{Namespace: "testNS", Name: "dispatched", UID: dispatchedPod.UID, Annotations: assumed},
+			{Namespace: "testNS", Name: "other-scheduler", UID: otherSchedulerPod.UID, Annotations: assumed},
+			{Namespace: "testNS", Name: "not-found", UID: "not-found", Annotations: assumed},
+			{Namespace: "testNS", Name: "dispatched", UID: "stale-uid", Annotations: assumed},
+		},
+	}
+	body, _ := json.Marshal(request)
+	recorder := httptest.NewRecorder()
+	binder.HandoffHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, handoff.Path, bytes.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", recorder.Code)
+	}
+	var response handoff.Response
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Accepted) != 1 || response.Accepted[0] != dispatchedPod.UID {
+		t.Fatalf("expected only the dispatched pod to be accepted, got %v", response.Accepted)
+	}
+
+	pending := binderQueue.PendingPods()
+	if len(pending) != 1 || pending[0].Name != dispatchedPod.Name || !podutil.AssumedPod(pending[0]) {
+		t.Fatalf("expected the handed off pod to be queued in assumed state, got %v", pending)
+	}
+	state := framework.NewCycleState()
+	binder.setBindingAnnotations(state, pending[0])
+	if got := framework.GetBindingAnnotations(state); got[podutil.AssumedNodeAnnotationKey] != "node-1" {
+		t.Errorf("expected the placement to be set on the binding, got %v", got)
+	}
+
+	// the handed off pod is removed from the queue once it's re-dispatched to another scheduler
+	redispatchedPod := dispatchedPod.DeepCopy()
+	redispatchedPod.Annotations[podutil.SchedulerAnnotationKey] = "scheduler-1"
+	binder.updatePodInBinderQueue(dispatchedPod, redispatchedPod)
+	if pending := binderQueue.PendingPods(); len(pending) != 0 {
+		t.Errorf("expected the re-dispatched pod to be removed from the queue, got %v", pending)
+	}
+	if binder.handoffs.get(dispatchedPod.UID) != nil {
+		t.Errorf("expected the re-dispatched pod to be forgotten")
+	}
+}
+
+func TestHandoffHandlerMethodNotAllowed(t *testing.T) {
+	binder := &Binder{handoffs: newHandoffStore()}
+	recorder := httptest.NewRecorder()
+	binder.HandoffHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, handoff.Path, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status code %d", recorder.Code)
+	}
+}
+
+func TestHandoffHandlerVerifiesPlacement(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	highPriority, lowPriority := int32(100), int32(10)
+	makeRunningPod := func(name, nodeName string, priority int32) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testNS", UID: types.UID(name)},
+			Spec:       v1.PodSpec{NodeName: nodeName, Priority: &priority},
+		}
+	}
+	victim := makeRunningPod("victim", "node-1", lowPriority)
+	victimOnOtherNode := makeRunningPod("victim-on-other-node", "node-2", lowPriority)
+	victimWithHighPriority := makeRunningPod("victim-with-high-priority", "node-1", highPriority)
+
+	client := clientsetfake.NewSimpleClientset()
+	podInformer := informers.NewSharedInformerFactory(client, 0).Core().V1().Pods()
+	for _, pod := range []*v1.Pod{victim, victimOnOtherNode, victimWithHighPriority} {
+		podInformer.Informer().GetStore().Add(pod)
+	}
+	binderCache := cache.New(10*time.Second, stopCh, "")
+	binderCache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	nominated := func(victims ...*v1.Pod) map[string]string {
+		nominatedNode := &framework.NominatedNode{NodeName: "node-1"}
+		for _, victim := range victims {
+			nominatedNode.VictimPods = append(nominatedNode.VictimPods, framework.VictimPod{
+				Name: victim.Name, Namespace: victim.Namespace, UID: string(victim.UID),
+			})
+		}
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			podutil.PodStateAnnotationKey: string(podutil.PodAssumed),
+		}}}
+		utils.SetPodNominatedNode(pod, nominatedNode)
+		return pod.Annotations
+	}
+	notFoundVictim := makeRunningPod("not-found", "node-1", lowPriority)
+	staleVictim := victim.DeepCopy()
+	staleVictim.UID = "stale-uid"
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:        "assumed on known node",
+			annotations: map[string]string{podutil.PodStateAnnotationKey: string(podutil.PodAssumed), podutil.AssumedNodeAnnotationKey: "node-1"},
+			expected:    true,
+		},
+		{
+			name:        "assumed on unknown node",
+			annotations: map[string]string{podutil.PodStateAnnotationKey: string(podutil.PodAssumed), podutil.AssumedNodeAnnotationKey: "unknown"},
+		},
+		{
+			name:        "nominated with valid victim",
+			annotations: nominated(victim),
+			expected:    true,
+		},
+		{
+			name:        "nominated with victim not found",
+			annotations: nominated(notFoundVictim),
+		},
+		{
+			name:        "nominated with stale victim",
+			annotations: nominated(staleVictim),
+		},
+		{
+			name:        "nominated with victim on other node",
+			annotations: nominated(victimOnOtherNode),
+		},
+		{
+			name:        "nominated with victim of higher priority",
+			annotations: nominated(victimWithHighPriority),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makeHandoffTestPod("preemptor", "scheduler-0")
+			pod.Spec.Priority = &highPriority
+			podInformer.Informer().GetStore().Add(pod)
+			defer podInformer.Informer().GetStore().Delete(pod)
+
+			binder := &Binder{
+				SchedulerName: &testSchedulerName,
+				BinderCache:   binderCache,
+				BinderQueue:   queue.NewPriorityQueue(DefaultUnitQueueSortFunc(), nil, nil),
+				podLister:     podInformer.Lister(),
+				handoffs:      newHandoffStore(),
+			}
+			placement := &handoff.Placement{Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID, Annotations: tt.annotations}
+			if got := binder.acceptHandoff("scheduler-0", placement); got != tt.expected {
+				t.Errorf("expected accepted %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
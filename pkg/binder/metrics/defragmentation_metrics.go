@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+
+	pkgmetrics "github.com/kubewharf/godel-scheduler/pkg/common/metrics"
+)
+
+const (
+	// DryRunResult - result label value for the operations which are only planned
+	DryRunResult = "dry_run"
+	// ThrottledResult - result label value for the operations which are rate limited
+	ThrottledResult = "throttled"
+)
+
+var (
+	gpuFragmentedNodes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "gpu_fragmented_nodes",
+			Help:           "Number of nodes whose GPUs are partially allocated, observed in the latest defragmentation round.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	gpuDefragmentationMigrations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "gpu_defragmentation_migrations_total",
+			Help:           "Number of pods migrated by GPU defragmentation, by the result.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.ResultLabel})
+
+	gpuDefragmentationReleasedNodes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "gpu_defragmentation_released_nodes_total",
+			Help:           "Number of nodes whose GPUs are planned to be fully released by GPU defragmentation, by the result.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.ResultLabel})
+)
+
+// SetGPUFragmentedNodes sets the number of GPU fragmented nodes.
+func SetGPUFragmentedNodes(count int) {
+	gpuFragmentedNodes.Set(float64(count))
+}
+
+// GPUDefragmentationMigrationsInc increases the number of migrated pods.
+func GPUDefragmentationMigrationsInc(result string) {
+	gpuDefragmentationMigrations.WithLabelValues(result).Inc()
+}
+
+// GPUDefragmentationReleasedNodesAdd adds the number of released nodes.
+func GPUDefragmentationReleasedNodesAdd(result string, count int) {
+	gpuDefragmentationReleasedNodes.WithLabelValues(result).Add(float64(count))
+}
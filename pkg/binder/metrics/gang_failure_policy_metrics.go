@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+
+	pkgmetrics "github.com/kubewharf/godel-scheduler/pkg/common/metrics"
+)
+
+var (
+	gangFailurePolicyActions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "gang_failure_policy_actions_total",
+			Help:           "Number of actions taken by the PodGroup timeout and failure policies, by the action type and the result.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.TypeLabel, pkgmetrics.ResultLabel})
+
+	gangFailurePolicyDeletedPods = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "gang_failure_policy_deleted_pods_total",
+			Help:           "Number of PodGroup members deleted by the PodGroup timeout and failure policies, by the action type.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.TypeLabel})
+)
+
+// GangFailurePolicyActionsInc increases the number of actions taken by the gang failure policies.
+func GangFailurePolicyActionsInc(action, result string) {
+	gangFailurePolicyActions.WithLabelValues(action, result).Inc()
+}
+
+// GangFailurePolicyDeletedPodsInc increases the number of members deleted by the gang failure policies.
+func GangFailurePolicyDeletedPodsInc(action string) {
+	gangFailurePolicyDeletedPods.WithLabelValues(action).Inc()
+}
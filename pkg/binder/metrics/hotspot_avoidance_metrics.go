@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+
+	pkgmetrics "github.com/kubewharf/godel-scheduler/pkg/common/metrics"
+)
+
+var (
+	hotspotNodes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "hotspot_nodes",
+			Help:           "Number of nodes whose usage exceeds the LoadAware usage thresholds, observed in the latest hotspot avoidance round.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	hotspotAvoidanceEvictions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "hotspot_avoidance_evictions_total",
+			Help:           "Number of pods evicted from hotspot nodes, by the result.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.ResultLabel})
+)
+
+// SetHotspotNodes sets the number of hotspot nodes.
+func SetHotspotNodes(count int) {
+	hotspotNodes.Set(float64(count))
+}
+
+// HotspotAvoidanceEvictionsInc increases the number of evicted pods.
+func HotspotAvoidanceEvictionsInc(result string) {
+	hotspotAvoidanceEvictions.WithLabelValues(result).Inc()
+}
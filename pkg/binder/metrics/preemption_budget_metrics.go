@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+
+	pkgmetrics "github.com/kubewharf/godel-scheduler/pkg/common/metrics"
+)
+
+var (
+	preemptionBudgetAdmittedVictims = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "preemption_budget_admitted_victims_total",
+			Help:           "Number of victims admitted by the preemption budgets before being deleted.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	preemptionBudgetExhausted = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "preemption_budget_exhausted_total",
+			Help:           "Number of preemptions rejected because of the exhausted preemption budgets, by the budget type.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.TypeLabel})
+)
+
+// PreemptionBudgetAdmittedVictimsAdd increases the number of victims admitted by the preemption budgets.
+func PreemptionBudgetAdmittedVictimsAdd(count int) {
+	preemptionBudgetAdmittedVictims.Add(float64(count))
+}
+
+// PreemptionBudgetExhaustedInc increases the number of preemptions rejected by the preemption budgets.
+func PreemptionBudgetExhaustedInc(budgetType string) {
+	preemptionBudgetExhausted.WithLabelValues(budgetType).Inc()
+}
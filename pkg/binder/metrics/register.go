@@ -57,6 +57,18 @@ var metricsList = []metrics.Registerable{
 
 	binderUnitE2ELatency,
 	rejectUnitMinMember,
+
+	gpuFragmentedNodes,
+	gpuDefragmentationMigrations,
+	gpuDefragmentationReleasedNodes,
+
+	hotspotNodes,
+	hotspotAvoidanceEvictions,
+
+	gangFailurePolicyActions,
+	gangFailurePolicyDeletedPods,
+	preemptionBudgetAdmittedVictims,
+	preemptionBudgetExhausted,
 }
 
 var registerMetrics sync.Once
@@ -20,6 +20,8 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
 	plugins "github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/defaultpreemption"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
 )
 
 var defaultBinderOptions = binderOptions{
@@ -40,6 +42,11 @@ type binderOptions struct {
 	victimCheckingPluginSet []*framework.VictimCheckingPluginCollectionSpec
 	preemptionPluginConfigs map[string]*config.PluginConfig
 	pluginConfigs           map[string]*config.PluginConfig
+	shardManager            *shard.Manager
+	shardKeyFunc            ShardKeyFunc
+	preemptionBudget        *preemptionbudget.Budget
+	// schedulerNameProfiles override the plugins and configs for the pods of the scheduler names.
+	schedulerNameProfiles []config.GodelBinderProfile
 }
 
 // Option configures a Scheduler
@@ -68,6 +75,49 @@ func WithPluginsAndConfigs(profile *config.GodelBinderProfile) Option {
 	}
 }
 
+// WithSchedulerNameProfiles sets Preemption Plugins and Configs for the pods whose spec.schedulerName is not
+// the primary one, the plugins and configs not set in the profiles are inherited from the primary profile.
+func WithSchedulerNameProfiles(profiles []config.GodelBinderProfile) Option {
+	return func(o *binderOptions) {
+		o.schedulerNameProfiles = profiles
+	}
+}
+
+// WithShardManager makes the binder only bind the pods whose shard keys are owned by the manager,
+// the default value is nil, which means the binder binds all the pods.
+func WithShardManager(manager *shard.Manager, keyFunc ShardKeyFunc) Option {
+	return func(o *binderOptions) {
+		o.shardManager = manager
+		o.shardKeyFunc = keyFunc
+	}
+}
+
+// WithPreemptionBudget makes the binder admit the victims into the preemption budget before deleting them,
+// the default value is nil, which means the victims are not limited.
+func WithPreemptionBudget(budget *preemptionbudget.Budget) Option {
+	return func(o *binderOptions) {
+		o.preemptionBudget = budget
+	}
+}
+
+// profileOptions returns the options for the pods of the schedulerName profile, which inherit the plugins
+// and configs of o and are overridden by the profile.
+func (o *binderOptions) profileOptions(profile *config.GodelBinderProfile) binderOptions {
+	options := binderOptions{
+		victimCheckingPluginSet: o.victimCheckingPluginSet,
+		preemptionPluginConfigs: make(map[string]*config.PluginConfig, len(o.preemptionPluginConfigs)),
+		pluginConfigs:           make(map[string]*config.PluginConfig, len(o.pluginConfigs)),
+	}
+	for name, pluginConfig := range o.preemptionPluginConfigs {
+		options.preemptionPluginConfigs[name] = pluginConfig
+	}
+	for name, pluginConfig := range o.pluginConfigs {
+		options.pluginConfigs[name] = pluginConfig
+	}
+	WithPluginsAndConfigs(profile)(&options)
+	return options
+}
+
 func renderOptions(opts ...Option) binderOptions {
 	options := defaultBinderOptions
 	for _, opt := range opts {
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+)
+
+// CheckPreemptionBudgetForUnit admits the victims of the new preemptors into the preemption budget node by node,
+// the new tasks on the nodes whose victims exceed the budget fail and their victims will not be deleted.
+// The victims admitted stay accounted even if the unit fails later, which errs on the side of fewer evictions.
+func (binder *Binder) CheckPreemptionBudgetForUnit(unitInfo *bindingUnitInfo) error {
+	if binder.preemptionBudget == nil {
+		return nil
+	}
+
+	nodeToVictims := make(map[string][]preemptionbudget.Victim)
+	for _, cr := range unitInfo.GetWaitingTasks() {
+		task := cr.runningUnit
+		if cr.assumed || len(task.victims) == 0 {
+			continue
+		}
+		nodeToVictims[task.suggestedNode] = append(nodeToVictims[task.suggestedNode], binder.budgetVictims(task)...)
+	}
+	if len(nodeToVictims) == 0 {
+		return nil
+	}
+	nodeNames := make([]string, 0, len(nodeToVictims))
+	for nodeName := range nodeToVictims {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	var errs []error
+	now := time.Now()
+	for _, nodeName := range nodeNames {
+		victims := nodeToVictims[nodeName]
+		if err := binder.preemptionTracker.Admit(binder.preemptionBudget, victims, now); err != nil {
+			var exhausted *preemptionbudget.ExhaustedError
+			if errors.As(err, &exhausted) {
+				metrics.PreemptionBudgetExhaustedInc(exhausted.Key.Type)
+			}
+			klog.InfoS("Failed to admit victims into preemption budget", "unitKey", unitInfo.queuedUnitInfo.GetKey(), "nodeName", nodeName, "err", err)
+			unitInfo.MoveAllNewTasksOnNodeFromWaitingToFailedList(nodeName, err)
+			errs = append(errs, err)
+			continue
+		}
+		metrics.PreemptionBudgetAdmittedVictimsAdd(len(victims))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// budgetVictims returns the victims of the preemptor accounted in the preemption budget.
+func (binder *Binder) budgetVictims(task *runningUnitInfo) []preemptionbudget.Victim {
+	var nodeLabels map[string]string
+	if nodeInfo, err := binder.BinderCache.GetNode(task.suggestedNode); err == nil && nodeInfo.GetNode() != nil {
+		nodeLabels = nodeInfo.GetNode().Labels
+	}
+	victims := make([]preemptionbudget.Victim, 0, len(task.victims))
+	for _, victim := range task.victims {
+		victims = append(victims, preemptionbudget.NewVictim(task.queuedPodInfo.Pod, victim.Namespace, victim.Name, string(victim.UID), nodeLabels))
+	}
+	return victims
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/binder/cache"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+)
+
+func TestCheckPreemptionBudgetForUnit(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	binderCache := godelcache.New(time.Minute, stop, "")
+	for _, name := range []string{"n1", "n2"} {
+		if err := binderCache.AddNode(testinghelper.MakeNode().Name(name).Label("pool", "a").Obj()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	binder := &Binder{
+		BinderCache: binderCache,
+		preemptionBudget: &preemptionbudget.Budget{
+			NodePoolLabelKey:               "pool",
+			MaxVictimsPerMinutePerNodePool: 2,
+		},
+		preemptionTracker: preemptionbudget.NewTracker(),
+	}
+
+	newTask := func(name, nodeName string, victims ...string) *checkResult {
+		task := &runningUnitInfo{
+			suggestedNode: nodeName,
+			queuedPodInfo: &framework.QueuedPodInfo{
+				Pod: testinghelper.MakePod().Namespace("default").Name(name).UID(name).Priority(100).Obj(),
+			},
+		}
+		for _, victim := range victims {
+			task.victims = append(task.victims, testinghelper.MakePod().Namespace("default").Name(victim).UID(victim).Node(nodeName).Obj())
+		}
+		return &checkResult{runningUnit: task}
+	}
+	preemptor1 := newTask("p1", "n1", "v1", "v2")
+	preemptor2 := newTask("p2", "n2", "v3")
+	unitInfo := &bindingUnitInfo{
+		queuedUnitInfo: &framework.QueuedUnitInfo{ScheduleUnit: framework.NewSinglePodUnit(preemptor1.runningUnit.queuedPodInfo)},
+		waitingTasks: map[types.UID]*checkResult{
+			"p1": preemptor1,
+			"p2": preemptor2,
+			"p3": newTask("p3", "n2"),
+		},
+		failedTasks: map[types.UID]*checkResult{},
+	}
+
+	if err := binder.CheckPreemptionBudgetForUnit(unitInfo); err == nil {
+		t.Fatalf("expected the node pool budget to be exhausted")
+	}
+	if _, ok := unitInfo.waitingTasks["p1"]; !ok {
+		t.Errorf("expected the victims of p1 to be admitted")
+	}
+	for _, uid := range []types.UID{"p2", "p3"} {
+		if _, ok := unitInfo.failedTasks[uid]; !ok {
+			t.Errorf("expected %v on the node exceeding the budget to fail", uid)
+		}
+	}
+
+	usage := binder.preemptionTracker.Usage(binder.preemptionBudget, time.Now())
+	if got := usage[preemptionbudget.Key{Type: preemptionbudget.NodePoolBudget, Value: "a"}]; got != 2 {
+		t.Errorf("expected 2 victims accounted in the node pool, got %d", got)
+	}
+
+	// The victims are not limited without the budget.
+	binder.preemptionBudget = nil
+	unitInfo.waitingTasks = map[types.UID]*checkResult{"p4": newTask("p4", "n2", "v4")}
+	if err := binder.CheckPreemptionBudgetForUnit(unitInfo); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
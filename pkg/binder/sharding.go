@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
+)
+
+// ShardLeaseGroupLabelKey is the label of the binder shard membership leases, whose value is the group of the binders
+// sharing the units, i.e. the binders serving the same scheduler name.
+const ShardLeaseGroupLabelKey = "godel.bytedance.com/binder-shard-group"
+
+// ShardKeyFunc returns the key deciding the shard of an assumed pod.
+type ShardKeyFunc func(pod *v1.Pod) string
+
+// NewShardKeyFunc returns the ShardKeyFunc of the sharding key.
+func NewShardKeyFunc(shardingKey string) ShardKeyFunc {
+	if shardingKey == config.BinderShardingByNodeName {
+		return nodeNameShardKey
+	}
+	return schedulerNameShardKey
+}
+
+// schedulerNameShardKey assigns all the pods assumed by a scheduler to the same shard.
+// The pods of a unit are always assumed by the same scheduler, so units never span shards.
+func schedulerNameShardKey(pod *v1.Pod) string {
+	return podutil.GetSchedulerNameForPod(pod)
+}
+
+// nodeNameShardKey assigns the pods to shards by their nodes. The members of a PodGroup may be placed on the
+// nodes of different shards, such a unit is bound as a whole by the shard owning the PodGroup.
+func nodeNameShardKey(pod *v1.Pod) string {
+	if pgName := podutil.GetPodGroupName(pod); len(pgName) != 0 {
+		return pod.Namespace + "/" + pgName
+	}
+	return utils.GetNodeNameFromPod(pod)
+}
+
+// ownsPod returns whether the assumed pod should be bound by this binder.
+func (binder *Binder) ownsPod(pod *v1.Pod) bool {
+	if binder.shardManager == nil {
+		return true
+	}
+	return binder.shardManager.Members().Owns(binder.shardKeyFunc(pod))
+}
+
+// reservePod reserves the resources of a pod assumed in another shard, so that this binder takes
+// the pod into account when checking conflicts until the pod is bound or reset.
+func (binder *Binder) reservePod(pod *v1.Pod) {
+	nodeName := utils.GetNodeNameFromPod(pod)
+	if len(nodeName) == 0 {
+		return
+	}
+	if _, err := binder.BinderCache.GetPod(pod); err == nil {
+		// The pod is reserved already or has been bound.
+		return
+	}
+	reservedPod := pod.DeepCopy()
+	reservedPod.Spec.NodeName = nodeName
+	if err := binder.BinderCache.AssumePod(reservedPod); err != nil {
+		klog.InfoS("Failed to reserve pod of another shard", "pod", klog.KObj(pod), "err", err)
+	}
+}
+
+// releasePod releases the reservation of a pod made by reservePod.
+func (binder *Binder) releasePod(pod *v1.Pod) {
+	if isAssumed, err := binder.BinderCache.IsAssumedPod(pod); err != nil || !isAssumed {
+		return
+	}
+	reservedPod, err := binder.BinderCache.GetPod(pod)
+	if err != nil {
+		return
+	}
+	if err := binder.BinderCache.ForgetPod(reservedPod); err != nil {
+		klog.InfoS("Failed to release pod of another shard", "pod", klog.KObj(pod), "err", err)
+	}
+}
+
+// onShardMembershipChanged hands over the assumed pods whose owners are changed. The pods taken over are
+// added to the binder queue, and the pods handed out are removed from the binder queue and reserved.
+// Pods being bound at the moment are not interrupted, the binding of the new owner will fail on conflict then.
+func (binder *Binder) onShardMembershipChanged(previous, current *shard.Members) {
+	pods, err := binder.podLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list pods for binder shard membership change", "err", err)
+		return
+	}
+	for _, pod := range pods {
+		if !binder.assumedPodOfGodel(pod) {
+			continue
+		}
+		key := binder.shardKeyFunc(pod)
+		switch wasOwned, isOwned := previous.Owns(key), current.Owns(key); {
+		case !wasOwned && isOwned:
+			binder.releasePod(pod)
+			binder.addPodToBinderQueue(pod)
+		case wasOwned && !isOwned:
+			if err := binder.BinderQueue.Delete(pod); err != nil {
+				klog.InfoS("Failed to delete pod handed over to another shard", "pod", klog.KObj(pod), "err", err)
+			}
+			binder.reservePod(pod)
+		}
+	}
+}
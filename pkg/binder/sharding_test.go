@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/queue"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
+)
+
+func makeShardTestPod(name, scheduler string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testNS",
+			UID:       types.UID(name),
+			Annotations: map[string]string{
+				podutil.AssumedNodeAnnotationKey:     "node-1",
+				podutil.PodStateAnnotationKey:        string(podutil.PodAssumed),
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+				podutil.SchedulerAnnotationKey:       scheduler,
+			},
+		},
+	}
+}
+
+func TestShardedBinderQueue(t *testing.T) {
+	ctx := context.Background()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	client := clientsetfake.NewSimpleClientset()
+	cfg := &config.BinderShardingConfiguration{
+		Enable:               true,
+		ShardingKey:          config.BinderShardingBySchedulerName,
+		LeaseNamespace:       "godel-system",
+		LeaseDurationSeconds: 15,
+		RenewIntervalSeconds: 5,
+	}
+	fakeClock := clock.NewFakeClock(time.Now())
+	shardConfig := shard.Config{
+		LeaseNamespace:     cfg.LeaseNamespace,
+		LeaseGroupLabelKey: ShardLeaseGroupLabelKey,
+		LeaseDuration:      time.Duration(cfg.LeaseDurationSeconds) * time.Second,
+		RenewInterval:      time.Duration(cfg.RenewIntervalSeconds) * time.Second,
+	}
+	self := shard.NewManager(client, fakeClock, testSchedulerName, "binder-0", shardConfig)
+	peer := shard.NewManager(client, fakeClock, testSchedulerName, "binder-1", shardConfig)
+	for _, m := range []*shard.Manager{peer, self} {
+		if err := m.Join(ctx); err != nil {
+			t.Fatalf("failed to join: %v", err)
+		}
+	}
+
+	// find a scheduler owned by each binder
+	var ownedScheduler, foreignScheduler string
+	for i := 0; len(ownedScheduler) == 0 || len(foreignScheduler) == 0; i++ {
+		scheduler := fmt.Sprintf("scheduler-%d", i)
+		if self.Members().Owns(scheduler) {
+			ownedScheduler = scheduler
+		} else {
+			foreignScheduler = scheduler
+		}
+	}
+	ownedPod, foreignPod := makeShardTestPod("owned", ownedScheduler), makeShardTestPod("foreign", foreignScheduler)
+
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	binderCache := cache.New(10*time.Second, stopCh, "")
+	binderQueue := queue.NewPriorityQueue(DefaultUnitQueueSortFunc(), nil, nil)
+	binder := &Binder{
+		SchedulerName: &testSchedulerName,
+		BinderCache:   binderCache,
+		BinderQueue:   binderQueue,
+		podLister:     podInformer.Lister(),
+		shardManager:  self,
+		shardKeyFunc:  NewShardKeyFunc(cfg.ShardingKey),
+	}
+	self.AddMembershipChangedHandler(binder.onShardMembershipChanged)
+
+	binder.addPodToBinderQueue(ownedPod)
+	binder.addPodToBinderQueue(foreignPod)
+	if pending := binderQueue.PendingPods(); len(pending) != 1 || pending[0].Name != ownedPod.Name {
+		t.Errorf("expected only the owned pod to be queued, got %v", pending)
+	}
+	if assumed, _ := binderCache.IsAssumedPod(foreignPod); !assumed {
+		t.Errorf("expected the pod of another shard to be reserved")
+	}
+
+	// the reservation is released once the pod of another shard is deleted
+	binder.deletePodFromBinderQueue(foreignPod)
+	if assumed, _ := binderCache.IsAssumedPod(foreignPod); assumed {
+		t.Errorf("expected the reservation of the deleted pod to be released")
+	}
+
+	// the pods are taken over once the other binder expires
+	binder.addPodToBinderQueue(foreignPod)
+	podInformer.Informer().GetIndexer().Add(ownedPod)
+	podInformer.Informer().GetIndexer().Add(foreignPod)
+	fakeClock.Step(20 * time.Second)
+	if err := self.Join(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if pending := binderQueue.PendingPods(); len(pending) != 2 {
+		t.Errorf("expected both pods to be queued after taking over, got %v", pending)
+	}
+	if assumed, _ := binderCache.IsAssumedPod(foreignPod); assumed {
+		t.Errorf("expected the reservation of the taken over pod to be released")
+	}
+}
@@ -111,6 +111,7 @@ func NewBinderFrameworkHandle(
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}, nil
@@ -39,10 +39,18 @@ func CleanupPodAnnotations(client clientset.Interface, pod *v1.Pod) error {
 	delete(podCopy.Annotations, podutil.NominatedNodeAnnotationKey)
 	delete(podCopy.Annotations, podutil.FailedSchedulersAnnotationKey)
 	delete(podCopy.Annotations, podutil.MicroTopologyKey)
+	delete(podCopy.Annotations, podutil.GPUShareDeviceAnnotationKey)
+	delete(podCopy.Annotations, podutil.LocalStorageRequestsAnnotationKey)
 
 	// reset pod state to dispatched
 	podCopy.Annotations[podutil.PodStateAnnotationKey] = string(podutil.PodDispatched)
 
+	// reject the handed off placement explicitly, the scheduler may persist it otherwise
+	if token, ok := podCopy.Annotations[podutil.HandoffTokenAnnotationKey]; ok {
+		delete(podCopy.Annotations, podutil.HandoffTokenAnnotationKey)
+		podCopy.Annotations[podutil.HandoffRejectedAnnotationKey] = token
+	}
+
 	startTime := time.Now()
 	err := util.PatchPod(client, pod, podCopy)
 	if err != nil {
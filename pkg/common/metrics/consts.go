@@ -48,6 +48,8 @@ const (
 	ReasonLabel          = "reason"
 	UnitTypeLabel        = "unit_type"
 	StageLabel           = "stage"
+	NamespaceLabel       = "namespace"
+	PriorityBandLabel    = "priority_band"
 )
 
 const (
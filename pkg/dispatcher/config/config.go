@@ -36,6 +36,10 @@ type GodelDispatcherConfiguration struct {
 	// scheduler, binder) will not accept a pod, unless pod.Spec.SchedulerName == SchedulerName
 	SchedulerName *string
 
+	// ProfileSchedulerNames are the scheduler names of the schedulerName profiles served by the same
+	// scheduling system, the pods with these names are dispatched as well as the ones with SchedulerName.
+	ProfileSchedulerNames []string `json:"profileSchedulerNames,omitempty" yaml:"profileSchedulerNames,omitempty"`
+
 	// LeaderElection defines the configuration of leader election client.
 	LeaderElection componentbaseconfig.LeaderElectionConfiguration
 
@@ -49,4 +53,54 @@ type GodelDispatcherConfiguration struct {
 
 	// Tracer defines the configuration of tracer
 	Tracer *tracing.TracerConfiguration `json:"tracer,omitempty" yaml:"tracer,omitempty"`
+
+	// NodePartitionType is the type of the node partition of schedulers, Physical or Logical.
+	// In Physical mode, pods will only be dispatched to the schedulers owning nodes that match
+	// their node selector and node affinity, defaulting to Logical.
+	NodePartitionType string `json:"nodePartitionType,omitempty" yaml:"nodePartitionType,omitempty"`
+
+	// TenantAdmission defines the rate limit of dispatching pending pods for each tenant.
+	TenantAdmission *TenantAdmissionConfiguration `json:"tenantAdmission,omitempty" yaml:"tenantAdmission,omitempty"`
+	// Sharding defines the sharding of pending pods among multiple active dispatchers.
+	Sharding *DispatcherShardingConfiguration `json:"sharding,omitempty" yaml:"sharding,omitempty"`
+}
+
+// DispatcherShardingConfiguration configures multiple active dispatchers, each of which dispatches
+// the pending pods of its own shard. Leader election is then only used by the reconciler and node shuffler.
+type DispatcherShardingConfiguration struct {
+	// Enable indicates whether the dispatchers are sharded.
+	Enable bool `json:"enable,omitempty" yaml:"enable,omitempty"`
+	// ShardingKey decides the shard of a pending pod, one of Namespace and PodGroup.
+	ShardingKey string `json:"shardingKey,omitempty" yaml:"shardingKey,omitempty"`
+	// Identity is the identity of this dispatcher in the shard membership, defaulting to the hostname with a random suffix.
+	Identity string `json:"identity,omitempty" yaml:"identity,omitempty"`
+	// LeaseNamespace is the namespace of the shard membership leases, defaulting to the namespace of leader election.
+	LeaseNamespace string `json:"leaseNamespace,omitempty" yaml:"leaseNamespace,omitempty"`
+	// LeaseDurationSeconds is the duration after which a dispatcher which stops renewing its lease loses its shard.
+	LeaseDurationSeconds int64 `json:"leaseDurationSeconds,omitempty" yaml:"leaseDurationSeconds,omitempty"`
+	// RenewIntervalSeconds is the interval of renewing the lease and refreshing the shard membership.
+	RenewIntervalSeconds int64 `json:"renewIntervalSeconds,omitempty" yaml:"renewIntervalSeconds,omitempty"`
+}
+
+// TenantAdmissionConfiguration configures the token bucket admission of pending pods, each namespace is
+// a tenant and has one token bucket for each priority band. Pods exceeding the rate are kept in the pending
+// queue until they are admitted.
+type TenantAdmissionConfiguration struct {
+	// QPS is the number of pods admitted per second for each tenant, 0 means no limit.
+	QPS float64 `json:"qps,omitempty" yaml:"qps,omitempty"`
+	// Burst is the max number of pods admitted at once for each tenant.
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+	// PriorityBands overrides QPS and Burst for the pods in the bands, a pod belongs to the band with the
+	// highest MinPriority not greater than its priority.
+	PriorityBands []PriorityBandAdmission `json:"priorityBands,omitempty" yaml:"priorityBands,omitempty"`
+}
+
+// PriorityBandAdmission is the admission rate of the pods in one priority band.
+type PriorityBandAdmission struct {
+	// MinPriority is the lower bound of the priority of the pods in the band.
+	MinPriority int32 `json:"minPriority" yaml:"minPriority"`
+	// QPS is the number of pods admitted per second for each tenant in the band, 0 means no limit.
+	QPS float64 `json:"qps,omitempty" yaml:"qps,omitempty"`
+	// Burst is the max number of pods admitted at once for each tenant in the band.
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
 }
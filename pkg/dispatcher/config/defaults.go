@@ -32,6 +32,15 @@ const (
 	DefaultInsecureBinderPort          = 10351
 
 	DispatcherDefaultLockObjectName = "dispatcher"
+
+	// DispatcherShardingByNamespace assigns the pending pods to shards by the hash of their namespaces
+	DispatcherShardingByNamespace = "Namespace"
+	// DispatcherShardingByPodGroup assigns the pending pods to shards by the hash of their PodGroups
+	DispatcherShardingByPodGroup = "PodGroup"
+	// DefaultDispatcherShardingLeaseDurationSeconds is the default duration of the shard membership leases
+	DefaultDispatcherShardingLeaseDurationSeconds = 15
+	// DefaultDispatcherShardingRenewIntervalSeconds is the default interval of renewing the shard membership leases
+	DefaultDispatcherShardingRenewIntervalSeconds = 5
 )
 
 func SetDefaults(cfg *GodelDispatcherConfiguration) {
@@ -48,6 +57,26 @@ func SetDefaults(cfg *GodelDispatcherConfiguration) {
 		cfg.Tracer = tracing.DefaultNoopOptions()
 	}
 
+	if len(cfg.NodePartitionType) == 0 {
+		cfg.NodePartitionType = defaultsconfig.DefaultNodePartitionType
+	}
+
+	if cfg.TenantAdmission == nil {
+		cfg.TenantAdmission = &TenantAdmissionConfiguration{}
+	}
+	if cfg.Sharding == nil {
+		cfg.Sharding = &DispatcherShardingConfiguration{}
+	}
+	if len(cfg.Sharding.ShardingKey) == 0 {
+		cfg.Sharding.ShardingKey = DispatcherShardingByNamespace
+	}
+	if cfg.Sharding.LeaseDurationSeconds == 0 {
+		cfg.Sharding.LeaseDurationSeconds = DefaultDispatcherShardingLeaseDurationSeconds
+	}
+	if cfg.Sharding.RenewIntervalSeconds == 0 {
+		cfg.Sharding.RenewIntervalSeconds = DefaultDispatcherShardingRenewIntervalSeconds
+	}
+
 	// Scheduler has an opinion about QPS/Burst, setting specific defaults for itself, instead of generic settings.
 	if cfg.ClientConnection.QPS == 0.0 {
 		cfg.ClientConnection.QPS = DefaultClientConnectionQPS
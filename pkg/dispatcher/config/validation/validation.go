@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
 	godelvalidation "github.com/kubewharf/godel-scheduler/pkg/util/validation"
 )
@@ -34,6 +35,33 @@ func ValidateGodelDispatcherConfiguration(cc *config.GodelDispatcherConfiguratio
 			cc.SchedulerName, "can not be nil"))
 	}
 
+	profileSchedulerNames := make(map[string]bool, len(cc.ProfileSchedulerNames))
+	for i, name := range cc.ProfileSchedulerNames {
+		fldPath := field.NewPath("profileSchedulerNames").Index(i)
+		switch {
+		case len(name) == 0:
+			errs = append(errs, field.Required(fldPath, ""))
+		case cc.SchedulerName != nil && name == *cc.SchedulerName:
+			errs = append(errs, field.Invalid(fldPath, name, "must be different from schedulerName"))
+		case profileSchedulerNames[name]:
+			errs = append(errs, field.Duplicate(fldPath, name))
+		}
+		profileSchedulerNames[name] = true
+	}
+
+	if cc.NodePartitionType != defaultsconfig.NodePartitionTypePhysical && cc.NodePartitionType != defaultsconfig.NodePartitionTypeLogical {
+		errs = append(errs, field.NotSupported(field.NewPath("nodePartitionType"), cc.NodePartitionType,
+			[]string{defaultsconfig.NodePartitionTypePhysical, defaultsconfig.NodePartitionTypeLogical}))
+	}
+
+	if cc.TenantAdmission != nil {
+		errs = append(errs, validateTenantAdmission(cc.TenantAdmission, field.NewPath("tenantAdmission"))...)
+	}
+
+	if cc.Sharding != nil && cc.Sharding.Enable {
+		errs = append(errs, validateSharding(cc.Sharding, field.NewPath("sharding"))...)
+	}
+
 	for _, msg := range validation.IsValidSocketAddr(cc.HealthzBindAddress) {
 		errs = append(errs, field.Invalid(field.NewPath("healthzBindAddress"), cc.HealthzBindAddress, msg))
 	}
@@ -43,3 +71,45 @@ func ValidateGodelDispatcherConfiguration(cc *config.GodelDispatcherConfiguratio
 
 	return errs
 }
+
+func validateSharding(cfg *config.DispatcherShardingConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if cfg.ShardingKey != config.DispatcherShardingByNamespace && cfg.ShardingKey != config.DispatcherShardingByPodGroup {
+		errs = append(errs, field.NotSupported(fldPath.Child("shardingKey"), cfg.ShardingKey,
+			[]string{config.DispatcherShardingByNamespace, config.DispatcherShardingByPodGroup}))
+	}
+	if cfg.RenewIntervalSeconds <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("renewIntervalSeconds"),
+			cfg.RenewIntervalSeconds, "must be greater than 0"))
+	}
+	if cfg.LeaseDurationSeconds <= cfg.RenewIntervalSeconds {
+		errs = append(errs, field.Invalid(fldPath.Child("leaseDurationSeconds"),
+			cfg.LeaseDurationSeconds, "must be greater than renewIntervalSeconds"))
+	}
+	return errs
+}
+
+func validateTenantAdmission(cfg *config.TenantAdmissionConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := validateAdmissionRate(cfg.QPS, cfg.Burst, fldPath)
+	priorities := make(map[int32]bool, len(cfg.PriorityBands))
+	for i, band := range cfg.PriorityBands {
+		bandPath := fldPath.Child("priorityBands").Index(i)
+		if priorities[band.MinPriority] {
+			errs = append(errs, field.Duplicate(bandPath.Child("minPriority"), band.MinPriority))
+		}
+		priorities[band.MinPriority] = true
+		errs = append(errs, validateAdmissionRate(band.QPS, band.Burst, bandPath)...)
+	}
+	return errs
+}
+
+func validateAdmissionRate(qps float64, burst int, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if qps < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("qps"), qps, "must be non-negative"))
+	}
+	if burst < 0 || (qps > 0 && burst == 0) {
+		errs = append(errs, field.Invalid(fldPath.Child("burst"), burst, "must be positive when qps is set"))
+	}
+	return errs
+}
@@ -30,6 +30,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apimachinery/pkg/util/wait"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -41,6 +42,7 @@ import (
 	"k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 
+	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/queue"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/store"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
@@ -51,6 +53,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/util"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
 )
@@ -90,11 +93,22 @@ type Dispatcher struct {
 	maintainer *schemaintainer.SchedulerMaintainer
 	shuffler   *nodeshuffler.NodeShuffler
 
+	// matchedSchedulers caches the schedulers owning nodes matching the node constraints of pods
+	// in Physical partition mode, so that the nodes are not scanned for every pod.
+	matchedSchedulers *utilcache.LRUExpireCache
+
 	reconciler *reconciler.PodStateReconciler
 
 	// SchedulerName here is the higher level scheduler name, which is used to select pods
 	// that godel schedulers should be responsible for and filter out irrelevant pods.
 	SchedulerName string
+	// schedulerNames are SchedulerName and the scheduler names of the schedulerName profiles.
+	schedulerNames []string
+
+	// shardManager decides the pending pods dispatched by this dispatcher when multiple dispatchers are
+	// running as shards, it is nil if the dispatcher dispatches all the pods.
+	shardManager *shard.Manager
+	shardKeyFunc ShardKeyFunc
 
 	recorder events.EventRecorder
 }
@@ -110,37 +124,60 @@ func New(
 	podGroupInformer schedulinginformer.PodGroupInformer,
 	priorityClassInformer schedinformers.PriorityClassInformer,
 	schedulerName string,
+	profileSchedulerNames []string,
+	nodePartitionType string,
+	tenantAdmission *dispatcherconfig.TenantAdmissionConfiguration,
+	shardManager *shard.Manager,
+	shardKeyFunc ShardKeyFunc,
 	recorder events.EventRecorder,
 ) *Dispatcher {
 	metrics.Register()
 
+	schedulerNames := append([]string{schedulerName}, profileSchedulerNames...)
+
 	maintainer := schemaintainer.NewSchedulerMaintainer(crdClient, schedulerInformer.Lister())
+	if len(nodePartitionType) > 0 {
+		maintainer.NodePartitionType = nodePartitionType
+	}
 	shuffler := nodeshuffler.NewNodeShuffler(client, crdClient, nodeInformer.Lister(), nmNodeInformer.Lister(), schedulerInformer.Lister(), maintainer)
 
+	var pendingQueue queue.PendingQueue = queue.NewPendingFIFO(metrics.NewPendingPodsRecorder("pending"))
+	if admission := queue.NewTenantAdmission(tenantAdmission); admission != nil {
+		pendingQueue = queue.NewAdmissionPendingQueue(pendingQueue.(*queue.PendingFIFO), admission)
+	}
+
 	dispatcher := &Dispatcher{
 		StopEverything:       stopCh,
 		client:               client,
 		podLister:            podInformer.Lister(),
 		UnitInfos:            queue.NewUnitInfos(recorder),
-		FIFOPendingPodsQueue: queue.NewPendingFIFO(metrics.NewPendingPodsRecorder("pending")),
+		FIFOPendingPodsQueue: pendingQueue,
 		SortedPodsQueue:      queue.NewSortedFIFO(metrics.NewPendingPodsRecorder("ready")),
 		DispatchInfo:         store.NewDispatchInfo(),
 		SchedulerLister:      schedulerInformer.Lister(),
 
-		maintainer:    maintainer,
-		shuffler:      shuffler,
-		SchedulerName: schedulerName,
+		maintainer:        maintainer,
+		shuffler:          shuffler,
+		matchedSchedulers: newMatchedSchedulersCache(),
+		SchedulerName:     schedulerName,
+		schedulerNames:    schedulerNames,
 
 		NodeLister:          nodeInformer.Lister(),
 		NMNodeLister:        nmNodeInformer.Lister(),
 		PodGroupLister:      podGroupInformer.Lister(),
 		PriorityClassLister: priorityClassInformer.Lister(),
 
+		shardManager: shardManager,
+		shardKeyFunc: shardKeyFunc,
+
 		recorder: recorder,
 	}
+	if dispatcher.shardManager != nil {
+		dispatcher.shardManager.AddMembershipChangedHandler(dispatcher.onShardMembershipChanged)
+	}
 
 	reconciler := reconciler.NewPodStateReconciler(client, podInformer.Lister(), nodeInformer.Lister(),
-		schedulerInformer.Lister(), nmNodeInformer.Lister(), schedulerName, dispatcher.DispatchInfo, maintainer)
+		schedulerInformer.Lister(), nmNodeInformer.Lister(), schedulerNames, dispatcher.DispatchInfo, maintainer)
 
 	dispatcher.reconciler = reconciler
 
@@ -154,6 +191,13 @@ func New(
 }
 
 func (d *Dispatcher) Run(ctx context.Context) {
+	d.RunShard(ctx)
+	d.RunLeader(ctx)
+}
+
+// RunShard starts dispatching the pending pods of this dispatcher, it is run by every dispatcher
+// when multiple dispatchers are running as shards.
+func (d *Dispatcher) RunShard(ctx context.Context) {
 	// TODO: move to policy manager
 	go d.UnitInfos.Run(d.StopEverything)
 
@@ -168,13 +212,18 @@ func (d *Dispatcher) Run(ctx context.Context) {
 
 	go d.maintainer.Run(d.StopEverything)
 
+	go wait.UntilWithContext(ctx, d.pendingLoop, 0)
+	go wait.UntilWithContext(ctx, d.pendingUnitPodsLoop, 0)
+}
+
+// RunLeader starts the workers updating nodes, abnormal pods and Scheduler CRDs, which must be run by the leader only.
+func (d *Dispatcher) RunLeader(ctx context.Context) {
+	go d.maintainer.RunCleanup(d.StopEverything)
+
 	if utilfeature.DefaultFeatureGate.Enabled(features.DispatcherNodeShuffle) {
 		go d.shuffler.Run(d.StopEverything)
 	}
 
-	go wait.UntilWithContext(ctx, d.pendingLoop, 0)
-	go wait.UntilWithContext(ctx, d.pendingUnitPodsLoop, 0)
-
 	go d.reconciler.Run(d.StopEverything)
 }
 
@@ -244,8 +293,9 @@ func (d *Dispatcher) dispatchingPod(ctx context.Context, podInfo *queue.QueuedPo
 
 	pod, err := d.podLister.Pods(namespace).Get(name)
 	if apierrs.IsNotFound(err) || pod.DeletionTimestamp != nil ||
-		!podutil.PendingPodOfGodel(pod, d.SchedulerName) {
-		// podInfo was deleted before or is being deleted, or is not in pending state now
+		!podutil.PendingPodOfGodel(pod, d.schedulerNames...) || !d.ownsPod(pod) {
+		// podInfo was deleted before or is being deleted, or is not in pending state now,
+		// or has been handed over to another shard
 		// return directly without re-enqueuing the podInfo
 		return
 	}
@@ -422,6 +472,20 @@ func (d *Dispatcher) pickScheduler(pod *v1.Pod) (string, error) {
 }
 
 func (d *Dispatcher) loadBalancing(pod *v1.Pod) (string, error) {
+	if d.isPhysicalPartition() {
+		// in physical mode, pods can only be dispatched to the schedulers owning the nodes they can run on
+		candidates := d.getSchedulersOwningMatchedNodes(pod)
+		if candidates.Len() == 0 {
+			metrics.PodsWithoutMatchedPartitionInc()
+			return "", fmt.Errorf("no scheduler owns nodes matching the node selector and affinity of the pod")
+		}
+		if schedulerName := d.DispatchInfo.GetMostIdleSchedulerAmongAndAddPodInAdvance(pod, candidates); len(schedulerName) == 0 {
+			return "", fmt.Errorf("no scheduler registered among %v", candidates.List())
+		} else {
+			return schedulerName, nil
+		}
+	}
+
 	if schedulerName := d.DispatchInfo.GetMostIdleSchedulerAndAddPodInAdvance(pod); len(schedulerName) == 0 {
 		return "", fmt.Errorf("no scheduler registered")
 	} else {
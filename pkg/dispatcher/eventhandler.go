@@ -31,6 +31,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/features"
 	frwkutils "github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
@@ -184,7 +185,7 @@ func (d *Dispatcher) addPodToDispatchedInfo(obj interface{}) {
 
 	d.DispatchInfo.AddPod(pod)
 
-	if podutil.DispatchedPodOfGodel(pod, d.SchedulerName) {
+	if podutil.DispatchedPodOfGodel(pod, d.schedulerNames...) {
 		schedulerName := pod.Annotations[podutil.SchedulerAnnotationKey]
 		metrics.PodsInPartitionSizeInc(schedulerName, string(podutil.PodDispatched))
 	}
@@ -200,7 +201,7 @@ func (d *Dispatcher) deletePodFromDispatchedInfo(obj interface{}) {
 	klog.V(3).InfoS("Detected a Delete event for the dispatched pod", "pod", klog.KObj(pod))
 	d.DispatchInfo.RemovePod(pod)
 
-	if podutil.DispatchedPodOfGodel(pod, d.SchedulerName) {
+	if podutil.DispatchedPodOfGodel(pod, d.schedulerNames...) {
 		schedulerName := pod.Annotations[podutil.SchedulerAnnotationKey]
 		metrics.PodsInPartitionSizeDec(schedulerName, string(podutil.PodDispatched))
 	}
@@ -220,10 +221,10 @@ func AddAllEventHandlers(
 			FilterFunc: func(obj interface{}) bool {
 				switch t := obj.(type) {
 				case *v1.Pod:
-					return podutil.PendingPodOfGodel(t, dispatcher.SchedulerName)
+					return podutil.PendingPodOfGodel(t, dispatcher.schedulerNames...) && dispatcher.ownsPod(t)
 				case cache.DeletedFinalStateUnknown:
 					if pod, ok := t.Obj.(*v1.Pod); ok {
-						return podutil.PendingPodOfGodel(pod, dispatcher.SchedulerName)
+						return podutil.PendingPodOfGodel(pod, dispatcher.schedulerNames...) && dispatcher.ownsPod(pod)
 					}
 					klog.InfoS("Failed to convert object to *v1.Pod", "object", obj, "component", dispatcher)
 					return false
@@ -240,16 +241,17 @@ func AddAllEventHandlers(
 		},
 	)
 
-	// dispatched pods queue
+	// dispatched pods queue, the pods dispatched by other shards are included, so that the load
+	// of the schedulers is balanced by all the dispatchers
 	podInformer.Informer().AddEventHandler(
 		cache.FilteringResourceEventHandler{
 			FilterFunc: func(obj interface{}) bool {
 				switch t := obj.(type) {
 				case *v1.Pod:
-					return podutil.DispatchedPodOfGodel(t, dispatcher.SchedulerName)
+					return podutil.DispatchedPodOfGodel(t, dispatcher.schedulerNames...)
 				case cache.DeletedFinalStateUnknown:
 					if pod, ok := t.Obj.(*v1.Pod); ok {
-						return podutil.DispatchedPodOfGodel(pod, dispatcher.SchedulerName)
+						return podutil.DispatchedPodOfGodel(pod, dispatcher.schedulerNames...)
 					}
 					klog.InfoS("Failed to convert object to *v1.Pod", "object", obj, "component", dispatcher)
 					return false
@@ -326,7 +328,9 @@ func (d *Dispatcher) addScheduler(obj interface{}) {
 
 	klog.V(3).InfoS("Started to add scheduler", "schedulerName", scheduler.Name)
 
-	d.DispatchInfo.AddScheduler(scheduler.Name)
+	if !util.IsSchedulerDraining(scheduler) {
+		d.DispatchInfo.AddScheduler(scheduler.Name)
+	}
 	d.maintainer.AddScheduler(scheduler)
 }
 
@@ -344,6 +348,19 @@ func (d *Dispatcher) updateScheduler(oldObj, newObj interface{}) {
 
 	d.maintainer.UpdateScheduler(oldScheduler, newScheduler)
 
+	wasDraining, draining := util.IsSchedulerDraining(oldScheduler), util.IsSchedulerDraining(newScheduler)
+	if draining && !wasDraining {
+		// stop dispatching new pods to the draining scheduler, and hand its nodes over to other schedulers
+		klog.InfoS("Scheduler started draining", "schedulerName", newScheduler.Name)
+		d.DispatchInfo.DeleteScheduler(newScheduler.Name)
+		if utilfeature.DefaultFeatureGate.Enabled(features.DispatcherNodeShuffle) {
+			d.shuffler.DrainScheduler(newScheduler.Name)
+		}
+	} else if !draining && wasDraining {
+		klog.InfoS("Scheduler stopped draining", "schedulerName", newScheduler.Name)
+		d.DispatchInfo.AddScheduler(newScheduler.Name)
+	}
+
 	klog.V(3).InfoS("Updated scheduler", "schedulerName", oldScheduler.Name)
 }
 
@@ -555,7 +572,7 @@ func (d *Dispatcher) addPodToAbnormalQueue(obj interface{}) {
 		return
 	}
 
-	if abnormal := podutil.AbnormalPodStateOfGodel(pod, d.SchedulerName); abnormal {
+	if abnormal := podutil.AbnormalPodStateOfGodel(pod, d.schedulerNames...); abnormal {
 		podKey, err := cache.MetaNamespaceKeyFunc(pod)
 		if err == nil {
 			d.reconciler.AbnormalPodsEnqueue(podKey)
@@ -571,7 +588,7 @@ func (d *Dispatcher) updatePodInAbnormalQueue(_, newObj interface{}) {
 		klog.InfoS("Failed to add pod to dispatched", "err", err)
 		return
 	}
-	if abnormal := podutil.AbnormalPodStateOfGodel(newPod, d.SchedulerName); abnormal {
+	if abnormal := podutil.AbnormalPodStateOfGodel(newPod, d.schedulerNames...); abnormal {
 		podKey, err := cache.MetaNamespaceKeyFunc(newPod)
 		if err == nil {
 			d.reconciler.AbnormalPodsEnqueue(podKey)
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+)
+
+const (
+	defaultPriorityBand = "default"
+
+	// highestUserDefinablePriority is the highest priority for user defined priority classes, pods with higher
+	// priority are system critical pods and never limited by TenantAdmission.
+	highestUserDefinablePriority = int32(1000000000)
+)
+
+// TenantAdmission limits the rate of dispatching pods for each tenant by token buckets, each namespace is a
+// tenant and has one token bucket for each priority band.
+type TenantAdmission struct {
+	defaultBand config.PriorityBandAdmission
+	// bands are sorted by MinPriority in descending order.
+	bands []config.PriorityBandAdmission
+
+	lock     sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewTenantAdmission returns nil if no rate limit is configured.
+func NewTenantAdmission(cfg *config.TenantAdmissionConfiguration) *TenantAdmission {
+	if cfg == nil {
+		return nil
+	}
+	enabled := cfg.QPS > 0
+	bands := make([]config.PriorityBandAdmission, len(cfg.PriorityBands))
+	for i, band := range cfg.PriorityBands {
+		bands[i] = band
+		enabled = enabled || band.QPS > 0
+	}
+	if !enabled {
+		return nil
+	}
+	sort.Slice(bands, func(i, j int) bool {
+		return bands[i].MinPriority > bands[j].MinPriority
+	})
+	return &TenantAdmission{
+		defaultBand: config.PriorityBandAdmission{QPS: cfg.QPS, Burst: cfg.Burst},
+		bands:       bands,
+		limiters:    make(map[string]*rate.Limiter),
+	}
+}
+
+// PriorityBand returns the admission rate and the name of the priority band the pod belongs to.
+func (a *TenantAdmission) PriorityBand(podInfo *QueuedPodInfo) (config.PriorityBandAdmission, string) {
+	if podInfo.PodProperty != nil {
+		for _, band := range a.bands {
+			if podInfo.PodProperty.Priority >= band.MinPriority {
+				return band, strconv.Itoa(int(band.MinPriority))
+			}
+		}
+	}
+	return a.defaultBand, defaultPriorityBand
+}
+
+// Reserve reserves the admission of the pod at now, nil means the pod is not limited.
+func (a *TenantAdmission) Reserve(podInfo *QueuedPodInfo, now time.Time) *rate.Reservation {
+	if podInfo.SkipTenantAdmission || podInfo.PodProperty == nil || podInfo.PodProperty.Priority > highestUserDefinablePriority {
+		return nil
+	}
+	band, bandName := a.PriorityBand(podInfo)
+	if band.QPS <= 0 {
+		return nil
+	}
+
+	key := podInfo.PodProperty.Namespace + "/" + bandName
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	limiter, ok := a.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(band.QPS), band.Burst)
+		a.limiters[key] = limiter
+	}
+	return limiter.ReserveN(now, 1)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	dispatchermetrics "github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
+)
+
+// throttledQueue is the queue label of the throttled pods in metrics.
+const throttledQueue = "throttled"
+
+type throttledPodInfo struct {
+	podInfo     *QueuedPodInfo
+	reservation *rate.Reservation
+}
+
+// AdmissionPendingQueue is a PendingQueue limiting the rate of popping pods by TenantAdmission. Pods
+// exceeding the rate are throttled and kept in the queue, and they will be popped once admitted.
+type AdmissionPendingQueue struct {
+	fifo      *PendingFIFO
+	admission *TenantAdmission
+
+	lock sync.Mutex
+	// throttled holds the pods waiting for admission.
+	throttled map[string]*throttledPodInfo
+	// admitted holds the pods which have been admitted and added back to fifo.
+	admitted map[string]bool
+	// waiting adds the throttled pods back to fifo once they are admitted.
+	waiting workqueue.DelayingInterface
+
+	now func() time.Time
+}
+
+var _ = PendingQueue(&AdmissionPendingQueue{})
+
+func NewAdmissionPendingQueue(fifo *PendingFIFO, admission *TenantAdmission) *AdmissionPendingQueue {
+	q := &AdmissionPendingQueue{
+		fifo:      fifo,
+		admission: admission,
+		throttled: make(map[string]*throttledPodInfo),
+		admitted:  make(map[string]bool),
+		waiting:   workqueue.NewNamedDelayingQueue("tenant-admission"),
+		now:       time.Now,
+	}
+	go q.run()
+	return q
+}
+
+func (q *AdmissionPendingQueue) AddPodInfo(podInfo *QueuedPodInfo) error {
+	if q.updateThrottled(podInfo) {
+		return nil
+	}
+	return q.fifo.AddPodInfo(podInfo)
+}
+
+func (q *AdmissionPendingQueue) UpdatePodInfo(podInfo *QueuedPodInfo) error {
+	if q.updateThrottled(podInfo) {
+		return nil
+	}
+	return q.fifo.UpdatePodInfo(podInfo)
+}
+
+func (q *AdmissionPendingQueue) RemovePodInfo(podInfo *QueuedPodInfo) error {
+	q.lock.Lock()
+	if throttled, ok := q.throttled[podInfo.PodKey]; ok {
+		// Return the token reserved by the pod, so that the following pods of the tenant can be admitted earlier.
+		throttled.reservation.CancelAt(q.now())
+		delete(q.throttled, podInfo.PodKey)
+		dispatchermetrics.PendingPodsDec(throttled.podInfo.PodProperty, throttledQueue)
+	}
+	delete(q.admitted, podInfo.PodKey)
+	q.lock.Unlock()
+	return q.fifo.RemovePodInfo(podInfo)
+}
+
+// Pop blocks until an admitted pod is available.
+func (q *AdmissionPendingQueue) Pop() ([]*QueuedPodInfo, error) {
+	for {
+		podInfos, err := q.fifo.Pop()
+		if err != nil {
+			return nil, err
+		}
+		if podInfos = q.admit(podInfos); len(podInfos) > 0 {
+			return podInfos, nil
+		}
+	}
+}
+
+func (q *AdmissionPendingQueue) Close() {
+	q.waiting.ShutDown()
+	q.fifo.Close()
+}
+
+// admit returns the admitted pods, and the others are throttled.
+func (q *AdmissionPendingQueue) admit(podInfos []*QueuedPodInfo) []*QueuedPodInfo {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.now()
+	admitted := make([]*QueuedPodInfo, 0, len(podInfos))
+	for _, podInfo := range podInfos {
+		if q.admitted[podInfo.PodKey] {
+			delete(q.admitted, podInfo.PodKey)
+			admitted = append(admitted, podInfo)
+			continue
+		}
+		reservation := q.admission.Reserve(podInfo, now)
+		if reservation == nil {
+			admitted = append(admitted, podInfo)
+			continue
+		}
+		delay := reservation.DelayFrom(now)
+		if delay == 0 {
+			admitted = append(admitted, podInfo)
+			continue
+		}
+		_, band := q.admission.PriorityBand(podInfo)
+		dispatchermetrics.TenantAdmissionThrottledPodsInc(podInfo.PodProperty, band)
+		klog.V(4).InfoS("Throttled pod by tenant admission", "pod", podInfo.PodKey, "priorityBand", band, "delay", delay)
+		q.throttled[podInfo.PodKey] = &throttledPodInfo{podInfo: podInfo, reservation: reservation}
+		dispatchermetrics.PendingPodsInc(podInfo.PodProperty, throttledQueue)
+		q.waiting.AddAfter(podInfo.PodKey, delay)
+	}
+	return admitted
+}
+
+// updateThrottled updates the pod if it's throttled.
+func (q *AdmissionPendingQueue) updateThrottled(podInfo *QueuedPodInfo) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	throttled, ok := q.throttled[podInfo.PodKey]
+	if !ok {
+		return false
+	}
+	podInfo.Timestamp = throttled.podInfo.Timestamp
+	podInfo.InitialAddedTimestamp = throttled.podInfo.InitialAddedTimestamp
+	throttled.podInfo = podInfo
+	return true
+}
+
+// run adds the throttled pods back to fifo once they are admitted.
+func (q *AdmissionPendingQueue) run() {
+	for {
+		item, shutdown := q.waiting.Get()
+		if shutdown {
+			return
+		}
+		key := item.(string)
+		q.lock.Lock()
+		if throttled, ok := q.throttled[key]; ok {
+			delete(q.throttled, key)
+			dispatchermetrics.PendingPodsDec(throttled.podInfo.PodProperty, throttledQueue)
+			q.admitted[key] = true
+			if err := q.fifo.AddPodInfo(throttled.podInfo); err != nil {
+				klog.InfoS("Failed to add admitted pod back to the pending queue", "pod", key, "err", err)
+				delete(q.admitted, key)
+			}
+		}
+		q.lock.Unlock()
+		q.waiting.Done(item)
+	}
+}
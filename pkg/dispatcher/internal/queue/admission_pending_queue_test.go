@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+)
+
+func makeTenantPodInfo(namespace, name string, priority int32, skip bool) *QueuedPodInfo {
+	return &QueuedPodInfo{
+		PodKey:              namespace + "/" + name,
+		PodProperty:         &framework.PodProperty{Namespace: namespace, Priority: priority},
+		SkipTenantAdmission: skip,
+	}
+}
+
+func popPodKeys(t *testing.T, q PendingQueue, count int) []string {
+	var keys []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for len(keys) < count {
+			podInfos, err := q.Pop()
+			if err != nil {
+				return
+			}
+			for _, podInfo := range podInfos {
+				keys = append(keys, podInfo.PodKey)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("timeout waiting for %d pods", count)
+		return nil
+	}
+	return keys
+}
+
+func TestTenantAdmissionPriorityBand(t *testing.T) {
+	admission := NewTenantAdmission(&config.TenantAdmissionConfiguration{
+		QPS:   1,
+		Burst: 1,
+		PriorityBands: []config.PriorityBandAdmission{
+			{MinPriority: 100, QPS: 10, Burst: 10},
+			{MinPriority: 1000, QPS: 0},
+		},
+	})
+	tests := []struct {
+		priority int32
+		want     string
+		limited  bool
+	}{
+		{priority: 0, want: "default", limited: true},
+		{priority: 100, want: "100", limited: true},
+		{priority: 999, want: "100", limited: true},
+		{priority: 1000, want: "1000", limited: false},
+		{priority: 2000000000, want: "1000", limited: false},
+	}
+	for _, tt := range tests {
+		podInfo := makeTenantPodInfo("ns", "p", tt.priority, false)
+		if _, got := admission.PriorityBand(podInfo); got != tt.want {
+			t.Errorf("priority %v: expected band %v, got %v", tt.priority, tt.want, got)
+		}
+		if got := admission.Reserve(podInfo, time.Now()) != nil; got != tt.limited {
+			t.Errorf("priority %v: expected limited %v, got %v", tt.priority, tt.limited, got)
+		}
+	}
+
+	if NewTenantAdmission(&config.TenantAdmissionConfiguration{}) != nil {
+		t.Errorf("expected nil admission without rate limit")
+	}
+}
+
+func TestAdmissionPendingQueue(t *testing.T) {
+	metrics.Register()
+	tests := []struct {
+		name     string
+		podInfos []*QueuedPodInfo
+		want     []string
+		minDelay time.Duration
+	}{
+		{
+			name: "throttled pods don't block other tenants",
+			podInfos: []*QueuedPodInfo{
+				makeTenantPodInfo("a", "p1", 0, false),
+				makeTenantPodInfo("a", "p2", 0, false),
+				makeTenantPodInfo("a", "p3", 0, false),
+				makeTenantPodInfo("b", "p1", 0, false),
+			},
+			want:     []string{"a/p1", "b/p1", "a/p2", "a/p3"},
+			minDelay: 150 * time.Millisecond,
+		},
+		{
+			name: "pods of system workloads are not limited",
+			podInfos: []*QueuedPodInfo{
+				makeTenantPodInfo("a", "p1", 0, false),
+				makeTenantPodInfo("a", "p2", 0, true),
+				makeTenantPodInfo("a", "p3", 2000000000, false),
+				makeTenantPodInfo("a", "p4", 0, false),
+			},
+			want: []string{"a/p1", "a/p2", "a/p3", "a/p4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewAdmissionPendingQueue(NewPendingFIFO(metrics.NewPendingPodsRecorder("pending")),
+				NewTenantAdmission(&config.TenantAdmissionConfiguration{QPS: 10, Burst: 1}))
+			defer q.Close()
+
+			start := time.Now()
+			for _, podInfo := range tt.podInfos {
+				q.AddPodInfo(podInfo)
+			}
+			got := popPodKeys(t, q, len(tt.want))
+			if diff := cmp.Diff(tt.want, got); len(diff) > 0 {
+				t.Errorf("unexpected pods popped: %v", diff)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minDelay {
+				t.Errorf("expected pods to be throttled for at least %v, got %v", tt.minDelay, elapsed)
+			}
+		})
+	}
+}
+
+func TestAdmissionPendingQueueRemoveThrottledPod(t *testing.T) {
+	metrics.Register()
+	q := NewAdmissionPendingQueue(NewPendingFIFO(metrics.NewPendingPodsRecorder("pending")),
+		NewTenantAdmission(&config.TenantAdmissionConfiguration{QPS: 10, Burst: 1}))
+	defer q.Close()
+
+	p1, p2, p3 := makeTenantPodInfo("a", "p1", 0, false), makeTenantPodInfo("a", "p2", 0, false), makeTenantPodInfo("a", "p3", 0, false)
+	q.AddPodInfo(p1)
+	q.AddPodInfo(p2)
+	if diff := cmp.Diff([]string{"a/p1"}, popPodKeys(t, q, 1)); len(diff) > 0 {
+		t.Fatalf("unexpected pods popped: %v", diff)
+	}
+
+	got := make(chan []string)
+	go func() {
+		got <- popPodKeys(t, q, 1)
+	}()
+	// Wait until p2 is throttled.
+	if err := wait.Poll(time.Millisecond, time.Second, func() (bool, error) {
+		q.lock.Lock()
+		defer q.lock.Unlock()
+		return q.throttled[p2.PodKey] != nil, nil
+	}); err != nil {
+		t.Fatalf("pod is not throttled: %v", err)
+	}
+	q.RemovePodInfo(p2)
+	q.AddPodInfo(p3)
+	if diff := cmp.Diff([]string{"a/p3"}, <-got); len(diff) > 0 {
+		t.Errorf("unexpected pods popped: %v", diff)
+	}
+}
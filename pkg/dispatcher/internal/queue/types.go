@@ -65,6 +65,9 @@ type QueuedPodInfo struct {
 
 	// The property of the pod, which is used to describe the pod's attributes
 	PodProperty *framework.PodProperty
+
+	// SkipTenantAdmission indicates whether the pod is dispatched without being limited by the tenant admission rate.
+	SkipTenantAdmission bool
 }
 
 func (qi *QueuedPodInfo) GetPodProperty() *framework.PodProperty {
@@ -81,6 +84,8 @@ func NewQueuedPodInfo(pod *v1.Pod) (*QueuedPodInfo, error) {
 		PodResourceType: resourceType,
 		SpanContext:     tracing.GetSpanContextFromPod(pod),
 		PodProperty:     framework.ExtractPodProperty(pod),
+
+		SkipTenantAdmission: pod.Annotations[podutil.SkipTenantAdmissionAnnotationKey] == "true",
 	}, nil
 }
 
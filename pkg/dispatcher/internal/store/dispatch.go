@@ -99,6 +99,9 @@ type DispatchInfo interface {
 	AddPodInAdvance(pod *v1.Pod, scheduler string)
 	UpdatePodInAdvance(pod *v1.Pod, scheduler string)
 	GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) string
+	// GetMostIdleSchedulerAmongAndAddPodInAdvance is the same as GetMostIdleSchedulerAndAddPodInAdvance,
+	// but only the schedulers in candidates will be considered.
+	GetMostIdleSchedulerAmongAndAddPodInAdvance(pod *v1.Pod, candidates sets.String) string
 	AddScheduler(schedulerName string)
 	DeleteScheduler(schedulerName string)
 	GetPodsOfOneScheduler(schedulerName string) []string
@@ -227,6 +230,18 @@ func (dq *dispatchInfo) UpdatePodInAdvance(pod *v1.Pod, scheduler string) {
 }
 
 func (dq *dispatchInfo) GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) string {
+	return dq.getMostIdleSchedulerAndAddPodInAdvance(pod, nil)
+}
+
+func (dq *dispatchInfo) GetMostIdleSchedulerAmongAndAddPodInAdvance(pod *v1.Pod, candidates sets.String) string {
+	if candidates == nil {
+		candidates = sets.NewString()
+	}
+	return dq.getMostIdleSchedulerAndAddPodInAdvance(pod, candidates)
+}
+
+// getMostIdleSchedulerAndAddPodInAdvance picks the scheduler with the least pods, nil candidates means all schedulers.
+func (dq *dispatchInfo) getMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod, candidates sets.String) string {
 	dq.lock.Lock()
 	defer dq.lock.Unlock()
 
@@ -235,6 +250,9 @@ func (dq *dispatchInfo) GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) stri
 	// Ref: https://en.wikipedia.org/wiki/reservoir_sampling for more details about Reservoir Sampling.
 	var randomPoolSize int
 	for schedulerName := range dq.Schedulers {
+		if candidates != nil && !candidates.Has(schedulerName) {
+			continue
+		}
 		cnt := 0
 		if dq.SchedulerToPods[schedulerName] != nil {
 			cnt = dq.SchedulerToPods[schedulerName].Len()
@@ -222,3 +222,60 @@ func Test_dispatchInfo_GetMostIdleSchedulerAndAddPodInAdvance(t *testing.T) {
 		})
 	}
 }
+
+func Test_dispatchInfo_GetMostIdleSchedulerAmongAndAddPodInAdvance(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		schedulers  []string
+		candidates  sets.String
+		existedPods []*corev1.Pod
+		expected    string
+	}{
+		{
+			name:       "return most idle scheduler among candidates",
+			pod:        newSimplePodWithSchedulerName("test-ns", "pod0", ""),
+			schedulers: []string{"test-scheduler-0", "test-scheduler-1", "test-scheduler-2"},
+			candidates: sets.NewString("test-scheduler-0", "test-scheduler-1"),
+			existedPods: []*corev1.Pod{
+				newSimplePodWithSchedulerName("test-ns", "pod1", "test-scheduler-0"),
+				newSimplePodWithSchedulerName("test-ns", "pod2", "test-scheduler-0"),
+				newSimplePodWithSchedulerName("test-ns", "pod3", "test-scheduler-1"),
+			},
+			expected: "test-scheduler-1",
+		},
+		{
+			name:       "candidate is not registered",
+			pod:        newSimplePodWithSchedulerName("test-ns", "pod0", ""),
+			schedulers: []string{"test-scheduler-0"},
+			candidates: sets.NewString("test-scheduler-1"),
+			expected:   "",
+		},
+		{
+			name:       "no candidates",
+			pod:        newSimplePodWithSchedulerName("test-ns", "pod0", ""),
+			schedulers: []string{"test-scheduler-0"},
+			candidates: nil,
+			expected:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dq := NewDispatchInfo()
+			for _, scheduler := range tt.schedulers {
+				dq.AddScheduler(scheduler)
+			}
+
+			for _, pod := range tt.existedPods {
+				dq.AddPod(pod)
+			}
+
+			if got := dq.GetMostIdleSchedulerAmongAndAddPodInAdvance(tt.pod, tt.candidates); got != tt.expected {
+				t.Errorf("GetMostIdleSchedulerAmongAndAddPodInAdvance() = %v, expected %v", got, tt.expected)
+			}
+			if len(tt.expected) > 0 && !sets.NewString(dq.GetPodsOfOneScheduler(tt.expected)...).Has(tt.pod.Namespace+"/"+tt.pod.Name) {
+				t.Errorf("expected pod to be added to scheduler %v in advance", tt.expected)
+			}
+		})
+	}
+}
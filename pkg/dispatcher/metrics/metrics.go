@@ -51,13 +51,13 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		})
 
-	nodePartitionSize = metrics.NewGauge(
+	nodePartitionSize = metrics.NewGaugeVec(
 		&metrics.GaugeOpts{
 			Subsystem:      DispatcherSubsystem,
-			Name:           "existing_node_partitions",
-			Help:           "Number of node partitions in Godel Scheduler.",
+			Name:           "node_partition_size",
+			Help:           "Number of nodes in the node partition of each active scheduler in Godel Scheduler.",
 			StabilityLevel: metrics.ALPHA,
-		})
+		}, []string{pkgmetrics.SchedulerLabel})
 
 	e2eDispatchingLatency = metrics.NewHistogram(
 		&metrics.HistogramOpts{
@@ -68,6 +68,22 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		})
 
+	tenantAdmissionThrottledPods = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DispatcherSubsystem,
+			Name:           "tenant_admission_throttled_pods_total",
+			Help:           "Number of pods throttled by the tenant admission rate limit, by namespace and priority band.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.NamespaceLabel, pkgmetrics.PriorityBandLabel})
+
+	podsWithoutMatchedPartition = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      DispatcherSubsystem,
+			Name:           "pods_without_matched_partition_total",
+			Help:           "Number of dispatching attempts failed because no node partition matches the pod in Physical mode.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
 	podShufflingCount = metrics.NewCounter(
 		&metrics.CounterOpts{
 			Subsystem:      DispatcherSubsystem,
@@ -208,6 +224,28 @@ func PodShufflingCountInc() {
 	podShufflingCount.Inc()
 }
 
+// NodePartitionSizesSet sets the number of nodes in the node partitions, by scheduler name.
+// The schedulers not in the sizes are dropped.
+func NodePartitionSizesSet(sizes map[string]int) {
+	nodePartitionSize.Reset()
+	for schedulerName, size := range sizes {
+		nodePartitionSize.WithLabelValues(schedulerName).Set(float64(size))
+	}
+}
+
+func PodsWithoutMatchedPartitionInc() {
+	podsWithoutMatchedPartition.Inc()
+}
+
+// TenantAdmissionThrottledPodsInc Invoke Inc method
+func TenantAdmissionThrottledPodsInc(podProperty *api.PodProperty, priorityBand string) {
+	namespace := pkgmetrics.UndefinedLabelValue
+	if podProperty != nil {
+		namespace = podProperty.Namespace
+	}
+	tenantAdmissionThrottledPods.WithLabelValues(namespace, priorityBand).Inc()
+}
+
 func E2EDispatchingLatencyQuantileObserve(duration float64) {
 	e2eDispatchingLatencyQuantile.Observe(duration)
 }
@@ -49,6 +49,8 @@ var metricsList = []metrics.Registerable{
 	dispatchingAttempts,
 	podUpdatingAttempts,
 	podShufflingCount,
+	podsWithoutMatchedPartition,
+	tenantAdmissionThrottledPods,
 	queueSortingLatency,
 
 	pendingUnits,
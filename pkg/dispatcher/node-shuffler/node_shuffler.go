@@ -126,6 +126,8 @@ const (
 	InactiveScheduler EnqueueReason = "InactiveScheduler"
 	// too many nodes in this scheduler's partition
 	TooManyNodesInThisPartition EnqueueReason = "TooManyNodesInThisPartition"
+	// node in draining scheduler's partition
+	DrainingScheduler EnqueueReason = "DrainingScheduler"
 )
 
 // NewNodeShuffler creates a new NodeShuffler struct
@@ -291,6 +293,18 @@ func (ns *NodeShuffler) ReBalanceSchedulerNodes() {
 	}
 }
 
+// DrainScheduler hands all nodes in the draining scheduler's partition over to other active schedulers
+func (ns *NodeShuffler) DrainScheduler(schedulerName string) {
+	nodeNames := ns.schedulerMaintainer.GetNodeNamesOfScheduler(schedulerName)
+	klog.V(3).InfoS("Started to hand over the nodes of draining scheduler", "schedulerName", schedulerName, "numberOfNodes", len(nodeNames))
+	for _, nodeName := range nodeNames {
+		ns.nodeProcessingQueue.Add(&NodeToBeProcessed{
+			nodeName: nodeName,
+			reason:   DrainingScheduler,
+		})
+	}
+}
+
 // SyncUpNodeAndCNR makes sure that node and cnr with same name share the same scheduler name annotation
 // TODO: sync up scheduler name in node and cnr
 func (ns *NodeShuffler) SyncUpNodeAndCNR() {}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+)
+
+const (
+	// matchedSchedulersCacheSize is the max number of node constraints whose matched schedulers are cached.
+	matchedSchedulersCacheSize = 1024
+	// matchedSchedulersTTL bounds how long the changes of node labels and partitions are not seen by the cached matches.
+	matchedSchedulersTTL = 5 * time.Second
+)
+
+func newMatchedSchedulersCache() *utilcache.LRUExpireCache {
+	return utilcache.NewLRUExpireCache(matchedSchedulersCacheSize)
+}
+
+// isPhysicalPartition checks whether the node partitions of schedulers are physical, which
+// means pods can only be placed on the nodes in the partition of the scheduler they are dispatched to.
+func (d *Dispatcher) isPhysicalPartition() bool {
+	return d.maintainer.NodePartitionType == defaultsconfig.NodePartitionTypePhysical
+}
+
+// getSchedulersOwningMatchedNodes returns the active schedulers whose partition contains
+// at least one node matching the node selector and required node affinity of the pod.
+// The result is cached by the node constraints for matchedSchedulersTTL, since the pods of
+// the same workload share them, the returned set must not be modified.
+func (d *Dispatcher) getSchedulersOwningMatchedNodes(pod *v1.Pod) sets.String {
+	key := getNodeConstraintsKey(pod)
+	if cached, ok := d.matchedSchedulers.Get(key); ok {
+		return cached.(sets.String)
+	}
+
+	candidates := sets.NewString()
+	for _, schedulerName := range d.maintainer.GetActiveSchedulers() {
+		for _, nodeName := range d.maintainer.GetNodeNamesOfScheduler(schedulerName) {
+			nodeLabels, ok := d.getNodeLabels(nodeName)
+			if ok && podMatchesNodeLabels(pod, nodeName, nodeLabels) {
+				candidates.Insert(schedulerName)
+				break
+			}
+		}
+	}
+	d.matchedSchedulers.Add(key, candidates, matchedSchedulersTTL)
+	return candidates
+}
+
+// getNodeConstraintsKey returns the key of the node selector and required node affinity of the pod.
+func getNodeConstraintsKey(pod *v1.Pod) string {
+	key := labels.SelectorFromSet(pod.Spec.NodeSelector).String()
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		key += "/" + affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.String()
+	}
+	return key
+}
+
+// getNodeLabels returns the labels of the node, NMNode labels will be used if the node doesn't exist.
+func (d *Dispatcher) getNodeLabels(nodeName string) (map[string]string, bool) {
+	if node, err := d.NodeLister.Get(nodeName); err == nil {
+		return node.Labels, true
+	}
+	if nmNode, err := d.NMNodeLister.Get(nodeName); err == nil {
+		return nmNode.Labels, true
+	}
+	return nil, false
+}
+
+// podMatchesNodeLabels checks whether the node matches the node selector and required node affinity of the pod.
+func podMatchesNodeLabels(pod *v1.Pod, nodeName string, nodeLabels map[string]string) bool {
+	if len(pod.Spec.NodeSelector) > 0 {
+		if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(nodeLabels)) {
+			return false
+		}
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	nodeSelectorTerms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	return helper.MatchNodeSelectorTerms(nodeSelectorTerms, labels.Set(nodeLabels), fields.Set{"metadata.name": nodeName})
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+
+	schedulerapi "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/store"
+	schemaintainer "github.com/kubewharf/godel-scheduler/pkg/dispatcher/scheduler-maintainer"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+)
+
+func TestPodMatchesNodeLabels(t *testing.T) {
+	nodeLabels := map[string]string{"group": "a", "zone": "z1"}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "pod without node selector and affinity",
+			pod:  testing_helper.MakePod().Namespace("default").Name("p").Obj(),
+			want: true,
+		},
+		{
+			name: "node selector matches",
+			pod:  testing_helper.MakePod().Namespace("default").Name("p").NodeSelector(map[string]string{"group": "a"}).Obj(),
+			want: true,
+		},
+		{
+			name: "node selector doesn't match",
+			pod:  testing_helper.MakePod().Namespace("default").Name("p").NodeSelector(map[string]string{"group": "b"}).Obj(),
+			want: false,
+		},
+		{
+			name: "required node affinity matches",
+			pod: testing_helper.MakePod().Namespace("default").Name("p").
+				NodeAffinityIn("zone", []string{"z1", "z2"}, testing_helper.NodeAffinityWithRequiredReq).Obj(),
+			want: true,
+		},
+		{
+			name: "required node affinity doesn't match",
+			pod: testing_helper.MakePod().Namespace("default").Name("p").
+				NodeAffinityIn("zone", []string{"z2"}, testing_helper.NodeAffinityWithRequiredReq).Obj(),
+			want: false,
+		},
+		{
+			name: "preferred node affinity is ignored",
+			pod: testing_helper.MakePod().Namespace("default").Name("p").
+				NodeAffinityIn("zone", []string{"z2"}, testing_helper.NodeAffinityWithPreferredReq).Obj(),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podMatchesNodeLabels(tt.pod, "n", nodeLabels); got != tt.want {
+				t.Errorf("podMatchesNodeLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBalancingInPhysicalPartition(t *testing.T) {
+	makeNode := func(name, group, scheduler string) *v1.Node {
+		node := testing_helper.MakeNode().Name(name).Label("group", group).Obj()
+		node.Annotations = map[string]string{nodeutil.GodelSchedulerNodeAnnotationKey: scheduler}
+		return node
+	}
+	nodes := []*v1.Node{
+		makeNode("n0", "a", "scheduler-0"),
+		makeNode("n1", "a", "scheduler-0"),
+		makeNode("n2", "b", "scheduler-1"),
+	}
+
+	tests := []struct {
+		name              string
+		nodePartitionType string
+		pod               *v1.Pod
+		want              []string
+		wantErr           bool
+	}{
+		{
+			name:              "physical partition routes pod to the scheduler owning matched nodes",
+			nodePartitionType: defaultsconfig.NodePartitionTypePhysical,
+			pod:               testing_helper.MakePod().Namespace("default").Name("p").NodeSelector(map[string]string{"group": "b"}).Obj(),
+			want:              []string{"scheduler-1"},
+		},
+		{
+			name:              "physical partition fails when no partition matches",
+			nodePartitionType: defaultsconfig.NodePartitionTypePhysical,
+			pod:               testing_helper.MakePod().Namespace("default").Name("p").NodeSelector(map[string]string{"group": "c"}).Obj(),
+			wantErr:           true,
+		},
+		{
+			name:              "logical partition ignores node selector",
+			nodePartitionType: defaultsconfig.NodePartitionTypeLogical,
+			pod:               testing_helper.MakePod().Namespace("default").Name("p").NodeSelector(map[string]string{"group": "c"}).Obj(),
+			want:              []string{"scheduler-0", "scheduler-1", "scheduler-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(client, 0)
+			crdClient := godelfake.NewSimpleClientset()
+			crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+
+			maintainer := schemaintainer.NewSchedulerMaintainer(crdClient, crdInformerFactory.Scheduling().V1alpha1().Schedulers().Lister())
+			maintainer.NodePartitionType = tt.nodePartitionType
+			dispatchInfo := store.NewDispatchInfo()
+			for _, name := range []string{"scheduler-0", "scheduler-1", "scheduler-2"} {
+				maintainer.AddScheduler(&schedulerapi.Scheduler{ObjectMeta: metav1.ObjectMeta{Name: name}})
+				dispatchInfo.AddScheduler(name)
+			}
+			for _, node := range nodes {
+				informerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(node)
+				maintainer.AddNodeToGodelSchedulerIfNotPresent(node)
+			}
+
+			d := &Dispatcher{
+				DispatchInfo:      dispatchInfo,
+				NodeLister:        informerFactory.Core().V1().Nodes().Lister(),
+				NMNodeLister:      crdInformerFactory.Node().V1alpha1().NMNodes().Lister(),
+				maintainer:        maintainer,
+				matchedSchedulers: newMatchedSchedulersCache(),
+			}
+			got, err := d.loadBalancing(tt.pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadBalancing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			found := false
+			for _, name := range tt.want {
+				if got == name {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("loadBalancing() = %v, want one of %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSchedulersOwningMatchedNodesCache(t *testing.T) {
+	makeNode := func(name, group, scheduler string) *v1.Node {
+		node := testing_helper.MakeNode().Name(name).Label("group", group).Obj()
+		node.Annotations = map[string]string{nodeutil.GodelSchedulerNodeAnnotationKey: scheduler}
+		return node
+	}
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdClient := godelfake.NewSimpleClientset()
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+
+	maintainer := schemaintainer.NewSchedulerMaintainer(crdClient, crdInformerFactory.Scheduling().V1alpha1().Schedulers().Lister())
+	maintainer.NodePartitionType = defaultsconfig.NodePartitionTypePhysical
+	for _, name := range []string{"scheduler-0", "scheduler-1"} {
+		maintainer.AddScheduler(&schedulerapi.Scheduler{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	addNode := func(node *v1.Node) {
+		informerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(node)
+		maintainer.AddNodeToGodelSchedulerIfNotPresent(node)
+	}
+	addNode(makeNode("n0", "a", "scheduler-0"))
+
+	d := &Dispatcher{
+		NodeLister:        informerFactory.Core().V1().Nodes().Lister(),
+		NMNodeLister:      crdInformerFactory.Node().V1alpha1().NMNodes().Lister(),
+		maintainer:        maintainer,
+		matchedSchedulers: newMatchedSchedulersCache(),
+	}
+	selectorPod := testing_helper.MakePod().Namespace("default").Name("p").NodeSelector(map[string]string{"group": "a"}).Obj()
+	if got := d.getSchedulersOwningMatchedNodes(selectorPod); !got.Equal(sets.NewString("scheduler-0")) {
+		t.Fatalf("expected [scheduler-0], got %v", got.List())
+	}
+
+	// The match of the same node constraints is served from the cache until it expires.
+	addNode(makeNode("n1", "a", "scheduler-1"))
+	samePod := testing_helper.MakePod().Namespace("default").Name("q").NodeSelector(map[string]string{"group": "a"}).Obj()
+	if got := d.getSchedulersOwningMatchedNodes(samePod); !got.Equal(sets.NewString("scheduler-0")) {
+		t.Errorf("expected the cached [scheduler-0], got %v", got.List())
+	}
+
+	// Different node constraints are matched against the nodes.
+	affinityPod := testing_helper.MakePod().Namespace("default").Name("r").
+		NodeAffinityIn("group", []string{"a"}, testing_helper.NodeAffinityWithRequiredReq).Obj()
+	if got := d.getSchedulersOwningMatchedNodes(affinityPod); !got.Equal(sets.NewString("scheduler-0", "scheduler-1")) {
+		t.Errorf("expected [scheduler-0 scheduler-1], got %v", got.List())
+	}
+}
@@ -32,18 +32,18 @@ import (
 
 // DispatchedPodsPopulator is the populator struct for collecting dispatched pods who are in inactive schedulers
 type DispatchedPodsPopulator struct {
-	schedulerName            string
+	schedulerNames           []string
 	podLister                v1.PodLister
 	staleDispatchedPodsQueue workqueue.Interface
 	schedulerMaintainer      *schemaintainer.SchedulerMaintainer
 }
 
 // NewDispatchedPodsPopulator creates a new DispatchedPodsPopulator struct
-func NewDispatchedPodsPopulator(schedulerName string, podLister v1.PodLister, queue workqueue.Interface,
+func NewDispatchedPodsPopulator(schedulerNames []string, podLister v1.PodLister, queue workqueue.Interface,
 	maintainer *schemaintainer.SchedulerMaintainer,
 ) *DispatchedPodsPopulator {
 	return &DispatchedPodsPopulator{
-		schedulerName:            schedulerName,
+		schedulerNames:           schedulerNames,
 		podLister:                podLister,
 		staleDispatchedPodsQueue: queue,
 		schedulerMaintainer:      maintainer,
@@ -65,9 +65,9 @@ func (dpp *DispatchedPodsPopulator) collectDispatchedPodsInInactiveSchedulers()
 	}
 
 	for _, pod := range pods {
-		if podutil.DispatchedPodOfGodel(pod, dpp.schedulerName) {
+		if podutil.DispatchedPodOfGodel(pod, dpp.schedulerNames...) {
 			schedulerName := pod.Annotations[podutil.SchedulerAnnotationKey]
-			if dpp.schedulerMaintainer.IsSchedulerInInactiveQueue(schedulerName) || !dpp.schedulerMaintainer.SchedulerExist(schedulerName) {
+			if dpp.schedulerMaintainer.IsSchedulerInInactiveQueue(schedulerName) && !dpp.schedulerMaintainer.IsSchedulerDraining(schedulerName) || !dpp.schedulerMaintainer.SchedulerExist(schedulerName) {
 				if podKey, err := cache.MetaNamespaceKeyFunc(pod); err != nil {
 					klog.InfoS("Failed to get the pod key in the dispatched pods populator", "pod", klog.KObj(pod), "err", err)
 				} else {
@@ -46,7 +46,7 @@ type PodStateReconciler struct {
 	nmNodeLister             nodelisterv1alpha1.NMNodeLister
 	abnormalPodsQueue        workqueue.Interface
 	staleDispatchedPodsQueue workqueue.Interface
-	schedulerName            string
+	schedulerNames           []string
 
 	dispatchedPodsStore store.DispatchInfo
 
@@ -60,15 +60,15 @@ func NewPodStateReconciler(client kubernetes.Interface,
 	nodeLister listerv1.NodeLister,
 	schedulerLister schedulerv1alpha1.SchedulerLister,
 	nmNodeLister nodelisterv1alpha1.NMNodeLister,
-	schedulerName string,
+	schedulerNames []string,
 	dispatchedPodsStore store.DispatchInfo,
 	maintainer *schemaintainer.SchedulerMaintainer,
 ) *PodStateReconciler {
 	staleDispatchedPodsQueue := workqueue.NewNamed("stale-dispatched-pods-queue")
-	populator := NewDispatchedPodsPopulator(schedulerName, podLister, staleDispatchedPodsQueue, maintainer)
+	populator := NewDispatchedPodsPopulator(schedulerNames, podLister, staleDispatchedPodsQueue, maintainer)
 
 	return &PodStateReconciler{
-		schedulerName:            schedulerName,
+		schedulerNames:           schedulerNames,
 		client:                   client,
 		podLister:                podLister,
 		nodeLister:               nodeLister,
@@ -146,9 +146,9 @@ func (psr *PodStateReconciler) StaleDispatchedPodsSyncer() {
 }
 
 func (psr *PodStateReconciler) updateStaleDispatchedStatePod(pod *corev1.Pod) error {
-	if podutil.DispatchedPodOfGodel(pod, psr.schedulerName) {
+	if podutil.DispatchedPodOfGodel(pod, psr.schedulerNames...) {
 		schedulerName := pod.Annotations[podutil.SchedulerAnnotationKey]
-		if psr.schedulerMaintainer.IsSchedulerInInactiveQueue(schedulerName) || !psr.schedulerMaintainer.SchedulerExist(schedulerName) {
+		if psr.schedulerMaintainer.IsSchedulerInInactiveQueue(schedulerName) && !psr.schedulerMaintainer.IsSchedulerDraining(schedulerName) || !psr.schedulerMaintainer.SchedulerExist(schedulerName) {
 			klog.V(3).InfoS("Reset the dispatched pod to Pending state on inactive/nonexistent scheduler", "pod", klog.KObj(pod), "schedulerName", schedulerName)
 			return psr.resetPodToPendingState(pod)
 		}
@@ -206,7 +206,7 @@ func (psr *PodStateReconciler) AbnormalStatePodsSyncer() {
 
 // updatePodState tries to update pod state if it is abnormal
 func (psr *PodStateReconciler) updateAbnormalStatePod(pod *corev1.Pod) error {
-	abnormal := podutil.AbnormalPodStateOfGodel(pod, psr.schedulerName)
+	abnormal := podutil.AbnormalPodStateOfGodel(pod, psr.schedulerNames...)
 	if abnormal {
 		klog.V(3).InfoS("Reset the abnormal pod to Pending state", "pod", klog.KObj(pod))
 		// pod is still abnormal
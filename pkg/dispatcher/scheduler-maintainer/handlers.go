@@ -23,6 +23,7 @@ import (
 
 	sche "github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/scheduler"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
 	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
 )
 
@@ -40,7 +41,13 @@ func (maintainer *SchedulerMaintainer) updateSchedulerBasedOnSchedulerCRD(schedu
 	if maintainer.generalSchedulers[scheduler.Name] == nil {
 		// schedulers are not added before, add it to active schedulers map directly
 		gs := sche.NewGodelSchedulerWithSchedulerCRD(scheduler)
-		metrics.SchedulerSizeInc(metrics.ActiveScheduler)
+		if util.IsSchedulerDraining(scheduler) {
+			// the draining schedulers should not be responsible for any pods or nodes
+			gs.SetSchedulerInActive()
+			metrics.SchedulerSizeInc(metrics.InactiveScheduler)
+		} else {
+			metrics.SchedulerSizeInc(metrics.ActiveScheduler)
+		}
 		maintainer.generalSchedulers[scheduler.Name] = gs
 		return
 	}
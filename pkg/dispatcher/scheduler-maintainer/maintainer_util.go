@@ -25,6 +25,7 @@ import (
 
 	sche "github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/scheduler"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
 )
 
 // ActivateScheduler moves schedulers from inactive queue to active queue
@@ -91,18 +92,23 @@ func (maintainer *SchedulerMaintainer) GetInactiveSchedulers() []string {
 	return inactiveSchedulers
 }
 
-type SchedulerNodePartitionType string
-
 const (
 	// if schedulers crd is not updated for 2 minutes (MaxSchedulerCRDNotUpdateDuration), schedulers will be considered as inactive
 	MaxSchedulerCRDNotUpdateDuration = 2 * time.Minute
-
-	Physical SchedulerNodePartitionType = "Physical"
-	Logical  SchedulerNodePartitionType = "Logical"
 )
 
-// IsSchedulerActive checks whether schedulers is active
+// IsSchedulerActive checks whether schedulers is active, a draining scheduler is not active any more
 func IsSchedulerActive(scheduler *schedulerapi.Scheduler) bool {
+	return isSchedulerAlive(scheduler) && !util.IsSchedulerDraining(scheduler)
+}
+
+// isSchedulerDrainingAndAlive checks whether the scheduler is still finishing its in-flight work before leaving
+func isSchedulerDrainingAndAlive(scheduler *schedulerapi.Scheduler) bool {
+	return util.IsSchedulerDraining(scheduler) && isSchedulerAlive(scheduler)
+}
+
+// isSchedulerAlive checks whether the status of scheduler is renewed recently
+func isSchedulerAlive(scheduler *schedulerapi.Scheduler) bool {
 	now := time.Now()
 	// TODO: need to figure out: if schedulers.Status.LastUpdateTime is not set, should we return ture ?
 	if scheduler.Status.LastUpdateTime == nil {
@@ -154,6 +160,16 @@ func (maintainer *SchedulerMaintainer) IsSchedulerInInactiveQueue(schedulerName
 	return maintainer.generalSchedulers[schedulerName] != nil && !maintainer.generalSchedulers[schedulerName].IsSchedulerActive()
 }
 
+// IsSchedulerDraining checks whether the schedulers is draining, its dispatched pods should not be reset
+// until it leaves or is not alive any more
+func (maintainer *SchedulerMaintainer) IsSchedulerDraining(schedulerName string) bool {
+	maintainer.schedulerMux.Lock()
+	defer maintainer.schedulerMux.Unlock()
+
+	gs := maintainer.generalSchedulers[schedulerName]
+	return gs != nil && gs.GetScheduler() != nil && isSchedulerDrainingAndAlive(gs.GetScheduler())
+}
+
 // IsSchedulerInActiveQueue checks whether the schedulers is active
 func (maintainer *SchedulerMaintainer) IsSchedulerInActiveQueue(schedulerName string) bool {
 	maintainer.schedulerMux.Lock()
@@ -272,3 +288,18 @@ func (maintainer *SchedulerMaintainer) GetSomeNodeNamesFromGeneralActiveSchedule
 	}
 	return nodeNames, nil
 }
+
+// GetNodeNamesOfScheduler returns the names of all nodes in the schedulers partition
+func (maintainer *SchedulerMaintainer) GetNodeNamesOfScheduler(schedulerName string) []string {
+	maintainer.schedulerMux.Lock()
+	defer maintainer.schedulerMux.Unlock()
+
+	if maintainer.generalSchedulers[schedulerName] == nil {
+		return nil
+	}
+	nodeNames := make([]string, 0, len(maintainer.generalSchedulers[schedulerName].GetNodes()))
+	for nodeName := range maintainer.generalSchedulers[schedulerName].GetNodes() {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	return nodeNames
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package scheduler_maintainer
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -24,7 +25,10 @@ import (
 	schedulerapi "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	"github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
 	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util"
 )
 
 func newSimpleActiveScheduler(schedulerName string) *schedulerapi.Scheduler {
@@ -54,6 +58,89 @@ func newSimpleInActiveScheduler(schedulerName string) *schedulerapi.Scheduler {
 	}
 }
 
+func newSimpleDrainingScheduler(schedulerName string, alive bool) *schedulerapi.Scheduler {
+	scheduler := newSimpleInActiveScheduler(schedulerName)
+	if alive {
+		scheduler = newSimpleActiveScheduler(schedulerName)
+	}
+	scheduler.Annotations = map[string]string{util.SchedulerDrainingAnnotationKey: time.Now().Format(time.RFC3339)}
+	return scheduler
+}
+
+func TestSchedulerMaintainer_SyncUpDrainingSchedulers(t *testing.T) {
+	tests := []struct {
+		name         string
+		scheduler    *schedulerapi.Scheduler
+		wantActive   bool
+		wantDraining bool
+		wantExist    bool
+	}{
+		{
+			name:       "active scheduler",
+			scheduler:  newSimpleActiveScheduler("test-scheduler"),
+			wantActive: true,
+			wantExist:  true,
+		},
+		{
+			name:         "draining scheduler is kept until it leaves",
+			scheduler:    newSimpleDrainingScheduler("test-scheduler", true),
+			wantDraining: true,
+			wantExist:    true,
+		},
+		{
+			name:      "draining scheduler is deleted once it is not alive",
+			scheduler: newSimpleDrainingScheduler("test-scheduler", false),
+			// it will be deactivated once the deletion is observed
+			wantActive: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCli := fake.NewSimpleClientset(tt.scheduler)
+			informerFactory := crdinformers.NewSharedInformerFactory(fakeCli, 0)
+			schedulerInformer := informerFactory.Scheduling().V1alpha1().Schedulers()
+			schedulerInformer.Informer().GetIndexer().Add(tt.scheduler)
+			maintainer := NewSchedulerMaintainer(fakeCli, schedulerInformer.Lister())
+			// the scheduler was added before it started draining
+			maintainer.AddScheduler(newSimpleActiveScheduler(tt.scheduler.Name))
+			maintainer.SyncUpSchedulersStatus()
+			maintainer.CleanupInActiveSchedulers()
+
+			if got := maintainer.IsSchedulerInActiveQueue(tt.scheduler.Name); got != tt.wantActive {
+				t.Errorf("IsSchedulerInActiveQueue() = %v, want %v", got, tt.wantActive)
+			}
+			maintainer.UpdateScheduler(tt.scheduler, tt.scheduler)
+			if got := maintainer.IsSchedulerDraining(tt.scheduler.Name); got != tt.wantDraining {
+				t.Errorf("IsSchedulerDraining() = %v, want %v", got, tt.wantDraining)
+			}
+			_, err := fakeCli.SchedulingV1alpha1().Schedulers().Get(context.TODO(), tt.scheduler.Name, metav1.GetOptions{})
+			if exist := !apierrors.IsNotFound(err); exist != tt.wantExist {
+				t.Errorf("Scheduler exists = %v, want %v", exist, tt.wantExist)
+			}
+		})
+	}
+}
+
+func TestSchedulerMaintainer_SyncUpSchedulersStatusReadOnly(t *testing.T) {
+	scheduler := newSimpleInActiveScheduler("test-scheduler")
+	fakeCli := fake.NewSimpleClientset(scheduler)
+	informerFactory := crdinformers.NewSharedInformerFactory(fakeCli, 0)
+	schedulerInformer := informerFactory.Scheduling().V1alpha1().Schedulers()
+	schedulerInformer.Informer().GetIndexer().Add(scheduler)
+	maintainer := NewSchedulerMaintainer(fakeCli, schedulerInformer.Lister())
+	maintainer.AddScheduler(newSimpleActiveScheduler(scheduler.Name))
+
+	// every dispatcher syncs up the status, only the leader deletes the inactive schedulers
+	maintainer.SyncUpSchedulersStatus()
+	if _, err := fakeCli.SchedulingV1alpha1().Schedulers().Get(context.TODO(), scheduler.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the inactive scheduler to be kept by SyncUpSchedulersStatus, got %v", err)
+	}
+	maintainer.CleanupInActiveSchedulers()
+	if _, err := fakeCli.SchedulingV1alpha1().Schedulers().Get(context.TODO(), scheduler.Name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the inactive scheduler to be deleted by CleanupInActiveSchedulers, got %v", err)
+	}
+}
+
 func TestSchedulerMaintainer_GetSchedulersWithMostAndLeastNumberOfNodes(t *testing.T) {
 	tests := []struct {
 		name             string
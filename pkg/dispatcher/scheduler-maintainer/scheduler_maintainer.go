@@ -29,7 +29,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	sche "github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/scheduler"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/metrics"
 )
 
 // TODO: figure out if we need a separate CRD for Node Partition
@@ -59,14 +61,15 @@ type SchedulerMaintainer struct {
 // NewSchedulerMaintainer creates a new SchedulerMaintainer struct object
 func NewSchedulerMaintainer(crdClient crdclient.Interface, schedulerLister schedulerlister.SchedulerLister) *SchedulerMaintainer {
 	return &SchedulerMaintainer{
-		NodePartitionType: string(Logical),
+		NodePartitionType: defaultsconfig.DefaultNodePartitionType,
 		crdClient:         crdClient,
 		schedulerLister:   schedulerLister,
 		generalSchedulers: make(map[string]*sche.GodelScheduler),
 	}
 }
 
-// Run runs all necessary workers
+// Run populates the schedulers and syncs up their status periodically, it only reads the Scheduler CRDs
+// so that it can be run by every dispatcher when multiple dispatchers are running as shards.
 func (maintainer *SchedulerMaintainer) Run(stopCh <-chan struct{}) {
 	// populate schedulers periodically
 	go wait.Until(maintainer.PopulateSchedulers, 1*time.Minute, stopCh)
@@ -76,6 +79,13 @@ func (maintainer *SchedulerMaintainer) Run(stopCh <-chan struct{}) {
 	<-stopCh
 }
 
+// RunCleanup deletes the inactive Scheduler CRDs periodically, which must be run by the leader only.
+func (maintainer *SchedulerMaintainer) RunCleanup(stopCh <-chan struct{}) {
+	go wait.Until(maintainer.CleanupInActiveSchedulers, 30*time.Second, stopCh)
+
+	<-stopCh
+}
+
 // PopulateSchedulers will populate existing schedulers to active queue
 func (maintainer *SchedulerMaintainer) PopulateSchedulers() {
 	schedulers, err := maintainer.schedulerLister.List(labels.Everything())
@@ -104,14 +114,11 @@ func (maintainer *SchedulerMaintainer) SyncUpSchedulersStatus() {
 			maintainer.DeactivateScheduler(schedulerName)
 			continue
 		}
-		if !IsSchedulerActive(scheduler) {
-			klog.V(3).InfoS("Started to delete the inactive schedulers", "schedulerName", schedulerName)
-			// schedulers is still there and it is not active, delete it.
-			err := maintainer.crdClient.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), scheduler.Name, metav1.DeleteOptions{})
-			if err != nil {
-				klog.InfoS("Failed to delete the inactive schedulers", "schedulerName", scheduler.Name, "err", err)
-			}
+		if !IsSchedulerActive(scheduler) && isSchedulerDrainingAndAlive(scheduler) {
+			// the draining schedulers will delete themselves once their in-flight work is finished
+			maintainer.DeactivateScheduler(schedulerName)
 		}
+		// the schedulers neither active nor draining are kept until the leader deletes them
 	}
 
 	inactiveSchedulers := maintainer.GetInactiveSchedulers()
@@ -127,19 +134,42 @@ func (maintainer *SchedulerMaintainer) SyncUpSchedulersStatus() {
 		}
 		if IsSchedulerActive(scheduler) {
 			maintainer.ActivateScheduler(scheduler.Name)
-		} else {
-			klog.V(3).InfoS("Started to delete the inactive schedulers", "schedulerName", schedulerName)
-			// schedulers is still there and it is not active, delete it.
-			err := maintainer.crdClient.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), scheduler.Name, metav1.DeleteOptions{})
-			if err != nil {
-				klog.InfoS("Failed to delete the inactive schedulers", "schedulerName", scheduler.Name, "err", err)
-			}
+		} else if isSchedulerDrainingAndAlive(scheduler) {
+			klog.V(4).InfoS("The schedulers was draining", "schedulerName", schedulerName)
 		}
 	}
+
+	// every active scheduler owns a node partition
+	partitionSizes := make(map[string]int)
+	for _, schedulerName := range maintainer.GetActiveSchedulers() {
+		partitionSizes[schedulerName] = maintainer.GetNumberOfNodesFromActiveScheduler(schedulerName)
+	}
+	metrics.NodePartitionSizesSet(partitionSizes)
 }
 
+// CleanupInActiveSchedulers deletes the Scheduler CRDs which are neither active nor draining.
+// The schedulers are removed from the queues once the deletion is observed.
+// TODO: if number of nodes in inactive schedulers's partition is 0, remove this inactive schedulers
 func (maintainer *SchedulerMaintainer) CleanupInActiveSchedulers() {
-	// TODO: if number of nodes in inactive schedulers's partition is 0, remove this inactive schedulers
+	schedulerNames := append(maintainer.GetActiveSchedulers(), maintainer.GetInactiveSchedulers()...)
+	for _, schedulerName := range schedulerNames {
+		scheduler, err := maintainer.schedulerLister.Get(schedulerName)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				klog.InfoS("Failed to get the schedulers CRD", "schedulerName", schedulerName, "err", err)
+			}
+			continue
+		}
+		if IsSchedulerActive(scheduler) || isSchedulerDrainingAndAlive(scheduler) {
+			continue
+		}
+		klog.V(3).InfoS("Started to delete the inactive schedulers", "schedulerName", schedulerName)
+		// schedulers is still there and it is not active, delete it.
+		err = maintainer.crdClient.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), scheduler.Name, metav1.DeleteOptions{})
+		if err != nil {
+			klog.InfoS("Failed to delete the inactive schedulers", "schedulerName", scheduler.Name, "err", err)
+		}
+	}
 }
 
 func (maintainer *SchedulerMaintainer) SyncupNodePartitionType() {
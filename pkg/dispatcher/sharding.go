@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
+)
+
+// ShardLeaseGroupLabelKey is the label of the dispatcher shard membership leases, whose value is the scheduler name.
+const ShardLeaseGroupLabelKey = "godel.bytedance.com/dispatcher-shard-group"
+
+// ShardKeyFunc returns the key deciding the shard of a pending pod.
+type ShardKeyFunc func(pod *v1.Pod) string
+
+// NewShardKeyFunc returns the ShardKeyFunc of the sharding key.
+func NewShardKeyFunc(shardingKey string) ShardKeyFunc {
+	if shardingKey == dispatcherconfig.DispatcherShardingByPodGroup {
+		return podGroupShardKey
+	}
+	return namespaceShardKey
+}
+
+// namespaceShardKey assigns all the pods of a namespace to the same shard, so that the tenant admission
+// of a namespace is enforced by a single dispatcher.
+func namespaceShardKey(pod *v1.Pod) string {
+	return pod.Namespace
+}
+
+// podGroupShardKey assigns the pods to shards by their PodGroups, the pods not belonging to any PodGroup
+// are spread by their own names.
+func podGroupShardKey(pod *v1.Pod) string {
+	if pgName := podutil.GetPodGroupName(pod); len(pgName) != 0 {
+		return pod.Namespace + "/" + pgName
+	}
+	return podutil.GetPodKey(pod)
+}
+
+// ownsPod returns whether the pending pod should be dispatched by this dispatcher.
+func (d *Dispatcher) ownsPod(pod *v1.Pod) bool {
+	if d.shardManager == nil {
+		return true
+	}
+	return d.shardManager.Members().Owns(d.shardKeyFunc(pod))
+}
+
+// onShardMembershipChanged hands over the pending pods whose owners are changed. The pods taken over are
+// added to the pending queues, and the pods handed out are removed from them. The dispatched pods are not
+// affected, they are always accounted in DispatchInfo so that every dispatcher balances the load of all of them.
+func (d *Dispatcher) onShardMembershipChanged(previous, current *shard.Members) {
+	pods, err := d.podLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list pods for dispatcher shard membership change", "err", err)
+		return
+	}
+	for _, pod := range pods {
+		if !podutil.PendingPodOfGodel(pod, d.schedulerNames...) {
+			continue
+		}
+		key := d.shardKeyFunc(pod)
+		switch wasOwned, isOwned := previous.Owns(key), current.Owns(key); {
+		case !wasOwned && isOwned:
+			d.addPodToPendingOrSortedQueue(pod)
+		case wasOwned && !isOwned:
+			d.deletePodFromPendingOrSortedQueue(pod)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/queue"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/shard"
+)
+
+// recordingPendingQueue records the pods added to and removed from the pending queue.
+type recordingPendingQueue struct {
+	added   []string
+	removed []string
+}
+
+func (q *recordingPendingQueue) AddPodInfo(podInfo *queue.QueuedPodInfo) error {
+	q.added = append(q.added, podInfo.PodKey)
+	return nil
+}
+
+func (q *recordingPendingQueue) UpdatePodInfo(podInfo *queue.QueuedPodInfo) error {
+	return q.AddPodInfo(podInfo)
+}
+
+func (q *recordingPendingQueue) RemovePodInfo(podInfo *queue.QueuedPodInfo) error {
+	q.removed = append(q.removed, podInfo.PodKey)
+	return nil
+}
+
+func (q *recordingPendingQueue) Pop() ([]*queue.QueuedPodInfo, error) { return nil, nil }
+
+func (q *recordingPendingQueue) Close() {}
+
+func TestNewShardKeyFunc(t *testing.T) {
+	pod := testing_helper.MakePod().Namespace("ns").Name("p").Obj()
+	pgPod := testing_helper.MakePod().Namespace("ns").Name("p").
+		Annotation(podutil.PodGroupNameAnnotationKey, "pg").Obj()
+
+	tests := []struct {
+		shardingKey string
+		pod         *v1.Pod
+		want        string
+	}{
+		{shardingKey: dispatcherconfig.DispatcherShardingByNamespace, pod: pod, want: "ns"},
+		{shardingKey: dispatcherconfig.DispatcherShardingByNamespace, pod: pgPod, want: "ns"},
+		{shardingKey: dispatcherconfig.DispatcherShardingByPodGroup, pod: pod, want: "ns/p"},
+		{shardingKey: dispatcherconfig.DispatcherShardingByPodGroup, pod: pgPod, want: "ns/pg"},
+	}
+	for _, tt := range tests {
+		if got := NewShardKeyFunc(tt.shardingKey)(tt.pod); got != tt.want {
+			t.Errorf("NewShardKeyFunc(%v)(%v) = %v, want %v", tt.shardingKey, podutil.GetPodKey(tt.pod), got, tt.want)
+		}
+	}
+}
+
+func TestOnShardMembershipChanged(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	podIndexer := informerFactory.Core().V1().Pods().Informer().GetIndexer()
+
+	alone := shard.NewMembers("dispatcher-0")
+	together := shard.NewMembers("dispatcher-0", "dispatcher-1")
+
+	var handedOut []string
+	for i := 0; i < 20; i++ {
+		pod := testing_helper.MakePod().Namespace(fmt.Sprintf("ns-%d", i)).Name("p").SchedulerName("godel-scheduler").Obj()
+		podIndexer.Add(pod)
+		if !together.Owns(pod.Namespace) {
+			handedOut = append(handedOut, podutil.GetPodKey(pod))
+		}
+	}
+	sort.Strings(handedOut)
+	if len(handedOut) == 0 || len(handedOut) == 20 {
+		t.Fatalf("expected the pods to be spread over the shards, got %d of 20 handed out", len(handedOut))
+	}
+	// dispatched pods are never handed over
+	dispatched := testing_helper.MakePod().Namespace("ns-0").Name("dispatched").SchedulerName("godel-scheduler").
+		Annotation(podutil.PodStateAnnotationKey, string(podutil.PodDispatched)).
+		Annotation(podutil.SchedulerAnnotationKey, "scheduler-0").Obj()
+	podIndexer.Add(dispatched)
+
+	newDispatcher := func() (*Dispatcher, *recordingPendingQueue) {
+		pendingQueue := &recordingPendingQueue{}
+		return &Dispatcher{
+			podLister:            informerFactory.Core().V1().Pods().Lister(),
+			FIFOPendingPodsQueue: pendingQueue,
+			SortedPodsQueue:      queue.NewSortedFIFO(nil),
+			schedulerNames:       []string{"godel-scheduler"},
+			shardKeyFunc:         NewShardKeyFunc(dispatcherconfig.DispatcherShardingByNamespace),
+		}, pendingQueue
+	}
+
+	// dispatcher-1 joins, the pods owned by it are handed out.
+	d, pendingQueue := newDispatcher()
+	d.onShardMembershipChanged(alone, together)
+	sort.Strings(pendingQueue.removed)
+	if len(pendingQueue.added) != 0 || !reflect.DeepEqual(pendingQueue.removed, handedOut) {
+		t.Errorf("expected pods %v to be handed out and none taken over, got removed %v and added %v",
+			handedOut, pendingQueue.removed, pendingQueue.added)
+	}
+
+	// dispatcher-1 leaves, its pods are taken over.
+	d, pendingQueue = newDispatcher()
+	d.onShardMembershipChanged(together, alone)
+	sort.Strings(pendingQueue.added)
+	if len(pendingQueue.removed) != 0 || !reflect.DeepEqual(pendingQueue.added, handedOut) {
+		t.Errorf("expected pods %v to be taken over and none handed out, got added %v and removed %v",
+			handedOut, pendingQueue.added, pendingQueue.removed)
+	}
+}
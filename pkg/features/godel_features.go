@@ -72,6 +72,31 @@ const (
 	//
 	// Allows colocation
 	EnableColocation featuregate.Feature = "EnableColocation"
+
+	// alpha: for now
+	//
+	// Allows to reserve node resources for upcoming units by node annotations.
+	ResourceReservation featuregate.Feature = "ResourceReservation"
+
+	// alpha: for now
+	//
+	// Allows to reuse the filter results across scheduling cycles for the pods of the same equivalence class.
+	EquivalenceCache featuregate.Feature = "EquivalenceCache"
+
+	// alpha: for now
+	//
+	// Allows multiple pods to share one GPU device by GPU memory and compute.
+	GPUShareScheduling featuregate.Feature = "GPUShareScheduling"
+
+	// alpha: for now
+	//
+	// Allows to place pods according to the capacity of the node-local storage pools serving their volumes.
+	LocalStoragePoolScheduling featuregate.Feature = "LocalStoragePoolScheduling"
+
+	// alpha: for now
+	//
+	// Allows to track the resources requested by each fair-share queue, which is required by the FairShare unit queue sort plugin.
+	FairShareQueueSort featuregate.Feature = "FairShareQueueSort"
 )
 
 func init() {
@@ -90,4 +115,9 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	SchedulerConcurrentScheduling:           {Default: true, PreRelease: featuregate.Alpha},
 	SchedulerSubClusterConcurrentScheduling: {Default: true, PreRelease: featuregate.Alpha},
 	EnableColocation:                        {Default: false, PreRelease: featuregate.Alpha},
+	ResourceReservation:                     {Default: false, PreRelease: featuregate.Alpha},
+	EquivalenceCache:                        {Default: false, PreRelease: featuregate.Alpha},
+	GPUShareScheduling:                      {Default: false, PreRelease: featuregate.Alpha},
+	LocalStoragePoolScheduling:              {Default: false, PreRelease: featuregate.Alpha},
+	FairShareQueueSort:                      {Default: false, PreRelease: featuregate.Alpha},
 }
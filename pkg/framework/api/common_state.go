@@ -18,6 +18,7 @@ package api
 
 import (
 	"fmt"
+	"sync"
 
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
@@ -37,6 +38,8 @@ const (
 	MatchedPDBIndexesKey      = "MatchedPDBIndexes"
 	VictimCountOfDeployKey    = "VictimCountOfDeployKey"
 	IndexOfPDBKey             = "IndexOfPDBKey"
+	PodAnnotationsOnNodeKey   = "PodAnnotationsOnNode"
+	BindingAnnotationsKey     = "BindingAnnotations"
 
 	// Error Message
 	NodePartitionTypeMissedErrorString = "failed to get NodePartitionType, supposed to be set in cycle state"
@@ -283,3 +286,85 @@ func GetPodProperty(state *CycleState) (*PodProperty, error) {
 	}
 	return nil, fmt.Errorf("unit property not found")
 }
+
+// podAnnotationsOnNode holds the annotations decided by plugins for each node, which should be set on the pod
+// once it's placed on the node. It's shared by the clones of the cycle state, and can be written concurrently.
+type podAnnotationsOnNode struct {
+	annotations sync.Map
+}
+
+func (s *podAnnotationsOnNode) Clone() StateData {
+	return s
+}
+
+// InitPodAnnotationsOnNode prepares the cycle state to record the pod annotations for nodes, it should be called
+// before filtering nodes concurrently, e.g. in PreFilter.
+func InitPodAnnotationsOnNode(state *CycleState) {
+	if _, err := state.Read(PodAnnotationsOnNodeKey); err != nil {
+		state.Write(PodAnnotationsOnNodeKey, &podAnnotationsOnNode{})
+	}
+}
+
+// SetPodAnnotationOnNode records an annotation which should be set on the pod once it's placed on the node.
+func SetPodAnnotationOnNode(state *CycleState, nodeName, key, value string) error {
+	data, err := state.Read(PodAnnotationsOnNodeKey)
+	if err != nil {
+		return err
+	}
+	s, ok := data.(*podAnnotationsOnNode)
+	if !ok {
+		return fmt.Errorf(UnsupportedError, PodAnnotationsOnNodeKey)
+	}
+	annotations := &sync.Map{}
+	if existing, loaded := s.annotations.LoadOrStore(nodeName, annotations); loaded {
+		annotations = existing.(*sync.Map)
+	}
+	annotations.Store(key, value)
+	return nil
+}
+
+// GetPodAnnotationsOnNode returns the annotations recorded for the node.
+func GetPodAnnotationsOnNode(state *CycleState, nodeName string) map[string]string {
+	if state == nil {
+		return nil
+	}
+	data, err := state.Read(PodAnnotationsOnNodeKey)
+	if err != nil {
+		return nil
+	}
+	s, ok := data.(*podAnnotationsOnNode)
+	if !ok {
+		return nil
+	}
+	value, ok := s.annotations.Load(nodeName)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string)
+	value.(*sync.Map).Range(func(k, v interface{}) bool {
+		result[k.(string)] = v.(string)
+		return true
+	})
+	return result
+}
+
+// SetBindingAnnotations records the annotations which should be set on the pod along with the binding, e.g. the
+// placement handed off by the scheduler which has not been persisted through the pod annotations.
+func SetBindingAnnotations(state *CycleState, annotations map[string]string) {
+	state.Write(BindingAnnotationsKey, &stateData{data: annotations})
+}
+
+// GetBindingAnnotations returns the annotations which should be set on the pod along with the binding.
+func GetBindingAnnotations(state *CycleState) map[string]string {
+	if state == nil {
+		return nil
+	}
+	if data, err := state.Read(BindingAnnotationsKey); err == nil {
+		if s, ok := data.(*stateData); ok {
+			if value, ok := s.data.(map[string]string); ok {
+				return value
+			}
+		}
+	}
+	return nil
+}
@@ -92,3 +92,15 @@ func TestSetGetIndexOfPDBKey(t *testing.T) {
 		t.Errorf("expected indexes map: %v, but got: %v", indexesMap, gotIndexesMap)
 	}
 }
+
+func TestSetGetBindingAnnotations(t *testing.T) {
+	state := NewCycleState()
+	if got := GetBindingAnnotations(state); got != nil {
+		t.Errorf("expected no binding annotations, but got %v", got)
+	}
+	annotations := map[string]string{"k": "v"}
+	SetBindingAnnotations(state, annotations)
+	if got := GetBindingAnnotations(state); !reflect.DeepEqual(annotations, got) {
+		t.Errorf("expected get %v, but got %v", annotations, got)
+	}
+}
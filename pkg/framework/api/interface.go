@@ -231,6 +231,37 @@ type UnitQueueSortPlugin interface {
 	Less(*QueuedUnitInfo, *QueuedUnitInfo) bool
 }
 
+// SnapshotUnitQueueSortPlugin is a UnitQueueSortPlugin whose order depends on the state changing over time,
+// e.g. how long a unit has been pending. Less must only compare the state snapshotted into the units by
+// SnapshotUnit, so that the order never changes under a live heap. The queue snapshots a unit when it's added,
+// and re-snapshots all the units and re-sorts them every SnapshotInterval.
+type SnapshotUnitQueueSortPlugin interface {
+	UnitQueueSortPlugin
+	// SnapshotUnit snapshots the state the unit is sorted by into the unit.
+	SnapshotUnit(*QueuedUnitInfo)
+	// SnapshotInterval returns how often the units are re-snapshotted and re-sorted.
+	SnapshotInterval() time.Duration
+}
+
+// StarvationAwareUnitQueueSortPlugin is a UnitQueueSortPlugin preventing big units from starving.
+// Starving units are sorted ahead of the others, and the resources they are able to use will be
+// held back for them when they fail to be scheduled, so that the others only backfill the rest.
+type StarvationAwareUnitQueueSortPlugin interface {
+	UnitQueueSortPlugin
+	// Starving checks whether the unit has been pending for too long.
+	Starving(*QueuedUnitInfo) bool
+	// ReservationTTL returns how long the resources can be held back for a starving unit.
+	ReservationTTL() time.Duration
+}
+
+// FairShareUnitQueueSortPlugin is a UnitQueueSortPlugin interleaving the units of equal priority by the
+// recent usage of their fair-share queues.
+type FairShareUnitQueueSortPlugin interface {
+	UnitQueueSortPlugin
+	// SetQueueShareLister sets the function returning the shares of the resources currently requested by each queue.
+	SetQueueShareLister(func() map[string]float64)
+}
+
 type VictimSearchingPluginCollection struct {
 	forceQuickPass  bool
 	enableQuickPass bool
@@ -663,6 +694,10 @@ type SchedulerFrameworkHandle interface {
 	GetOwnerLabels(ownerType, ownerKey string) map[string]string
 	GetLoadAwareNodeMetricInfo(nodeName string, resourceType podutil.PodResourceType) *LoadAwareNodeMetricInfo
 	GetLoadAwareNodeUsage(nodeName string, resourceType podutil.PodResourceType) *LoadAwareNodeUsage
+	// Note: The function's underlying access is Snapshot, Snapshot operations are lock-free.
+	GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *LoadAwareWorkloadUsage
+	// Note: The function's underlying access is Snapshot, Snapshot operations are lock-free.
+	GetNodeReservations(nodeName string) []*ResourceReservation
 
 	GetPreemptionFrameworkForPod(*v1.Pod) SchedulerPreemptionFramework
 	GetPreemptionPolicy(deployName string) string
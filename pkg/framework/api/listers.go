@@ -16,6 +16,8 @@ limitations under the License.
 
 package api
 
+import "github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+
 // NodeInfoLister interface represents anything that can list/get NodeInfo objects from node name.
 type NodeInfoLister interface {
 	ClusterNodeInfoLister
@@ -29,6 +31,9 @@ type NodeInfoLister interface {
 type SharedLister interface {
 	NodeInfos() NodeInfoLister
 	GetPreemptorsByVictim(string, string) []string
+	// GetPreemptionBudgetUsage returns the victims nominated by all the schedulers in the last minute,
+	// accounted in the preemption budget.
+	GetPreemptionBudgetUsage(*preemptionbudget.Budget) preemptionbudget.Usage
 }
 
 // ClusterNodeInfoLister interface represents anything that can list/get NodeInfo objects from node name.
@@ -103,6 +103,10 @@ type NodeInfo interface {
 	GetResourcesAvailableForSharedCoresPods(unavailableNumaList []int) *Resource
 	GetResourcesRequestsOfSharedCoresPods() *Resource
 
+	GetGPUShareDevices() []*GPUDeviceStatus
+	GetLocalStoragePools() []*LocalStoragePool
+	GetResourceReservations() []*ResourceReservation
+
 	GetPrioritiesForPodsMayBePreempted(resourceType podutil.PodResourceType) []int64
 }
 
@@ -177,6 +181,14 @@ type NodeInfoImpl struct {
 
 	NumaTopologyStatus *NumaTopologyStatus
 
+	GPUShareStatus *GPUShareStatus
+
+	LocalStoragePoolStatus *LocalStoragePoolStatus
+
+	// ResourceReservations are the reservations declared by the node annotation, they are parsed only if
+	// ResourceReservation is enabled and never modified after parsed.
+	ResourceReservations []*ResourceReservation
+
 	mu sync.RWMutex
 }
 
@@ -201,6 +213,12 @@ func NewNodeInfo(pods ...*v1.Pod) NodeInfo {
 	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.NonNativeResourceSchedulingSupport) {
 		ni.NumaTopologyStatus = newNumaTopologyStatus(NewResource(nil))
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.GPUShareScheduling) {
+		ni.GPUShareStatus = newGPUShareStatus()
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.LocalStoragePoolScheduling) {
+		ni.LocalStoragePoolStatus = newLocalStoragePoolStatus()
+	}
 	for _, pod := range pods {
 		ni.AddPod(pod)
 	}
@@ -519,6 +537,9 @@ func (n *NodeInfoImpl) Clone() NodeInfo {
 		ImageStates:                n.ImageStates,
 		Generation:                 n.Generation,
 		NumaTopologyStatus:         n.NumaTopologyStatus.clone(),
+		GPUShareStatus:             n.GPUShareStatus.clone(),
+		LocalStoragePoolStatus:     n.LocalStoragePoolStatus.clone(),
+		ResourceReservations:       n.ResourceReservations,
 	}
 	if len(n.UsedPorts) > 0 {
 		// HostPortInfo is a map-in-map struct
@@ -596,6 +617,12 @@ func (n *NodeInfoImpl) update(podInfo *PodInfo, sign int64, preempt bool) {
 
 	// update non-native resources
 	n.NumaTopologyStatus.updateNonNativeResource(podInfo, sign > 0, preempt)
+
+	// update shared GPU devices
+	n.GPUShareStatus.updatePod(podInfo, sign > 0)
+
+	// update local storage pools
+	n.LocalStoragePoolStatus.updatePod(podInfo, sign > 0)
 }
 
 // AddPod adds pod information to this NodeInfoImpl.
@@ -686,10 +713,27 @@ func (n *NodeInfoImpl) SetNode(node *v1.Node) error {
 	n.Node = node
 	n.setGuaranteedAllocatableResource()
 	n.setGuaranteedCapacityResource()
+	n.GPUShareStatus.setNode(node)
+	n.LocalStoragePoolStatus.setNode(node)
+	n.setResourceReservations(node)
 	n.TransientInfo = NewTransientSchedulerInfo()
 	return nil
 }
 
+// setResourceReservations parses the reservations from the node annotation, the malformed annotation is ignored.
+func (n *NodeInfoImpl) setResourceReservations(node *v1.Node) {
+	n.ResourceReservations = nil
+	if !utilfeature.DefaultFeatureGate.Enabled(godelfeatures.ResourceReservation) {
+		return
+	}
+	reservations, err := ParseResourceReservations(node)
+	if err != nil {
+		klog.InfoS("Failed to parse resource reservations, ignored them", "node", klog.KObj(node), "err", err)
+		return
+	}
+	n.ResourceReservations = reservations
+}
+
 // SetNodePartition sets whether the node is in partition of scheduler
 func (n *NodeInfoImpl) SetNodePartition(inSchedulerPartition bool) error {
 	n.mu.Lock()
@@ -739,6 +783,8 @@ func (n *NodeInfoImpl) SetCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
 
 	n.CNR = cnr
 	n.NumaTopologyStatus.parseNumaTopologyStatus(cnr, n.PodInfoMaintainer)
+	n.GPUShareStatus.setCNR(cnr)
+	n.LocalStoragePoolStatus.setCNR(cnr)
 	n.BestEffortAllocatable = NewResourceFromPtr(cnr.Status.Resources.Allocatable)
 	return nil
 }
@@ -765,6 +811,27 @@ func (n *NodeInfoImpl) GetResourcesRequestsOfSharedCoresPods() *Resource {
 	return n.NumaTopologyStatus.GetResourcesRequestsOfSharedCoresPods()
 }
 
+// GetGPUShareDevices returns the status of the GPU devices shared by pods, nil if GPUShareScheduling is disabled.
+func (n *NodeInfoImpl) GetGPUShareDevices() []*GPUDeviceStatus {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.GPUShareStatus.getDevices()
+}
+
+// GetResourceReservations returns the reservations declared by the node annotation, including the expired ones.
+func (n *NodeInfoImpl) GetResourceReservations() []*ResourceReservation {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.ResourceReservations
+}
+
+// GetLocalStoragePools returns the status of the node-local storage pools, nil if LocalStoragePoolScheduling is disabled.
+func (n *NodeInfoImpl) GetLocalStoragePools() []*LocalStoragePool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.LocalStoragePoolStatus.getPools()
+}
+
 func newNumaTopologyStatus(requestsOfSharedCores *Resource) *NumaTopologyStatus {
 	return &NumaTopologyStatus{
 		podAllocations:         make(map[string]*PodAllocation),
@@ -1425,6 +1492,9 @@ func (n *NodeInfoImpl) RemoveNode() {
 	n.Node = nil
 	n.setGuaranteedAllocatableResource()
 	n.setGuaranteedCapacityResource()
+	n.GPUShareStatus.setNode(nil)
+	n.LocalStoragePoolStatus.setNode(nil)
+	n.ResourceReservations = nil
 }
 
 func (n *NodeInfoImpl) RemoveNMNode() {
@@ -1442,6 +1512,8 @@ func (n *NodeInfoImpl) RemoveCNR() {
 	n.CNR = nil
 	n.BestEffortAllocatable = &Resource{}
 	n.NumaTopologyStatus.removeCNR()
+	n.GPUShareStatus.setCNR(nil)
+	n.LocalStoragePoolStatus.setCNR(nil)
 }
 
 // GetPodKey returns the string key of a pod.
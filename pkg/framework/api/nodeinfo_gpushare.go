@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sort"
+	"strconv"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	godelutil "github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// gpuShareResources are the resources of one GPU device that can be shared by pods.
+var gpuShareResources = []v1.ResourceName{godelutil.ResourceGPUMemory, godelutil.ResourceGPUCore}
+
+// GPUDeviceStatus is the status of one GPU device shared by pods.
+type GPUDeviceStatus struct {
+	Index       int
+	Allocatable map[v1.ResourceName]int64
+	Requested   map[v1.ResourceName]int64
+}
+
+// Free returns the free amount of the shared GPU resource on the device.
+func (d *GPUDeviceStatus) Free(resourceName v1.ResourceName) int64 {
+	return d.Allocatable[resourceName] - d.Requested[resourceName]
+}
+
+// GPUShareStatus tracks the GPU devices of the node shared by pods. The devices are reported by the
+// GPU topology zones in CNR, or by the node annotation if CNR doesn't report any of them.
+type GPUShareStatus struct {
+	devicesFromNode map[int]map[v1.ResourceName]int64
+	devicesFromCNR  map[int]map[v1.ResourceName]int64
+	// requested holds the shared GPU resources requested by the pods assigned to each device.
+	requested map[int]map[v1.ResourceName]int64
+}
+
+func newGPUShareStatus() *GPUShareStatus {
+	return &GPUShareStatus{
+		requested: make(map[int]map[v1.ResourceName]int64),
+	}
+}
+
+func (s *GPUShareStatus) clone() *GPUShareStatus {
+	if s == nil {
+		return nil
+	}
+	return &GPUShareStatus{
+		// devices are replaced as a whole when node or CNR is updated, so they can be shared.
+		devicesFromNode: s.devicesFromNode,
+		devicesFromCNR:  s.devicesFromCNR,
+		requested:       cloneDeviceResources(s.requested),
+	}
+}
+
+func (s *GPUShareStatus) setNode(node *v1.Node) {
+	if s == nil {
+		return
+	}
+	s.devicesFromNode = nil
+	if node == nil {
+		return
+	}
+	value, ok := node.Annotations[godelutil.GPUShareDevicesAnnotationKey]
+	if !ok {
+		return
+	}
+	devices, err := godelutil.UnmarshalMicroTopology(value)
+	if err != nil {
+		klog.InfoS("WARN: Failed to parse shared GPU devices of node", "node", node.Name, "err", err)
+		return
+	}
+	s.devicesFromNode = make(map[int]map[v1.ResourceName]int64, len(devices))
+	for index, resources := range devices {
+		if resources != nil {
+			s.devicesFromNode[index] = gpuShareResourcesOf(*resources)
+		}
+	}
+}
+
+func (s *GPUShareStatus) setCNR(cnr *katalystv1alpha1.CustomNodeResource) {
+	if s == nil {
+		return
+	}
+	s.devicesFromCNR = nil
+	if cnr == nil {
+		return
+	}
+	devices := make(map[int]map[v1.ResourceName]int64)
+	var walk func(zones []*katalystv1alpha1.TopologyZone)
+	walk = func(zones []*katalystv1alpha1.TopologyZone) {
+		for _, zone := range zones {
+			if zone == nil {
+				continue
+			}
+			if zone.Type == katalystv1alpha1.TopologyTypeGPU && zone.Resources.Allocatable != nil {
+				index, err := strconv.Atoi(zone.Name)
+				if err != nil {
+					klog.InfoS("WARN: Invalid GPU topology zone in CNR", "cnr", cnr.Name, "zone", zone.Name)
+					continue
+				}
+				if resources := gpuShareResourcesOf(*zone.Resources.Allocatable); len(resources) > 0 {
+					devices[index] = resources
+				}
+			}
+			walk(zone.Children)
+		}
+	}
+	walk(cnr.Status.TopologyZone)
+	if len(devices) > 0 {
+		s.devicesFromCNR = devices
+	}
+}
+
+func (s *GPUShareStatus) updatePod(podInfo *PodInfo, isAdd bool) {
+	if s == nil || podInfo.Pod == nil {
+		return
+	}
+	index, ok := podutil.GetPodGPUShareDevice(podInfo.Pod)
+	if !ok {
+		return
+	}
+	sign := int64(-1)
+	if isAdd {
+		sign = 1
+	}
+	for _, resourceName := range gpuShareResources {
+		request := podInfo.Res.ScalarResources[resourceName]
+		if request == 0 {
+			continue
+		}
+		if s.requested[index] == nil {
+			s.requested[index] = make(map[v1.ResourceName]int64)
+		}
+		s.requested[index][resourceName] += sign * request
+	}
+}
+
+// getDevices returns the status of shared GPU devices sorted by index.
+func (s *GPUShareStatus) getDevices() []*GPUDeviceStatus {
+	if s == nil {
+		return nil
+	}
+	devices := s.devicesFromCNR
+	if len(devices) == 0 {
+		devices = s.devicesFromNode
+	}
+	result := make([]*GPUDeviceStatus, 0, len(devices))
+	for index, allocatable := range devices {
+		requested := make(map[v1.ResourceName]int64, len(s.requested[index]))
+		for resourceName, value := range s.requested[index] {
+			requested[resourceName] = value
+		}
+		result = append(result, &GPUDeviceStatus{
+			Index:       index,
+			Allocatable: allocatable,
+			Requested:   requested,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Index < result[j].Index
+	})
+	return result
+}
+
+func gpuShareResourcesOf(resourceList v1.ResourceList) map[v1.ResourceName]int64 {
+	resources := make(map[v1.ResourceName]int64, len(gpuShareResources))
+	for _, resourceName := range gpuShareResources {
+		if quantity, ok := resourceList[resourceName]; ok {
+			resources[resourceName] = quantity.Value()
+		}
+	}
+	return resources
+}
+
+func cloneDeviceResources(devices map[int]map[v1.ResourceName]int64) map[int]map[v1.ResourceName]int64 {
+	clone := make(map[int]map[v1.ResourceName]int64, len(devices))
+	for index, resources := range devices {
+		clone[index] = make(map[v1.ResourceName]int64, len(resources))
+		for resourceName, value := range resources {
+			clone[index][resourceName] = value
+		}
+	}
+	return clone
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const gi = int64(1024 * 1024 * 1024)
+
+func makeGPUSharePod(name, memory, device string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Annotations: map[string]string{
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+				podutil.GPUShareDeviceAnnotationKey:  device,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					util.ResourceGPUMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+	}
+}
+
+// gpuMemoryOf summarizes devices as index -> [allocatable, requested] GPU memory.
+func gpuMemoryOf(devices []*GPUDeviceStatus) map[int][2]int64 {
+	result := make(map[int][2]int64, len(devices))
+	for _, device := range devices {
+		result[device.Index] = [2]int64{device.Allocatable[util.ResourceGPUMemory], device.Requested[util.ResourceGPUMemory]}
+	}
+	return result
+}
+
+func TestGPUShareDevices(t *testing.T) {
+	defer utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.GPUShareScheduling): false})
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.GPUShareScheduling): true})
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Annotations: map[string]string{
+				util.GPUShareDevicesAnnotationKey: "0:godel.bytedance.com/gpu-memory=16Gi;1:godel.bytedance.com/gpu-memory=16Gi",
+			},
+		},
+	}
+	nodeInfo := NewNodeInfo()
+	nodeInfo.SetNode(node)
+	nodeInfo.AddPod(makeGPUSharePod("p1", "4Gi", "1"))
+	nodeInfo.AddPod(makeGPUSharePod("p2", "2Gi", "1"))
+
+	expected := map[int][2]int64{0: {16 * gi, 0}, 1: {16 * gi, 6 * gi}}
+	if got := gpuMemoryOf(nodeInfo.GetGPUShareDevices()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected devices %v, got %v", expected, got)
+	}
+
+	cloned := nodeInfo.Clone()
+	if err := nodeInfo.RemovePod(makeGPUSharePod("p1", "4Gi", "1"), false); err != nil {
+		t.Fatal(err)
+	}
+	expected = map[int][2]int64{0: {16 * gi, 0}, 1: {16 * gi, 2 * gi}}
+	if got := gpuMemoryOf(nodeInfo.GetGPUShareDevices()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected devices after removal %v, got %v", expected, got)
+	}
+	expected = map[int][2]int64{0: {16 * gi, 0}, 1: {16 * gi, 6 * gi}}
+	if got := gpuMemoryOf(cloned.GetGPUShareDevices()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected cloned devices %v, got %v", expected, got)
+	}
+
+	// Devices reported by CNR take precedence over the node annotation.
+	allocatable := v1.ResourceList{util.ResourceGPUMemory: resource.MustParse("24Gi")}
+	nodeInfo.SetCNR(&katalystv1alpha1.CustomNodeResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+		Status: katalystv1alpha1.CustomNodeResourceStatus{
+			TopologyZone: []*katalystv1alpha1.TopologyZone{{
+				Type: katalystv1alpha1.TopologyTypeSocket,
+				Name: "0",
+				Children: []*katalystv1alpha1.TopologyZone{{
+					Type:      katalystv1alpha1.TopologyTypeGPU,
+					Name:      "1",
+					Resources: katalystv1alpha1.Resources{Allocatable: &allocatable},
+				}},
+			}},
+		},
+	})
+	expected = map[int][2]int64{1: {24 * gi, 2 * gi}}
+	if got := gpuMemoryOf(nodeInfo.GetGPUShareDevices()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected devices from CNR %v, got %v", expected, got)
+	}
+
+	nodeInfo.RemoveCNR()
+	expected = map[int][2]int64{0: {16 * gi, 0}, 1: {16 * gi, 2 * gi}}
+	if got := gpuMemoryOf(nodeInfo.GetGPUShareDevices()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected devices after removing CNR %v, got %v", expected, got)
+	}
+}
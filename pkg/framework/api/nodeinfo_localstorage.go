@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sort"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	godelutil "github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// LocalStoragePool is the status of one node-local storage pool, e.g. a LVM volume group or NVMe disks.
+type LocalStoragePool struct {
+	// StorageClass is the name of the storage class whose volumes are provisioned from the pool.
+	StorageClass string
+	Capacity     int64
+	Requested    int64
+}
+
+// Free returns the free capacity of the pool.
+func (p *LocalStoragePool) Free() int64 {
+	return p.Capacity - p.Requested
+}
+
+// LocalStoragePoolStatus tracks the node-local storage pools of the node. The pools are reported by the
+// annotation of CNR, or by the node annotation if CNR doesn't report any of them.
+type LocalStoragePoolStatus struct {
+	poolsFromNode map[string]int64
+	poolsFromCNR  map[string]int64
+	// requested holds the storage requested by the pods from each pool.
+	requested map[string]int64
+}
+
+func newLocalStoragePoolStatus() *LocalStoragePoolStatus {
+	return &LocalStoragePoolStatus{
+		requested: make(map[string]int64),
+	}
+}
+
+func (s *LocalStoragePoolStatus) clone() *LocalStoragePoolStatus {
+	if s == nil {
+		return nil
+	}
+	requested := make(map[string]int64, len(s.requested))
+	for storageClass, value := range s.requested {
+		requested[storageClass] = value
+	}
+	return &LocalStoragePoolStatus{
+		// pools are replaced as a whole when node or CNR is updated, so they can be shared.
+		poolsFromNode: s.poolsFromNode,
+		poolsFromCNR:  s.poolsFromCNR,
+		requested:     requested,
+	}
+}
+
+func (s *LocalStoragePoolStatus) setNode(node *v1.Node) {
+	if s == nil {
+		return
+	}
+	s.poolsFromNode = nil
+	if node != nil {
+		s.poolsFromNode = parseLocalStoragePools(node.Name, node.Annotations)
+	}
+}
+
+func (s *LocalStoragePoolStatus) setCNR(cnr *katalystv1alpha1.CustomNodeResource) {
+	if s == nil {
+		return
+	}
+	s.poolsFromCNR = nil
+	if cnr != nil {
+		s.poolsFromCNR = parseLocalStoragePools(cnr.Name, cnr.Annotations)
+	}
+}
+
+func (s *LocalStoragePoolStatus) updatePod(podInfo *PodInfo, isAdd bool) {
+	if s == nil || podInfo.Pod == nil {
+		return
+	}
+	sign := int64(-1)
+	if isAdd {
+		sign = 1
+	}
+	for storageClass, request := range podutil.GetPodLocalStorageRequests(podInfo.Pod) {
+		s.requested[storageClass] += sign * request
+		if s.requested[storageClass] == 0 {
+			delete(s.requested, storageClass)
+		}
+	}
+}
+
+// getPools returns the status of local storage pools sorted by storage class.
+func (s *LocalStoragePoolStatus) getPools() []*LocalStoragePool {
+	if s == nil {
+		return nil
+	}
+	pools := s.poolsFromCNR
+	if len(pools) == 0 {
+		pools = s.poolsFromNode
+	}
+	result := make([]*LocalStoragePool, 0, len(pools))
+	for storageClass, capacity := range pools {
+		result = append(result, &LocalStoragePool{
+			StorageClass: storageClass,
+			Capacity:     capacity,
+			Requested:    s.requested[storageClass],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StorageClass < result[j].StorageClass
+	})
+	return result
+}
+
+func parseLocalStoragePools(name string, annotations map[string]string) map[string]int64 {
+	value, ok := annotations[godelutil.LocalStoragePoolsAnnotationKey]
+	if !ok {
+		return nil
+	}
+	pools, err := godelutil.UnmarshalStorageQuantities(value)
+	if err != nil {
+		klog.InfoS("WARN: Failed to parse local storage pools", "name", name, "err", err)
+		return nil
+	}
+	return pools
+}
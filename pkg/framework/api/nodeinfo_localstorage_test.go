@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeLocalStoragePod(name, requests string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Annotations: map[string]string{
+				podutil.PodResourceTypeAnnotationKey:      string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:          string(podutil.Kubelet),
+				podutil.LocalStorageRequestsAnnotationKey: requests,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{}},
+		},
+	}
+}
+
+// localStorageOf summarizes pools as storage class -> [capacity, requested].
+func localStorageOf(pools []*LocalStoragePool) map[string][2]int64 {
+	result := make(map[string][2]int64, len(pools))
+	for _, pool := range pools {
+		result[pool.StorageClass] = [2]int64{pool.Capacity, pool.Requested}
+	}
+	return result
+}
+
+func TestLocalStoragePools(t *testing.T) {
+	defer utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.LocalStoragePoolScheduling): false})
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.LocalStoragePoolScheduling): true})
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Annotations: map[string]string{
+				util.LocalStoragePoolsAnnotationKey: "local-lvm=100Gi,local-nvme=200Gi",
+			},
+		},
+	}
+	nodeInfo := NewNodeInfo()
+	nodeInfo.SetNode(node)
+	nodeInfo.AddPod(makeLocalStoragePod("p1", "local-lvm=10Gi"))
+	nodeInfo.AddPod(makeLocalStoragePod("p2", "local-lvm=20Gi,local-nvme=50Gi"))
+
+	expected := map[string][2]int64{"local-lvm": {100 * gi, 30 * gi}, "local-nvme": {200 * gi, 50 * gi}}
+	if got := localStorageOf(nodeInfo.GetLocalStoragePools()); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected pools %v, got %v", expected, got)
+	}
+
+	clone := nodeInfo.Clone()
+	if err := nodeInfo.RemovePod(makeLocalStoragePod("p2", "local-lvm=20Gi,local-nvme=50Gi"), false); err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string][2]int64{"local-lvm": {100 * gi, 10 * gi}, "local-nvme": {200 * gi, 0}}
+	if got := localStorageOf(nodeInfo.GetLocalStoragePools()); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected pools after removing pod %v, got %v", expected, got)
+	}
+	expected = map[string][2]int64{"local-lvm": {100 * gi, 30 * gi}, "local-nvme": {200 * gi, 50 * gi}}
+	if got := localStorageOf(clone.GetLocalStoragePools()); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected the clone to be unchanged %v, got %v", expected, got)
+	}
+
+	// The pools reported by CNR take precedence over the node annotation.
+	nodeInfo.SetCNR(&katalystv1alpha1.CustomNodeResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node",
+			Annotations: map[string]string{util.LocalStoragePoolsAnnotationKey: "local-lvm=50Gi"},
+		},
+	})
+	expected = map[string][2]int64{"local-lvm": {50 * gi, 10 * gi}}
+	if got := localStorageOf(nodeInfo.GetLocalStoragePools()); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected pools from CNR %v, got %v", expected, got)
+	}
+
+	nodeInfo.RemoveCNR()
+	expected = map[string][2]int64{"local-lvm": {100 * gi, 10 * gi}, "local-nvme": {200 * gi, 0}}
+	if got := localStorageOf(nodeInfo.GetLocalStoragePools()); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected pools after removing CNR %v, got %v", expected, got)
+	}
+}
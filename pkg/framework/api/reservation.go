@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// ResourceReservationAnnotationKey is the node annotation which holds the resource reservations of the node.
+// The value is a json list of ResourceReservation, e.g.
+//
+//	[{"name":"train-0","owner":"default/train","resources":{"nvidia.com/gpu":"8"},"creationTime":"2023-06-01T00:00:00Z","ttlSeconds":3600}]
+const ResourceReservationAnnotationKey = "godel.bytedance.com/resource-reservations"
+
+// ResourceReservation is the capacity reserved on a node for an upcoming unit.
+// The reserved resources are hidden from all the pods except the ones belonging to the owner,
+// until the reservation expires.
+type ResourceReservation struct {
+	// Name identifies the reservation on the node.
+	Name string `json:"name"`
+	// Owner is the key of the PodGroup the resources are reserved for, in the format of `namespace/name`.
+	Owner string `json:"owner"`
+	// OwnerUID is the UID of the controller the resources are reserved for, e.g. the ReplicaSet of the pods
+	// migrated away from another node. If it is set, Owner is the key of the controller and only the pods
+	// created by the controller after the reservation are counted as its owner pods.
+	OwnerUID types.UID `json:"ownerUID,omitempty"`
+	// Resources is the amount of resources reserved.
+	Resources v1.ResourceList `json:"resources"`
+	// CreationTime is the time when the reservation was created.
+	CreationTime metav1.Time `json:"creationTime"`
+	// TTLSeconds is how long the reservation lasts since CreationTime.
+	TTLSeconds int64 `json:"ttlSeconds"`
+}
+
+// ExpireTime returns the time when the reservation expires.
+func (r *ResourceReservation) ExpireTime() time.Time {
+	return r.CreationTime.Add(time.Duration(r.TTLSeconds) * time.Second)
+}
+
+// Expired checks whether the reservation has expired at the given time.
+func (r *ResourceReservation) Expired(now time.Time) bool {
+	return !now.Before(r.ExpireTime())
+}
+
+// OwnedBy checks whether the pod belongs to the owner of the reservation.
+func (r *ResourceReservation) OwnedBy(pod *v1.Pod) bool {
+	if len(r.OwnerUID) > 0 {
+		controllerRef := metav1.GetControllerOf(pod)
+		return controllerRef != nil && controllerRef.UID == r.OwnerUID && !pod.CreationTimestamp.Before(&r.CreationTime)
+	}
+	pgName := podutil.GetPodGroupName(pod)
+	return len(pgName) > 0 && r.Owner == pod.Namespace+"/"+pgName
+}
+
+// ParseResourceReservations parses the resource reservations from the node annotation.
+// Reservations without owner or with non-positive ttl are invalid and will be dropped.
+func ParseResourceReservations(node *v1.Node) ([]*ResourceReservation, error) {
+	if node == nil {
+		return nil, nil
+	}
+	value, ok := node.Annotations[ResourceReservationAnnotationKey]
+	if !ok || len(value) == 0 {
+		return nil, nil
+	}
+	var reservations []*ResourceReservation
+	if err := json.Unmarshal([]byte(value), &reservations); err != nil {
+		return nil, fmt.Errorf("failed to parse resource reservations of node %v: %v", node.Name, err)
+	}
+	valid := reservations[:0]
+	for _, r := range reservations {
+		if r == nil || len(r.Owner) == 0 || r.TTLSeconds <= 0 {
+			continue
+		}
+		valid = append(valid, r)
+	}
+	return valid, nil
+}
+
+// FormatResourceReservations formats the resource reservations into the value of the node annotation.
+func FormatResourceReservations(reservations []*ResourceReservation) (string, error) {
+	value, err := json.Marshal(reservations)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// GetReservedResource returns the resources reserved on the node which can't be used by the pod.
+// The resources of a reservation are released gradually as the pods of its owner are placed on the node,
+// and are no longer reserved after the reservation expires. Nil is returned if nothing is reserved.
+func GetReservedResource(reservations []*ResourceReservation, nodeInfo NodeInfo, pod *v1.Pod, now time.Time) *Resource {
+	var reserved *Resource
+	for _, r := range reservations {
+		if r.Expired(now) || r.OwnedBy(pod) {
+			continue
+		}
+		remaining := NewResource(r.Resources)
+		for _, podInfo := range nodeInfo.GetPods() {
+			if r.OwnedBy(podInfo.Pod) {
+				remaining.SubResource(&podInfo.Res)
+			}
+		}
+		clampNonNegative(remaining)
+		if remaining.IsZero() {
+			continue
+		}
+		if reserved == nil {
+			reserved = &Resource{}
+		}
+		reserved.AddResource(remaining)
+	}
+	return reserved
+}
+
+func clampNonNegative(r *Resource) {
+	if r.MilliCPU < 0 {
+		r.MilliCPU = 0
+	}
+	if r.Memory < 0 {
+		r.Memory = 0
+	}
+	if r.EphemeralStorage < 0 {
+		r.EphemeralStorage = 0
+	}
+	r.AllowedPodNumber = 0
+	for name, quant := range r.ScalarResources {
+		if quant <= 0 {
+			delete(r.ScalarResources, name)
+		}
+	}
+}
+
+// GenerationNodeReservations holds the resource reservations of a node in generationstore.
+type GenerationNodeReservations interface {
+	GetReservations() []*ResourceReservation
+	Clone() GenerationNodeReservations
+	generationstore.StoredObj
+}
+
+type GenerationNodeReservationsImpl struct {
+	reservations []*ResourceReservation
+	generation   int64
+}
+
+var _ GenerationNodeReservations = &GenerationNodeReservationsImpl{}
+
+func NewGenerationNodeReservations(reservations []*ResourceReservation) GenerationNodeReservations {
+	return &GenerationNodeReservationsImpl{
+		reservations: reservations,
+		generation:   0,
+	}
+}
+
+func (i *GenerationNodeReservationsImpl) GetReservations() []*ResourceReservation {
+	return i.reservations
+}
+
+func (i *GenerationNodeReservationsImpl) SetGeneration(generation int64) {
+	i.generation = generation
+}
+
+func (i *GenerationNodeReservationsImpl) GetGeneration() int64 {
+	return i.generation
+}
+
+// ATTENTION: Clone will not deep-copy the reservations, they are never modified after parsed.
+func (i GenerationNodeReservationsImpl) Clone() GenerationNodeReservations {
+	return &GenerationNodeReservationsImpl{
+		reservations: i.reservations,
+		generation:   i.generation,
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeReservationPod(name, podGroup string, cpu string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			}},
+		},
+	}
+	if len(podGroup) > 0 {
+		pod.Annotations = map[string]string{podutil.PodGroupNameAnnotationKey: podGroup}
+	}
+	return pod
+}
+
+func TestParseResourceReservations(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n", Annotations: map[string]string{
+		ResourceReservationAnnotationKey: `[{"name":"r1","owner":"default/pg","resources":{"cpu":"4"},"creationTime":"2023-06-01T00:00:00Z","ttlSeconds":60},` +
+			`{"name":"r2","resources":{"cpu":"4"},"ttlSeconds":60},{"name":"r3","owner":"default/pg","ttlSeconds":0}]`,
+	}}}
+	reservations, err := ParseResourceReservations(node)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reservations) != 1 || reservations[0].Name != "r1" {
+		t.Fatalf("expected only r1 to be valid, got %v", reservations)
+	}
+	expire := time.Date(2023, 6, 1, 0, 1, 0, 0, time.UTC)
+	if !reservations[0].ExpireTime().Equal(expire) {
+		t.Errorf("expected expire time %v, got %v", expire, reservations[0].ExpireTime())
+	}
+
+	node.Annotations[ResourceReservationAnnotationKey] = "invalid"
+	if _, err := ParseResourceReservations(node); err == nil {
+		t.Errorf("expected error for invalid annotation")
+	}
+}
+
+func TestResourceReservationOwnedByController(t *testing.T) {
+	now := time.Now()
+	r := &ResourceReservation{
+		Name:         "r",
+		Owner:        "default/rs",
+		OwnerUID:     "rs-uid",
+		Resources:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+		CreationTime: metav1.NewTime(now.Add(-time.Minute)),
+		TTLSeconds:   3600,
+	}
+	controlled := func(name, uid string, creationTime time.Time) *v1.Pod {
+		pod := makeReservationPod(name, "", "1")
+		pod.CreationTimestamp = metav1.NewTime(creationTime)
+		isController := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", UID: types.UID(uid), Controller: &isController}}
+		return pod
+	}
+
+	if !r.OwnedBy(controlled("new", "rs-uid", now)) {
+		t.Errorf("expected the pod recreated by the controller to own the reservation")
+	}
+	if r.OwnedBy(controlled("old", "rs-uid", now.Add(-time.Hour))) {
+		t.Errorf("expected the pod created before the reservation not to own it")
+	}
+	if r.OwnedBy(controlled("other", "other-uid", now)) {
+		t.Errorf("expected the pod of another controller not to own the reservation")
+	}
+
+	value, err := FormatResourceReservations([]*ResourceReservation{r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n", Annotations: map[string]string{ResourceReservationAnnotationKey: value}}}
+	if reservations, err := ParseResourceReservations(node); err != nil || len(reservations) != 1 || reservations[0].OwnerUID != r.OwnerUID {
+		t.Errorf("expected the reservation to be formatted and parsed back, got %v, %v", reservations, err)
+	}
+}
+
+func TestGetReservedResource(t *testing.T) {
+	now := time.Now()
+	reservations := []*ResourceReservation{
+		{
+			Name:         "r1",
+			Owner:        "default/pg",
+			Resources:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			CreationTime: metav1.NewTime(now.Add(-time.Minute)),
+			TTLSeconds:   3600,
+		},
+		{
+			Name:         "expired",
+			Owner:        "default/other",
+			Resources:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			CreationTime: metav1.NewTime(now.Add(-time.Hour)),
+			TTLSeconds:   60,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		nodePods []*v1.Pod
+		expected *Resource
+	}{
+		{
+			name:     "reserved resources are hidden from other pods",
+			pod:      makeReservationPod("p", "", "1"),
+			expected: &Resource{MilliCPU: 4000},
+		},
+		{
+			name:     "reserved resources are available to the owner",
+			pod:      makeReservationPod("p", "pg", "1"),
+			expected: nil,
+		},
+		{
+			name:     "reservation is consumed by the owner pods on the node",
+			pod:      makeReservationPod("p", "", "1"),
+			nodePods: []*v1.Pod{makeReservationPod("pg-0", "pg", "3")},
+			expected: &Resource{MilliCPU: 1000},
+		},
+		{
+			name:     "reservation is fully consumed",
+			pod:      makeReservationPod("p", "", "1"),
+			nodePods: []*v1.Pod{makeReservationPod("pg-0", "pg", "3"), makeReservationPod("pg-1", "pg", "3")},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := NewNodeInfo(tt.nodePods...)
+			got := GetReservedResource(reservations, nodeInfo, tt.pod, now)
+			if got != nil {
+				got = &Resource{MilliCPU: got.MilliCPU, Memory: got.Memory}
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package api
 
 import (
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kubewharf/godel-scheduler/pkg/util/bitplace"
@@ -43,6 +45,10 @@ const (
 	DefaultSubClusterIndex      = 0
 	DefaultSubClusterSwitchType = (SwitchType(1) << DefaultSubClusterIndex) | (SwitchType(1) << DefaultSubClusterIndex << MaxSwitchNum)
 	DefaultSubCluster           = ""
+
+	// SchedulerNameProfilePrefix is the prefix of the cluster index keys of the schedulerName profiles.
+	// The colon is not allowed in label values, so the keys never collide with the subclusters.
+	SchedulerNameProfilePrefix = "schedulerName:"
 )
 
 func (st SwitchType) String() string {
@@ -158,13 +164,47 @@ func ParseSwitchTypeFromSubCluster(subCluster string) SwitchType {
 	return 0
 }
 
-var globalSubClusterKey string
+// SchedulerNameProfileKey returns the cluster index key of the schedulerName profile.
+func SchedulerNameProfileKey(schedulerName string) string {
+	return SchedulerNameProfilePrefix + schedulerName
+}
+
+// IsSchedulerNameProfileKey returns true if the cluster index key belongs to a schedulerName profile.
+func IsSchedulerNameProfileKey(key string) bool {
+	return strings.HasPrefix(key, SchedulerNameProfilePrefix)
+}
+
+// ParseSwitchTypeFromSchedulerName returns the switch types of the schedulerName profile, 0 if the
+// profile doesn't exist.
+func ParseSwitchTypeFromSchedulerName(schedulerName string) SwitchType {
+	return ParseSwitchTypeFromSubCluster(SchedulerNameProfileKey(schedulerName))
+}
+
+// ParseSwitchTypeFromSchedulerNameProfiles returns the switch types of all the schedulerName profiles.
+// The workflows of the schedulerName profiles see all the nodes, so the node events should be sent to them.
+func ParseSwitchTypeFromSchedulerNameProfiles() SwitchType {
+	globalClusterIndexLock.RLock()
+	defer globalClusterIndexLock.RUnlock()
+	var st SwitchType
+	for key, idx := range globalClusterIndexMaintainer.hash {
+		if IsSchedulerNameProfileKey(key) {
+			gt, be := ClusterIndexToSwitchType(idx)
+			st |= gt | be
+		}
+	}
+	return st
+}
+
+// globalSubClusterKey is stored atomically, since the schedulers running in the same process (e.g. integration tests)
+// set it while the others are handling events.
+var globalSubClusterKey atomic.Value
 
 func GetGlobalSubClusterKey() string {
-	return globalSubClusterKey
+	key, _ := globalSubClusterKey.Load().(string)
+	return key
 }
 
 // SetGlobalSubClusterKey will be called only when init scheduler.
 func SetGlobalSubClusterKey(key string) {
-	globalSubClusterKey = key
+	globalSubClusterKey.Store(key)
 }
@@ -208,6 +208,11 @@ type QueuedUnitInfo struct {
 
 	// QueuePriorityScore is calculated according to pod.Spec, combined with priority. It should not change if no changes in pod.Spec.
 	QueuePriorityScore float64
+
+	// Starving and WeightedQueueUsage are snapshotted by the SnapshotUnitQueueSortPlugins, they're only
+	// updated when the unit is added to the queue or the queue re-sorts the units.
+	Starving           bool
+	WeightedQueueUsage float64
 }
 
 var (
@@ -1165,3 +1170,9 @@ type LoadAwareNodeMetricInfo struct {
 	ProfileMilliCPUUsage int64
 	ProfileMEMUsage      int64
 }
+
+// LoadAwareWorkloadUsage is the historical usage of one pod of the workload at some percentile.
+type LoadAwareWorkloadUsage struct {
+	MilliCPU int64
+	Memory   int64
+}
@@ -50,4 +50,6 @@ type SchedulerUnitFrameworkHandle interface {
 	GetUnitStatus(string) unitstatus.UnitStatus
 	IsCachedPod(pod *v1.Pod) (bool, error)
 	GetNodeInfo(nodeName string) NodeInfo
+	// GetReservedNodes returns the nodes having resource reservations owned by the pod.
+	GetReservedNodes(pod *v1.Pod) []string
 }
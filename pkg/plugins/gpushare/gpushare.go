@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpushare
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// Policy is the policy to pick the shared GPU device for pods.
+type Policy string
+
+const (
+	// PolicyBinpack packs pods onto the device with the least free GPU memory to reduce fragmentation.
+	PolicyBinpack Policy = "Binpack"
+	// PolicySpread spreads pods onto the device with the most free GPU memory to reduce interference.
+	PolicySpread Policy = "Spread"
+
+	ErrReasonInsufficientDevice = "Insufficient shared GPU device"
+)
+
+// Request is the shared GPU resources requested by a pod, which must be served by one device.
+type Request struct {
+	Memory int64
+	Core   int64
+}
+
+// IsEmpty checks whether the pod requests shared GPU resources.
+func (r Request) IsEmpty() bool {
+	return r.Memory <= 0 && r.Core <= 0
+}
+
+// GetPodRequest returns the shared GPU resources requested by the pod.
+func GetPodRequest(pod *v1.Pod) Request {
+	res, _, _ := framework.CalculateResource(pod)
+	return Request{
+		Memory: res.ScalarResources[util.ResourceGPUMemory],
+		Core:   res.ScalarResources[util.ResourceGPUCore],
+	}
+}
+
+// Fits checks whether the device has enough free shared GPU resources for the request.
+func Fits(device *framework.GPUDeviceStatus, request Request) bool {
+	return device.Free(util.ResourceGPUMemory) >= request.Memory && device.Free(util.ResourceGPUCore) >= request.Core
+}
+
+// SelectDevice picks the device for the request according to the policy, nil means none of the devices fits.
+func SelectDevice(devices []*framework.GPUDeviceStatus, request Request, policy Policy) *framework.GPUDeviceStatus {
+	var selected *framework.GPUDeviceStatus
+	for _, device := range devices {
+		if !Fits(device, request) {
+			continue
+		}
+		if selected == nil || better(device, selected, policy) {
+			selected = device
+		}
+	}
+	return selected
+}
+
+// better checks whether device a should be preferred to device b, the devices are compared by free GPU memory
+// first and then by free GPU core. Devices are iterated by index, so the smaller index wins the tie.
+func better(a, b *framework.GPUDeviceStatus, policy Policy) bool {
+	freeMemoryA, freeMemoryB := a.Free(util.ResourceGPUMemory), b.Free(util.ResourceGPUMemory)
+	freeCoreA, freeCoreB := a.Free(util.ResourceGPUCore), b.Free(util.ResourceGPUCore)
+	if policy == PolicySpread {
+		return freeMemoryA > freeMemoryB || (freeMemoryA == freeMemoryB && freeCoreA > freeCoreB)
+	}
+	return freeMemoryA < freeMemoryB || (freeMemoryA == freeMemoryB && freeCoreA < freeCoreB)
+}
+
+// Utilization returns the GPU memory utilization of the device after placing the request, in [0, 1].
+func Utilization(device *framework.GPUDeviceStatus, request Request) float64 {
+	allocatable := device.Allocatable[util.ResourceGPUMemory]
+	if allocatable <= 0 {
+		return 0
+	}
+	utilization := float64(device.Requested[util.ResourceGPUMemory]+request.Memory) / float64(allocatable)
+	if utilization > 1 {
+		return 1
+	}
+	return utilization
+}
+
+// FormatDevice returns the value of the pod annotation for the device.
+func FormatDevice(device *framework.GPUDeviceStatus) string {
+	return strconv.Itoa(device.Index)
+}
+
+// FeasibleAssignedDevice checks whether the shared GPU device assigned to the pod still fits on the node, which may be
+// changed by the pods placed concurrently. If no device is assigned, any fitting device is accepted.
+func FeasibleAssignedDevice(pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	request := GetPodRequest(pod)
+	if request.IsEmpty() {
+		return nil
+	}
+	devices := nodeInfo.GetGPUShareDevices()
+	index, assigned := podutil.GetPodGPUShareDevice(pod)
+	if !assigned {
+		if SelectDevice(devices, request, PolicyBinpack) == nil {
+			return framework.NewStatus(framework.Unschedulable, ErrReasonInsufficientDevice)
+		}
+		return nil
+	}
+	for _, device := range devices {
+		if device.Index == index {
+			if !Fits(device, request) {
+				return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("%s: device %d is overcommitted", ErrReasonInsufficientDevice, index))
+			}
+			return nil
+		}
+	}
+	return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("%s: device %d not found", ErrReasonInsufficientDevice, index))
+}
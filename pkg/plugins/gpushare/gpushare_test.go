@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpushare
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeDevice(index int, memory, requestedMemory, core, requestedCore int64) *framework.GPUDeviceStatus {
+	return &framework.GPUDeviceStatus{
+		Index:       index,
+		Allocatable: map[v1.ResourceName]int64{util.ResourceGPUMemory: memory, util.ResourceGPUCore: core},
+		Requested:   map[v1.ResourceName]int64{util.ResourceGPUMemory: requestedMemory, util.ResourceGPUCore: requestedCore},
+	}
+}
+
+func makePod(name, memory, device string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			Annotations: map[string]string{
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{}},
+			}},
+		},
+	}
+	if len(memory) > 0 {
+		pod.Spec.Containers[0].Resources.Requests[util.ResourceGPUMemory] = resource.MustParse(memory)
+	}
+	if len(device) > 0 {
+		pod.Annotations[podutil.GPUShareDeviceAnnotationKey] = device
+	}
+	return pod
+}
+
+func TestSelectDevice(t *testing.T) {
+	devices := []*framework.GPUDeviceStatus{
+		makeDevice(0, 16, 0, 100, 0),
+		makeDevice(1, 16, 10, 100, 20),
+		makeDevice(2, 16, 10, 100, 60),
+		makeDevice(3, 16, 14, 100, 0),
+	}
+	tests := []struct {
+		name     string
+		request  Request
+		policy   Policy
+		expected int
+	}{
+		{name: "binpack prefers least free memory then least free core", request: Request{Memory: 4}, policy: PolicyBinpack, expected: 2},
+		{name: "binpack skips devices without enough core", request: Request{Memory: 4, Core: 50}, policy: PolicyBinpack, expected: 1},
+		{name: "spread prefers most free memory", request: Request{Memory: 4}, policy: PolicySpread, expected: 0},
+		{name: "no device fits", request: Request{Memory: 20}, policy: PolicyBinpack, expected: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := -1
+			if device := SelectDevice(devices, tt.request, tt.policy); device != nil {
+				got = device.Index
+			}
+			if got != tt.expected {
+				t.Errorf("expected device %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFeasibleAssignedDevice(t *testing.T) {
+	defer utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.GPUShareScheduling): false})
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.GPUShareScheduling): true})
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Annotations: map[string]string{
+				util.GPUShareDevicesAnnotationKey: "0:godel.bytedance.com/gpu-memory=16Gi;1:godel.bytedance.com/gpu-memory=16Gi",
+			},
+		},
+	})
+	nodeInfo.AddPod(makePod("existing-0", "12Gi", "0"))
+	nodeInfo.AddPod(makePod("existing-1", "16Gi", "1"))
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected framework.Code
+	}{
+		{name: "no shared GPU request", pod: makePod("p", "", ""), expected: framework.Success},
+		{name: "assigned device fits", pod: makePod("p", "4Gi", "0"), expected: framework.Success},
+		{name: "assigned device overcommitted", pod: makePod("p", "8Gi", "0"), expected: framework.Unschedulable},
+		{name: "assigned device not found", pod: makePod("p", "4Gi", "2"), expected: framework.Unschedulable},
+		{name: "unassigned and some device fits", pod: makePod("p", "4Gi", ""), expected: framework.Success},
+		{name: "unassigned and no device fits", pod: makePod("p", "8Gi", ""), expected: framework.Unschedulable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FeasibleAssignedDevice(tt.pod, nodeInfo).Code(); got != tt.expected {
+				t.Errorf("expected code %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
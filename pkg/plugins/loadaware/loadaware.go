@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"encoding/json"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+)
+
+// GetNodeMetricInfo returns the usage reported in CNR by the pods of the given resource type,
+// nil means there is no node metric in CNR.
+func GetNodeMetricInfo(cnr *katalystv1alpha1.CustomNodeResource, resourceType podutil.PodResourceType) *framework.LoadAwareNodeMetricInfo {
+	if cnr == nil || cnr.Status.NodeMetricStatus == nil {
+		return nil
+	}
+	info := &framework.LoadAwareNodeMetricInfo{
+		Name:       cnr.Name,
+		UpdateTime: cnr.Status.NodeMetricStatus.UpdateTime,
+	}
+	groupMetric := cnr.Status.NodeMetricStatus.GroupMetric
+	for i := range groupMetric {
+		if podutil.GetResourceTypeFromQoS(groupMetric[i].QoSLevel) == resourceType {
+			u := groupMetric[i].GenericUsage
+			if u == nil {
+				continue
+			}
+			if u.CPU != nil {
+				info.ProfileMilliCPUUsage += u.CPU.MilliValue()
+			}
+			if u.Memory != nil {
+				info.ProfileMEMUsage += u.Memory.Value()
+			}
+		}
+	}
+	return info
+}
+
+// GetAllocatable returns the allocatable resources of the node for pods of the given resource type.
+func GetAllocatable(nodeInfo framework.NodeInfo, resourceType podutil.PodResourceType) *framework.Resource {
+	switch resourceType {
+	case podutil.GuaranteedPod:
+		return nodeInfo.GetGuaranteedAllocatable()
+	case podutil.BestEffortPod:
+		return nodeInfo.GetBestEffortAllocatable()
+	}
+	return framework.NewResource(nil)
+}
+
+// ExcessUsage returns how much the usage of each resource exceeds its threshold, which is the percentage
+// of the allocatable resources. Only the resources whose usage exceed the thresholds are returned.
+func ExcessUsage(nodeMetricInfo *framework.LoadAwareNodeMetricInfo, allocatable *framework.Resource, usageThresholds map[v1.ResourceName]int64) map[v1.ResourceName]int64 {
+	var excess map[v1.ResourceName]int64
+	for resourceName, threshold := range usageThresholds {
+		// TODO: support more resources.
+		var usage, total int64
+		switch resourceName {
+		case v1.ResourceCPU:
+			usage = nodeMetricInfo.ProfileMilliCPUUsage
+			total = allocatable.MilliCPU
+		case v1.ResourceMemory:
+			usage = nodeMetricInfo.ProfileMEMUsage
+			total = allocatable.Memory
+		default:
+			continue
+		}
+		if limit := float64(threshold) / 100.0 * float64(total); float64(usage) > limit {
+			if excess == nil {
+				excess = make(map[v1.ResourceName]int64)
+			}
+			excess[resourceName] = usage - int64(limit)
+		}
+	}
+	return excess
+}
+
+// ExceedsUsageThresholds checks whether the usage of any resource exceeds its threshold.
+func ExceedsUsageThresholds(nodeMetricInfo *framework.LoadAwareNodeMetricInfo, allocatable *framework.Resource, usageThresholds map[v1.ResourceName]int64) bool {
+	return len(ExcessUsage(nodeMetricInfo, allocatable, usageThresholds)) > 0
+}
+
+// WorkloadUsageProfile is the historical usage of one pod of the workload, keyed by percentile.
+type WorkloadUsageProfile map[int64]framework.LoadAwareWorkloadUsage
+
+// Usage returns the usage at the smallest reported percentile which is not less than the given one.
+func (p WorkloadUsageProfile) Usage(percentile int64) (framework.LoadAwareWorkloadUsage, bool) {
+	var (
+		selected int64
+		found    bool
+	)
+	for reported := range p {
+		if reported >= percentile && (!found || reported < selected) {
+			selected, found = reported, true
+		}
+	}
+	return p[selected], found
+}
+
+// WorkloadUsageProfiles are the usage profiles of one workload reported by different nodes, keyed by node name.
+type WorkloadUsageProfiles map[string]WorkloadUsageProfile
+
+// Usage returns the max usage at the given percentile among all the nodes, nil means there is no usage history.
+func (p WorkloadUsageProfiles) Usage(percentile int64) *framework.LoadAwareWorkloadUsage {
+	var ret *framework.LoadAwareWorkloadUsage
+	for _, profile := range p {
+		usage, ok := profile.Usage(percentile)
+		if !ok {
+			continue
+		}
+		if ret == nil {
+			ret = &framework.LoadAwareWorkloadUsage{}
+		}
+		if usage.MilliCPU > ret.MilliCPU {
+			ret.MilliCPU = usage.MilliCPU
+		}
+		if usage.Memory > ret.Memory {
+			ret.Memory = usage.Memory
+		}
+	}
+	return ret
+}
+
+// ParseWorkloadUsageProfiles parses the workload usage profiles reported by the node in the CNR annotation.
+func ParseWorkloadUsageProfiles(cnr *katalystv1alpha1.CustomNodeResource) map[string]WorkloadUsageProfile {
+	if cnr == nil {
+		return nil
+	}
+	value := cnr.Annotations[util.WorkloadUsageProfileAnnotationKey]
+	if len(value) == 0 {
+		return nil
+	}
+	raw := make(map[string]map[string]v1.ResourceList)
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		klog.InfoS("WARN: Failed to parse workload usage profiles from CNR", "cnr", cnr.Name, "err", err)
+		return nil
+	}
+	profiles := make(map[string]WorkloadUsageProfile, len(raw))
+	for workloadKey, percentiles := range raw {
+		profile := make(WorkloadUsageProfile, len(percentiles))
+		for str, usage := range percentiles {
+			percentile, err := strconv.ParseInt(str, 10, 64)
+			if err != nil || percentile <= 0 || percentile > 100 {
+				klog.InfoS("WARN: Invalid percentile in workload usage profiles", "cnr", cnr.Name, "workload", workloadKey, "percentile", str)
+				continue
+			}
+			profile[percentile] = framework.LoadAwareWorkloadUsage{
+				MilliCPU: usage.Cpu().MilliValue(),
+				Memory:   usage.Memory().Value(),
+			}
+		}
+		if len(profile) > 0 {
+			profiles[workloadKey] = profile
+		}
+	}
+	return profiles
+}
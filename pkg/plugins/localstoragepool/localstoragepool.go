@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstoragepool
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+)
+
+const (
+	ErrReasonInsufficientLocalStorage = "Insufficient local storage pool"
+)
+
+// Requests is the storage requested by a pod from the node-local storage pools, keyed by storage class.
+type Requests struct {
+	// All contains the requests of all the local volumes of the pod.
+	All map[string]int64
+	// Pending contains the requests of the volumes which are not provisioned yet,
+	// only they need the free capacity of the pools.
+	Pending map[string]int64
+}
+
+// IsEmpty checks whether the pod requests any local storage.
+func (r *Requests) IsEmpty() bool {
+	return len(r.All) == 0
+}
+
+// GetPodRequests returns the storage requested by the pod from the pools of the given local storage classes.
+func GetPodRequests(pod *v1.Pod, localStorageClasses sets.String, pvcLister corelisters.PersistentVolumeClaimLister) (*Requests, error) {
+	requests := &Requests{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := pvcLister.PersistentVolumeClaims(pod.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
+		if err != nil {
+			return nil, err
+		}
+		if pvc.Spec.StorageClassName == nil || !localStorageClasses.Has(*pvc.Spec.StorageClassName) {
+			continue
+		}
+		storageClass := *pvc.Spec.StorageClassName
+		quantity := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+		if requests.All == nil {
+			requests.All = make(map[string]int64)
+		}
+		requests.All[storageClass] += quantity.Value()
+		if len(pvc.Spec.VolumeName) == 0 {
+			if requests.Pending == nil {
+				requests.Pending = make(map[string]int64)
+			}
+			requests.Pending[storageClass] += quantity.Value()
+		}
+	}
+	return requests, nil
+}
+
+// Fits checks whether the pools have enough free capacity for the requests.
+func Fits(requests map[string]int64, pools []*framework.LocalStoragePool) *framework.Status {
+	if len(requests) == 0 {
+		return nil
+	}
+	free := make(map[string]int64, len(pools))
+	for _, pool := range pools {
+		free[pool.StorageClass] = pool.Free()
+	}
+	for storageClass, request := range requests {
+		if available, ok := free[storageClass]; !ok || available < request {
+			return framework.NewStatus(framework.Unschedulable, ErrReasonInsufficientLocalStorage,
+				fmt.Sprintf("%s: requested %d, available %d", storageClass, request, available))
+		}
+	}
+	return nil
+}
+
+// Utilization returns the weighted average of the utilization of the pools requested by the pod,
+// assuming the pod is placed onto the node. Storage classes without weight are weighted as 1.
+func Utilization(requests map[string]int64, pools []*framework.LocalStoragePool, weights map[string]int64) float64 {
+	var weightSum, utilizationSum float64
+	for _, pool := range pools {
+		request, ok := requests[pool.StorageClass]
+		if !ok || pool.Capacity <= 0 {
+			continue
+		}
+		weight := float64(1)
+		if w, ok := weights[pool.StorageClass]; ok {
+			weight = float64(w)
+		}
+		utilization := float64(pool.Requested+request) / float64(pool.Capacity)
+		if utilization > 1 {
+			utilization = 1
+		}
+		weightSum += weight
+		utilizationSum += weight * utilization
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return utilizationSum / weightSum
+}
@@ -105,6 +105,17 @@ func FitsRequest(podRequest *PodRequest, nodeInfo framework.NodeInfo, ignoredExt
 	}
 }
 
+// FitsRequestWithReserved is similar to FitsRequest, but the resources reserved on the node are treated as requested.
+// Reservations only apply to guaranteed resources.
+func FitsRequestWithReserved(podRequest *PodRequest, nodeInfo framework.NodeInfo, reserved *framework.Resource, ignoredExtendedResources, ignoredResourceGroups sets.String) []InsufficientResource {
+	if reserved == nil || podRequest.ResourceType != podutil.GuaranteedPod {
+		return FitsRequest(podRequest, nodeInfo, ignoredExtendedResources, ignoredResourceGroups)
+	}
+	requested := nodeInfo.GetGuaranteedRequested().Clone()
+	requested.AddResource(reserved)
+	return fitsRequestCore(podRequest, nodeInfo.NumPods(), nodeInfo.GetGuaranteedAllocatable(), requested, ignoredExtendedResources, ignoredResourceGroups)
+}
+
 func fitsRequestCore(
 	podRequest *PodRequest,
 	podNumber int,
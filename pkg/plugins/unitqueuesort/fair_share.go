@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// FairShareName is the name of the plugin used in the plugin registry and configurations.
+// FairShare interleaves the units of equal priority by the recent usage of their fair-share queues.
+const FairShareName = "FairShare"
+
+const (
+	// DefaultUsageHalfLifeSeconds is the default time it takes for the recent usage of a queue to decay by half.
+	DefaultUsageHalfLifeSeconds int64 = 600
+
+	// fairShareRefreshInterval is how often the usage is refreshed from the scheduler cache, and the queue
+	// re-snapshots the usage into the units and re-sorts them.
+	fairShareRefreshInterval = time.Second
+	// minQueueUsage is the usage below which an idle queue is forgotten.
+	minQueueUsage = 1e-6
+)
+
+// FairShare is a plugin that keeps one queue from monopolizing the scheduler within a priority band.
+// The recent usage of a queue is the exponential moving average of the share of the resources requested
+// by its assumed and bound pods, and the units of the queue with a lower usage relative to its weight go first.
+// ATTENTION: The usage is tracked by the scheduler cache only if FairShareQueueSort is enabled.
+type FairShare struct {
+	DefaultUnitQueueSort
+
+	weights  map[string]float64
+	halfLife time.Duration
+	now      func() time.Time
+
+	mu          sync.Mutex
+	listShares  func() map[string]float64
+	lastRefresh time.Time
+	usage       map[string]float64
+}
+
+var (
+	_ framework.FairShareUnitQueueSortPlugin = &FairShare{}
+	_ framework.SnapshotUnitQueueSortPlugin  = &FairShare{}
+)
+
+// Name returns name of the plugin.
+func (p *FairShare) Name() string {
+	return FairShareName
+}
+
+// Less is the function used by the activeQ heap algorithm to sort units.
+// Units are sorted by priority first, then the one whose queue has a lower weighted usage goes first.
+// Otherwise, units are sorted in the same way as DefaultUnitQueueSort.
+// The usage is read from the snapshots of the units, so that the order doesn't change over time.
+func (p *FairShare) Less(uInfo1 *framework.QueuedUnitInfo, uInfo2 *framework.QueuedUnitInfo) bool {
+	compareResult := ComparePriorityForDebug(uInfo1.GetAnnotations(), uInfo2.GetAnnotations())
+	if compareResult != EQUAL {
+		return compareResult == GREATER
+	}
+	score1, score2 := uInfo1.QueuePriorityScore, uInfo2.QueuePriorityScore
+	if score1 != score2 {
+		return score1 > score2
+	}
+	queue1 := podutil.GetFairShareQueue(uInfo1.GetNamespace(), uInfo1.GetAnnotations())
+	queue2 := podutil.GetFairShareQueue(uInfo2.GetNamespace(), uInfo2.GetAnnotations())
+	if queue1 != queue2 && uInfo1.WeightedQueueUsage != uInfo2.WeightedQueueUsage {
+		return uInfo1.WeightedQueueUsage < uInfo2.WeightedQueueUsage
+	}
+	return p.DefaultUnitQueueSort.Less(uInfo1, uInfo2)
+}
+
+// SetQueueShareLister sets the function returning the shares of the resources currently requested by each queue.
+func (p *FairShare) SetQueueShareLister(listShares func() map[string]float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listShares = listShares
+}
+
+// SnapshotUnit snapshots the current weighted usage of the queue of the unit.
+func (p *FairShare) SnapshotUnit(uInfo *framework.QueuedUnitInfo) {
+	uInfo.WeightedQueueUsage = p.currentWeightedUsage(podutil.GetFairShareQueue(uInfo.GetNamespace(), uInfo.GetAnnotations()))
+}
+
+// SnapshotInterval returns how often the usage is refreshed.
+func (p *FairShare) SnapshotInterval() time.Duration {
+	return fairShareRefreshInterval
+}
+
+// currentWeightedUsage returns the recent usage of the queue divided by its weight, the usage is refreshed
+// if it's older than the refresh interval.
+func (p *FairShare) currentWeightedUsage(queue string) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now := p.now(); now.Sub(p.lastRefresh) >= fairShareRefreshInterval {
+		p.refresh(now)
+	}
+	return p.weightedUsage(queue)
+}
+
+func (p *FairShare) weightedUsage(queue string) float64 {
+	weight, ok := p.weights[queue]
+	if !ok {
+		weight = 1
+	}
+	return p.usage[queue] / weight
+}
+
+// refresh ages the usage to now, moving it towards the current shares. It must be called with the lock held.
+func (p *FairShare) refresh(now time.Time) {
+	if p.listShares == nil {
+		return
+	}
+	shares := p.listShares()
+	if p.lastRefresh.IsZero() {
+		p.usage = make(map[string]float64, len(shares))
+		for queue, share := range shares {
+			p.usage[queue] = share
+		}
+		p.lastRefresh = now
+		return
+	}
+
+	decay := math.Exp2(-float64(now.Sub(p.lastRefresh)) / float64(p.halfLife))
+	for queue, usage := range p.usage {
+		if _, ok := shares[queue]; ok {
+			continue
+		}
+		if usage *= decay; usage < minQueueUsage {
+			delete(p.usage, queue)
+		} else {
+			p.usage[queue] = usage
+		}
+	}
+	for queue, share := range shares {
+		p.usage[queue] = share + (p.usage[queue]-share)*decay
+	}
+	p.lastRefresh = now
+}
+
+// NewFairShare initializes a new plugin and returns it.
+func NewFairShare(plArgs runtime.Object) (framework.UnitQueueSortPlugin, error) {
+	halfLifeSeconds := DefaultUsageHalfLifeSeconds
+	var weights map[string]float64
+	if plArgs != nil {
+		args, ok := plArgs.(*config.FairShareArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type FairShareArgs, got %T", plArgs)
+		}
+		if args.UsageHalfLifeSeconds != nil {
+			halfLifeSeconds = *args.UsageHalfLifeSeconds
+		}
+		weights = args.QueueWeights
+	}
+	if halfLifeSeconds <= 0 {
+		return nil, fmt.Errorf("invalid args of %v: usageHalfLifeSeconds %v", FairShareName, halfLifeSeconds)
+	}
+	for queue, weight := range weights {
+		if weight <= 0 {
+			return nil, fmt.Errorf("invalid args of %v: weight %v of queue %v", FairShareName, weight, queue)
+		}
+	}
+	return &FairShare{
+		weights:  weights,
+		halfLife: time.Duration(halfLifeSeconds) * time.Second,
+		now:      time.Now,
+		usage:    make(map[string]float64),
+	}, nil
+}
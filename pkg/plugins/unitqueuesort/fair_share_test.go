@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func createNamespacedGangUnit(namespace, name string, priority int32, annotations map[string]string, timestamp time.Time) *framework.QueuedUnitInfo {
+	pg := v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations, CreationTimestamp: metav1.NewTime(timestamp)},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: 2},
+	}
+	return &framework.QueuedUnitInfo{
+		ScheduleUnit:       framework.NewPodGroupUnit(&pg, priority),
+		Timestamp:          timestamp,
+		QueuePriorityScore: float64(priority),
+	}
+}
+
+func TestFairShare_Less(t *testing.T) {
+	now := time.Now()
+	halfLife := int64(60)
+	pl, err := NewFairShare(&config.FairShareArgs{
+		QueueWeights:         map[string]float64{"heavy": 4},
+		UsageHalfLifeSeconds: &halfLife,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort := pl.(*FairShare)
+	sort.now = func() time.Time { return now }
+	shares := map[string]float64{"busy": 0.4, "heavy": 0.2, "idle": 0.1}
+	sort.SetQueueShareLister(func() map[string]float64 { return shares })
+
+	earlier, later := now.Add(-time.Minute), now
+	for _, tt := range []struct {
+		name     string
+		u1       *framework.QueuedUnitInfo
+		u2       *framework.QueuedUnitInfo
+		expected bool
+	}{
+		{
+			name:     "higher priority goes first regardless of usage",
+			u1:       createNamespacedGangUnit("busy", "a", 100, nil, later),
+			u2:       createNamespacedGangUnit("idle", "b", 10, nil, earlier),
+			expected: true,
+		},
+		{
+			name:     "queue with lower usage goes first within a priority",
+			u1:       createNamespacedGangUnit("busy", "a", 10, nil, earlier),
+			u2:       createNamespacedGangUnit("idle", "b", 10, nil, later),
+			expected: false,
+		},
+		{
+			name:     "usage is relative to the queue weight",
+			u1:       createNamespacedGangUnit("heavy", "a", 10, nil, later),
+			u2:       createNamespacedGangUnit("idle", "b", 10, nil, earlier),
+			expected: true,
+		},
+		{
+			name:     "queue annotation overrides the namespace",
+			u1:       createNamespacedGangUnit("busy", "a", 10, map[string]string{podutil.FairShareQueueAnnotationKey: "idle"}, later),
+			u2:       createNamespacedGangUnit("busy", "b", 10, nil, earlier),
+			expected: true,
+		},
+		{
+			name:     "units of the same queue are sorted by timestamp",
+			u1:       createNamespacedGangUnit("busy", "a", 10, nil, later),
+			u2:       createNamespacedGangUnit("busy", "b", 10, nil, earlier),
+			expected: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sort.SnapshotUnit(tt.u1)
+			sort.SnapshotUnit(tt.u2)
+			if got := sort.Less(tt.u1, tt.u2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFairShare_UsageAging(t *testing.T) {
+	now := time.Now()
+	halfLife := int64(60)
+	pl, err := NewFairShare(&config.FairShareArgs{UsageHalfLifeSeconds: &halfLife})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort := pl.(*FairShare)
+	sort.now = func() time.Time { return now }
+	shares := map[string]float64{"a": 0.8}
+	sort.SetQueueShareLister(func() map[string]float64 { return shares })
+
+	if usage := sort.currentWeightedUsage("a"); usage != 0.8 {
+		t.Fatalf("expected initial usage 0.8, got %v", usage)
+	}
+
+	// The usage of a queue moves towards its current share by half every half-life.
+	shares = map[string]float64{"b": 0.4}
+	now = now.Add(time.Minute)
+	usageA, usageB := sort.currentWeightedUsage("a"), sort.currentWeightedUsage("b")
+	if usageA != 0.4 || usageB != 0.2 {
+		t.Errorf("expected usage 0.4 and 0.2 after one half-life, got %v and %v", usageA, usageB)
+	}
+
+	// The usage is kept within the refresh interval.
+	shares = map[string]float64{}
+	now = now.Add(fairShareRefreshInterval / 2)
+	if usageA = sort.currentWeightedUsage("a"); usageA != 0.4 {
+		t.Errorf("expected usage 0.4 within the refresh interval, got %v", usageA)
+	}
+
+	// Idle queues are forgotten eventually.
+	now = now.Add(time.Hour)
+	sort.currentWeightedUsage("a")
+	if len(sort.usage) != 0 {
+		t.Errorf("expected idle queues to be forgotten, got %v", sort.usage)
+	}
+}
+
+func TestFairShare_SnapshotUnit(t *testing.T) {
+	now := time.Now()
+	pl, err := NewFairShare(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort := pl.(*FairShare)
+	sort.now = func() time.Time { return now }
+	shares := map[string]float64{"a": 0.2, "b": 0.4}
+	sort.SetQueueShareLister(func() map[string]float64 { return shares })
+
+	u1 := createNamespacedGangUnit("a", "u1", 10, nil, now)
+	u2 := createNamespacedGangUnit("b", "u2", 10, nil, now)
+	sort.SnapshotUnit(u1)
+	sort.SnapshotUnit(u2)
+	if !sort.Less(u1, u2) {
+		t.Fatalf("expected the unit of the queue with lower usage to go first")
+	}
+
+	// The order is kept until the units are snapshotted again, even if the usage has been refreshed.
+	shares = map[string]float64{"a": 0.8}
+	now = now.Add(time.Hour)
+	sort.currentWeightedUsage("a")
+	if !sort.Less(u1, u2) {
+		t.Errorf("expected the order to be kept until the units are snapshotted again")
+	}
+	sort.SnapshotUnit(u1)
+	sort.SnapshotUnit(u2)
+	if sort.Less(u1, u2) {
+		t.Errorf("expected the order to follow the usage after the units are snapshotted")
+	}
+}
+
+func TestNewFairShare(t *testing.T) {
+	if _, err := NewFairShare(nil); err != nil {
+		t.Errorf("unexpected error for default args: %v", err)
+	}
+	invalid := int64(0)
+	if _, err := NewFairShare(&config.FairShareArgs{UsageHalfLifeSeconds: &invalid}); err == nil {
+		t.Errorf("expected error for non-positive half-life")
+	}
+	if _, err := NewFairShare(&config.FairShareArgs{QueueWeights: map[string]float64{"a": 0}}); err == nil {
+		t.Errorf("expected error for non-positive weight")
+	}
+	if _, err := NewFairShare(&v1.Pod{}); err == nil {
+		t.Errorf("expected error for unexpected args type")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+)
+
+// StarvationAwareName is the name of the plugin used in the plugin registry and configurations.
+// StarvationAware sorts the starving PodGroup units ahead, and holds back resources for them to backfill the others.
+const StarvationAwareName = "StarvationAware"
+
+const (
+	// DefaultStarvationThresholdSeconds is the default time a PodGroup unit keeps pending before it is considered starving.
+	DefaultStarvationThresholdSeconds int64 = 300
+	// DefaultReservationTTLSeconds is the default time the resources can be held back for a starving unit.
+	DefaultReservationTTLSeconds int64 = 600
+
+	// starvationSnapshotInterval is how often the queue re-checks whether the units are starving.
+	starvationSnapshotInterval = time.Second
+)
+
+// StarvationAware is a plugin that prevents PodGroup units from being starved by a stream of smaller units.
+// ATTENTION: Holding back resources relies on the ResourceReservation feature.
+type StarvationAware struct {
+	DefaultUnitQueueSort
+
+	starvationThreshold time.Duration
+	reservationTTL      time.Duration
+	now                 func() time.Time
+}
+
+var (
+	_ framework.StarvationAwareUnitQueueSortPlugin = &StarvationAware{}
+	_ framework.SnapshotUnitQueueSortPlugin        = &StarvationAware{}
+)
+
+// Name returns name of the plugin.
+func (p *StarvationAware) Name() string {
+	return StarvationAwareName
+}
+
+// Less is the function used by the activeQ heap algorithm to sort units.
+// Starving units are placed ahead of the others, and the one pending for the longest time goes first.
+// Otherwise, units are sorted in the same way as DefaultUnitQueueSort.
+// Whether a unit is starving is read from its snapshot, so that the order doesn't change over time.
+func (p *StarvationAware) Less(uInfo1 *framework.QueuedUnitInfo, uInfo2 *framework.QueuedUnitInfo) bool {
+	compareResult := ComparePriorityForDebug(uInfo1.GetAnnotations(), uInfo2.GetAnnotations())
+	if compareResult != EQUAL {
+		return compareResult == GREATER
+	}
+	starving1, starving2 := uInfo1.Starving, uInfo2.Starving
+	if starving1 != starving2 {
+		return starving1
+	}
+	if starving1 && !uInfo1.InitialAttemptTimestamp.Equal(uInfo2.InitialAttemptTimestamp) {
+		return uInfo1.InitialAttemptTimestamp.Before(uInfo2.InitialAttemptTimestamp)
+	}
+	return p.DefaultUnitQueueSort.Less(uInfo1, uInfo2)
+}
+
+// Starving checks whether the unit is a PodGroup requiring more than one member and has been
+// pending for longer than the starvation threshold.
+func (p *StarvationAware) Starving(uInfo *framework.QueuedUnitInfo) bool {
+	if uInfo.Type() != framework.PodGroupUnitType || uInfo.InitialAttemptTimestamp.IsZero() {
+		return false
+	}
+	if minMember, err := uInfo.GetMinMember(); err != nil || minMember <= 1 {
+		return false
+	}
+	return p.now().Sub(uInfo.InitialAttemptTimestamp) >= p.starvationThreshold
+}
+
+// SnapshotUnit snapshots whether the unit is starving now.
+func (p *StarvationAware) SnapshotUnit(uInfo *framework.QueuedUnitInfo) {
+	uInfo.Starving = p.Starving(uInfo)
+}
+
+// SnapshotInterval returns how often the units are re-checked for starvation.
+func (p *StarvationAware) SnapshotInterval() time.Duration {
+	return starvationSnapshotInterval
+}
+
+// ReservationTTL returns how long the resources can be held back for a starving unit.
+func (p *StarvationAware) ReservationTTL() time.Duration {
+	return p.reservationTTL
+}
+
+// NewStarvationAware initializes a new plugin and returns it.
+func NewStarvationAware(plArgs runtime.Object) (framework.UnitQueueSortPlugin, error) {
+	starvationThresholdSeconds, reservationTTLSeconds := DefaultStarvationThresholdSeconds, DefaultReservationTTLSeconds
+	if plArgs != nil {
+		args, ok := plArgs.(*config.StarvationAwareArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type StarvationAwareArgs, got %T", plArgs)
+		}
+		if args.StarvationThresholdSeconds != nil {
+			starvationThresholdSeconds = *args.StarvationThresholdSeconds
+		}
+		if args.ReservationTTLSeconds != nil {
+			reservationTTLSeconds = *args.ReservationTTLSeconds
+		}
+	}
+	if starvationThresholdSeconds < 0 || reservationTTLSeconds <= 0 {
+		return nil, fmt.Errorf("invalid args of %v: starvationThresholdSeconds %v, reservationTTLSeconds %v",
+			StarvationAwareName, starvationThresholdSeconds, reservationTTLSeconds)
+	}
+	return &StarvationAware{
+		starvationThreshold: time.Duration(starvationThresholdSeconds) * time.Second,
+		reservationTTL:      time.Duration(reservationTTLSeconds) * time.Second,
+		now:                 time.Now,
+	}, nil
+}
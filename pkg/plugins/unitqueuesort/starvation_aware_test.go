@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+)
+
+func createGangUnit(name string, minMember int32, priority int32, initialAttempt time.Time) *framework.QueuedUnitInfo {
+	pg := v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(initialAttempt)},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: minMember},
+	}
+	return &framework.QueuedUnitInfo{
+		ScheduleUnit:            framework.NewPodGroupUnit(&pg, priority),
+		Timestamp:               initialAttempt,
+		InitialAttemptTimestamp: initialAttempt,
+		QueuePriorityScore:      float64(priority),
+	}
+}
+
+func TestStarvationAware_Less(t *testing.T) {
+	now := time.Now()
+	pl, err := NewStarvationAware(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort := pl.(*StarvationAware)
+	sort.now = func() time.Time { return now }
+
+	longAgo := now.Add(-time.Duration(DefaultStarvationThresholdSeconds+60) * time.Second)
+	recently := now.Add(-time.Second)
+	for _, tt := range []struct {
+		name     string
+		u1       *framework.QueuedUnitInfo
+		u2       *framework.QueuedUnitInfo
+		expected bool
+	}{
+		{
+			name:     "starving gang is ahead of higher priority units",
+			u1:       createGangUnit("gang", 8, 10, longAgo),
+			u2:       createGangUnit("small", 2, 100, recently),
+			expected: true,
+		},
+		{
+			name:     "gang requiring only one member never starves",
+			u1:       createGangUnit("single", 1, 10, longAgo),
+			u2:       createGangUnit("small", 2, 100, recently),
+			expected: false,
+		},
+		{
+			name:     "the gang pending for longer goes first among starving gangs",
+			u1:       createGangUnit("gang-1", 8, 100, longAgo),
+			u2:       createGangUnit("gang-2", 8, 10, longAgo.Add(-time.Minute)),
+			expected: false,
+		},
+		{
+			name:     "non-starving units are sorted by priority",
+			u1:       createGangUnit("gang-1", 8, 100, recently),
+			u2:       createGangUnit("gang-2", 8, 10, recently),
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sort.SnapshotUnit(tt.u1)
+			sort.SnapshotUnit(tt.u2)
+			if got := sort.Less(tt.u1, tt.u2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestStarvationAware_SnapshotUnit(t *testing.T) {
+	now := time.Now()
+	pl, err := NewStarvationAware(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort := pl.(*StarvationAware)
+	sort.now = func() time.Time { return now }
+
+	recently := now.Add(-time.Second)
+	gang, small := createGangUnit("gang", 8, 10, recently), createGangUnit("small", 1, 100, recently)
+	sort.SnapshotUnit(gang)
+	sort.SnapshotUnit(small)
+	if sort.Less(gang, small) {
+		t.Fatalf("expected the gang not to be ahead before it starves")
+	}
+
+	// The order is kept until the units are snapshotted again.
+	now = now.Add(time.Duration(DefaultStarvationThresholdSeconds) * time.Second)
+	if sort.Less(gang, small) {
+		t.Errorf("expected the order to be kept until the units are snapshotted again")
+	}
+	sort.SnapshotUnit(gang)
+	sort.SnapshotUnit(small)
+	if !gang.Starving || !sort.Less(gang, small) {
+		t.Errorf("expected the starving gang to be ahead after it's snapshotted")
+	}
+}
+
+func TestNewStarvationAware(t *testing.T) {
+	threshold, ttl := int64(60), int64(120)
+	pl, err := NewStarvationAware(&config.StarvationAwareArgs{StarvationThresholdSeconds: &threshold, ReservationTTLSeconds: &ttl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pl.(*StarvationAware).ReservationTTL(); got != 2*time.Minute {
+		t.Errorf("expected reservation ttl 2m, got %v", got)
+	}
+
+	invalid := int64(0)
+	if _, err := NewStarvationAware(&config.StarvationAwareArgs{ReservationTTLSeconds: &invalid}); err == nil {
+		t.Errorf("expected error for non-positive reservation ttl")
+	}
+	if _, err := NewStarvationAware(&v1.Pod{}); err == nil {
+		t.Errorf("expected error for unexpected args type")
+	}
+}
@@ -43,6 +43,9 @@ func SetDefaults_GodelSchedulerConfiguration(obj *GodelSchedulerConfiguration) {
 		if obj.SchedulerRenewIntervalSeconds == 0 {
 			obj.SchedulerRenewIntervalSeconds = DefaultRenewIntervalInSeconds
 		}
+		if obj.SchedulerDrainTimeoutSeconds == 0 {
+			obj.SchedulerDrainTimeoutSeconds = DefaultDrainTimeoutInSeconds
+		}
 	}
 
 	// 2. ClientConnection and BindSetting
@@ -136,10 +139,19 @@ func SetDefaults_GodelSchedulerConfiguration(obj *GodelSchedulerConfiguration) {
 		if obj.Tracer == nil {
 			obj.Tracer = tracing.DefaultNoopOptions()
 		}
+		if obj.BinderHandoff == nil {
+			obj.BinderHandoff = &BinderHandoffConfiguration{}
+		}
+		if obj.BinderHandoff.TimeoutSeconds == 0 {
+			obj.BinderHandoff.TimeoutSeconds = DefaultBinderHandoffTimeoutInSeconds
+		}
 		if obj.SubClusterKey == nil {
 			defaultValue := DefaultSubClusterKey
 			obj.SubClusterKey = &defaultValue
 		}
+		if len(obj.NodePartitionType) == 0 {
+			obj.NodePartitionType = defaultsconfig.DefaultNodePartitionType
+		}
 	}
 	// 5. Godel Profiles
 	{
@@ -51,6 +51,11 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NodeResourcesBalancedAllocatedArgs{},
 		&LocalStoragePoolCheckerArgs{},
 		&LoadAwareArgs{},
+		&StarvationAwareArgs{},
+		&FairShareArgs{},
+		&PreemptionBudgetArgs{},
+		&NetworkTopologyArgs{},
+		&GPUShareArgs{},
 	)
 	return nil
 }
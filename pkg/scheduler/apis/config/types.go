@@ -104,6 +104,11 @@ const (
 	DefaultGodelSchedulerName = "godel-scheduler"
 	// DefaultRenewIntervalInSeconds is the default value for the renew interval duration for scheduler.
 	DefaultRenewIntervalInSeconds = 30
+	// DefaultDrainTimeoutInSeconds is the default value for the time a terminating scheduler waits for its units being scheduled.
+	DefaultDrainTimeoutInSeconds = 30
+	// DefaultBinderHandoffTimeoutInSeconds is the default value for the time a scheduler waits for the binder to bind
+	// the pods handed off to it before persisting their placements through the pod annotations.
+	DefaultBinderHandoffTimeoutInSeconds = 30
 
 	// DefaultSchedulerName is default high level scheduler name
 	DefaultSchedulerName = "godel-scheduler"
@@ -124,6 +129,8 @@ const (
 
 	// DefaultAttemptImpactFactorOnPriority is the default attempt factors used by godel sort
 	DefaultAttemptImpactFactorOnPriority = 10.0
+	// DefaultParallelUnitWorkers is the default number of units scheduled concurrently in one scheduling workflow.
+	DefaultParallelUnitWorkers = 1
 )
 
 var DefaultBindAddress = net.JoinHostPort(DefaultGodelSchedulerAddress, strconv.Itoa(DefaultInsecureSchedulerPort))
@@ -139,6 +146,9 @@ type GodelSchedulerConfiguration struct {
 	// SchedulerRenewIntervalSeconds is the duration for updating scheduler.
 	// If this value is null, the default value (30s) will be used.
 	SchedulerRenewIntervalSeconds int64
+	// SchedulerDrainTimeoutSeconds is the longest duration a terminating scheduler waits for the units being
+	// scheduled to finish before it leaves. If this value is null, the default value (30s) will be used.
+	SchedulerDrainTimeoutSeconds int64
 
 	// ClientConnection specifies the kubeconfig file and client connection
 	// settings for the proxy server to use when communicating with the apiserver.
@@ -165,8 +175,15 @@ type GodelSchedulerConfiguration struct {
 	// Tracer defines the configuration of tracer
 	Tracer *tracing.TracerConfiguration
 
+	// BinderHandoff defines the configuration of handing off the scheduled pods to the binder directly.
+	BinderHandoff *BinderHandoffConfiguration
+
 	SubClusterKey *string
 
+	// NodePartitionType is the type of the node partition of the scheduler, Physical or Logical.
+	// If this value is empty, the default value (Logical) will be used.
+	NodePartitionType string
+
 	// TODO: update the comment
 	// Profiles are scheduling profiles that kube-scheduler supports. Pods can
 	// choose to be scheduled under a particular profile by setting its associated
@@ -174,6 +191,31 @@ type GodelSchedulerConfiguration struct {
 	// with the "default-scheduler" profile, if present here.
 	DefaultProfile     *GodelSchedulerProfile
 	SubClusterProfiles []GodelSchedulerProfile
+
+	// SchedulerNameProfiles are the profiles for the pods whose spec.schedulerName is not SchedulerName
+	// but should be served by the same scheduling system, e.g. `godel-batch` and `godel-online`. Each profile
+	// is keyed by its SchedulerName, and the fields not set are inherited from DefaultProfile.
+	SchedulerNameProfiles []GodelSchedulerProfile
+}
+
+// BinderHandoffConfiguration configures handing off the scheduled pods to the binder directly.
+type BinderHandoffConfiguration struct {
+	// Enable hands off the assumed placements to the binder through BinderAddress, instead of
+	// persisting them through the pod annotations first. Placements not accepted by the binder
+	// still go through the pod annotations.
+	Enable bool
+	// BinderAddress is the address of the handoff server of the binder, e.g. `godel-binder:10452`.
+	BinderAddress string
+	// TimeoutSeconds is the longest duration the scheduler waits for the binder to bind a handed off pod.
+	// After that, its placement is persisted through the pod annotations if the pod is still not bound,
+	// so that the pods are not lost when the binder restarts. If this value is null, the default value (30s) will be used.
+	TimeoutSeconds int64
+	// CertFile and KeyFile are the client certificate and key presented to the handoff server of the binder.
+	// The common name of the certificate must be allowed by the binder.
+	CertFile string
+	KeyFile  string
+	// CAFile is the CA bundle verifying the serving certificate of the handoff server.
+	CAFile string
 }
 
 // GodelSchedulerProfile is a scheduling profile.
@@ -181,6 +223,10 @@ type GodelSchedulerProfile struct {
 	// ProfileKey associates the profile to a subcluster if it is not nil.
 	SubClusterName string
 
+	// SchedulerName associates the profile to the pods with the same spec.schedulerName.
+	// It is only used by SchedulerNameProfiles.
+	SchedulerName string
+
 	// BasePluginsForKubelet specify the set of default plugins.
 	BasePluginsForKubelet *Plugins
 
@@ -221,6 +267,10 @@ type GodelSchedulerProfile struct {
 	// BetterSelectPolicies
 	BetterSelectPolicies *StringSlice
 
+	// PreemptionDryRun runs the preemption fully even if DisablePreemption is true, the chosen node,
+	// victims and sorted candidates are recorded, but the victims are never nominated or persisted.
+	PreemptionDryRun *bool
+
 	// BlockQueue indicates whether a BlockQueue is required.
 	BlockQueue *bool
 
@@ -235,6 +285,12 @@ type GodelSchedulerProfile struct {
 	// If specified, it must be greater than or equal to unitInitialBackoffSeconds. If this value is null,
 	// the default value (10s) will be used.
 	UnitMaxBackoffSeconds *int64
+
+	// ParallelUnitWorkers is the number of units scheduled concurrently in one scheduling workflow.
+	// Each worker schedules against its own snapshot, and the decisions are validated by the node
+	// generations when they are applied to cache, the conflicted units will be retried.
+	// If specified, it must be greater than 0. If this value is null, the default value (1) will be used.
+	ParallelUnitWorkers *int32
 }
 
 // Plugins include multiple extension points. When specified, the list of plugins for
@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -216,6 +217,66 @@ type StartRecentlyArgs struct {
 	PreemptMinIntervalSeconds *int64 `json:"preemptMinIntervalSeconds,omitempty"`
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PreemptionBudgetArgs holds arguments used to configure the PreemptionBudgetChecker plugin,
+// they should be consistent with the preemption budget of the binders.
+type PreemptionBudgetArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	preemptionbudget.Budget `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// StarvationAwareArgs holds arguments used to configure the StarvationAware unit queue sort plugin.
+type StarvationAwareArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// StarvationThresholdSeconds is how long a PodGroup unit keeps pending before it is considered starving.
+	StarvationThresholdSeconds *int64 `json:"starvationThresholdSeconds,omitempty"`
+	// ReservationTTLSeconds is how long the resources can be held back for a starving unit.
+	ReservationTTLSeconds *int64 `json:"reservationTTLSeconds,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkTopologyArgs holds arguments used to configure the NetworkTopology unit plugin.
+type NetworkTopologyArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// TopologyLevels are the node label keys describing the network topology, ordered from the
+	// smallest domain to the largest one, e.g. ToR, spine and superpod. The node itself is always
+	// the implicit lowest level and should not be listed here.
+	TopologyLevels []string `json:"topologyLevels,omitempty"`
+	// MaxDomainsPerLevel is the max number of candidate topology domains returned for each level.
+	MaxDomainsPerLevel *int32 `json:"maxDomainsPerLevel,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FairShareArgs holds arguments used to configure the FairShare unit queue sort plugin.
+type FairShareArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// QueueWeights are the weights of the fair-share queues, keyed by the queue name, which is the namespace
+	// unless the queue annotation is set. The queues not listed have a weight of 1.
+	QueueWeights map[string]float64 `json:"queueWeights,omitempty"`
+	// UsageHalfLifeSeconds is how long it takes for the recent usage of a queue to decay by half.
+	UsageHalfLifeSeconds *int64 `json:"usageHalfLifeSeconds,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GPUShareArgs holds arguments used to configure the GPUShare plugin.
+type GPUShareArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Policy is how to pick the shared GPU device on a node, either "Binpack" or "Spread".
+	// Defaults to "Binpack".
+	Policy string `json:"policy,omitempty"`
+}
+
 type StringSlice []string
 
 type ScorePolicy string
@@ -227,13 +288,15 @@ const (
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
-// LocalStoragePoolCheckerArgs holds arguments used to configure NodeResourcesLeastAllocated plugin.
+// LocalStoragePoolCheckerArgs holds arguments used to configure LocalStoragePoolChecker plugin.
 type LocalStoragePoolCheckerArgs struct {
 	metav1.TypeMeta `json:",inline"`
 
 	// ScorePolicy to use
 	ScorePolicy ScorePolicy `json:"score_policy"`
 
+	// StorageClassWeights are the storage classes served by the node-local storage pools and their weights
+	// when scoring, the volumes of other storage classes are ignored by the plugin.
 	StorageClassWeights []ResourceSpec `json:"storage_class_weights"`
 }
 
@@ -258,4 +321,8 @@ type LoadAwareArgs struct {
 	// EstimatedScalingFactors indicates the factor when estimating resource usage.
 	// Is CPU scaling factor is 80, estimated CPU = 80 / 100 * request.cpu
 	EstimatedScalingFactors map[v1.ResourceName]int64 `json:"estimatedScalingFactors,omitempty"`
+
+	// HistoricalUsagePercentile indicates the percentile of the workload historical usage used by
+	// historicalUsageEstimator to estimate the usage of a pod, allowed values go from 1 to 100, defaults to 95.
+	HistoricalUsagePercentile int64 `json:"historicalUsagePercentile,omitempty"`
 }
@@ -44,6 +44,9 @@ func SetDefaults_GodelSchedulerConfiguration(obj *GodelSchedulerConfiguration) {
 		if obj.SchedulerRenewIntervalSeconds == 0 {
 			obj.SchedulerRenewIntervalSeconds = config.DefaultRenewIntervalInSeconds
 		}
+		if obj.SchedulerDrainTimeoutSeconds == 0 {
+			obj.SchedulerDrainTimeoutSeconds = config.DefaultDrainTimeoutInSeconds
+		}
 	}
 
 	// 2. ClientConnection and BindSetting
@@ -137,9 +140,18 @@ func SetDefaults_GodelSchedulerConfiguration(obj *GodelSchedulerConfiguration) {
 			defaultValue := config.DefaultSubClusterKey
 			obj.SubClusterKey = &defaultValue
 		}
+		if len(obj.NodePartitionType) == 0 {
+			obj.NodePartitionType = defaultsconfig.DefaultNodePartitionType
+		}
 		if obj.Tracer == nil {
 			obj.Tracer = tracing.DefaultNoopOptions()
 		}
+		if obj.BinderHandoff == nil {
+			obj.BinderHandoff = &BinderHandoffConfiguration{}
+		}
+		if obj.BinderHandoff.TimeoutSeconds == 0 {
+			obj.BinderHandoff.TimeoutSeconds = config.DefaultBinderHandoffTimeoutInSeconds
+		}
 	}
 	// 5. Godel Profiles
 	{
@@ -53,6 +53,11 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&config.NodeResourcesBalancedAllocatedArgs{},
 		&config.LocalStoragePoolCheckerArgs{},
 		&config.LoadAwareArgs{},
+		&config.StarvationAwareArgs{},
+		&config.FairShareArgs{},
+		&config.PreemptionBudgetArgs{},
+		&config.NetworkTopologyArgs{},
+		&config.GPUShareArgs{},
 	)
 	return nil
 }
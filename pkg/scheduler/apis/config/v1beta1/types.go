@@ -38,6 +38,9 @@ type GodelSchedulerConfiguration struct {
 	// SchedulerRenewIntervalSeconds is the duration for updating scheduler.
 	// If this value is null, the default value (30s) will be used.
 	SchedulerRenewIntervalSeconds int64 `json:"schedulerRenewIntervalSeconds"`
+	// SchedulerDrainTimeoutSeconds is the longest duration a terminating scheduler waits for the units being
+	// scheduled to finish before it leaves. If this value is null, the default value (30s) will be used.
+	SchedulerDrainTimeoutSeconds int64 `json:"schedulerDrainTimeoutSeconds,omitempty"`
 
 	// ClientConnection specifies the kubeconfig file and client connection
 	// settings for the proxy server to use when communicating with the apiserver.
@@ -61,10 +64,16 @@ type GodelSchedulerConfiguration struct {
 	// scheduler, binder) will not accept a pod, unless pod.Spec.SchedulerName == SchedulerName
 	SchedulerName *string `json:"schedulerName,omitempty"`
 	SubClusterKey *string `json:"subClusterKey,omitempty"`
+	// NodePartitionType is the type of the node partition of the scheduler, Physical or Logical.
+	// If this value is empty, the default value (Logical) will be used.
+	NodePartitionType string `json:"nodePartitionType,omitempty"`
 
 	// Tracer defines the configuration of tracer
 	Tracer *tracing.TracerConfiguration
 
+	// BinderHandoff defines the configuration of handing off the scheduled pods to the binder directly.
+	BinderHandoff *BinderHandoffConfiguration `json:"binderHandoff,omitempty"`
+
 	// TODO: update the comment
 	// Profiles are scheduling profiles that kube-scheduler supports. Pods can
 	// choose to be scheduled under a particular profile by setting its associated
@@ -72,6 +81,31 @@ type GodelSchedulerConfiguration struct {
 	// with the "default-scheduler" profile, if present here.
 	DefaultProfile     *GodelSchedulerProfile  `json:"defaultProfile,omitempty"`
 	SubClusterProfiles []GodelSchedulerProfile `json:"subClusterProfiles,omitempty"`
+
+	// SchedulerNameProfiles are the profiles for the pods whose spec.schedulerName is not SchedulerName
+	// but should be served by the same scheduling system, e.g. `godel-batch` and `godel-online`. Each profile
+	// is keyed by its SchedulerName, and the fields not set are inherited from DefaultProfile.
+	SchedulerNameProfiles []GodelSchedulerProfile `json:"schedulerNameProfiles,omitempty"`
+}
+
+// BinderHandoffConfiguration configures handing off the scheduled pods to the binder directly.
+type BinderHandoffConfiguration struct {
+	// Enable hands off the assumed placements to the binder through BinderAddress, instead of
+	// persisting them through the pod annotations first. Placements not accepted by the binder
+	// still go through the pod annotations.
+	Enable bool `json:"enable,omitempty"`
+	// BinderAddress is the address of the handoff server of the binder, e.g. `godel-binder:10452`.
+	BinderAddress string `json:"binderAddress,omitempty"`
+	// TimeoutSeconds is the longest duration the scheduler waits for the binder to bind a handed off pod.
+	// After that, its placement is persisted through the pod annotations if the pod is still not bound,
+	// so that the pods are not lost when the binder restarts. If this value is null, the default value (30s) will be used.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// CertFile and KeyFile are the client certificate and key presented to the handoff server of the binder.
+	// The common name of the certificate must be allowed by the binder.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	// CAFile is the CA bundle verifying the serving certificate of the handoff server.
+	CAFile string `json:"caFile,omitempty"`
 }
 
 // DecodeNestedObjects decodes plugin args for known types.
@@ -103,6 +137,11 @@ func (in *GodelSchedulerConfiguration) DecodeNestedObjects(d runtime.Decoder) er
 			return err
 		}
 	}
+	for i := range in.SchedulerNameProfiles {
+		if err := decodeProfile(&in.SchedulerNameProfiles[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -135,6 +174,11 @@ func (in *GodelSchedulerConfiguration) EncodeNestedObjects(e runtime.Encoder) er
 			return err
 		}
 	}
+	for i := range in.SchedulerNameProfiles {
+		if err := encodeProfile(&in.SchedulerNameProfiles[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -143,6 +187,10 @@ type GodelSchedulerProfile struct {
 	// ProfileKey associates the profile to a subcluster if it is not nil.
 	SubClusterName string `json:"subClusterName,omitempty"`
 
+	// SchedulerName associates the profile to the pods with the same spec.schedulerName.
+	// It is only used by SchedulerNameProfiles.
+	SchedulerName string `json:"schedulerName,omitempty"`
+
 	// BasePluginsForKubelet specify the set of default plugins.
 	BasePluginsForKubelet *config.Plugins `json:"baseKubeletPlugins,omitempty"`
 
@@ -197,4 +245,14 @@ type GodelSchedulerProfile struct {
 
 	// BetterSelectPolicies
 	BetterSelectPolicies *config.StringSlice `json:"betterSelectPolicies,omitempty"`
+
+	// PreemptionDryRun runs the preemption fully even if DisablePreemption is true, the chosen node,
+	// victims and sorted candidates are recorded, but the victims are never nominated or persisted.
+	PreemptionDryRun *bool `json:"preemptionDryRun,omitempty"`
+
+	// ParallelUnitWorkers is the number of units scheduled concurrently in one scheduling workflow.
+	// Each worker schedules against its own snapshot, and the decisions are validated by the node
+	// generations when they are applied to cache, the conflicted units will be retried.
+	// If specified, it must be greater than 0. If this value is null, the default value (1) will be used.
+	ParallelUnitWorkers *int32 `json:"parallelUnitWorkers,omitempty"`
 }
@@ -64,6 +64,7 @@ func RegisterConversions(s *runtime.Scheme) error {
 func autoConvert_v1beta1_GodelSchedulerConfiguration_To_config_GodelSchedulerConfiguration(in *GodelSchedulerConfiguration, out *config.GodelSchedulerConfiguration, s conversion.Scope) error {
 	out.LeaderElection = in.LeaderElection
 	out.SchedulerRenewIntervalSeconds = in.SchedulerRenewIntervalSeconds
+	out.SchedulerDrainTimeoutSeconds = in.SchedulerDrainTimeoutSeconds
 	out.ClientConnection = in.ClientConnection
 	out.HealthzBindAddress = in.HealthzBindAddress
 	out.MetricsBindAddress = in.MetricsBindAddress
@@ -71,7 +72,9 @@ func autoConvert_v1beta1_GodelSchedulerConfiguration_To_config_GodelSchedulerCon
 	out.GodelSchedulerName = in.GodelSchedulerName
 	out.SchedulerName = (*string)(unsafe.Pointer(in.SchedulerName))
 	out.SubClusterKey = (*string)(unsafe.Pointer(in.SubClusterKey))
+	out.NodePartitionType = in.NodePartitionType
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
+	out.BinderHandoff = (*config.BinderHandoffConfiguration)(unsafe.Pointer(in.BinderHandoff))
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(config.GodelSchedulerProfile)
@@ -92,6 +95,17 @@ func autoConvert_v1beta1_GodelSchedulerConfiguration_To_config_GodelSchedulerCon
 	} else {
 		out.SubClusterProfiles = nil
 	}
+	if in.SchedulerNameProfiles != nil {
+		in, out := &in.SchedulerNameProfiles, &out.SchedulerNameProfiles
+		*out = make([]config.GodelSchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_GodelSchedulerProfile_To_config_GodelSchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SchedulerNameProfiles = nil
+	}
 	return nil
 }
 
@@ -103,6 +117,7 @@ func Convert_v1beta1_GodelSchedulerConfiguration_To_config_GodelSchedulerConfigu
 func autoConvert_config_GodelSchedulerConfiguration_To_v1beta1_GodelSchedulerConfiguration(in *config.GodelSchedulerConfiguration, out *GodelSchedulerConfiguration, s conversion.Scope) error {
 	out.LeaderElection = in.LeaderElection
 	out.SchedulerRenewIntervalSeconds = in.SchedulerRenewIntervalSeconds
+	out.SchedulerDrainTimeoutSeconds = in.SchedulerDrainTimeoutSeconds
 	out.ClientConnection = in.ClientConnection
 	out.HealthzBindAddress = in.HealthzBindAddress
 	out.MetricsBindAddress = in.MetricsBindAddress
@@ -110,7 +125,9 @@ func autoConvert_config_GodelSchedulerConfiguration_To_v1beta1_GodelSchedulerCon
 	out.GodelSchedulerName = in.GodelSchedulerName
 	out.SchedulerName = (*string)(unsafe.Pointer(in.SchedulerName))
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
+	out.BinderHandoff = (*BinderHandoffConfiguration)(unsafe.Pointer(in.BinderHandoff))
 	out.SubClusterKey = (*string)(unsafe.Pointer(in.SubClusterKey))
+	out.NodePartitionType = in.NodePartitionType
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(GodelSchedulerProfile)
@@ -131,6 +148,17 @@ func autoConvert_config_GodelSchedulerConfiguration_To_v1beta1_GodelSchedulerCon
 	} else {
 		out.SubClusterProfiles = nil
 	}
+	if in.SchedulerNameProfiles != nil {
+		in, out := &in.SchedulerNameProfiles, &out.SchedulerNameProfiles
+		*out = make([]GodelSchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_config_GodelSchedulerProfile_To_v1beta1_GodelSchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SchedulerNameProfiles = nil
+	}
 	return nil
 }
 
@@ -141,6 +169,7 @@ func Convert_config_GodelSchedulerConfiguration_To_v1beta1_GodelSchedulerConfigu
 
 func autoConvert_v1beta1_GodelSchedulerProfile_To_config_GodelSchedulerProfile(in *GodelSchedulerProfile, out *config.GodelSchedulerProfile, s conversion.Scope) error {
 	out.SubClusterName = in.SubClusterName
+	out.SchedulerName = in.SchedulerName
 	out.BasePluginsForKubelet = (*config.Plugins)(unsafe.Pointer(in.BasePluginsForKubelet))
 	out.BasePluginsForNM = (*config.Plugins)(unsafe.Pointer(in.BasePluginsForNM))
 	out.PluginConfigs = *(*[]config.PluginConfig)(unsafe.Pointer(&in.PluginConfigs))
@@ -155,6 +184,8 @@ func autoConvert_v1beta1_GodelSchedulerProfile_To_config_GodelSchedulerProfile(i
 	out.UnitMaxBackoffSeconds = (*int64)(unsafe.Pointer(in.UnitMaxBackoffSeconds))
 	out.CandidatesSelectPolicy = (*string)(unsafe.Pointer(in.CandidatesSelectPolicy))
 	out.BetterSelectPolicies = (*config.StringSlice)(unsafe.Pointer(in.BetterSelectPolicies))
+	out.PreemptionDryRun = (*bool)(unsafe.Pointer(in.PreemptionDryRun))
+	out.ParallelUnitWorkers = (*int32)(unsafe.Pointer(in.ParallelUnitWorkers))
 	return nil
 }
 
@@ -165,6 +196,7 @@ func Convert_v1beta1_GodelSchedulerProfile_To_config_GodelSchedulerProfile(in *G
 
 func autoConvert_config_GodelSchedulerProfile_To_v1beta1_GodelSchedulerProfile(in *config.GodelSchedulerProfile, out *GodelSchedulerProfile, s conversion.Scope) error {
 	out.SubClusterName = in.SubClusterName
+	out.SchedulerName = in.SchedulerName
 	out.BasePluginsForKubelet = (*config.Plugins)(unsafe.Pointer(in.BasePluginsForKubelet))
 	out.BasePluginsForNM = (*config.Plugins)(unsafe.Pointer(in.BasePluginsForNM))
 	out.PluginConfigs = *(*[]config.PluginConfig)(unsafe.Pointer(&in.PluginConfigs))
@@ -174,11 +206,13 @@ func autoConvert_config_GodelSchedulerProfile_To_v1beta1_GodelSchedulerProfile(i
 	out.DisablePreemption = (*bool)(unsafe.Pointer(in.DisablePreemption))
 	out.CandidatesSelectPolicy = (*string)(unsafe.Pointer(in.CandidatesSelectPolicy))
 	out.BetterSelectPolicies = (*config.StringSlice)(unsafe.Pointer(in.BetterSelectPolicies))
+	out.PreemptionDryRun = (*bool)(unsafe.Pointer(in.PreemptionDryRun))
 	out.BlockQueue = (*bool)(unsafe.Pointer(in.BlockQueue))
 	out.UnitQueueSortPlugin = (*config.Plugin)(unsafe.Pointer(in.UnitQueueSortPlugin))
 	out.AttemptImpactFactorOnPriority = (*float64)(unsafe.Pointer(in.AttemptImpactFactorOnPriority))
 	out.UnitInitialBackoffSeconds = (*int64)(unsafe.Pointer(in.UnitInitialBackoffSeconds))
 	out.UnitMaxBackoffSeconds = (*int64)(unsafe.Pointer(in.UnitMaxBackoffSeconds))
+	out.ParallelUnitWorkers = (*int32)(unsafe.Pointer(in.ParallelUnitWorkers))
 	return nil
 }
 
@@ -27,6 +27,22 @@ import (
 	config "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BinderHandoffConfiguration) DeepCopyInto(out *BinderHandoffConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BinderHandoffConfiguration.
+func (in *BinderHandoffConfiguration) DeepCopy() *BinderHandoffConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BinderHandoffConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfiguration) {
 	*out = *in
@@ -48,6 +64,11 @@ func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfigura
 		in, out := &in.Tracer, &out.Tracer
 		*out = (*in).DeepCopy()
 	}
+	if in.BinderHandoff != nil {
+		in, out := &in.BinderHandoff, &out.BinderHandoff
+		*out = new(BinderHandoffConfiguration)
+		**out = **in
+	}
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(GodelSchedulerProfile)
@@ -60,6 +81,13 @@ func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfigura
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SchedulerNameProfiles != nil {
+		in, out := &in.SchedulerNameProfiles, &out.SchedulerNameProfiles
+		*out = make([]GodelSchedulerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -162,6 +190,16 @@ func (in *GodelSchedulerProfile) DeepCopyInto(out *GodelSchedulerProfile) {
 			copy(*out, *in)
 		}
 	}
+	if in.PreemptionDryRun != nil {
+		in, out := &in.PreemptionDryRun, &out.PreemptionDryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ParallelUnitWorkers != nil {
+		in, out := &in.ParallelUnitWorkers, &out.ParallelUnitWorkers
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
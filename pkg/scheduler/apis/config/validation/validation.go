@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	godelvalidation "github.com/kubewharf/godel-scheduler/pkg/util/validation"
 )
@@ -34,6 +35,10 @@ func ValidateGodelSchedulerConfiguration(cc *config.GodelSchedulerConfiguration)
 			errs = append(errs, field.Invalid(field.NewPath("schedulerRenewInterval"),
 				cc.SchedulerRenewIntervalSeconds, "must be greater than 0"))
 		}
+		if cc.SchedulerDrainTimeoutSeconds <= 0 {
+			errs = append(errs, field.Invalid(field.NewPath("schedulerDrainTimeoutSeconds"),
+				cc.SchedulerDrainTimeoutSeconds, "must be greater than 0"))
+		}
 	}
 
 	// 2. ClientConnection and BindSetting
@@ -62,6 +67,28 @@ func ValidateGodelSchedulerConfiguration(cc *config.GodelSchedulerConfiguration)
 		// if cc.SubClusterKey == nil || len(*cc.SubClusterKey) == 0 {
 		// 	errs = append(errs, field.Required(field.NewPath("subClusterKey"), ""))
 		// }
+		if cc.NodePartitionType != defaultsconfig.NodePartitionTypePhysical && cc.NodePartitionType != defaultsconfig.NodePartitionTypeLogical {
+			errs = append(errs, field.NotSupported(field.NewPath("nodePartitionType"), cc.NodePartitionType,
+				[]string{defaultsconfig.NodePartitionTypePhysical, defaultsconfig.NodePartitionTypeLogical}))
+		}
+		if cc.BinderHandoff != nil && cc.BinderHandoff.Enable {
+			if len(cc.BinderHandoff.BinderAddress) == 0 {
+				errs = append(errs, field.Required(field.NewPath("binderHandoff", "binderAddress"), ""))
+			}
+			if cc.BinderHandoff.TimeoutSeconds <= 0 {
+				errs = append(errs, field.Invalid(field.NewPath("binderHandoff", "timeoutSeconds"),
+					cc.BinderHandoff.TimeoutSeconds, "must be greater than 0"))
+			}
+			if len(cc.BinderHandoff.CertFile) == 0 {
+				errs = append(errs, field.Required(field.NewPath("binderHandoff", "certFile"), ""))
+			}
+			if len(cc.BinderHandoff.KeyFile) == 0 {
+				errs = append(errs, field.Required(field.NewPath("binderHandoff", "keyFile"), ""))
+			}
+			if len(cc.BinderHandoff.CAFile) == 0 {
+				errs = append(errs, field.Required(field.NewPath("binderHandoff", "caFile"), ""))
+			}
+		}
 	}
 
 	// 5. Godel Profiles
@@ -79,6 +106,21 @@ func ValidateGodelSchedulerConfiguration(cc *config.GodelSchedulerConfiguration)
 				errs = append(errs, ValidateSubClusterArgs(cc.DefaultProfile, field.NewPath("subClusterProfile"))...)
 			}
 		}
+		schedulerNames := sets.NewString()
+		for i := range cc.SchedulerNameProfiles {
+			profile := &cc.SchedulerNameProfiles[i]
+			fldPath := field.NewPath("schedulerNameProfiles").Index(i)
+			switch {
+			case len(profile.SchedulerName) == 0:
+				errs = append(errs, field.Required(fldPath.Child("schedulerName"), ""))
+			case cc.SchedulerName != nil && profile.SchedulerName == *cc.SchedulerName:
+				errs = append(errs, field.Invalid(fldPath.Child("schedulerName"), profile.SchedulerName, "must be different from schedulerName"))
+			case schedulerNames.Has(profile.SchedulerName):
+				errs = append(errs, field.Duplicate(fldPath.Child("schedulerName"), profile.SchedulerName))
+			}
+			schedulerNames.Insert(profile.SchedulerName)
+			errs = append(errs, ValidateSubClusterArgs(profile, fldPath)...)
+		}
 	}
 
 	return errs
@@ -158,5 +200,9 @@ func ValidateSubClusterArgs(cc *config.GodelSchedulerProfile, fldPath *field.Pat
 		errs = append(errs, field.Invalid(field.NewPath("attemptImpactFactorOnPriority"),
 			cc.AttemptImpactFactorOnPriority, "must be greater than 0"))
 	}
+	if cc.ParallelUnitWorkers != nil && *cc.ParallelUnitWorkers <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("parallelUnitWorkers"),
+			cc.ParallelUnitWorkers, "must be greater than 0"))
+	}
 	return errs
 }
@@ -26,6 +26,84 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FairShareArgs) DeepCopyInto(out *FairShareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.QueueWeights != nil {
+		in, out := &in.QueueWeights, &out.QueueWeights
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UsageHalfLifeSeconds != nil {
+		in, out := &in.UsageHalfLifeSeconds, &out.UsageHalfLifeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FairShareArgs.
+func (in *FairShareArgs) DeepCopy() *FairShareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(FairShareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FairShareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUShareArgs) DeepCopyInto(out *GPUShareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUShareArgs.
+func (in *GPUShareArgs) DeepCopy() *GPUShareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUShareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GPUShareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BinderHandoffConfiguration) DeepCopyInto(out *BinderHandoffConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BinderHandoffConfiguration.
+func (in *BinderHandoffConfiguration) DeepCopy() *BinderHandoffConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BinderHandoffConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfiguration) {
 	*out = *in
@@ -42,6 +120,11 @@ func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfigura
 		in, out := &in.Tracer, &out.Tracer
 		*out = (*in).DeepCopy()
 	}
+	if in.BinderHandoff != nil {
+		in, out := &in.BinderHandoff, &out.BinderHandoff
+		*out = new(BinderHandoffConfiguration)
+		**out = **in
+	}
 	if in.SubClusterKey != nil {
 		in, out := &in.SubClusterKey, &out.SubClusterKey
 		*out = new(string)
@@ -59,6 +142,13 @@ func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfigura
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SchedulerNameProfiles != nil {
+		in, out := &in.SchedulerNameProfiles, &out.SchedulerNameProfiles
+		*out = make([]GodelSchedulerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -136,6 +226,11 @@ func (in *GodelSchedulerProfile) DeepCopyInto(out *GodelSchedulerProfile) {
 			copy(*out, *in)
 		}
 	}
+	if in.PreemptionDryRun != nil {
+		in, out := &in.PreemptionDryRun, &out.PreemptionDryRun
+		*out = new(bool)
+		**out = **in
+	}
 	if in.BlockQueue != nil {
 		in, out := &in.BlockQueue, &out.BlockQueue
 		*out = new(bool)
@@ -161,6 +256,11 @@ func (in *GodelSchedulerProfile) DeepCopyInto(out *GodelSchedulerProfile) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ParallelUnitWorkers != nil {
+		in, out := &in.ParallelUnitWorkers, &out.ParallelUnitWorkers
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -273,6 +373,41 @@ func (in *LocalStoragePoolCheckerArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkTopologyArgs) DeepCopyInto(out *NetworkTopologyArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.TopologyLevels != nil {
+		in, out := &in.TopologyLevels, &out.TopologyLevels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxDomainsPerLevel != nil {
+		in, out := &in.MaxDomainsPerLevel, &out.MaxDomainsPerLevel
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkTopologyArgs.
+func (in *NetworkTopologyArgs) DeepCopy() *NetworkTopologyArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkTopologyArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkTopologyArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeLabelArgs) DeepCopyInto(out *NodeLabelArgs) {
 	*out = *in
@@ -620,6 +755,32 @@ func (in *PodTopologySpreadArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreemptionBudgetArgs) DeepCopyInto(out *PreemptionBudgetArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Budget.DeepCopyInto(&out.Budget)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreemptionBudgetArgs.
+func (in *PreemptionBudgetArgs) DeepCopy() *PreemptionBudgetArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PreemptionBudgetArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PreemptionBudgetArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RequestedToCapacityRatioArgs) DeepCopyInto(out *RequestedToCapacityRatioArgs) {
 	*out = *in
@@ -752,6 +913,41 @@ func (in *StartRecentlyArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarvationAwareArgs) DeepCopyInto(out *StarvationAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.StarvationThresholdSeconds != nil {
+		in, out := &in.StarvationThresholdSeconds, &out.StarvationThresholdSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReservationTTLSeconds != nil {
+		in, out := &in.ReservationTTLSeconds, &out.ReservationTTLSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarvationAwareArgs.
+func (in *StarvationAwareArgs) DeepCopy() *StarvationAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(StarvationAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarvationAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in StringSlice) DeepCopyInto(out *StringSlice) {
 	{
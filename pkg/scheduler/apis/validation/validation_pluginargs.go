@@ -241,5 +241,8 @@ func ValidateLoadAwareArgs(args *config.LoadAwareArgs) error {
 			return fmt.Errorf("resource type %v is invalid", resourceSpec.ResourceType)
 		}
 	}
+	if args.HistoricalUsagePercentile < 0 || args.HistoricalUsagePercentile > 100 {
+		return fmt.Errorf("historical usage percentile %v is invalid", args.HistoricalUsagePercentile)
+	}
 	return nil
 }
@@ -30,6 +30,8 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
 	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
 	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
+	queueusagestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/queue_usage_store"
+	reservationstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/reservation_store"
 	unitstatusstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/unit_status_store"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
@@ -183,6 +185,42 @@ func (cache *schedulerCache) GetUnitSchedulingStatus(unitKey string) unitstatus.
 	return cache.storeSwitch.Find(unitstatusstore.Name).(*unitstatusstore.UnitStatusStore).GetUnitSchedulingStatus(unitKey)
 }
 
+// SetUnitReservations does nothing if ResourceReservation is disabled.
+func (cache *schedulerCache) SetUnitReservations(owner string, reservations map[string]*framework.ResourceReservation) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if store := cache.storeSwitch.Find(reservationstore.Name); store != nil {
+		store.(*reservationstore.ReservationStore).SetUnitReservations(owner, reservations)
+	}
+}
+
+func (cache *schedulerCache) GetUnitReservations(owner string) map[string]*framework.ResourceReservation {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if store := cache.storeSwitch.Find(reservationstore.Name); store != nil {
+		return store.(*reservationstore.ReservationStore).GetUnitReservations(owner)
+	}
+	return nil
+}
+
+func (cache *schedulerCache) RemoveUnitReservations(owner string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if store := cache.storeSwitch.Find(reservationstore.Name); store != nil {
+		store.(*reservationstore.ReservationStore).RemoveUnitReservations(owner)
+	}
+}
+
+// GetQueueShares returns nil if FairShareQueueSort is disabled.
+func (cache *schedulerCache) GetQueueShares() map[string]float64 {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if store := cache.storeSwitch.Find(queueusagestore.Name); store != nil {
+		return store.(*queueusagestore.QueueUsageStore).GetQueueShares()
+	}
+	return nil
+}
+
 func (cache *schedulerCache) FinishReserving(pod *v1.Pod) error {
 	return cache.finishReserving(pod, time.Now())
 }
@@ -259,6 +297,15 @@ func (cache *schedulerCache) NodeInThisPartition(nodeName string) bool {
 	return cache.storeSwitch.Find(nodestore.Name).(*nodestore.NodeStore).NodeInThisPartition(nodeName)
 }
 
+func (cache *schedulerCache) GetNodeGeneration(nodeName string) int64 {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if nodeInfo := cache.storeSwitch.Find(nodestore.Name).(*nodestore.NodeStore).Get(nodeName); nodeInfo != nil {
+		return nodeInfo.GetGeneration()
+	}
+	return 0
+}
+
 // PodCount returns the number of pods in the cache (including those from deleted nodes).
 // DO NOT use outside of tests.
 func (cache *schedulerCache) PodCount() (int, error) {
@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
@@ -42,6 +43,7 @@ import (
 	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
@@ -1186,6 +1188,71 @@ func TestNodeOps(t *testing.T) {
 	}
 }
 
+func TestSchedulerCache_UpdatePhysicalPartitionSnapshot(t *testing.T) {
+	schedulerName := "scheduler-0"
+	makeNode := func(name, scheduler string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{nodeutil.GodelSchedulerNodeAnnotationKey: scheduler},
+			},
+		}
+	}
+	n1, n2 := makeNode("n1", schedulerName), makeNode("n2", "scheduler-1")
+
+	cacheHandler := handler.MakeCacheHandlerWrapper().
+		SchedulerName(schedulerName).SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		Obj()
+	cache := newSchedulerCache(cacheHandler)
+	cache.AddNode(n1)
+	cache.AddNode(n2)
+
+	snapshotHandler := handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		NodePartitionType(defaultsconfig.NodePartitionTypePhysical).
+		Obj()
+	snapshot := NewEmptySnapshot(snapshotHandler)
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got := snapshot.NumNodes(); got != 1 {
+		t.Errorf("expected 1 node in snapshot, got %v", got)
+	}
+	if got := len(snapshot.OutOfPartitionList()); got != 0 {
+		t.Errorf("expected no out-of-partition nodes in snapshot, got %v", got)
+	}
+	if _, err := snapshot.Get(n2.Name); err == nil {
+		t.Errorf("expected node %v out of partition to be invisible", n2.Name)
+	}
+
+	// n2 is moved into the partition of the scheduler.
+	newN2 := makeNode("n2", schedulerName)
+	cache.UpdateNode(n2, newN2)
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(snapshot.InPartitionList()); got != 2 {
+		t.Errorf("expected 2 in-partition nodes in snapshot, got %v", got)
+	}
+	if _, err := snapshot.Get(newN2.Name); err != nil {
+		t.Errorf("expected node %v in partition to be visible, got error %v", newN2.Name, err)
+	}
+
+	// n1 is moved out of the partition of the scheduler.
+	newN1 := makeNode("n1", "scheduler-1")
+	cache.UpdateNode(n1, newN1)
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got := snapshot.NumNodes(); got != 1 {
+		t.Errorf("expected 1 node in snapshot, got %v", got)
+	}
+	if _, err := snapshot.Get(newN1.Name); err == nil {
+		t.Errorf("expected node %v out of partition to be invisible", newN1.Name)
+	}
+}
+
 func TestSchedulerCache_UpdateSubClusterSnapshot(t *testing.T) {
 	testSubClusterKey := "nodeLevel"
 	framework.SetGlobalSubClusterKey(testSubClusterKey)
@@ -111,7 +111,7 @@ func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
 func (s *ExampleStore) AddPod(pod *v1.Pod) error {
 	// For a specific store, it may only care about pods that meet specific conditions, so it is able
 	// to do filtering here based on specific objects.
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod)
@@ -147,7 +147,7 @@ func (s *ExampleStore) UpdatePod(oldPod *v1.Pod, newPod *v1.Pod) error {
 func (s *ExampleStore) RemovePod(pod *v1.Pod) error {
 	// For a specific store, it may only care about pods that meet specific conditions, so it is able
 	// to do filtering here based on specific objects.
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod)
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package equivalencestore
+
+import (
+	"sync"
+	"time"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+)
+
+const Name commonstores.StoreName = "EquivalenceStore"
+
+// DefaultIdleTimeout is how long the filter results of an equivalence class are kept without being accessed.
+const DefaultIdleTimeout = 10 * time.Minute
+
+func (s *EquivalenceStore) Name() commonstores.StoreName {
+	return Name
+}
+
+func init() {
+	commonstores.GlobalRegistry.Register(
+		Name,
+		func(h handler.CacheHandler) bool {
+			return utilfeature.DefaultFeatureGate.Enabled(features.EquivalenceCache)
+		},
+		NewCache,
+		NewSnapshot)
+}
+
+// ---------------------------------------------------------------------------------------
+
+// FilterResults is a map from filter plugin name to its status, nil status means success.
+type FilterResults map[string]*framework.Status
+
+// nodeFilterResults is the filter results of an equivalence class on a node.
+// The results are valid only if the generation of the NodeInfo is unchanged.
+type nodeFilterResults struct {
+	generation int64
+	results    FilterResults
+}
+
+type classFilterResults struct {
+	nodes      map[string]*nodeFilterResults
+	lastAccess time.Time
+}
+
+// EquivalenceStore caches the filter results of the pods belonging to the same equivalence class, so that
+// they can be reused across scheduling cycles and units.
+//
+// The results only live in Snapshot. They are invalidated by the generation of NodeInfo, which changes
+// whenever the node or the pods on it are updated in Cache, and are dropped when pods are assumed or
+// forgotten in Snapshot (including the victims removed or added back), since the generation will not
+// change in that case.
+type EquivalenceStore struct {
+	commonstores.BaseStore
+	storeType commonstores.StoreType
+	handler   handler.CacheHandler
+
+	// mu guards classes, since the filter results are read and written by the parallel filtering.
+	mu      sync.RWMutex
+	classes map[string]*classFilterResults
+
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+func NewCache(handler handler.CacheHandler) commonstores.CommonStore {
+	return &EquivalenceStore{
+		BaseStore: commonstores.NewBaseStore(),
+		storeType: commonstores.Cache,
+		handler:   handler,
+	}
+}
+
+func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
+	return &EquivalenceStore{
+		BaseStore: commonstores.NewBaseStore(),
+		storeType: commonstores.Snapshot,
+		handler:   handler,
+
+		classes:     make(map[string]*classFilterResults),
+		idleTimeout: DefaultIdleTimeout,
+		now:         time.Now,
+	}
+}
+
+func (s *EquivalenceStore) AssumePod(podInfo *framework.CachePodInfo) error {
+	s.invalidateNodes(podInfo)
+	return nil
+}
+
+func (s *EquivalenceStore) ForgetPod(podInfo *framework.CachePodInfo) error {
+	s.invalidateNodes(podInfo)
+	return nil
+}
+
+// UpdateSnapshot drops the filter results of the equivalence classes which have been idle for a while.
+// The results of the updated nodes are invalidated lazily by the generation.
+func (s *EquivalenceStore) UpdateSnapshot(store commonstores.CommonStore) error {
+	snapshot := store.(*EquivalenceStore)
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+	now := snapshot.now()
+	for class, c := range snapshot.classes {
+		if now.Sub(c.lastAccess) > snapshot.idleTimeout {
+			delete(snapshot.classes, class)
+		}
+	}
+	return nil
+}
+
+// -------------------------------------- Other Interface --------------------------------------
+
+// GetFilterResults returns the cached filter results of the equivalence class on the node.
+// Nil is returned if there are no valid results.
+func (s *EquivalenceStore) GetFilterResults(class string, nodeInfo framework.NodeInfo) FilterResults {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.classes[class]
+	if !ok {
+		return nil
+	}
+	n, ok := c.nodes[nodeInfo.GetNodeName()]
+	if !ok || n.generation != nodeInfo.GetGeneration() {
+		return nil
+	}
+	return n.results
+}
+
+// AddFilterResults merges the filter results of the equivalence class on the node into the store.
+// The previous results will be replaced if the generation of the node has changed.
+func (s *EquivalenceStore) AddFilterResults(class string, nodeInfo framework.NodeInfo, results FilterResults) {
+	if len(results) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.classes[class]
+	if !ok {
+		c = &classFilterResults{nodes: make(map[string]*nodeFilterResults)}
+		s.classes[class] = c
+	}
+	c.lastAccess = s.now()
+
+	nodeName, generation := nodeInfo.GetNodeName(), nodeInfo.GetGeneration()
+	n, ok := c.nodes[nodeName]
+	if !ok || n.generation != generation {
+		n = &nodeFilterResults{generation: generation, results: make(FilterResults, len(results))}
+		c.nodes[nodeName] = n
+	} else {
+		// Copy on write, the results may be being read by others.
+		merged := make(FilterResults, len(n.results)+len(results))
+		for pl, status := range n.results {
+			merged[pl] = status
+		}
+		n.results = merged
+	}
+	for pl, status := range results {
+		n.results[pl] = status
+	}
+}
+
+// -------------------------------------- Internal Function --------------------------------------
+
+// invalidateNodes drops the filter results of the node the pod is placed on, along with the nodes its victims
+// are removed from in Snapshot, because neither of them bumps the generation of NodeInfo.
+func (s *EquivalenceStore) invalidateNodes(podInfo *framework.CachePodInfo) {
+	if s.storeType != commonstores.Snapshot {
+		return
+	}
+	nodeNames := []string{utils.GetNodeNameFromPod(podInfo.Pod)}
+	if podInfo.Victims != nil {
+		for _, victim := range podInfo.Victims.Pods {
+			nodeNames = append(nodeNames, utils.GetNodeNameFromPod(victim))
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, nodeName := range nodeNames {
+		if len(nodeName) == 0 {
+			continue
+		}
+		for _, c := range s.classes {
+			delete(c.nodes, nodeName)
+		}
+	}
+}
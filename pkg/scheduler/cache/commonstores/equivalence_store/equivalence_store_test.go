@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package equivalencestore
+
+import (
+	"testing"
+	"time"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+)
+
+func makeNodeInfo(name string, generation int64) framework.NodeInfo {
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(testing_helper.MakeNode().Name(name).Obj())
+	nodeInfo.SetGeneration(generation)
+	return nodeInfo
+}
+
+func TestFilterResults(t *testing.T) {
+	store := NewSnapshot(nil).(*EquivalenceStore)
+	unschedulable := framework.NewStatus(framework.Unschedulable, "unschedulable")
+
+	n1, n2 := makeNodeInfo("n1", 1), makeNodeInfo("n2", 1)
+	store.AddFilterResults("c1", n1, FilterResults{"A": nil})
+	store.AddFilterResults("c1", n1, FilterResults{"B": unschedulable})
+	store.AddFilterResults("c1", n2, FilterResults{"A": nil})
+
+	if got := store.GetFilterResults("c1", n1); len(got) != 2 || got["A"] != nil || got["B"] != unschedulable {
+		t.Errorf("unexpected results of n1: %v", got)
+	}
+	if got := store.GetFilterResults("c2", n1); got != nil {
+		t.Errorf("expected no results for unknown class, got %v", got)
+	}
+
+	// The results are invalidated once the generation of node changed.
+	n1.SetGeneration(2)
+	if got := store.GetFilterResults("c1", n1); got != nil {
+		t.Errorf("expected no results after generation changed, got %v", got)
+	}
+	store.AddFilterResults("c1", n1, FilterResults{"C": nil})
+	if got := store.GetFilterResults("c1", n1); len(got) != 1 {
+		t.Errorf("expected stale results to be replaced, got %v", got)
+	}
+
+	// The results are dropped when the pod is assumed in snapshot.
+	pod := testing_helper.MakePod().Namespace("default").Name("p").UID("p").Node("n2").Obj()
+	if err := store.AssumePod(&framework.CachePodInfo{Pod: pod}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.GetFilterResults("c1", n2); got != nil {
+		t.Errorf("expected no results after pod assumed, got %v", got)
+	}
+	if got := store.GetFilterResults("c1", n1); len(got) != 1 {
+		t.Errorf("expected results of other nodes to be kept, got %v", got)
+	}
+}
+
+func TestUpdateSnapshotEvictsIdleClasses(t *testing.T) {
+	now := time.Now()
+	store := NewSnapshot(nil).(*EquivalenceStore)
+	store.now = func() time.Time { return now }
+
+	nodeInfo := makeNodeInfo("n1", 1)
+	store.AddFilterResults("idle", nodeInfo, FilterResults{"A": nil})
+	now = now.Add(DefaultIdleTimeout)
+	store.AddFilterResults("active", nodeInfo, FilterResults{"A": nil})
+	now = now.Add(time.Minute)
+
+	if err := NewCache(nil).UpdateSnapshot(store); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.GetFilterResults("idle", nodeInfo); got != nil {
+		t.Errorf("expected idle class to be evicted, got %v", got)
+	}
+	if got := store.GetFilterResults("active", nodeInfo); got == nil {
+		t.Errorf("expected active class to be kept")
+	}
+}
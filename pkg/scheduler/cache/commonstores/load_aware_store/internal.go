@@ -17,6 +17,8 @@ limitations under the License.
 package loadawarestore
 
 import (
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/loadaware"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
@@ -194,3 +196,56 @@ func cloneAllPods(allPods map[string]podBasicInfo) map[string]podBasicInfo {
 	}
 	return ret
 }
+
+// ----------------------------------- WorkloadUsageInfo -----------------------------------
+
+// WorkloadUsageInfo holds the usage profiles of one workload reported by different nodes.
+type WorkloadUsageInfo struct {
+	nodeProfiles loadaware.WorkloadUsageProfiles
+	generation   int64
+}
+
+var _ generationstore.StoredObj = &WorkloadUsageInfo{}
+
+func NewWorkloadUsageInfo() *WorkloadUsageInfo {
+	return &WorkloadUsageInfo{
+		nodeProfiles: make(loadaware.WorkloadUsageProfiles),
+	}
+}
+
+func (i *WorkloadUsageInfo) SetProfile(nodeName string, profile loadaware.WorkloadUsageProfile) {
+	i.nodeProfiles[nodeName] = profile
+}
+
+func (i *WorkloadUsageInfo) RemoveProfile(nodeName string) {
+	delete(i.nodeProfiles, nodeName)
+}
+
+// Usage returns the max usage at the given percentile among all the nodes, nil means there is no usage history.
+func (i *WorkloadUsageInfo) Usage(percentile int64) *framework.LoadAwareWorkloadUsage {
+	return i.nodeProfiles.Usage(percentile)
+}
+
+func (i *WorkloadUsageInfo) GetGeneration() int64 {
+	return i.generation
+}
+
+func (i *WorkloadUsageInfo) SetGeneration(generation int64) {
+	i.generation = generation
+}
+
+func (i *WorkloadUsageInfo) CanBeRecycle() bool {
+	return i == nil || len(i.nodeProfiles) == 0
+}
+
+func (i *WorkloadUsageInfo) Clone() *WorkloadUsageInfo {
+	nodeProfiles := make(loadaware.WorkloadUsageProfiles, len(i.nodeProfiles))
+	for nodeName, profile := range i.nodeProfiles {
+		// The profile won't be changed once parsed, so there is no need to copy it.
+		nodeProfiles[nodeName] = profile
+	}
+	return &WorkloadUsageInfo{
+		nodeProfiles: nodeProfiles,
+		generation:   i.generation,
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/loadaware"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
@@ -52,6 +53,8 @@ type LoadAwareStore struct {
 
 	// NodeMetricInfo
 	Store generationstore.Store
+	// WorkloadUsageInfo
+	WorkloadStore generationstore.Store
 }
 
 func NewCache(handler handler.CacheHandler) commonstores.CommonStore {
@@ -60,7 +63,8 @@ func NewCache(handler handler.CacheHandler) commonstores.CommonStore {
 		storeType: commonstores.Cache,
 		handler:   handler,
 
-		Store: generationstore.NewListStore(),
+		Store:         generationstore.NewListStore(),
+		WorkloadStore: generationstore.NewListStore(),
 	}
 }
 
@@ -70,17 +74,21 @@ func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
 		storeType: commonstores.Snapshot,
 		handler:   handler,
 
-		Store: generationstore.NewRawStore(),
+		Store:         generationstore.NewRawStore(),
+		WorkloadStore: generationstore.NewRawStore(),
 	}
 }
 
 // -------------------------------------- ClusterCache --------------------------------------
 
 func (s *LoadAwareStore) AddCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	s.workloadUsageOp(cnr, true)
 	return s.nodeMetricOp(cnr, true)
 }
 
 func (s *LoadAwareStore) UpdateCNR(oldCNR, newCNR *katalystv1alpha1.CustomNodeResource) error {
+	s.workloadUsageOp(oldCNR, false)
+	s.workloadUsageOp(newCNR, true)
 	if err := s.nodeMetricOp(oldCNR, false); err != nil {
 		return err
 	}
@@ -88,11 +96,12 @@ func (s *LoadAwareStore) UpdateCNR(oldCNR, newCNR *katalystv1alpha1.CustomNodeRe
 }
 
 func (s *LoadAwareStore) RemoveCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	s.workloadUsageOp(cnr, false)
 	return s.nodeMetricOp(cnr, true)
 }
 
 func (s *LoadAwareStore) AddPod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod, true)
@@ -122,7 +131,7 @@ func (s *LoadAwareStore) UpdatePod(oldPod *v1.Pod, newPod *v1.Pod) error {
 }
 
 func (s *LoadAwareStore) RemovePod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod, false)
@@ -147,6 +156,15 @@ func (s *LoadAwareStore) UpdateSnapshot(store commonstores.CommonStore) error {
 		},
 		generationstore.DefaultCleanFunc(cache, snapshot),
 	)
+
+	workloadCache, workloadSnapshot := framework.TransferGenerationStore(s.WorkloadStore, store.(*LoadAwareStore).WorkloadStore)
+	workloadCache.UpdateRawStore(
+		workloadSnapshot,
+		func(key string, so generationstore.StoredObj) {
+			workloadSnapshot.Set(key, so.(*WorkloadUsageInfo).Clone())
+		},
+		generationstore.DefaultCleanFunc(workloadCache, workloadSnapshot),
+	)
 	return nil
 }
 
@@ -218,6 +236,33 @@ func (s *LoadAwareStore) nodeMetricOp(cnr *katalystv1alpha1.CustomNodeResource,
 	return nil
 }
 
+// workloadUsageOp adds or removes the workload usage profiles reported by the node of the CNR.
+func (s *LoadAwareStore) workloadUsageOp(cnr *katalystv1alpha1.CustomNodeResource, isAdd bool) {
+	if cnr == nil || len(cnr.Name) == 0 {
+		return
+	}
+	for workloadKey, profile := range loadaware.ParseWorkloadUsageProfiles(cnr) {
+		var workloadUsageInfo *WorkloadUsageInfo
+		if obj := s.WorkloadStore.Get(workloadKey); obj != nil {
+			workloadUsageInfo = obj.(*WorkloadUsageInfo)
+		} else if isAdd {
+			workloadUsageInfo = NewWorkloadUsageInfo()
+		} else {
+			continue
+		}
+		if isAdd {
+			workloadUsageInfo.SetProfile(cnr.Name, profile)
+		} else {
+			workloadUsageInfo.RemoveProfile(cnr.Name)
+		}
+		if workloadUsageInfo.CanBeRecycle() {
+			s.WorkloadStore.Delete(workloadKey)
+		} else {
+			s.WorkloadStore.Set(workloadKey, workloadUsageInfo)
+		}
+	}
+}
+
 func (s *LoadAwareStore) GetLoadAwareNodeMetricInfo(nodeName string, resourceType podutil.PodResourceType) *framework.LoadAwareNodeMetricInfo {
 	nodeMetricInfoObj := s.Store.Get(nodeName)
 	if nodeMetricInfoObj == nil {
@@ -278,3 +323,13 @@ func (s *LoadAwareStore) GetLoadAwareNodeUsage(nodeName string, resourceType pod
 		RequestMEM:      podMetricsInfos.RequestMEM,
 	}
 }
+
+// GetLoadAwareWorkloadUsage returns the historical usage of one pod of the workload at the given percentile,
+// nil means there is no usage history of the workload.
+func (s *LoadAwareStore) GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *framework.LoadAwareWorkloadUsage {
+	obj := s.WorkloadStore.Get(workloadKey)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*WorkloadUsageInfo).Usage(percentile)
+}
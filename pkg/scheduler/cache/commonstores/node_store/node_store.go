@@ -26,6 +26,7 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
@@ -61,6 +62,11 @@ func nodeInfoBelongToSubCluster(n framework.NodeInfo, matchedSubCluster string)
 		n.GetNMNode() != nil && n.GetNMNode().Labels[framework.GetGlobalSubClusterKey()] == matchedSubCluster
 }
 
+// nodeInfoBelongToPartition checks whether the node is in the partition of the scheduler.
+func nodeInfoBelongToPartition(n framework.NodeInfo) bool {
+	return n.GetNodeInSchedulerPartition() || n.GetNMNodeInSchedulerPartition()
+}
+
 // The NodeStore under this file will be held by Cache and Snapshot respectively.
 // The difference is that:
 //	- The Store used in the Cache is generationstore.ListStore which will be organized in the form of linked list and hash table.
@@ -264,7 +270,7 @@ func (s *NodeStore) RemoveCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
 }
 
 func (s *NodeStore) AddPod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	nodeName := utils.GetNodeNameFromPod(pod)
@@ -301,7 +307,7 @@ func (s *NodeStore) UpdatePod(oldPod, newPod *v1.Pod) error {
 }
 
 func (s *NodeStore) RemovePod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	nodeName := utils.GetNodeNameFromPod(pod)
@@ -394,6 +400,7 @@ func (cacheStore *NodeStore) UpdateSnapshot(store commonstores.CommonStore) erro
 	subClusterConcurrentSchedulingEnabled := utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling)
 
 	subCluster := snapshotStore.handler.SubCluster()
+	physicalPartition := snapshotStore.handler.NodePartitionType() == defaultsconfig.NodePartitionTypePhysical
 
 	cache.UpdateRawStore(
 		snapshot,
@@ -410,7 +417,8 @@ func (cacheStore *NodeStore) UpdateSnapshot(store commonstores.CommonStore) erro
 					snapshotStore.Delete(nodeName, obj.(framework.NodeInfo))
 				}
 
-				if subClusterConcurrentSchedulingEnabled && !nodeInfoBelongToSubCluster(nodeInfo, subCluster) {
+				if subClusterConcurrentSchedulingEnabled && !nodeInfoBelongToSubCluster(nodeInfo, subCluster) ||
+					physicalPartition && !nodeInfoBelongToPartition(nodeInfo) {
 					// ATTENTION: We should ensure that the `globalNodeInfoPlaceHolder` will not be added to nodeslice.
 					snapshotStore.Add(nodeName, GlobalNodeInfoPlaceHolder)
 				} else {
@@ -88,7 +88,7 @@ func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
 }
 
 func (s *PodStore) AddPod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod, true)
@@ -122,7 +122,7 @@ func (s *PodStore) UpdatePod(oldPod *v1.Pod, newPod *v1.Pod) error {
 }
 
 func (s *PodStore) RemovePod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod, false)
@@ -29,6 +29,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 )
 
 const Name commonstores.StoreName = "PreemptionStore"
@@ -54,6 +55,9 @@ type PreemptionStore struct {
 	handler   handler.CacheHandler
 
 	store *PreemptionDetails
+	// victims are the victims nominated in the last minute, accounted in the preemption budgets. They are
+	// recorded by the Cache and shared with the Snapshot, since the budgets are not versioned by generations.
+	victims *preemptionbudget.Tracker
 }
 
 var _ commonstores.CommonStore = &PreemptionStore{}
@@ -64,7 +68,8 @@ func NewCache(handler handler.CacheHandler) commonstores.CommonStore {
 		storeType: commonstores.Cache,
 		handler:   handler,
 
-		store: NewCachePreemptionDetails(),
+		store:   NewCachePreemptionDetails(),
+		victims: preemptionbudget.NewTracker(),
 	}
 }
 
@@ -74,12 +79,13 @@ func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
 		storeType: commonstores.Snapshot,
 		handler:   handler,
 
-		store: NewSnapshotPreemptionDetails(),
+		store:   NewSnapshotPreemptionDetails(),
+		victims: preemptionbudget.NewTracker(),
 	}
 }
 
 func (s *PreemptionStore) AddPod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod, true)
@@ -109,7 +115,7 @@ func (s *PreemptionStore) UpdatePod(oldPod *v1.Pod, newPod *v1.Pod) error {
 }
 
 func (s *PreemptionStore) RemovePod(pod *v1.Pod) error {
-	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
 		return nil
 	}
 	return s.podOp(pod, false)
@@ -143,6 +149,8 @@ func (s *PreemptionStore) UpdateSnapshot(store commonstores.CommonStore) error {
 		)
 	}
 
+	store.(*PreemptionStore).victims = s.victims
+
 	cache, snapshot := framework.TransferGenerationStore(s.store.NodeToVictims, store.(*PreemptionStore).store.NodeToVictims)
 	cache.UpdateRawStore(
 		snapshot,
@@ -178,6 +186,11 @@ func (s *PreemptionStore) GetPreemptorsByVictim(node, victim string) []string {
 	return s.store.GetPreemptorsByVictim(node, victim)
 }
 
+// GetPreemptionBudgetUsage returns the victims nominated in the last minute accounted in the budget.
+func (s *PreemptionStore) GetPreemptionBudgetUsage(budget *preemptionbudget.Budget) preemptionbudget.Usage {
+	return s.victims.Usage(budget, time.Now())
+}
+
 func (s *PreemptionStore) podOp(pod *v1.Pod, isAdd bool) error {
 	nominatedNode, err := utils.GetPodNominatedNode(pod)
 	if err != nil {
@@ -186,6 +199,9 @@ func (s *PreemptionStore) podOp(pod *v1.Pod, isAdd bool) error {
 	}
 	nodeName := nominatedNode.NodeName
 	preemptorKey := podutil.GeneratePodKey(pod)
+	if isAdd {
+		s.recordVictims(pod, nominatedNode)
+	}
 	for _, victimPod := range nominatedNode.VictimPods {
 		victimKey := podutil.GetPodFullKey(victimPod.Namespace, victimPod.Name, victimPod.UID)
 		if isAdd {
@@ -196,3 +212,21 @@ func (s *PreemptionStore) podOp(pod *v1.Pod, isAdd bool) error {
 	}
 	return nil
 }
+
+// recordVictims accounts the victims of the preemptor in the preemption budgets once it is assumed by any
+// scheduler. The bound preemptors are skipped, their victims have been accounted when they were assumed.
+// The victims stay accounted even if the preemptor is forgotten later, which errs on the side of fewer evictions.
+func (s *PreemptionStore) recordVictims(preemptor *v1.Pod, nominatedNode *framework.NominatedNode) {
+	if s.storeType != commonstores.Cache || podutil.BoundPod(preemptor) || len(nominatedNode.VictimPods) == 0 {
+		return
+	}
+	var nodeLabels map[string]string
+	if nodeInfo := s.handler.GetNodeInfo(nominatedNode.NodeName); nodeInfo != nil && nodeInfo.GetNode() != nil {
+		nodeLabels = nodeInfo.GetNode().Labels
+	}
+	victims := make([]preemptionbudget.Victim, 0, len(nominatedNode.VictimPods))
+	for _, victimPod := range nominatedNode.VictimPods {
+		victims = append(victims, preemptionbudget.NewVictim(preemptor, victimPod.Namespace, victimPod.Name, victimPod.UID, nodeLabels))
+	}
+	s.victims.Record(victims, time.Now())
+}
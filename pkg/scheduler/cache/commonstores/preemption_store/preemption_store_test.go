@@ -18,6 +18,7 @@ package preemptionstore
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 )
 
 func makeGenerationPreemptionDetailForNode(data map[string]sets.String) *PreemptionDetailForNode {
@@ -472,3 +474,34 @@ func TestCleanUpResidualPreemptionItems(t *testing.T) {
 		t.Errorf("expected: %v, but got %v", expectedPreemptionDetails, cache.(*PreemptionStore).store)
 	}
 }
+
+func TestGetPreemptionBudgetUsage(t *testing.T) {
+	assumed := testing_helper.MakePod().Namespace("foo1").Name("foo1").UID("foo1").Priority(100).
+		Annotation(podutil.PodStateAnnotationKey, string(podutil.PodAssumed)).
+		Annotation(podutil.NominatedNodeAnnotationKey, "{\"node\":\"n1\",\"victims\":[{\"name\":\"p1\",\"namespace\":\"p1\",\"uid\":\"p1\"},{\"name\":\"p2\",\"namespace\":\"p2\",\"uid\":\"p2\"}]}").Obj()
+	// The victims of the bound preemptors have been accounted when they were assumed.
+	bound := testing_helper.MakePod().Namespace("foo2").Name("foo2").UID("foo2").Node("n1").Priority(100).
+		Annotation(podutil.NominatedNodeAnnotationKey, "{\"node\":\"n1\",\"victims\":[{\"name\":\"p3\",\"namespace\":\"p1\",\"uid\":\"p3\"}]}").Obj()
+
+	handler := handler.MakeCacheHandlerWrapper().EnableStore(string(Name)).Obj()
+	cache := NewCache(handler)
+	snapshot := NewSnapshot(handler)
+	cache.AssumePod(framework.MakeCachePodInfoWrapper().Pod(assumed).Obj())
+	// The victims of the same preemptor are accounted once when it is added again.
+	cache.AssumePod(framework.MakeCachePodInfoWrapper().Pod(assumed).Obj())
+	cache.AddPod(bound)
+	cache.UpdateSnapshot(snapshot)
+
+	budget := &preemptionbudget.Budget{
+		PriorityBands:                   []preemptionbudget.PriorityBand{{MinPriority: 0, MaxVictimsPerMinute: 10}},
+		MaxVictimsPerMinutePerNamespace: 10,
+	}
+	expected := preemptionbudget.Usage{
+		{Type: preemptionbudget.PriorityBandBudget, Value: "0"}: 2,
+		{Type: preemptionbudget.NamespaceBudget, Value: "p1"}:   1,
+		{Type: preemptionbudget.NamespaceBudget, Value: "p2"}:   1,
+	}
+	if got := snapshot.(*PreemptionStore).GetPreemptionBudgetUsage(budget); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected usage: %v, but got: %v", expected, got)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queueusagestore
+
+import (
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const Name commonstores.StoreName = "QueueUsageStore"
+
+func (s *QueueUsageStore) Name() commonstores.StoreName {
+	return Name
+}
+
+func init() {
+	commonstores.GlobalRegistry.Register(
+		Name,
+		func(h handler.CacheHandler) bool {
+			return utilfeature.DefaultFeatureGate.Enabled(features.FairShareQueueSort)
+		},
+		NewCache,
+		NewSnapshot)
+}
+
+// ---------------------------------------------------------------------------------------
+
+// queuedPod is the part of an assumed or bound pod accounted to its fair-share queue.
+type queuedPod struct {
+	queue    string
+	milliCPU int64
+	memory   int64
+}
+
+// QueueUsageStore maintains the cpu and memory requested by the assumed and bound pods of each fair-share queue,
+// and the allocatable cpu and memory of all nodes. It's only maintained in Cache.
+type QueueUsageStore struct {
+	commonstores.BaseStore
+	storeType commonstores.StoreType
+	handler   handler.CacheHandler
+
+	// pods is a map from pod key to the requests accounted to its queue, which makes the pod operations idempotent.
+	pods map[string]queuedPod
+	// queues is a map from queue to the total requests of its pods.
+	queues map[string]*framework.Resource
+	// nodes is a map from node name to its allocatable resources.
+	nodes       map[string]framework.Resource
+	allocatable framework.Resource
+}
+
+var _ commonstores.CommonStore = &QueueUsageStore{}
+
+func NewCache(handler handler.CacheHandler) commonstores.CommonStore {
+	return &QueueUsageStore{
+		BaseStore: commonstores.NewBaseStore(),
+		storeType: commonstores.Cache,
+		handler:   handler,
+
+		pods:   make(map[string]queuedPod),
+		queues: make(map[string]*framework.Resource),
+		nodes:  make(map[string]framework.Resource),
+	}
+}
+
+func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
+	return &QueueUsageStore{
+		BaseStore: commonstores.NewBaseStore(),
+		storeType: commonstores.Snapshot,
+		handler:   handler,
+	}
+}
+
+func (s *QueueUsageStore) AddPod(pod *v1.Pod) error {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerTypes()...) {
+		return nil
+	}
+	s.podOp(pod, true)
+	return nil
+}
+
+func (s *QueueUsageStore) UpdatePod(oldPod *v1.Pod, newPod *v1.Pod) error {
+	// Pods are tracked by key, so removing the old one is enough even if it's different from the one in Cache.
+	s.podOp(oldPod, false)
+	return s.AddPod(newPod)
+}
+
+func (s *QueueUsageStore) RemovePod(pod *v1.Pod) error {
+	s.podOp(pod, false)
+	return nil
+}
+
+func (s *QueueUsageStore) AssumePod(podInfo *framework.CachePodInfo) error {
+	s.podOp(podInfo.Pod, true)
+	return nil
+}
+
+func (s *QueueUsageStore) ForgetPod(podInfo *framework.CachePodInfo) error {
+	s.podOp(podInfo.Pod, false)
+	return nil
+}
+
+func (s *QueueUsageStore) AddNode(node *v1.Node) error {
+	s.nodeOp(node, true)
+	return nil
+}
+
+func (s *QueueUsageStore) UpdateNode(oldNode, newNode *v1.Node) error {
+	s.nodeOp(oldNode, false)
+	s.nodeOp(newNode, true)
+	return nil
+}
+
+func (s *QueueUsageStore) RemoveNode(node *v1.Node) error {
+	s.nodeOp(node, false)
+	return nil
+}
+
+func (s *QueueUsageStore) UpdateSnapshot(_ commonstores.CommonStore) error {
+	return nil
+}
+
+// -------------------------------------- Other Interface --------------------------------------
+
+// GetQueueShares returns the dominant share of the allocatable cpu and memory requested by each queue.
+func (s *QueueUsageStore) GetQueueShares() map[string]float64 {
+	shares := make(map[string]float64, len(s.queues))
+	for queue, requested := range s.queues {
+		var share float64
+		if s.allocatable.MilliCPU > 0 {
+			share = float64(requested.MilliCPU) / float64(s.allocatable.MilliCPU)
+		}
+		if s.allocatable.Memory > 0 {
+			if memoryShare := float64(requested.Memory) / float64(s.allocatable.Memory); memoryShare > share {
+				share = memoryShare
+			}
+		}
+		shares[queue] = share
+	}
+	return shares
+}
+
+// -------------------------------------- Internal Function --------------------------------------
+
+func (s *QueueUsageStore) podOp(pod *v1.Pod, isAdd bool) {
+	if s.storeType != commonstores.Cache {
+		return
+	}
+	key := podutil.GetPodKey(pod)
+	if p, ok := s.pods[key]; ok {
+		delete(s.pods, key)
+		requested := s.queues[p.queue]
+		requested.MilliCPU -= p.milliCPU
+		requested.Memory -= p.memory
+		if requested.MilliCPU <= 0 && requested.Memory <= 0 {
+			delete(s.queues, p.queue)
+		}
+	}
+	if !isAdd || utils.GetNodeNameFromPod(pod) == "" {
+		return
+	}
+
+	res, _, _ := framework.CalculateResource(pod)
+	p := queuedPod{queue: podutil.GetFairShareQueue(pod.Namespace, pod.Annotations), milliCPU: res.MilliCPU, memory: res.Memory}
+	s.pods[key] = p
+	requested, ok := s.queues[p.queue]
+	if !ok {
+		requested = &framework.Resource{}
+		s.queues[p.queue] = requested
+	}
+	requested.MilliCPU += p.milliCPU
+	requested.Memory += p.memory
+}
+
+func (s *QueueUsageStore) nodeOp(node *v1.Node, isAdd bool) {
+	if s.storeType != commonstores.Cache {
+		return
+	}
+	if allocatable, ok := s.nodes[node.Name]; ok {
+		delete(s.nodes, node.Name)
+		s.allocatable.MilliCPU -= allocatable.MilliCPU
+		s.allocatable.Memory -= allocatable.Memory
+	}
+	if !isAdd {
+		return
+	}
+	allocatable := framework.Resource{
+		MilliCPU: node.Status.Allocatable.Cpu().MilliValue(),
+		Memory:   node.Status.Allocatable.Memory().Value(),
+	}
+	s.nodes[node.Name] = allocatable
+	s.allocatable.MilliCPU += allocatable.MilliCPU
+	s.allocatable.Memory += allocatable.Memory
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queueusagestore
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func expectShares(t *testing.T, store *QueueUsageStore, want map[string]float64) {
+	t.Helper()
+	got := store.GetQueueShares()
+	if len(got) != len(want) {
+		t.Fatalf("expected shares %v, got %v", want, got)
+	}
+	for queue, share := range want {
+		if got[queue] != share {
+			t.Errorf("expected share %v of queue %v, got %v", share, queue, got[queue])
+		}
+	}
+}
+
+func TestQueueShares(t *testing.T) {
+	store := NewCache(nil).(*QueueUsageStore)
+	node := testing_helper.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10", v1.ResourceMemory: "100Gi"}).Obj()
+	if err := store.AddNode(node); err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := testing_helper.MakePod().Namespace("ns1").Name("p1").UID("p1").Node("n1").
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "10Gi"}).Obj()
+	p2 := testing_helper.MakePod().Namespace("ns1").Name("p2").UID("p2").Node("n1").
+		Annotation(podutil.FairShareQueueAnnotationKey, "q").
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "50Gi"}).Obj()
+	if err := store.AddPod(p1); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AssumePod(&framework.CachePodInfo{Pod: p2}); err != nil {
+		t.Fatal(err)
+	}
+	// The dominant share is used, and adding the same pod again doesn't count twice.
+	if err := store.AddPod(p2); err != nil {
+		t.Fatal(err)
+	}
+	expectShares(t, store, map[string]float64{"ns1": 0.2, "q": 0.5})
+
+	// The allocatable resources follow the nodes.
+	node2 := testing_helper.MakeNode().Name("n2").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10", v1.ResourceMemory: "100Gi"}).Obj()
+	if err := store.AddNode(node2); err != nil {
+		t.Fatal(err)
+	}
+	expectShares(t, store, map[string]float64{"ns1": 0.1, "q": 0.25})
+
+	if err := store.ForgetPod(&framework.CachePodInfo{Pod: p2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RemovePod(p1); err != nil {
+		t.Fatal(err)
+	}
+	expectShares(t, store, map[string]float64{})
+}
+
+func TestSnapshotIgnoresPods(t *testing.T) {
+	store := NewSnapshot(nil).(*QueueUsageStore)
+	pod := testing_helper.MakePod().Namespace("ns1").Name("p1").UID("p1").Node("n1").
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+	if err := store.AssumePod(&framework.CachePodInfo{Pod: pod}); err != nil {
+		t.Fatal(err)
+	}
+	expectShares(t, store, map[string]float64{})
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservationstore
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+)
+
+const Name commonstores.StoreName = "ReservationStore"
+
+func (s *ReservationStore) Name() commonstores.StoreName {
+	return Name
+}
+
+func init() {
+	commonstores.GlobalRegistry.Register(
+		Name,
+		func(h handler.CacheHandler) bool {
+			return utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation)
+		},
+		NewCache,
+		NewSnapshot)
+}
+
+// ---------------------------------------------------------------------------------------
+
+// ReservationStore maintains the resource reservations declared by node annotations, and the ones held back
+// by the scheduler itself for the units. Only the nodes having reservations are stored.
+type ReservationStore struct {
+	commonstores.BaseStore
+	storeType commonstores.StoreType
+	handler   handler.CacheHandler
+
+	store generationstore.Store
+	// nodeReservations and unitReservations are only maintained in Cache, Snapshot only has the merged results in store.
+	// nodeReservations is a map from node name to the reservations declared by its annotation.
+	nodeReservations map[string][]*framework.ResourceReservation
+	// unitReservations is a map from owner to the reservations held back for it, keyed by node name.
+	unitReservations map[string]map[string]*framework.ResourceReservation
+
+	now func() time.Time
+}
+
+func NewCache(handler handler.CacheHandler) commonstores.CommonStore {
+	return &ReservationStore{
+		BaseStore: commonstores.NewBaseStore(),
+		storeType: commonstores.Cache,
+		handler:   handler,
+
+		store:            generationstore.NewListStore(),
+		nodeReservations: make(map[string][]*framework.ResourceReservation),
+		unitReservations: make(map[string]map[string]*framework.ResourceReservation),
+		now:              time.Now,
+	}
+}
+
+func NewSnapshot(handler handler.CacheHandler) commonstores.CommonStore {
+	return &ReservationStore{
+		BaseStore: commonstores.NewBaseStore(),
+		storeType: commonstores.Snapshot,
+		handler:   handler,
+
+		store: generationstore.NewRawStore(),
+		now:   time.Now,
+	}
+}
+
+func (s *ReservationStore) AddNode(node *v1.Node) error {
+	return s.nodeOp(node)
+}
+
+func (s *ReservationStore) UpdateNode(oldNode, newNode *v1.Node) error {
+	return s.nodeOp(newNode)
+}
+
+func (s *ReservationStore) RemoveNode(node *v1.Node) error {
+	delete(s.nodeReservations, node.Name)
+	s.refreshNode(node.Name)
+	return nil
+}
+
+func (s *ReservationStore) UpdateSnapshot(store commonstores.CommonStore) error {
+	cache, snapshot := framework.TransferGenerationStore(s.store, store.(*ReservationStore).store)
+	cache.UpdateRawStore(
+		snapshot,
+		func(key string, obj generationstore.StoredObj) {
+			reservations := obj.(framework.GenerationNodeReservations)
+			snapshot.Set(key, reservations.Clone())
+		},
+		generationstore.DefaultCleanFunc(cache, snapshot),
+	)
+	return nil
+}
+
+// -------------------------------------- Other Interface --------------------------------------
+
+// GetNodeReservations returns the unexpired reservations of the node.
+func (s *ReservationStore) GetNodeReservations(nodeName string) []*framework.ResourceReservation {
+	obj := s.store.Get(nodeName)
+	if obj == nil {
+		return nil
+	}
+	now := s.now()
+	var ret []*framework.ResourceReservation
+	for _, r := range obj.(framework.GenerationNodeReservations).GetReservations() {
+		if !r.Expired(now) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// GetReservedNodes returns the names of nodes having unexpired reservations owned by the pod.
+func (s *ReservationStore) GetReservedNodes(pod *v1.Pod) []string {
+	now := s.now()
+	var nodes []string
+	s.store.Range(func(nodeName string, obj generationstore.StoredObj) {
+		for _, r := range obj.(framework.GenerationNodeReservations).GetReservations() {
+			if !r.Expired(now) && r.OwnedBy(pod) {
+				nodes = append(nodes, nodeName)
+				return
+			}
+		}
+	})
+	return nodes
+}
+
+// SetUnitReservations holds back the resources on nodes for the owner, the previous reservations of the owner are replaced.
+func (s *ReservationStore) SetUnitReservations(owner string, reservations map[string]*framework.ResourceReservation) {
+	nodes := sets.NewString()
+	for nodeName := range s.unitReservations[owner] {
+		nodes.Insert(nodeName)
+	}
+	for nodeName := range reservations {
+		nodes.Insert(nodeName)
+	}
+	if len(reservations) == 0 {
+		delete(s.unitReservations, owner)
+	} else {
+		s.unitReservations[owner] = reservations
+	}
+	// Clean up the expired ones in passing, in case of the owners which are never scheduled again.
+	now := s.now()
+	for o, rs := range s.unitReservations {
+		if o != owner && allExpired(rs, now) {
+			delete(s.unitReservations, o)
+			for nodeName := range rs {
+				nodes.Insert(nodeName)
+			}
+		}
+	}
+	for nodeName := range nodes {
+		s.refreshNode(nodeName)
+	}
+}
+
+// GetUnitReservations returns the unexpired reservations held back for the owner, keyed by node name.
+func (s *ReservationStore) GetUnitReservations(owner string) map[string]*framework.ResourceReservation {
+	now := s.now()
+	var ret map[string]*framework.ResourceReservation
+	for nodeName, r := range s.unitReservations[owner] {
+		if r.Expired(now) {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]*framework.ResourceReservation)
+		}
+		ret[nodeName] = r
+	}
+	return ret
+}
+
+// RemoveUnitReservations releases the resources held back for the owner.
+func (s *ReservationStore) RemoveUnitReservations(owner string) {
+	s.SetUnitReservations(owner, nil)
+}
+
+// -------------------------------------- Internal Function --------------------------------------
+
+func (s *ReservationStore) nodeOp(node *v1.Node) error {
+	reservations, err := framework.ParseResourceReservations(node)
+	if err != nil {
+		// Invalid reservations should not block the node events, just ignore them.
+		klog.InfoS("Failed to parse resource reservations, ignored them", "node", node.Name, "err", err)
+	}
+	if len(reservations) == 0 {
+		delete(s.nodeReservations, node.Name)
+	} else {
+		s.nodeReservations[node.Name] = reservations
+	}
+	s.refreshNode(node.Name)
+	return nil
+}
+
+// refreshNode merges the reservations of the node and updates the store.
+func (s *ReservationStore) refreshNode(nodeName string) {
+	var reservations []*framework.ResourceReservation
+	reservations = append(reservations, s.nodeReservations[nodeName]...)
+	for _, rs := range s.unitReservations {
+		if r, ok := rs[nodeName]; ok {
+			reservations = append(reservations, r)
+		}
+	}
+	if len(reservations) == 0 {
+		s.store.Delete(nodeName)
+		return
+	}
+	s.store.Set(nodeName, framework.NewGenerationNodeReservations(reservations))
+}
+
+func allExpired(reservations map[string]*framework.ResourceReservation, now time.Time) bool {
+	for _, r := range reservations {
+		if !r.Expired(now) {
+			return false
+		}
+	}
+	return true
+}
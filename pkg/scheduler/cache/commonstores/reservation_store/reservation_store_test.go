@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservationstore
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeReservation(name, owner string, cpu string, creationTime time.Time, ttlSeconds int64) *framework.ResourceReservation {
+	return &framework.ResourceReservation{
+		Name:         name,
+		Owner:        owner,
+		Resources:    v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+		CreationTime: metav1.NewTime(creationTime),
+		TTLSeconds:   ttlSeconds,
+	}
+}
+
+func makePodGroupPod(pgName string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        pgName + "-0",
+		Annotations: map[string]string{podutil.PodGroupNameAnnotationKey: pgName},
+	}}
+}
+
+func expectReservationNames(t *testing.T, store *ReservationStore, nodeName string, want ...string) {
+	t.Helper()
+	got := store.GetNodeReservations(nodeName)
+	if len(got) != len(want) {
+		t.Fatalf("expected reservations %v on node %v, got %v", want, nodeName, got)
+	}
+	names := make(map[string]bool, len(got))
+	for _, r := range got {
+		names[r.Name] = true
+	}
+	for _, name := range want {
+		if !names[name] {
+			t.Errorf("expected reservation %v on node %v, got %v", name, nodeName, got)
+		}
+	}
+}
+
+func TestUnitReservations(t *testing.T) {
+	now := time.Now()
+	store := NewCache(nil).(*ReservationStore)
+	store.now = func() time.Time { return now }
+
+	store.SetUnitReservations("default/pg-1", map[string]*framework.ResourceReservation{
+		"n1": makeReservation("r1", "default/pg-1", "4", now, 60),
+		"n2": makeReservation("r2", "default/pg-1", "2", now, 60),
+	})
+	store.SetUnitReservations("default/pg-2", map[string]*framework.ResourceReservation{
+		"n1": makeReservation("r3", "default/pg-2", "1", now, 10),
+	})
+	expectReservationNames(t, store, "n1", "r1", "r3")
+	expectReservationNames(t, store, "n2", "r2")
+	if got := store.GetReservedNodes(makePodGroupPod("pg-1")); len(got) != 2 {
+		t.Errorf("expected pg-1 to have reservations on 2 nodes, got %v", got)
+	}
+
+	// The previous reservations of the owner are replaced.
+	store.SetUnitReservations("default/pg-1", map[string]*framework.ResourceReservation{
+		"n2": makeReservation("r4", "default/pg-1", "2", now, 60),
+	})
+	expectReservationNames(t, store, "n1", "r3")
+	expectReservationNames(t, store, "n2", "r4")
+
+	// The expired reservations are hidden, and dropped when the reservations of other owners change.
+	now = now.Add(30 * time.Second)
+	expectReservationNames(t, store, "n1")
+	if got := store.GetUnitReservations("default/pg-2"); len(got) != 0 {
+		t.Errorf("expected no unexpired reservations of pg-2, got %v", got)
+	}
+	store.RemoveUnitReservations("default/pg-1")
+	if len(store.unitReservations) != 0 {
+		t.Errorf("expected all the unit reservations to be dropped, got %v", store.unitReservations)
+	}
+	if store.store.Get("n1") != nil || store.store.Get("n2") != nil {
+		t.Errorf("expected the nodes without reservations to be removed from the store")
+	}
+}
+
+func makeReservedNode(t *testing.T, name string, reservations ...*framework.ResourceReservation) *v1.Node {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if len(reservations) > 0 {
+		value, err := framework.FormatResourceReservations(reservations)
+		if err != nil {
+			t.Fatal(err)
+		}
+		node.Annotations = map[string]string{framework.ResourceReservationAnnotationKey: value}
+	}
+	return node
+}
+
+func TestNodeReservations(t *testing.T) {
+	now := time.Now()
+	store := NewCache(nil).(*ReservationStore)
+	store.now = func() time.Time { return now }
+
+	store.AddNode(makeReservedNode(t, "n1", makeReservation("r1", "default/pg-1", "4", now, 60)))
+	store.SetUnitReservations("default/pg-2", map[string]*framework.ResourceReservation{
+		"n1": makeReservation("r2", "default/pg-2", "2", now, 60),
+	})
+	expectReservationNames(t, store, "n1", "r1", "r2")
+	if got := store.GetReservedNodes(makePodGroupPod("pg-1")); len(got) != 1 || got[0] != "n1" {
+		t.Errorf("expected pg-1 to have reservations on n1, got %v", got)
+	}
+
+	// The reservations declared by the node are replaced on update, the unit reservations are kept.
+	store.UpdateNode(nil, makeReservedNode(t, "n1", makeReservation("r3", "default/pg-1", "4", now, 60)))
+	expectReservationNames(t, store, "n1", "r2", "r3")
+
+	// The malformed annotation is ignored.
+	malformed := makeReservedNode(t, "n1")
+	malformed.Annotations = map[string]string{framework.ResourceReservationAnnotationKey: "malformed"}
+	store.UpdateNode(nil, malformed)
+	expectReservationNames(t, store, "n1", "r2")
+
+	store.RemoveNode(makeReservedNode(t, "n1"))
+	expectReservationNames(t, store, "n1", "r2")
+	store.RemoveUnitReservations("default/pg-2")
+	if store.store.Get("n1") != nil {
+		t.Errorf("expected the node without reservations to be removed from the store")
+	}
+}
+
+func TestControllerReservedNodes(t *testing.T) {
+	now := time.Now()
+	store := NewCache(nil).(*ReservationStore)
+	store.now = func() time.Time { return now }
+
+	r := makeReservation("r1", "default/rs", "4", now, 60)
+	r.OwnerUID = "rs"
+	store.AddNode(makeReservedNode(t, "n1", r))
+	store.AddNode(makeReservedNode(t, "n2", makeReservation("r2", "default/rs", "4", now, 60)))
+
+	makeReplicaSetPod := func(creationTime time.Time) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "rs-0",
+			CreationTimestamp: metav1.NewTime(creationTime),
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "rs", UID: "rs", Controller: pointer.Bool(true)},
+			},
+		}}
+	}
+	// Only the pods recreated by the controller after the reservation own it.
+	if got := store.GetReservedNodes(makeReplicaSetPod(now.Add(time.Second))); len(got) != 1 || got[0] != "n1" {
+		t.Errorf("expected the recreated pod to have reservations on n1, got %v", got)
+	}
+	if got := store.GetReservedNodes(makeReplicaSetPod(now.Add(-time.Second))); len(got) != 0 {
+		t.Errorf("expected the pod created before the reservation to have no reservations, got %v", got)
+	}
+}
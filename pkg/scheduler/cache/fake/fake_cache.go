@@ -124,6 +124,11 @@ func (c *Cache) NodeInThisPartition(nodeName string) bool {
 	return true
 }
 
+// GetNodeGeneration is a fake method for testing.
+func (c *Cache) GetNodeGeneration(nodeName string) int64 {
+	return 0
+}
+
 // SetNodeInPartition sets node in partition of scheduler
 func (c *Cache) SetNodeInPartition(nodeName string) error {
 	return nil
@@ -193,6 +198,19 @@ func (c *Cache) GetUnitSchedulingStatus(unitKey string) unitstatus.SchedulingSta
 	return c.UnitStatus.GetUnitSchedulingStatus(unitKey)
 }
 
+func (c *Cache) SetUnitReservations(owner string, reservations map[string]*framework.ResourceReservation) {
+}
+
+func (c *Cache) GetUnitReservations(owner string) map[string]*framework.ResourceReservation {
+	return nil
+}
+
+func (c *Cache) RemoveUnitReservations(owner string) {}
+
+func (c *Cache) GetQueueShares() map[string]float64 {
+	return nil
+}
+
 func (cache *Cache) GetUnitStatus(unitKey string) unitstatus.UnitStatus {
 	return cache.UnitStatus.GetUnitStatus(unitKey)
 }
@@ -37,8 +37,13 @@ type (
 type CacheHandler interface {
 	SchedulerName() string
 	SchedulerType() string
+	// SchedulerTypes returns all the scheduler names served by the scheduler, including
+	// the primary one returned by SchedulerType and the ones of the schedulerName profiles.
+	SchedulerTypes() []string
 	SubCluster() string
 	SwitchType() framework.SwitchType
+	// NodePartitionType returns the node partition type of the scheduler, Physical or Logical.
+	NodePartitionType() string
 
 	// TODO: Revisit this and split the judgment section on whether storage needs to be enabled.
 	IsStoreEnabled(string) bool
@@ -63,10 +68,12 @@ type CacheHandler interface {
 }
 
 type handler struct {
-	schedulerName string
-	schedulerType string
-	subCluster    string
-	switchType    framework.SwitchType // Only be used in Snapshot.
+	schedulerName  string
+	schedulerTypes []string
+	subCluster     string
+	switchType     framework.SwitchType // Only be used in Snapshot.
+
+	nodePartitionType string
 
 	enabledStores sets.String
 
@@ -87,9 +94,10 @@ type handler struct {
 var _ CacheHandler = &handler{}
 
 func (h *handler) SchedulerName() string                  { return h.schedulerName }
-func (h *handler) SchedulerType() string                  { return h.schedulerType }
+func (h *handler) SchedulerTypes() []string               { return h.schedulerTypes }
 func (h *handler) SubCluster() string                     { return h.subCluster }
 func (h *handler) SwitchType() framework.SwitchType       { return h.switchType }
+func (h *handler) NodePartitionType() string              { return h.nodePartitionType }
 func (h *handler) IsStoreEnabled(storeName string) bool   { return h.enabledStores.Has(storeName) }
 func (h *handler) TTL() time.Duration                     { return h.ttl }
 func (h *handler) Period() time.Duration                  { return h.period }
@@ -97,11 +105,25 @@ func (h *handler) StopCh() <-chan struct{}                { return h.stop }
 func (h *handler) PodLister() corelister.PodLister        { return h.podLister }
 func (h *handler) PodInformer() coreinformers.PodInformer { return h.podInformer }
 
-func (h *handler) GetNodeInfo(nodeName string) framework.NodeInfo          { return h.nodeHandler(nodeName) }
+func (h *handler) SchedulerType() string {
+	if len(h.schedulerTypes) == 0 {
+		return ""
+	}
+	return h.schedulerTypes[0]
+}
+
 func (h *handler) GetPodState(key string) (*framework.CachePodState, bool) { return h.podHandler(key) }
 func (h *handler) SetNodeHandler(f NodeHandler)                            { h.nodeHandler = f }
 func (h *handler) SetPodHandler(f PodHandler)                              { h.podHandler = f }
 
+// GetNodeInfo returns nil if the node handler is not set.
+func (h *handler) GetNodeInfo(nodeName string) framework.NodeInfo {
+	if h.nodeHandler == nil {
+		return nil
+	}
+	return h.nodeHandler(nodeName)
+}
+
 func (h *handler) PodOp(pod *v1.Pod, isAdd bool, skippStores sets.String) error {
 	return h.podOpFunc(pod, isAdd, skippStores)
 }
@@ -124,8 +146,9 @@ func (w *handlerWrapper) SchedulerName(schedulerName string) *handlerWrapper {
 	return w
 }
 
-func (w *handlerWrapper) SchedulerType(schedulerType string) *handlerWrapper {
-	w.obj.schedulerType = schedulerType
+// SchedulerType sets the scheduler names served by the scheduler, the first one is the primary name.
+func (w *handlerWrapper) SchedulerType(schedulerTypes ...string) *handlerWrapper {
+	w.obj.schedulerTypes = schedulerTypes
 	return w
 }
 
@@ -134,6 +157,11 @@ func (w *handlerWrapper) SubCluster(subCluster string) *handlerWrapper {
 	return w
 }
 
+func (w *handlerWrapper) NodePartitionType(nodePartitionType string) *handlerWrapper {
+	w.obj.nodePartitionType = nodePartitionType
+	return w
+}
+
 func (w *handlerWrapper) SwitchType(switchType framework.SwitchType) *handlerWrapper {
 	w.obj.switchType = switchType
 	return w
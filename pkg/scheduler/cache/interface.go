@@ -92,6 +92,10 @@ type SchedulerCache interface {
 	// NodeInThisPartition returns whether this node is in this scheduler's partition
 	NodeInThisPartition(nodeName string) bool
 
+	// GetNodeGeneration returns the generation of the node in cache, 0 is returned if the node does not exist.
+	// The generation is bumped whenever the node or the pods on it are updated.
+	GetNodeGeneration(nodeName string) int64
+
 	// UpdateSnapshot updates the passed infoSnapshot to the current contents of SchedulerCache.
 	// The node info contains aggregated information of pods scheduled (including assumed to be)
 	// on this node.
@@ -106,6 +110,17 @@ type SchedulerCache interface {
 	// SetUnitSchedulingStatus set the scheduling status
 	SetUnitSchedulingStatus(unitKey string, status unitstatus.SchedulingStatus)
 
+	// SetUnitReservations holds back the resources on nodes for the owner, replacing the previous ones.
+	SetUnitReservations(owner string, reservations map[string]*framework.ResourceReservation)
+	// GetUnitReservations returns the unexpired resources held back for the owner, keyed by node name.
+	GetUnitReservations(owner string) map[string]*framework.ResourceReservation
+	// RemoveUnitReservations releases the resources held back for the owner.
+	RemoveUnitReservations(owner string)
+
+	// GetQueueShares returns the dominant share of the allocatable resources requested by the assumed and bound
+	// pods of each fair-share queue, nil is returned if FairShareQueueSort is disabled.
+	GetQueueShares() map[string]float64
+
 	// AssumePod assumes a pod scheduled and aggregates the pod's information into its node.
 	// The implementation also decides the policy to expire pod before being confirmed (receiving Add event).
 	// After expiration, its information would be subtracted.
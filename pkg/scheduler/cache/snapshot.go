@@ -20,16 +20,20 @@ import (
 	"fmt"
 
 	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
+	equivalencestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/equivalence_store"
 	loadawarestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/load_aware_store"
 	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
 	pdbstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pdb_store"
 	podgroupstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/podgroup_store"
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
+	reservationstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/reservation_store"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 )
 
 // Snapshot is a snapshot of s NodeInfo and NodeTree order. The scheduler takes a
@@ -176,6 +180,11 @@ func (s *Snapshot) GetPreemptorsByVictim(node, victim string) []string {
 	return s.storeSwitch.Find(preemptionstore.Name).(*preemptionstore.PreemptionStore).GetPreemptorsByVictim(node, victim)
 }
 
+// GetPreemptionBudgetUsage returns the victims nominated in the last minute accounted in the budget.
+func (s *Snapshot) GetPreemptionBudgetUsage(budget *preemptionbudget.Budget) preemptionbudget.Usage {
+	return s.storeSwitch.Find(preemptionstore.Name).(*preemptionstore.PreemptionStore).GetPreemptionBudgetUsage(budget)
+}
+
 // GetPDBItemList return PDB items in snapshot.
 //
 // Note: Snapshot operations are lock-free. Our premise for removing lock: even if read operations
@@ -227,6 +236,65 @@ func (s *Snapshot) GetLoadAwareNodeUsage(nodeName string, resourceType podutil.P
 	return s.storeSwitch.Find(loadawarestore.Name).(*loadawarestore.LoadAwareStore).GetLoadAwareNodeUsage(nodeName, resourceType)
 }
 
+func (s *Snapshot) GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *framework.LoadAwareWorkloadUsage {
+	// TODO: Remove GetLoadAwareWorkloadUsage interface and expose Store by ScheduleFrameworkHandler directly.
+	return s.storeSwitch.Find(loadawarestore.Name).(*loadawarestore.LoadAwareStore).GetLoadAwareWorkloadUsage(workloadKey, percentile)
+}
+
+// GetNodeReservations returns the unexpired resource reservations of the node.
+// Nil is returned if ResourceReservation is disabled.
+//
+// Note: Snapshot operations are lock-free. Our premise for removing lock: even if read operations
+// are concurrent, write operations(AssumePod/ForgetPod/AddOneVictim) should always be serial.
+func (s *Snapshot) GetNodeReservations(nodeName string) []*framework.ResourceReservation {
+	store := s.storeSwitch.Find(reservationstore.Name)
+	if store == nil {
+		return nil
+	}
+	return store.(*reservationstore.ReservationStore).GetNodeReservations(nodeName)
+}
+
+// GetReservedNodes returns the nodes having unexpired resource reservations owned by the pod.
+// Nil is returned if ResourceReservation is disabled.
+//
+// Note: Snapshot operations are lock-free. Our premise for removing lock: even if read operations
+// are concurrent, write operations(AssumePod/ForgetPod/AddOneVictim) should always be serial.
+func (s *Snapshot) GetReservedNodes(pod *v1.Pod) []string {
+	store := s.storeSwitch.Find(reservationstore.Name)
+	if store == nil {
+		return nil
+	}
+	return store.(*reservationstore.ReservationStore).GetReservedNodes(pod)
+}
+
+// GetEquivalentFilterResults returns the cached filter results of the equivalence class on the node.
+// Nil is returned if EquivalenceCache is disabled.
+//
+// Note: Snapshot operations are lock-free. Our premise for removing lock: even if read operations
+// are concurrent, write operations(AssumePod/ForgetPod/AddOneVictim) should always be serial.
+func (s *Snapshot) GetEquivalentFilterResults(class string, nodeInfo framework.NodeInfo) equivalencestore.FilterResults {
+	store := s.storeSwitch.Find(equivalencestore.Name)
+	if store == nil {
+		return nil
+	}
+	return store.(*equivalencestore.EquivalenceStore).GetFilterResults(class, nodeInfo)
+}
+
+// AddEquivalentFilterResults caches the filter results of the equivalence class on the node.
+// It's safe to be called concurrently during filtering.
+func (s *Snapshot) AddEquivalentFilterResults(class string, nodeInfo framework.NodeInfo, results equivalencestore.FilterResults) {
+	store := s.storeSwitch.Find(equivalencestore.Name)
+	if store == nil {
+		return
+	}
+	store.(*equivalencestore.EquivalenceStore).AddFilterResults(class, nodeInfo, results)
+}
+
+// EquivalenceCacheEnabled checks whether the filter results can be cached by equivalence class.
+func (s *Snapshot) EquivalenceCacheEnabled() bool {
+	return s.storeSwitch.Find(equivalencestore.Name) != nil
+}
+
 // -------------------------------------- node slice for snapshot --------------------------------------
 
 type nodeSlices struct {
@@ -21,12 +21,15 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
+	equivalencestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/equivalence_store"
 	loadawarestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/load_aware_store"
 	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
 	pdbstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pdb_store"
 	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
 	podgroupstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/podgroup_store"
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
+	queueusagestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/queue_usage_store"
+	reservationstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/reservation_store"
 	unitstatusstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/unit_status_store"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
 )
@@ -36,11 +39,14 @@ var orderedStoreNames = []commonstores.StoreName{
 	// misc
 	pdbstore.Name,
 	podgroupstore.Name,
+	reservationstore.Name,
+	equivalencestore.Name,
 
 	// pod related
 	preemptionstore.Name,
 	unitstatusstore.Name,
 	loadawarestore.Name,
+	queueusagestore.Name,
 
 	nodestore.Name, // NodeStore be placed second to last.
 	podstore.Name,  // PodStore must be placed at the end.
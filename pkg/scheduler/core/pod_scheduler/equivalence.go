@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podscheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	equivalencestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/equivalence_store"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeaffinity"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeports"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/noderesources"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeunschedulable"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/podlauncher"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/tainttoleration"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/runtime"
+	hashutil "github.com/kubewharf/godel-scheduler/pkg/util/hash"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// equivalentFilterPlugins are the filter plugins whose results only depend on the pod spec and the NodeInfo,
+// so the results can be reused by the pods of the same equivalence class until the NodeInfo changes.
+var equivalentFilterPlugins = sets.NewString(
+	nodeunschedulable.Name,
+	nodeaffinity.Name,
+	tainttoleration.Name,
+	podlauncher.Name,
+	nodeports.Name,
+	noderesources.FitName,
+)
+
+// isEquivalentFilterPlugin checks whether the results of the filter plugin can be cached by equivalence class.
+func isEquivalentFilterPlugin(pluginName string) bool {
+	if !equivalentFilterPlugins.Has(pluginName) {
+		return false
+	}
+	// The resources reserved for units are not reflected by the generation of NodeInfo.
+	if pluginName == noderesources.FitName && utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation) {
+		return false
+	}
+	return true
+}
+
+// getEquivalenceClass returns the equivalence class of the pod, which is composed of the pod owner and the hash
+// of the pod template. Empty string is returned if the pod has no owner or EquivalenceCache is disabled.
+func (gs *podScheduler) getEquivalenceClass(pod *v1.Pod) string {
+	if gs.snapshot == nil || !gs.snapshot.EquivalenceCacheEnabled() {
+		return ""
+	}
+	owner := podutil.GetPodOwner(pod)
+	if len(owner) == 0 {
+		return ""
+	}
+	resourceType, _ := podutil.GetPodResourceType(pod)
+	podLauncher, _ := podutil.GetPodLauncher(pod)
+
+	hasher := fnv.New64a()
+	hashutil.DeepHashObject(hasher, struct {
+		Namespace    string
+		Spec         v1.PodSpec
+		ResourceType podutil.PodResourceType
+		PodLauncher  podutil.PodLauncher
+	}{pod.Namespace, pod.Spec, resourceType, podLauncher})
+	return owner + "/" + strconv.FormatUint(hasher.Sum64(), 16)
+}
+
+// podPassesFiltersWithEquivalence is similar to runtime.PodPassesFiltersOnNode, but reuses the filter results
+// of the pods of the same equivalence class and caches the new ones.
+// ATTENTION: the NodeInfo must be the one in snapshot. Preemption and the preferred node hooks filter the clones
+// with pods removed, which keep the generation, so they must call runtime.PodPassesFiltersOnNode directly.
+func (gs *podScheduler) podPassesFiltersWithEquivalence(
+	ctx context.Context,
+	f framework.SchedulerFramework,
+	state *framework.CycleState,
+	pod *v1.Pod,
+	nodeInfo framework.NodeInfo,
+	equivalenceClass string,
+) (bool, *framework.Status, error) {
+	if len(equivalenceClass) == 0 {
+		fit, status, _, err := runtime.PodPassesFiltersOnNode(ctx, f, state, pod, nodeInfo)
+		return fit, status, err
+	}
+
+	var skipPlugins []string
+	for pluginName, status := range gs.snapshot.GetEquivalentFilterResults(equivalenceClass, nodeInfo) {
+		if !status.IsSuccess() {
+			return false, status, nil
+		}
+		skipPlugins = append(skipPlugins, pluginName)
+	}
+
+	fit, status, statusMap, err := runtime.PodPassesFiltersOnNode(ctx, f, state, pod, nodeInfo, skipPlugins...)
+	if err != nil {
+		return fit, status, err
+	}
+
+	results := make(equivalencestore.FilterResults)
+	if fit {
+		// All the filter plugins have been passed.
+		for pluginName := range equivalentFilterPlugins {
+			if isEquivalentFilterPlugin(pluginName) {
+				results[pluginName] = nil
+			}
+		}
+	} else {
+		for pluginName, pluginStatus := range statusMap {
+			if isEquivalentFilterPlugin(pluginName) && pluginStatus.IsUnschedulable() {
+				results[pluginName] = pluginStatus
+			}
+		}
+	}
+	gs.snapshot.AddEquivalentFilterResults(equivalenceClass, nodeInfo, results)
+	return fit, status, nil
+}
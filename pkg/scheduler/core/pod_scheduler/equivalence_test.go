@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podscheduler
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	schedulerframework "github.com/kubewharf/godel-scheduler/pkg/scheduler/framework"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/noderesources"
+	frameworkruntime "github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/runtime"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestFindFeasibleNodesWithEquivalence(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EquivalenceCache, true)()
+
+	schedulerCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		Obj())
+	snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		Obj())
+	victim := testinghelper.MakePod().Namespace("default").Name("victim").UID("victim").Node("n2").
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj()
+	for _, node := range []*v1.Node{
+		testinghelper.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+		testinghelper.MakeNode().Name("n2").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+	} {
+		schedulerCache.AddNode(node)
+	}
+	schedulerCache.AddPod(victim)
+	schedulerCache.UpdateSnapshot(snapshot)
+
+	client := clientsetfake.NewSimpleClientset()
+	crdClient := godelclientfake.NewSimpleClientset()
+	gs := &podScheduler{
+		clientSet:          client,
+		crdClient:          crdClient,
+		informerFactory:    informers.NewSharedInformerFactory(client, 0),
+		crdInformerFactory: crdinformers.NewSharedInformerFactory(crdClient, 0),
+		basePlugins:        newBasePlugins(),
+		snapshot:           snapshot,
+	}
+	pluginRegistry, err := schedulerframework.NewPluginsRegistry(schedulerframework.NewInTreeRegistry(), nil, gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	makePod := func(name string) *v1.Pod {
+		return testinghelper.MakePod().Namespace("default").Name(name).UID(name).
+			ControllerRef(metav1.OwnerReference{Kind: "ReplicaSet", Name: "rs", UID: "rs"}).
+			Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()
+	}
+	findFeasibleNodes := func(pod *v1.Pod) ([]string, framework.NodeToStatusMap) {
+		f, err := frameworkruntime.NewPodFramework(pluginRegistry, nil, gs.getBasePluginsForPod(pod), &framework.PluginCollection{}, &framework.PluginCollection{}, gs.metricsRecorder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := framework.NewCycleState()
+		framework.SetPodResourceTypeState(podutil.GuaranteedPod, state)
+		if s := f.RunPreFilterPlugins(context.Background(), state, pod); !s.IsSuccess() {
+			t.Fatal(s.AsError())
+		}
+		statuses := make(framework.NodeToStatusMap)
+		feasibleNodes, err := gs.findFeasibleNodes(context.Background(), f, state, pod, statuses, nil, snapshot.List(), false, &framework.UnitSchedulingRequest{AllMember: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var nodeNames []string
+		for _, nodeInfo := range feasibleNodes {
+			nodeNames = append(nodeNames, nodeInfo.GetNodeName())
+		}
+		sort.Strings(nodeNames)
+		return nodeNames, statuses
+	}
+
+	foo := makePod("foo")
+	class := gs.getEquivalenceClass(foo)
+	if len(class) == 0 {
+		t.Fatal("expected the pod owned by ReplicaSet to have an equivalence class")
+	}
+	nodeNames, _ := findFeasibleNodes(foo)
+	if len(nodeNames) != 1 || nodeNames[0] != "n1" {
+		t.Errorf("expected feasible nodes [n1], got %v", nodeNames)
+	}
+	cached := snapshot.GetEquivalentFilterResults(class, snapshot.GetNodeInfo("n2"))[noderesources.FitName]
+	if cached == nil || cached.IsSuccess() {
+		t.Fatalf("expected the Fit failure on n2 to be cached, got %v", cached)
+	}
+	if results := snapshot.GetEquivalentFilterResults(class, snapshot.GetNodeInfo("n1")); len(results) == 0 {
+		t.Errorf("expected the filter results on n1 to be cached")
+	}
+
+	// The pod of the same equivalence class gets the cached status instead of running the filter again.
+	bar := makePod("bar")
+	if got := gs.getEquivalenceClass(bar); got != class {
+		t.Fatalf("expected the same equivalence class %v, got %v", class, got)
+	}
+	nodeNames, statuses := findFeasibleNodes(bar)
+	if len(nodeNames) != 1 || nodeNames[0] != "n1" {
+		t.Errorf("expected feasible nodes [n1], got %v", nodeNames)
+	}
+	if statuses["n2"] != cached {
+		t.Errorf("expected the cached status %v on n2, got %v", cached, statuses["n2"])
+	}
+
+	// Assuming a preemptor with the victim removed from n2 invalidates the cached results on n2.
+	preemptor := testinghelper.MakePod().Namespace("default").Name("preemptor").UID("preemptor").
+		Annotation(podutil.AssumedNodeAnnotationKey, "n2").Obj()
+	if err := snapshot.AssumePod(framework.MakeCachePodInfoWrapper().Pod(preemptor).
+		Victims(&framework.Victims{Pods: []*v1.Pod{victim}}).Obj()); err != nil {
+		t.Fatal(err)
+	}
+	if results := snapshot.GetEquivalentFilterResults(class, snapshot.GetNodeInfo("n2")); results != nil {
+		t.Errorf("expected the cached results on n2 to be invalidated, got %v", results)
+	}
+	nodeNames, _ = findFeasibleNodes(makePod("baz"))
+	if len(nodeNames) != 2 {
+		t.Errorf("expected feasible nodes [n1 n2] after the victim was removed, got %v", nodeNames)
+	}
+}
@@ -69,6 +69,7 @@ type podScheduler struct {
 	snapshot *cache.Snapshot
 
 	disablePreemption                 bool
+	preemptionDryRun                  bool
 	candidateSelectPolicy             string
 	betterSelectPolicies              []string
 	percentageOfNodesToScore          int32
@@ -427,6 +428,7 @@ func (gs *podScheduler) findNodesThatPassFilters(
 	// always prefer to find feasible nodes from in-partition nodes,
 	// if none is found and the schedulerNodePartitionType is 'Logical',
 	// try to find feasible nodes from out-of-partition nodes.
+	// If the schedulerNodePartitionType is 'Physical', out-of-partition nodes are never put into the snapshot.
 	inPartitionNodes := nodeLister.InPartitionList()
 	outOfPartitionNodes := nodeLister.OutOfPartitionList()
 
@@ -485,6 +487,7 @@ func (gs *podScheduler) findFeasibleNodes(
 	var feasibleNodesLen int32
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	equivalenceClass := gs.getEquivalenceClass(pod)
 
 	checkNode := func(i int) {
 		// We check the nodes starting from where we left off in the previous scheduling cycle,
@@ -501,7 +504,7 @@ func (gs *podScheduler) findFeasibleNodes(
 			// Keep the status to nil, we will delete this item when finish the scheduling phase.
 		} else {
 			var err error
-			fit, status, _, err = runtime.PodPassesFiltersOnNode(ctx, f, state, pod, nodeInfo)
+			fit, status, err = gs.podPassesFiltersWithEquivalence(ctx, f, state, pod, nodeInfo, equivalenceClass)
 			if err != nil {
 				klog.ErrorS(err, "error occurred in PodPassesFiltersOnNode", "pod", klog.KObj(pod), "node", nodeInfo.GetNodeName())
 				errCh.SendErrorWithCancel(err, cancel)
@@ -684,6 +687,14 @@ func (gs *podScheduler) GetLoadAwareNodeUsage(nodeName string, resourceType podu
 	return gs.snapshot.GetLoadAwareNodeUsage(nodeName, resourceType)
 }
 
+func (gs *podScheduler) GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *framework.LoadAwareWorkloadUsage {
+	return gs.snapshot.GetLoadAwareWorkloadUsage(workloadKey, percentile)
+}
+
+func (gs *podScheduler) GetNodeReservations(nodeName string) []*framework.ResourceReservation {
+	return gs.snapshot.GetNodeReservations(nodeName)
+}
+
 func needCacheNodesForPod(pod *v1.Pod, podOwner string) bool {
 	// TODO: figure out whether pod group affinity && bin-packing-first will affect the checking logic
 	if len(podOwner) == 0 {
@@ -841,6 +852,10 @@ func (gs *podScheduler) DisablePreemption() bool {
 	return gs.disablePreemption
 }
 
+func (gs *podScheduler) PreemptionDryRun() bool {
+	return gs.preemptionDryRun
+}
+
 func (gs *podScheduler) GetPreemptionFrameworkForPod(pod *v1.Pod) framework.SchedulerPreemptionFramework {
 	return runtime.NewPreemptionFramework(gs.preemptionPluginRegistry, gs.getBasePluginsForPod(pod))
 }
@@ -892,6 +907,7 @@ func NewPodScheduler(
 	snapshot *cache.Snapshot,
 	clock clock.Clock,
 	disablePreemption bool,
+	preemptionDryRun bool,
 	candidateSelectPolicy string,
 	betterSelectPolicies []string,
 	percentageOfNodesToScore int32,
@@ -911,6 +927,7 @@ func NewPodScheduler(
 		crdInformerFactory:                crdInformerFactory,
 		snapshot:                          snapshot,
 		disablePreemption:                 disablePreemption,
+		preemptionDryRun:                  preemptionDryRun,
 		percentageOfNodesToScore:          percentageOfNodesToScore,
 		increasedPercentageOfNodesToScore: increasedPercentageOfNodesToScore,
 		basePlugins:                       basePlugins,
@@ -24,6 +24,7 @@ import (
 	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -113,19 +114,19 @@ func (gs *podScheduler) PreemptInSpecificNodeGroup(
 	}
 
 	// check all nodes
-	runPreemption := func(ctx context.Context, f framework.SchedulerFramework, pf framework.SchedulerPreemptionFramework, state *framework.CycleState, pod *v1.Pod, preemptNodeCandidates []framework.NodeInfo) (nominatedNode string, victims *framework.Victims, err error) {
-		nominatedNodeName, victims, err := gs.runPreemption(ctx, f, pf, state, commonPreemptionState, pod, preemptNodeCandidates, nodeToStatus, cachedNominatedNodes)
+	runPreemption := func(ctx context.Context, f framework.SchedulerFramework, pf framework.SchedulerPreemptionFramework, state *framework.CycleState, pod *v1.Pod, preemptNodeCandidates []framework.NodeInfo) (nominatedNode string, victims *framework.Victims, sortedCandidates []string, err error) {
+		nominatedNodeName, victims, sortedCandidates, err := gs.runPreemption(ctx, f, pf, state, commonPreemptionState, pod, preemptNodeCandidates, nodeToStatus, cachedNominatedNodes)
 		if err != nil {
 			err = fmt.Errorf("error occured when preempting for pod %v/%v: %v", pod.Namespace, pod.Name, err)
 			klog.ErrorS(err, "Failed to preempt", "pod", klog.KObj(pod), "status", err)
-			return "", nil, err
+			return "", nil, nil, err
 		}
 
 		if len(nominatedNodeName) == 0 {
 			// NewPreemptionError indicates that scheduler can't find a nominated node by preempting.
-			return "", nil, framework.NewPreemptionError(podutil.GetPodKey(pod), len(preemptNodeCandidates), nil)
+			return "", nil, nil, framework.NewPreemptionError(podutil.GetPodKey(pod), len(preemptNodeCandidates), nil)
 		}
-		return nominatedNodeName, victims, nil
+		return nominatedNodeName, victims, sortedCandidates, nil
 	}
 
 	// Preferred nodes
@@ -148,7 +149,7 @@ func (gs *podScheduler) PreemptInSpecificNodeGroup(
 				}
 
 				nodeSet := []framework.NodeInfo{nodeInfoToUse}
-				nominatedNodeName, victims, err := runPreemption(ctx, f, pf, stateToUse, pod, nodeSet)
+				nominatedNodeName, victims, sortedCandidates, err := runPreemption(ctx, f, pf, stateToUse, pod, nodeSet)
 
 				// TODO: how to define `fit` in preemption?
 				fit := err == nil && len(nominatedNodeName) > 0
@@ -174,8 +175,9 @@ func (gs *podScheduler) PreemptInSpecificNodeGroup(
 					preferTraceContext.WithFields(tracing.WithMessageField(fmt.Sprintf("evaluate %d preferred nodes and select %v in preempting", i+1, nodeName)))
 					preferTraceContext.WithTags(tracing.WithResultTag(tracing.ResultSuccess))
 					return core.PodScheduleResult{
-						NominatedNode: utils.ConstructNominatedNode(nominatedNodeName, victims),
-						Victims:       victims,
+						NominatedNode:    utils.ConstructNominatedNode(nominatedNodeName, victims),
+						Victims:          victims,
+						SortedCandidates: sortedCandidates,
 						// TODO: revisit this. Left the FilteredNodesStatuses to be nil for preferred nodes.
 						FilteredNodesStatuses: nil,
 					}, nil
@@ -191,13 +193,14 @@ func (gs *podScheduler) PreemptInSpecificNodeGroup(
 	preemptInSpecificNodeCircle := func(nodeCircle framework.NodeCircle) (result core.PodScheduleResult, err error) {
 		klog.InfoS("Start preempt in specific node circle", "pod", podutil.GetPodKey(pod), "nodeCircle", nodeCircle.GetKey())
 		nodeSet := nodeCircle.List()
-		nominatedNodeName, victims, err := runPreemption(ctx, f, pf, state, pod, nodeSet)
+		nominatedNodeName, victims, sortedCandidates, err := runPreemption(ctx, f, pf, state, pod, nodeSet)
 		if err != nil || len(nominatedNodeName) == 0 {
 			return core.PodScheduleResult{}, err
 		}
 		return core.PodScheduleResult{
-			NominatedNode: utils.ConstructNominatedNode(nominatedNodeName, victims),
-			Victims:       victims,
+			NominatedNode:    utils.ConstructNominatedNode(nominatedNodeName, victims),
+			Victims:          victims,
+			SortedCandidates: sortedCandidates,
 		}, nil
 	}
 
@@ -290,8 +293,7 @@ func (gs *podScheduler) preemptOnNode(
 func (gs *podScheduler) prepareNodes(ctx context.Context, preemptNodeCandidates []framework.NodeInfo, m framework.NodeToStatusMap) ([]framework.NodeInfo, error) {
 	// 1) Filter nodes that might be useful by preemption.
 	potentialCandidates := make([]framework.NodeInfo, len(preemptNodeCandidates))
-	stop := false
-	passed := false
+	var stop, passed atomic.Bool
 	checkNode := func(i int) {
 		node := preemptNodeCandidates[i]
 		name := node.GetNodeName()
@@ -299,12 +301,12 @@ func (gs *podScheduler) prepareNodes(ctx context.Context, preemptNodeCandidates
 		// to determine whether preemption may help or not on the node.
 		if m[name].Code() != framework.UnschedulableAndUnresolvable {
 			potentialCandidates[i] = node
-			passed = true
+			passed.Store(true)
 		}
 	}
 	util.ParallelizeUntil(&stop, 32, len(preemptNodeCandidates), checkNode)
 
-	if !passed {
+	if !passed.Load() {
 		// no potential nodes, return nil.
 		return nil, errors.New(ReasonUnresolvableByPreemption)
 	}
@@ -317,21 +319,21 @@ func (gs *podScheduler) runPreemption(ctx context.Context,
 	state, commonPreemptionState *framework.CycleState, clonePod *v1.Pod,
 	nodeSet []framework.NodeInfo, nodeToStatus framework.NodeToStatusMap,
 	cachedNominatedNodes *framework.CachedNominatedNodes,
-) (string, *framework.Victims, error) {
+) (string, *framework.Victims, []string, error) {
 	// 0) Prepare preemptor pod and nodes.
 	start := time.Now()
 	pod, canPreemptOthers, err := gs.preparePod(ctx, clonePod)
 	if err != nil {
-		return "", nil, err
+		return "", nil, nil, err
 	} else if !canPreemptOthers {
-		return "", nil, errors.New(ReasonNotEligibleToPreemptOthers)
+		return "", nil, nil, errors.New(ReasonNotEligibleToPreemptOthers)
 	}
 	completePreparePod := time.Now()
 	klog.InfoS("Complete prepare pod", "pod", podutil.GetPodKey(pod), "duration", completePreparePod.Sub(start))
 
 	nodeSet, err = gs.prepareNodes(ctx, nodeSet, nodeToStatus)
 	if err != nil {
-		return "", nil, err
+		return "", nil, nil, err
 	}
 	completePrepareNode := time.Now()
 	klog.InfoS("Complete prepare node", "pod", podutil.GetPodKey(pod), "duration", completePrepareNode.Sub(completePreparePod))
@@ -343,11 +345,11 @@ func (gs *podScheduler) runPreemption(ctx context.Context,
 	candidates, err := gs.FindCandidates(ctx, f, pf, state, commonPreemptionState, pod, nodeSet, cachedNominatedNodes)
 	if err != nil {
 		metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingFindCandidates, helper.SinceInSeconds(findCandidatesStart))
-		return "", nil, err
+		return "", nil, nil, err
 	}
 	if len(candidates) == 0 {
 		metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingFindCandidates, helper.SinceInSeconds(findCandidatesStart))
-		return "", nil, errors.New(ReasonPreemptionCandidatesNotFound)
+		return "", nil, nil, errors.New(ReasonPreemptionCandidatesNotFound)
 	}
 	metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingFindCandidates, helper.SinceInSeconds(findCandidatesStart))
 
@@ -356,15 +358,24 @@ func (gs *podScheduler) runPreemption(ctx context.Context,
 	bestCandidate := gs.SelectCandidate(ctx, f, pf, state, pod, candidates, nil, cachedNominatedNodes, false)
 	if bestCandidate == nil || len(bestCandidate.Name) == 0 {
 		metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingSelectCandidate, helper.SinceInSeconds(selectCandidateStart))
-		return "", nil, errors.New(ReasonPreemptionBestCandidateNotFound)
+		return "", nil, nil, errors.New(ReasonPreemptionBestCandidateNotFound)
 	}
 	metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingSelectCandidate, helper.SinceInSeconds(selectCandidateStart))
 
 	if status := pf.RunNodePostPreemptingPlugins(pod, bestCandidate.Victims.Pods, state, commonPreemptionState); !status.IsSuccess() {
-		return "", nil, status.AsError()
+		return "", nil, nil, status.AsError()
 	}
 
-	return bestCandidate.Name, bestCandidate.Victims, nil
+	var sortedCandidates []string
+	if gs.preemptionDryRun {
+		// SelectCandidate sorts the candidates in place by the candidates sorting plugins.
+		sortedCandidates = make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			sortedCandidates = append(sortedCandidates, c.Name)
+		}
+	}
+
+	return bestCandidate.Name, bestCandidate.Victims, sortedCandidates, nil
 }
 
 func (gs *podScheduler) preparePod(ctx context.Context, pod *v1.Pod) (*v1.Pod, bool, error) {
@@ -430,7 +441,7 @@ func (gs *podScheduler) randomPreemption(
 	partitionPriority := preemptionplugins.GetPodPartitionPriority(pod)
 	podResource, _, _ := framework.CalculateResource(pod)
 
-	var stop bool
+	var stop atomic.Bool
 	var count int
 	checkNode := func(i int) {
 		nodeInfo := nodesList[i]
@@ -467,13 +478,13 @@ func (gs *podScheduler) randomPreemption(
 				count++
 			}
 			if count >= cachedNominatedNodes.GetPodCount() {
-				stop = true
+				stop.Store(true)
 			}
 			lock.Unlock()
 		}
 	}
 
-	stop = false
+	stop.Store(false)
 	util.ParallelizeUntil(&stop, 32, len(nodesList), checkNode)
 	return candidates, nil
 }
@@ -490,7 +501,7 @@ func (gs *podScheduler) bestPreemption(
 	partitionPriority := preemptionplugins.GetPodPartitionPriority(pod)
 	podResource, _, _ := framework.CalculateResource(pod)
 
-	var stop bool
+	var stop atomic.Bool
 	checkNode := func(i int) {
 		nodeInfo := nodesList[i]
 		if nodeInfo == nil {
@@ -522,7 +533,7 @@ func (gs *podScheduler) bestPreemption(
 	}
 
 	// check all node list
-	stop = false
+	stop.Store(false)
 	util.ParallelizeUntil(&stop, 32, len(nodesList), checkNode)
 	return candidates, nil
 }
@@ -557,7 +568,7 @@ func (gs *podScheduler) betterPreemption(
 	}
 
 	start := time.Now()
-	stop := false
+	var stop atomic.Bool
 	priorities := make([][]int64, len(nodesList))
 	getPriorities := func(i int) {
 		nodeInfo := nodesList[i]
@@ -591,14 +602,14 @@ func (gs *podScheduler) betterPreemption(
 
 	if len(prioritiesSlice) == 0 {
 		var lock sync.Mutex
-		stop := false
+		var stop atomic.Bool
 		checkNode := func(i int) {
 			nodeInfo := nodesList[i]
 			if fits, _, _, _ := frameworkruntime.PodPassesFiltersOnNode(ctx, fw, state, pod, nodeInfo); fits {
 				lock.Lock()
 				nominatedNodeIndex = append(nominatedNodeIndex, i)
 				if len(nominatedNodeIndex) >= cachedNominatedNodes.GetPodCount() {
-					stop = true
+					stop.Store(true)
 				}
 				lock.Unlock()
 			}
@@ -815,7 +826,7 @@ func (gs *podScheduler) ascendingOrderPreemption(ctx context.Context,
 	var firstPriorityIndex int = -1
 	for priorityIndex, priority := range prioritiesSlice {
 		start := time.Now()
-		stop := false
+		var stop atomic.Bool
 		checkNode := func(i int) {
 			nodeInfo := nodesListCopy[i]
 			if nodeInfo == nil {
@@ -858,7 +869,7 @@ func (gs *podScheduler) ascendingOrderPreemption(ctx context.Context,
 				nodesListCopy[i] = nil
 				selectedNodeIndex = append(selectedNodeIndex, i)
 				if len(selectedNodeIndex) >= expectedCount {
-					stop = true
+					stop.Store(true)
 				}
 				if firstPriorityIndex < 0 {
 					firstPriorityIndex = priorityIndex
@@ -874,7 +885,7 @@ func (gs *podScheduler) ascendingOrderPreemption(ctx context.Context,
 			}
 		}
 		util.ParallelizeUntil(&stop, 32, len(nodesListCopy), checkNode)
-		if stop {
+		if stop.Load() {
 			klog.InfoS("Better preemption policy check priority for pod", "pod", podutil.GeneratePodKey(pod), "policy", config.BetterPreemptionPolicyAscending, "priority", priority, "duration", time.Since(start))
 			return selectedNodeIndex, firstPriorityIndex
 		}
@@ -913,7 +924,7 @@ func (gs *podScheduler) dichotomyPreemption(ctx context.Context,
 		failedNodes := make([]bool, len(nodesListCopy))
 		mid := (left + right) / 2
 		priority := prioritiesSlice[mid]
-		passed := false
+		var passed atomic.Bool
 
 		checkNode := func(i int) {
 			nodeInfo := nodesListCopy[i]
@@ -954,7 +965,7 @@ func (gs *podScheduler) dichotomyPreemption(ctx context.Context,
 				lock.Lock()
 				selectedNodeIndex = append(selectedNodeIndex, i)
 				if len(selectedNodeIndex) >= expectedCount {
-					passed = true
+					passed.Store(true)
 				}
 				firstMaxVictimPriorityIndex = mid
 				lock.Unlock()
@@ -22,6 +22,7 @@ import (
 	"math"
 	"reflect"
 	"sort"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -3932,7 +3933,7 @@ func TestBetterPreemption_PDB(t *testing.T) {
 				}
 
 				resourceType, _ := podutil.GetPodResourceType(tt.pod)
-				stop := false
+				var stop atomic.Bool
 				priorities := make([][]int64, len(nodeInfoList))
 				getPriorities := func(i int) {
 					nodeInfo := nodeInfoList[i]
@@ -5822,7 +5823,7 @@ func TestBetterPreemption_PDB2(t *testing.T) {
 				}
 
 				resourceType, _ := podutil.GetPodResourceType(tt.pod)
-				stop := false
+				var stop atomic.Bool
 				priorities := make([][]int64, len(nodeInfoList))
 				getPriorities := func(i int) {
 					nodeInfo := nodeInfoList[i]
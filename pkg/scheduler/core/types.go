@@ -50,6 +50,8 @@ type PodScheduler interface {
 		nodeToStatus framework.NodeToStatusMap, cachedNominatedNodes *framework.CachedNominatedNodes) (podScheduleResult PodScheduleResult, err error)
 
 	DisablePreemption() bool
+	// PreemptionDryRun returns true if the preemption only records what it would have done.
+	PreemptionDryRun() bool
 	Close()
 }
 
@@ -98,6 +100,10 @@ type PodScheduleResult struct {
 	NominatedNode *framework.NominatedNode
 
 	Victims *framework.Victims
+	// SortedCandidates is the names of the preemption candidates found by the candidate select policy, in the
+	// order sorted by the candidates sorting plugins. The nominated node is the first one.
+	// It is only filled in the dry-run preemption.
+	SortedCandidates []string
 
 	// ATTENTION: We reserve this field to take into account the possibility of modifying the original data (SchedulingUnitInfo.NodeToStatusMapByTemplate)
 	// in the event of pod failure at any stage.
@@ -191,6 +197,12 @@ type SchedulingUnitInfo struct {
 
 	UnitCycleState *framework.CycleState
 
+	// Starving indicates whether the unit has been pending for too long, if true, the resources it is able to use
+	// will be held back for it when it fails to be scheduled, and the other units can only backfill the rest.
+	Starving bool
+	// Reservations are the resources held back for the unit, key is node name.
+	Reservations map[string]*framework.ResourceReservation
+
 	// ATTENTION: The following fields will be RESET during scheduling.
 	// So we don't need to care about them during initialization.
 	NotScheduledPodKeysByTemplate map[string]sets.String
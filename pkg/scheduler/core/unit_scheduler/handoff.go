@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// handOffPods hands off the placements of the successful pods to the binder directly, and returns the handoff
+// tokens of the pods accepted by the binder, keyed by the pod keys. The other pods must be persisted through the
+// pod annotations.
+func (gs *unitScheduler) handOffPods(ctx context.Context, result *core.UnitResult, unitInfo *core.SchedulingUnitInfo) map[string]string {
+	if gs.handoffClient == nil || len(result.SuccessfulPods) == 0 {
+		return nil
+	}
+
+	request := &handoff.Request{Scheduler: gs.schedulerName}
+	podKeys := make(map[types.UID]string, len(result.SuccessfulPods))
+	for _, podKey := range result.SuccessfulPods {
+		runningUnitInfo := unitInfo.DispatchedPods[podKey]
+		placement, ok := newPlacement(runningUnitInfo.QueuedPodInfo.Pod, runningUnitInfo.ClonedPod)
+		if !ok {
+			continue
+		}
+		// The token identifies this placement, the binder rejects it by the token so that the placement
+		// is never persisted once rejected.
+		placement.Annotations[podutil.HandoffTokenAnnotationKey] = string(uuid.NewUUID())
+		request.Placements = append(request.Placements, *placement)
+		podKeys[placement.UID] = podKey
+	}
+	if len(request.Placements) == 0 {
+		return nil
+	}
+
+	accepted, err := gs.handoffClient.HandOff(ctx, request)
+	if err != nil {
+		klog.InfoS("Failed to hand off pods to the binder, will persist them through the pod annotations",
+			"switchType", gs.switchType, "subCluster", gs.subCluster, "unitKey", unitInfo.UnitKey, "err", err)
+		return nil
+	}
+	handedOff := make(map[string]string, len(accepted))
+	for _, placement := range request.Placements {
+		if accepted[placement.UID] {
+			handedOff[podKeys[placement.UID]] = placement.Annotations[podutil.HandoffTokenAnnotationKey]
+		}
+	}
+	return handedOff
+}
+
+// newPlacement returns the placement carrying the annotations the scheduler would patch on the pod. It returns
+// false if anything other than adding or updating annotations is changed, since only those can be handed off.
+func newPlacement(pod, clonedPod *v1.Pod) (*handoff.Placement, bool) {
+	for key := range pod.Annotations {
+		if _, ok := clonedPod.Annotations[key]; !ok {
+			return nil, false
+		}
+	}
+	withoutAnnotations := *clonedPod
+	withoutAnnotations.Annotations = pod.Annotations
+	if !equality.Semantic.DeepEqual(&withoutAnnotations, pod) {
+		return nil, false
+	}
+
+	annotations := make(map[string]string)
+	for key, value := range clonedPod.Annotations {
+		if oldValue, ok := pod.Annotations[key]; !ok || oldValue != value {
+			annotations[key] = value
+		}
+	}
+	return &handoff.Placement{
+		Namespace:   pod.Namespace,
+		Name:        pod.Name,
+		UID:         pod.UID,
+		Annotations: annotations,
+	}, true
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestNewPlacement(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "p",
+			UID:       "uid",
+			Annotations: map[string]string{
+				podutil.PodStateAnnotationKey:  string(podutil.PodDispatched),
+				podutil.SchedulerAnnotationKey: "scheduler",
+			},
+		},
+	}
+	assumed := func(mutate func(*v1.Pod)) *v1.Pod {
+		clonedPod := pod.DeepCopy()
+		clonedPod.Annotations[podutil.PodStateAnnotationKey] = string(podutil.PodAssumed)
+		clonedPod.Annotations[podutil.AssumedNodeAnnotationKey] = "node"
+		if mutate != nil {
+			mutate(clonedPod)
+		}
+		return clonedPod
+	}
+
+	tests := []struct {
+		name            string
+		clonedPod       *v1.Pod
+		wantOK          bool
+		wantAnnotations map[string]string
+	}{
+		{
+			name:      "only annotations are added or updated",
+			clonedPod: assumed(nil),
+			wantOK:    true,
+			wantAnnotations: map[string]string{
+				podutil.PodStateAnnotationKey:    string(podutil.PodAssumed),
+				podutil.AssumedNodeAnnotationKey: "node",
+			},
+		},
+		{
+			name: "annotation is removed",
+			clonedPod: assumed(func(p *v1.Pod) {
+				delete(p.Annotations, podutil.SchedulerAnnotationKey)
+			}),
+		},
+		{
+			name: "labels are changed",
+			clonedPod: assumed(func(p *v1.Pod) {
+				p.Labels = map[string]string{"k": "v"}
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placement, ok := newPlacement(pod, tt.clonedPod)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if placement.UID != pod.UID || placement.Namespace != pod.Namespace || placement.Name != pod.Name {
+				t.Errorf("unexpected placement %+v", placement)
+			}
+			if !reflect.DeepEqual(placement.Annotations, tt.wantAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tt.wantAnnotations, placement.Annotations)
+			}
+		})
+	}
+}
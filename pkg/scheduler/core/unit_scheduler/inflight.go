@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+	"sync"
+)
+
+type ctxKeyInFlightType string
+
+const ctxKeyInFlight = ctxKeyInFlightType("InFlight")
+
+// InFlightTracker tracks the units being scheduled along with the pods being persisted for them, so that
+// a draining scheduler knows when all of its work has been finished.
+type InFlightTracker struct {
+	mu       sync.RWMutex
+	draining bool
+	wg       sync.WaitGroup
+}
+
+// Enter marks a unit as in-flight, it returns false if the tracker is draining and the unit should not be
+// scheduled. Exit must be called once the unit is finished if true is returned.
+func (t *InFlightTracker) Enter() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.draining {
+		return false
+	}
+	t.wg.Add(1)
+	return true
+}
+
+// Exit marks an in-flight unit as finished.
+func (t *InFlightTracker) Exit() {
+	t.wg.Done()
+}
+
+// StartDraining stops admitting new units.
+func (t *InFlightTracker) StartDraining() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.draining = true
+}
+
+// Wait waits until all the in-flight units and their pods have been finished.
+func (t *InFlightTracker) Wait() {
+	t.wg.Wait()
+}
+
+// WithInFlightTracker returns a context carrying the tracker of in-flight units.
+// Units popped after the tracker started draining or the context is done won't be scheduled any more.
+func WithInFlightTracker(ctx context.Context, tracker *InFlightTracker) context.Context {
+	return context.WithValue(ctx, ctxKeyInFlight, tracker)
+}
+
+// enterInFlight marks a unit as in-flight, it returns false if the unit should not be scheduled.
+// The returned function must be called once the unit is finished.
+func enterInFlight(ctx context.Context) (bool, func()) {
+	tracker, ok := ctx.Value(ctxKeyInFlight).(*InFlightTracker)
+	if !ok {
+		return true, func() {}
+	}
+	if ctx.Err() != nil || !tracker.Enter() {
+		return false, func() {}
+	}
+	return true, tracker.Exit
+}
+
+// goInFlight runs f in a new goroutine tracked as in-flight. It must be called by an in-flight unit, so that
+// the work left by the unit is tracked even if the tracker has started draining.
+func goInFlight(ctx context.Context, f func()) {
+	tracker, ok := ctx.Value(ctxKeyInFlight).(*InFlightTracker)
+	if !ok {
+		go f()
+		return
+	}
+	tracker.wg.Add(1)
+	go func() {
+		defer tracker.wg.Done()
+		f()
+	}()
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestInFlightTracker(t *testing.T) {
+	tracker := &InFlightTracker{}
+	ctx := WithInFlightTracker(context.Background(), tracker)
+
+	ok, exit := enterInFlight(ctx)
+	if !ok {
+		t.Fatalf("expected the unit to be admitted")
+	}
+	persisting := make(chan struct{})
+	goInFlight(ctx, func() { <-persisting })
+	tracker.StartDraining()
+	exit()
+
+	if ok, _ := enterInFlight(ctx); ok {
+		t.Errorf("expected no unit to be admitted after draining started")
+	}
+	waited := make(chan struct{})
+	go func() {
+		tracker.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("expected to wait for the pods being persisted")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(persisting)
+	select {
+	case <-waited:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the in-flight units")
+	}
+}
+
+func TestInFlightWithCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(WithInFlightTracker(context.Background(), &InFlightTracker{}))
+	cancel()
+	if ok, _ := enterInFlight(ctx); ok {
+		t.Errorf("expected no unit to be admitted after the context is done")
+	}
+	if ok, exit := enterInFlight(context.Background()); !ok {
+		t.Errorf("expected the unit to be admitted without tracker")
+	} else {
+		exit()
+	}
+}
@@ -78,6 +78,8 @@ func updateFailedSchedulingPod(cs clientset.Interface, schedulerName string,
 		delete(podCopy.Annotations, podutil.SchedulerAnnotationKey)
 	}
 	delete(podCopy.Annotations, podutil.MicroTopologyKey)
+	delete(podCopy.Annotations, podutil.GPUShareDeviceAnnotationKey)
+	delete(podCopy.Annotations, podutil.LocalStorageRequestsAnnotationKey)
 
 	if util.GetPodDebugMode(failedPod) == util.DebugModeOn {
 		delete(podCopy.Annotations, util.DebugModeAnnotationKey)
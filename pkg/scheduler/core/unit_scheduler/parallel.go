@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+)
+
+// DefaultMaxConflictRetries is the max number of times a unit is retried directly when it loses the race with
+// other workers, after that the unit will be re-enqueued as the normal scheduling failure.
+const DefaultMaxConflictRetries = 3
+
+// committer serializes the commits of the workers in parallel scheduling, so that each scheduling result
+// can be validated against the latest cache before being applied.
+type committer struct {
+	sync.Mutex
+	maxRetries int
+}
+
+// retriable returns whether the conflicted unit can be retried after the given attempts.
+func (c *committer) retriable(attempts int) bool {
+	return c != nil && attempts < c.maxRetries
+}
+
+// parallelUnitScheduler schedules multiple units of the same scheduling workflow concurrently.
+//
+// Each worker pops units from the shared scheduling queue and schedules them against its own snapshot, which is
+// a fork of the shared cache. The scheduling results are applied to cache one by one, and rejected if any node
+// has been changed since the snapshot of the worker was updated. The rejected units are retried by the worker.
+// The changes are detected by the generation of node, which is bumped by any update of the node or the pods on it,
+// so the updates unrelated to the result (e.g. node status, pod deletion) force a retry as well. That's the price
+// of keeping the check cheap under the commit lock, and such retries are bounded by the max conflict retries.
+// ATTENTION: the conflicts are detected by node, the constraints across nodes (e.g. inter-pod affinity) may be
+// broken by the units scheduled concurrently.
+type parallelUnitScheduler struct {
+	workers []*unitScheduler
+}
+
+var _ core.UnitScheduler = &parallelUnitScheduler{}
+
+// NewParallelUnitScheduler returns a UnitScheduler running the workers concurrently. The workers must be created by
+// NewUnitScheduler, sharing the same cache and scheduling queue but owning different snapshots and pod schedulers.
+func NewParallelUnitScheduler(maxRetries int, workers ...core.UnitScheduler) core.UnitScheduler {
+	c := &committer{maxRetries: maxRetries}
+	ps := &parallelUnitScheduler{workers: make([]*unitScheduler, 0, len(workers))}
+	for _, worker := range workers {
+		gs := worker.(*unitScheduler)
+		gs.committer = c
+		ps.workers = append(ps.workers, gs)
+	}
+	return ps
+}
+
+// Schedule runs the scheduling loops of all workers, and blocks until the context is done.
+func (ps *parallelUnitScheduler) Schedule(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, worker := range ps.workers {
+		wg.Add(1)
+		go func(gs *unitScheduler) {
+			defer wg.Done()
+			wait.UntilWithContext(ctx, gs.Schedule, 0)
+		}(worker)
+	}
+	wg.Wait()
+}
+
+func (ps *parallelUnitScheduler) CanBeRecycle() bool {
+	for _, worker := range ps.workers {
+		if !worker.CanBeRecycle() {
+			return false
+		}
+	}
+	return true
+}
+
+func (ps *parallelUnitScheduler) Close() {
+	for _, worker := range ps.workers {
+		worker.Close()
+	}
+}
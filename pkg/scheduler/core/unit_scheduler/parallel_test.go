@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	schedulingqueue "github.com/kubewharf/godel-scheduler/pkg/scheduler/queue"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/reconciler"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
+)
+
+func makeAssumedPod(name, nodeName string) *v1.Pod {
+	return testing_helper.MakePod().Namespace("default").Name(name).UID(name).
+		Annotation(podutil.PodStateAnnotationKey, string(podutil.PodAssumed)).
+		Annotation(podutil.AssumedNodeAnnotationKey, nodeName).Obj()
+}
+
+func TestCommitWithConflicts(t *testing.T) {
+	sCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		Obj())
+	snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		Obj())
+	for _, nodeName := range []string{"n1", "n2"} {
+		if err := sCache.AddNode(testing_helper.MakeNode().Name(nodeName).Obj()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sCache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	gs := &unitScheduler{Cache: sCache, Snapshot: snapshot, committer: &committer{maxRetries: 1}}
+	newUnitInfo := func(podName, nodeName string) (*core.SchedulingUnitInfo, *core.UnitResult) {
+		pod := makeAssumedPod(podName, nodeName)
+		unitInfo := &core.SchedulingUnitInfo{
+			UnitKey: podName,
+			DispatchedPods: map[string]*core.RunningUnitInfo{
+				podName: {ClonedPod: pod, NodeToPlace: nodeName, Trace: tracing.NewSchedulingTrace(pod)},
+			},
+		}
+		result := core.NewUnitResult(false, 1)
+		result.SuccessfulPods = []string{podName}
+		return unitInfo, result
+	}
+
+	// Another worker has placed a pod on n1 after the snapshot was updated.
+	if err := sCache.AssumePod(framework.MakeCachePodInfoWrapper().Pod(makeAssumedPod("p0", "n1")).Obj()); err != nil {
+		t.Fatal(err)
+	}
+
+	unitInfo, result := newUnitInfo("p1", "n1")
+	if got := gs.conflictedNodes(unitInfo, result); !reflect.DeepEqual(got, []string{"n1"}) {
+		t.Errorf("expected conflicted nodes [n1], got %v", got)
+	}
+	if applied, conflicted := gs.commit(context.Background(), unitInfo, result); applied || !conflicted {
+		t.Errorf("expected the result to be rejected, got applied=%v conflicted=%v", applied, conflicted)
+	}
+
+	unitInfo, result = newUnitInfo("p2", "n2")
+	if applied, conflicted := gs.commit(context.Background(), unitInfo, result); !applied || conflicted {
+		t.Errorf("expected the result to be applied, got applied=%v conflicted=%v", applied, conflicted)
+	}
+	if assumed, _ := sCache.IsAssumedPod(unitInfo.DispatchedPods["p2"].ClonedPod); !assumed {
+		t.Errorf("expected pod p2 to be assumed in cache")
+	}
+
+	// The snapshot is consistent with cache after being updated.
+	if err := sCache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	unitInfo, result = newUnitInfo("p3", "n1")
+	if got := gs.conflictedNodes(unitInfo, result); len(got) != 0 {
+		t.Errorf("expected no conflicted nodes, got %v", got)
+	}
+}
+
+func TestCommitterRetriable(t *testing.T) {
+	var c *committer
+	if c.retriable(0) {
+		t.Errorf("expected no retry without committer")
+	}
+	c = &committer{maxRetries: 2}
+	for attempts, expected := range []bool{true, true, false} {
+		if got := c.retriable(attempts); got != expected {
+			t.Errorf("attempts %d: expected retriable %v, got %v", attempts, expected, got)
+		}
+	}
+}
+
+// barrierCache holds the first snapshot update of each worker until all workers have updated their snapshots,
+// so that all of them schedule against the same state of cache.
+type barrierCache struct {
+	godelcache.SchedulerCache
+	once    sync.Once
+	barrier *sync.WaitGroup
+	updates *int32
+}
+
+func (c *barrierCache) UpdateSnapshot(snapshot *godelcache.Snapshot) error {
+	err := c.SchedulerCache.UpdateSnapshot(snapshot)
+	atomic.AddInt32(c.updates, 1)
+	c.once.Do(func() {
+		c.barrier.Done()
+		c.barrier.Wait()
+	})
+	return err
+}
+
+func TestParallelWorkersRetryConflicts(t *testing.T) {
+	workerCount := 4
+	nodeName := "n1"
+
+	client := clientsetfake.NewSimpleClientset()
+	crdClient := godelclientfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	stop := make(chan struct{})
+	defer close(stop)
+	informerFactory.Core().V1().Pods().Informer()
+	informerFactory.Start(stop)
+	informerFactory.WaitForCacheSync(stop)
+	broadcaster := cmdutil.NewEventBroadcasterAdapter(client)
+
+	sCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore("PreemptionStore").
+		Obj())
+	if err := sCache.AddNode(testing_helper.MakeNode().Name(nodeName).
+		Capacity(map[v1.ResourceName]string{"cpu": "10", "memory": "10Gi", "pods": "10"}).Obj()); err != nil {
+		t.Fatal(err)
+	}
+	queue := schedulingqueue.NewSchedulingQueue(sCache, nil, nil, nil, false)
+
+	// All workers place their pods on the same node, so that each commit changes the node seen by the others.
+	var (
+		barrier sync.WaitGroup
+		updates int32
+		workers []core.UnitScheduler
+		pods    []*v1.Pod
+	)
+	barrier.Add(workerCount)
+	for i := 0; i < workerCount; i++ {
+		podGroup := testing_helper.MakePodGroup().Namespace("default").Name(fmt.Sprintf("pg-%d", i)).MinMember(1).Obj()
+		pod := testing_helper.MakePod().Namespace("default").Name(fmt.Sprintf("p-%d", i)).UID(fmt.Sprintf("p-%d", i)).
+			SchedulerName(testSchedulerName).Req(map[v1.ResourceName]string{"cpu": "1"}).
+			Annotation(podutil.PodLauncherAnnotationKey, string(podutil.Kubelet)).
+			Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+			Annotation(podutil.PodGroupNameAnnotationKey, podGroup.Name).Obj()
+		if _, err := client.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		pods = append(pods, pod)
+		unit := framework.NewPodGroupUnit(podGroup, 100)
+		unit.AddPod(&framework.QueuedPodInfo{Pod: pod, Timestamp: time.Now(), InitialAttemptTimestamp: time.Now()})
+
+		workers = append(workers, &unitScheduler{
+			schedulerName: testSchedulerName,
+			switchType:    framework.SwitchType(1),
+
+			client:    client,
+			crdClient: crdClient,
+
+			podLister: informerFactory.Core().V1().Pods().Lister(),
+			pgLister:  testing_helper.NewFakePodGroupLister(nil),
+
+			Cache: &barrierCache{SchedulerCache: sCache, barrier: &barrier, updates: &updates},
+			Snapshot: godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+				SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+				EnableStore("PreemptionStore").
+				Obj()),
+			Queue:      queue,
+			Reconciler: reconciler.NewFailedTaskReconciler(nil, nil, sCache, ""),
+			Scheduler:  mockScheduler{result: core.PodScheduleResult{SuggestedHost: nodeName, NumberOfEvaluatedNodes: 1, NumberOfFeasibleNodes: 1}},
+
+			nextUnit: func() *framework.QueuedUnitInfo {
+				return &framework.QueuedUnitInfo{
+					UnitKey:            unit.GetKey(),
+					ScheduleUnit:       unit,
+					QueuePriorityScore: float64(unit.GetPriority()),
+				}
+			},
+
+			Recorder: broadcaster.NewRecorder(testSchedulerName),
+			Clock:    clock.RealClock{},
+		})
+	}
+	NewParallelUnitScheduler(workerCount, workers...)
+
+	tracker := &InFlightTracker{}
+	ctx := WithInFlightTracker(context.Background(), tracker)
+	var wg sync.WaitGroup
+	for _, worker := range workers {
+		wg.Add(1)
+		go func(worker core.UnitScheduler) {
+			defer wg.Done()
+			worker.Schedule(ctx)
+		}(worker)
+	}
+	wg.Wait()
+	tracker.Wait()
+
+	// Only the first commit matches the snapshots taken behind the barrier, every other worker has to
+	// detect the conflict and retry against a refreshed snapshot at least once.
+	if got := int(atomic.LoadInt32(&updates)); got < 2*workerCount-1 {
+		t.Errorf("expected at least %d snapshot updates, got %d", 2*workerCount-1, got)
+	}
+	for _, pod := range pods {
+		if assumed, err := sCache.IsAssumedPod(pod); err != nil || !assumed {
+			t.Errorf("expected pod %v to be assumed, got %v, err: %v", pod.Name, assumed, err)
+		}
+	}
+	snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		EnableStore("PreemptionStore").
+		Obj())
+	if err := sCache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(snapshot.GetNodeInfo(nodeName).GetPods()); got != workerCount {
+		t.Errorf("expected %d pods on node %v, got %d", workerCount, nodeName, got)
+	}
+}
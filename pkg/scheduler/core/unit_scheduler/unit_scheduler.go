@@ -31,6 +31,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientset "k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/events"
@@ -38,6 +39,7 @@ import (
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
 	schedulerframework "github.com/kubewharf/godel-scheduler/pkg/scheduler/framework"
@@ -47,6 +49,7 @@ import (
 	schedulingqueue "github.com/kubewharf/godel-scheduler/pkg/scheduler/queue"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/reconciler"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/interpretabity"
 	"github.com/kubewharf/godel-scheduler/pkg/util/parallelize"
@@ -77,8 +80,15 @@ type unitScheduler struct {
 	Queue      schedulingqueue.SchedulingQueue
 	Scheduler  core.PodScheduler
 	Reconciler *reconciler.FailedTaskReconciler
+	// handoffClient hands off the scheduled pods to the binder directly, it is nil if disabled.
+	handoffClient  *handoff.Client
+	handoffTimeout time.Duration
 
 	nextUnit func() *framework.QueuedUnitInfo
+	// starvationAware is set if the unit queue sort plugin is able to prevent units from starving.
+	starvationAware framework.StarvationAwareUnitQueueSortPlugin
+	// committer is shared by the workers of parallel scheduling, it is nil if units are scheduled one by one.
+	committer *committer
 
 	PluginRegistry framework.PluginMap
 	PluginOrder    framework.PluginOrder
@@ -113,7 +123,11 @@ func NewUnitScheduler(
 	snapshot *cache.Snapshot,
 	queue schedulingqueue.SchedulingQueue,
 	reconciler *reconciler.FailedTaskReconciler,
+	handoffClient *handoff.Client,
+	handoffTimeout time.Duration,
 	podScheduler core.PodScheduler,
+	unitQueueSortPlugin framework.UnitQueueSortPlugin,
+	pluginArgs map[string]*config.PluginConfig,
 	clock clock.Clock,
 	recorder events.EventRecorder,
 ) core.UnitScheduler {
@@ -135,6 +149,9 @@ func NewUnitScheduler(
 		Scheduler:  podScheduler,
 		Reconciler: reconciler,
 
+		handoffClient:  handoffClient,
+		handoffTimeout: handoffTimeout,
+
 		nextUnit: schedulingqueue.MakeNextUnitFunc(queue),
 
 		Recorder:                recorder,
@@ -143,7 +160,10 @@ func NewUnitScheduler(
 		LatestScheduleTimestamp: clock.Now(),
 	}
 
-	gs.PluginRegistry = schedulerframework.NewUnitPluginsRegistry(schedulerframework.NewUnitInTreeRegistry(), nil, gs)
+	if starvationAware, ok := unitQueueSortPlugin.(framework.StarvationAwareUnitQueueSortPlugin); ok {
+		gs.starvationAware = starvationAware
+	}
+	gs.PluginRegistry = schedulerframework.NewUnitPluginsRegistry(schedulerframework.NewUnitInTreeRegistry(), pluginArgs, gs)
 	gs.PluginOrder = schedulerframework.NewOrderedUnitPluginRegistry()
 
 	return gs
@@ -256,6 +276,10 @@ func (gs *unitScheduler) GetNodeInfo(nodeName string) framework.NodeInfo {
 	return gs.Snapshot.GetNodeInfo(nodeName)
 }
 
+func (gs *unitScheduler) GetReservedNodes(pod *v1.Pod) []string {
+	return gs.Snapshot.GetReservedNodes(pod)
+}
+
 // --------------------------------------------------- UnitScheduler ---------------------------------------------------
 
 func (gs *unitScheduler) CanBeRecycle() bool {
@@ -269,13 +293,31 @@ func (gs *unitScheduler) Close() {
 
 func (gs *unitScheduler) Schedule(ctx context.Context) {
 	gs.LatestScheduleTimestamp = gs.Clock.Now()
-	snapshot, switchType, subCluster := gs.Snapshot, gs.switchType, gs.subCluster
 	queuedUnitInfo := gs.nextUnit()
 	if inValidUnit(queuedUnitInfo) {
 		klog.InfoS("Empty unit or invalid queued pod info, ignore this unit and don't re-enqueue", "unit", queuedUnitInfo)
 		gs.recordUnitSchedulingResults(queuedUnitInfo, false, "InvalidUnit", core.ReturnAction, "Empty unit or invalid queued pod info, ignore this unit and don't re-enqueue")
 		return
 	}
+	// The unit popped after the scheduler stopped will be returned to the dispatcher once the scheduler leaves.
+	ok, exit := enterInFlight(ctx)
+	defer exit()
+	if !ok {
+		klog.V(4).InfoS("Skipped scheduling unit because the scheduler was stopping", "switchType", gs.switchType, "subCluster", gs.subCluster, "unitKey", queuedUnitInfo.UnitKey)
+		return
+	}
+
+	// The unit losing the race with the other workers will be retried against the refreshed snapshot directly,
+	// instead of going through the backoff of scheduling queue.
+	for attempts := 0; gs.scheduleUnit(ctx, queuedUnitInfo, gs.committer.retriable(attempts)); attempts++ {
+		klog.V(4).InfoS("Retrying to schedule unit due to the conflicts with other workers", "switchType", gs.switchType, "subCluster", gs.subCluster, "unitKey", queuedUnitInfo.UnitKey, "attempts", attempts+1)
+	}
+}
+
+// scheduleUnit runs the scheduling workflow of the unit. If retriable is true and the unit failed to be scheduled
+// because of the conflicts with other workers, the failure won't be handled and true will be returned.
+func (gs *unitScheduler) scheduleUnit(ctx context.Context, queuedUnitInfo *framework.QueuedUnitInfo, retriable bool) bool {
+	snapshot, switchType, subCluster := gs.Snapshot, gs.switchType, gs.subCluster
 	klog.V(4).InfoS("Attempting to schedule unit", "switchType", switchType, "subCluster", subCluster, "unitKey", queuedUnitInfo.UnitKey)
 
 	unitInfo, err := gs.constructSchedulingUnitInfo(ctx, queuedUnitInfo)
@@ -283,14 +325,14 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		klog.InfoS("Failed to construct scheduling unit info", "switchType", switchType, "subCluster", subCluster, "unitKey", queuedUnitInfo.UnitKey, "err", err)
 		gs.recordUnitSchedulingResults(queuedUnitInfo, false, "FailToConstructUnitInfo", core.ReturnAction, helper.TruncateMessage(err.Error()))
 		gs.handleSchedulingUnitFailure(ctx, core.NewUnitResult(false, 0), unitInfo, err, "FailToConstructUnitInfo")
-		return
+		return false
 	}
 
 	if err = gs.Cache.UpdateSnapshot(snapshot); err != nil {
 		klog.InfoS("Failed to update snapshot", "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "err", err)
 		gs.recordUnitSchedulingResults(queuedUnitInfo, false, "FailToUpdateSnapshot", core.ReturnAction, helper.TruncateMessage(err.Error()))
 		gs.handleSchedulingUnitFailure(ctx, core.NewUnitResult(false, 0), unitInfo, err, "FailToUpdateSnapshot")
-		return
+		return false
 	}
 
 	unitFramework := unitruntime.NewUnitFramework(gs, gs, gs.PluginRegistry, gs.PluginOrder, unitInfo.QueuedUnitInfo)
@@ -300,7 +342,7 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		klog.InfoS("Failed to run locating plugins", "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "status", status)
 		gs.recordUnitSchedulingResults(queuedUnitInfo, false, "FailToLocating", core.ReturnAction, helper.TruncateMessage(status.AsError().Error()))
 		gs.handleSchedulingUnitFailure(ctx, core.NewUnitResult(false, 0), unitInfo, err, "FailToLocating")
-		return
+		return false
 	}
 
 	nodeGroups, status := unitFramework.RunGroupingPlugin(ctx, unitInfo.QueuedUnitInfo, unitInfo.UnitCycleState, nodeGroup)
@@ -308,15 +350,22 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		klog.InfoS("Failed to run grouping plugin", "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "status", status)
 		gs.recordUnitSchedulingResults(queuedUnitInfo, false, "FailToGrouping", core.ReturnAction, helper.TruncateMessage(status.AsError().Error()))
 		gs.handleSchedulingUnitFailure(ctx, core.NewUnitResult(false, 0), unitInfo, err, "FailToGrouping")
-		return
+		return false
 	}
 
 	var (
+		// the placements of the starving unit in the node group having the most successful pods.
+		placements       map[string]v1.ResourceList
+		placedPodsNumber int
+
 		// basic message for unit.
 		unitMessage = fmt.Sprintf("uint key=%v, ever scheduled=%v, allMember=%d, minMember=%d", unitInfo.UnitKey, unitInfo.EverScheduled, unitInfo.AllMember, unitInfo.MinMember)
 
 		// record final scheduling result,
 		finalUnitResult = core.NewUnitResult(false, unitInfo.AllMember)
+
+		// whether the scheduling result was rejected because of the conflicts with other workers.
+		conflicted bool
 	)
 
 	// TODO: we will cache some feasible nodes based on pod owners, make sure this (per node group scheduling) will not affect that
@@ -330,7 +379,12 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 
 		unitResult := gs.scheduleUnitInNodeGroup(ctx, unitInfo, unitFramework, nodeGroup)
 		scheduleSucceed := (unitInfo.EverScheduled && len(unitResult.SuccessfulPods) > 0) || len(unitResult.SuccessfulPods) >= unitInfo.MinMember
-		if scheduleSucceed && gs.applyToCache(ctx, unitInfo, unitResult) {
+		applied, conflictedInNodeGroup := false, false
+		if scheduleSucceed {
+			applied, conflictedInNodeGroup = gs.commit(ctx, unitInfo, unitResult)
+			conflicted = conflicted || conflictedInNodeGroup
+		}
+		if applied {
 			msg := "Schedule unit succeeded both for snapshot and cache"
 			klog.V(4).InfoS(msg, "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "nodeGroup", nodeGroupName)
 
@@ -341,6 +395,9 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 			unitResult.Successfully = true
 			metrics.UnitScheduleResultObserve(unitInfo.QueuedUnitInfo.GetUnitProperty(), metrics.UnitScheduleSucceed, float64(unitInfo.MinMember))
 		} else {
+			if unitInfo.Starving && len(unitResult.SuccessfulPods) > placedPodsNumber {
+				placements, placedPodsNumber = collectPlacements(unitInfo, unitResult), len(unitResult.SuccessfulPods)
+			}
 			// reset running unit info and un-reserve successful pods
 			gs.resetRunningUnitInfo(ctx, unitInfo, unitResult, nodeGroupName)
 
@@ -371,7 +428,9 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 			}
 
 			failedReason := metrics.UnitScheduleFailed
-			if scheduleSucceed {
+			if conflictedInNodeGroup {
+				failedReason = metrics.UnitScheduleConflicted
+			} else if scheduleSucceed {
 				failedReason = metrics.UnitApplyToCacheFailed
 			}
 			metrics.UnitScheduleResultObserve(unitInfo.QueuedUnitInfo.GetUnitProperty(), failedReason, float64(unitInfo.MinMember))
@@ -392,6 +451,10 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 
 	// if scheduling failed, stop the workflow and return
 	if !finalUnitResult.Successfully {
+		if conflicted && retriable {
+			return true
+		}
+
 		gs.recordUnitSchedulingResults(queuedUnitInfo, false, FailToScheduleUnit, core.ReturnAction, helper.TruncateMessage(errMessage))
 		klog.V(4).InfoS(errMessage)
 
@@ -404,11 +467,19 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 				"message", finalUnitResult.Details.FailureMessage())
 		}
 
+		if unitInfo.Starving {
+			gs.holdBackResources(unitInfo, placements)
+		}
+
 		// re-enqueue pods based on the `schedulingSuccessfully` value of scheduling result
 		// TODO: add more specific error messages -> attach scheduling errors to scheduling result
 		gs.handleSchedulingUnitFailure(ctx, finalUnitResult, unitInfo, errors.New(errMessage), "SchedulingFailed")
 
-		return
+		return false
+	}
+
+	if len(unitInfo.Reservations) > 0 {
+		gs.Cache.RemoveUnitReservations(reservationOwner(unitInfo.QueuedUnitInfo))
 	}
 
 	message := fmt.Sprintf("Schedule unit successfully. uint message: %v; successful pods:%d, failed pods:%d",
@@ -431,7 +502,8 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 	// TODO: reserve all successful pods when we implement mark/unmark in cache
 
 	// scheduling successfully, update the successful scheduled pods
-	go gs.PersistSuccessfulPods(ctx, finalUnitResult, unitInfo)
+	goInFlight(ctx, func() { gs.PersistSuccessfulPods(ctx, finalUnitResult, unitInfo) })
+	return false
 }
 
 func (gs *unitScheduler) constructSchedulingUnitInfo(ctx context.Context, queuedUnitInfo *framework.QueuedUnitInfo) (*core.SchedulingUnitInfo, error) {
@@ -455,6 +527,11 @@ func (gs *unitScheduler) constructSchedulingUnitInfo(ctx context.Context, queued
 		return unitInfo, fmt.Errorf("min member is greater than all member which is unexpected")
 	}
 
+	if gs.starvationAware != nil && !unitInfo.EverScheduled && gs.starvationAware.Starving(queuedUnitInfo) {
+		unitInfo.Starving = true
+		unitInfo.Reservations = gs.Cache.GetUnitReservations(reservationOwner(queuedUnitInfo))
+	}
+
 	// only when the node partition is Physical and the preemption feature is disabled,
 	// we will reset the selected scheduler annotation and let dispatcher re-dispatch these pods when scheduling failed
 	// TODO: revisit this
@@ -505,7 +582,7 @@ func (gs *unitScheduler) scheduleUnitInNodeGroup(ctx context.Context, unitInfo *
 	unitInfo.SetUnitTraceContextFields(tracing.SchedulerScheduleUnitSpan, tracing.WithErrorFields(tracing.TruncateErrors(scheduleResult.Details.GetErrors()))...)
 	unitInfo.FinishUnitTraceContext(tracing.SchedulerScheduleUnitSpan)
 
-	if gs.disablePreemption {
+	if gs.disablePreemption && !gs.PodScheduler().PreemptionDryRun() {
 		return core.TransferToUnitResult(unitInfo, scheduleResult.Details, scheduleResult.SuccessfulPods, scheduleResult.FailedPods)
 	}
 
@@ -547,6 +624,37 @@ func (gs *unitScheduler) unReservePods(ctx context.Context, unitInfo *core.Sched
 	}
 }
 
+// holdBackResources holds back the resources the starving unit is able to use in this attempt,
+// so that they won't be taken away by the other units before the unit gets enough resources.
+func (gs *unitScheduler) holdBackResources(unitInfo *core.SchedulingUnitInfo, placements map[string]v1.ResourceList) {
+	if len(placements) == 0 {
+		// Keep the resources held back before if nothing is available in this attempt.
+		return
+	}
+	owner := reservationOwner(unitInfo.QueuedUnitInfo)
+	// The creation time is inherited from the previous reservations, so that the resources won't be held back forever.
+	creationTime := metav1.NewTime(gs.Clock.Now())
+	for _, r := range unitInfo.Reservations {
+		creationTime = r.CreationTime
+		break
+	}
+	ttlSeconds := int64(gs.starvationAware.ReservationTTL().Seconds())
+
+	reservations := make(map[string]*framework.ResourceReservation, len(placements))
+	for nodeName, resources := range placements {
+		reservations[nodeName] = &framework.ResourceReservation{
+			Name:         owner,
+			Owner:        owner,
+			Resources:    resources,
+			CreationTime: creationTime,
+			TTLSeconds:   ttlSeconds,
+		}
+	}
+	gs.Cache.SetUnitReservations(owner, reservations)
+	unitInfo.Reservations = reservations
+	klog.V(4).InfoS("Held back resources for starving unit", "unitKey", unitInfo.UnitKey, "nodes", len(reservations))
+}
+
 func (gs *unitScheduler) handleSchedulingUnitFailure(ctx context.Context, result *core.UnitResult, unitInfo *core.SchedulingUnitInfo,
 	err error, reason string,
 ) {
@@ -663,6 +771,44 @@ func (gs *unitScheduler) updateFailedScheduleUnit(scheduleUnit framework.Schedul
 	return interpretabity.UpdatePreSchedulingCondition(gs.crdClient, pg, cond)
 }
 
+// commit applies the scheduling result to cache. In parallel scheduling, the result will be rejected if any node
+// it touches has been changed since the snapshot was updated, and conflicted will be returned as true.
+func (gs *unitScheduler) commit(ctx context.Context, unitInfo *core.SchedulingUnitInfo, result *core.UnitResult) (applied bool, conflicted bool) {
+	if gs.committer == nil {
+		return gs.applyToCache(ctx, unitInfo, result), false
+	}
+	gs.committer.Lock()
+	defer gs.committer.Unlock()
+
+	if nodes := gs.conflictedNodes(unitInfo, result); len(nodes) > 0 {
+		klog.V(4).InfoS("Rejected the scheduling result of unit because of conflicts", "switchType", gs.switchType, "subCluster", gs.subCluster, "unitKey", unitInfo.UnitKey, "nodes", nodes)
+		result.Details.AddPodsError(fmt.Errorf("nodes %v were changed by others during scheduling", nodes), result.SuccessfulPods...)
+		return false, true
+	}
+	return gs.applyToCache(ctx, unitInfo, result), false
+}
+
+// conflictedNodes returns the nodes whose generation in snapshot doesn't match the one in cache,
+// which means the nodes have been changed by other workers or events since the snapshot was updated.
+// Any change bumps the generation, even the ones that can't invalidate the result, e.g. releasing resources.
+func (gs *unitScheduler) conflictedNodes(unitInfo *core.SchedulingUnitInfo, result *core.UnitResult) []string {
+	var nodes []string
+	checked := sets.NewString()
+	for _, podKey := range result.SuccessfulPods {
+		runningUnitInfo := unitInfo.DispatchedPods[podKey]
+		if runningUnitInfo == nil || len(runningUnitInfo.NodeToPlace) == 0 || checked.Has(runningUnitInfo.NodeToPlace) {
+			continue
+		}
+		nodeName := runningUnitInfo.NodeToPlace
+		checked.Insert(nodeName)
+		nodeInfo := gs.Snapshot.GetNodeInfo(nodeName)
+		if nodeInfo == nil || nodeInfo.GetGeneration() != gs.Cache.GetNodeGeneration(nodeName) {
+			nodes = append(nodes, nodeName)
+		}
+	}
+	return nodes
+}
+
 func (gs *unitScheduler) applyToCache(ctx context.Context, unitInfo *core.SchedulingUnitInfo, result *core.UnitResult) bool {
 	cache, switchType, subCluster := gs.Cache, gs.switchType, gs.subCluster
 	for i, key := range result.SuccessfulPods {
@@ -742,6 +888,16 @@ func (gs *unitScheduler) PersistSuccessfulPods(ctx context.Context,
 	cache, switchType, subCluster := gs.Cache, gs.switchType, gs.subCluster
 	unitProperty := unitInfo.QueuedUnitInfo.GetUnitProperty()
 
+	// exclude pods that are not scheduled successfully within one scheduling attempt
+	for _, podKey := range result.SuccessfulPods {
+		runningUnitInfo := unitInfo.DispatchedPods[podKey]
+		if unitInfo.QueuedUnitInfo.Attempts > 1 ||
+			!runningUnitInfo.QueuedPodInfo.InitialPreemptAttemptTimestamp.IsZero() {
+			runningUnitInfo.ClonedPod.Annotations[podutil.E2EExcludedPodAnnotationKey] = "true"
+		}
+	}
+	handedOff := gs.handOffPods(ctx, result, unitInfo)
+
 	updatePod := func(i int) {
 		podKey := result.SuccessfulPods[i]
 		runningUnitInfo := unitInfo.DispatchedPods[podKey]
@@ -754,10 +910,22 @@ func (gs *unitScheduler) PersistSuccessfulPods(ctx context.Context,
 		updatingTraceContext := podTrace.NewTraceContext(tracing.RootSpan, tracing.SchedulerUpdatingPodSpan)
 		defer tracing.AsyncFinishTraceContext(updatingTraceContext, time.Now())
 
-		// exclude pods that are not scheduled successfully within one scheduling attempt
-		if unitInfo.QueuedUnitInfo.Attempts > 1 ||
-			!runningUnitInfo.QueuedPodInfo.InitialPreemptAttemptTimestamp.IsZero() {
-			runningUnitInfo.ClonedPod.Annotations[podutil.E2EExcludedPodAnnotationKey] = "true"
+		if handoffToken, ok := handedOff[podKey]; ok {
+			updatingTraceContext.WithTags(tracing.WithResultTag(tracing.ResultSuccess))
+			if err := cache.FinishReserving(runningUnitInfo.ClonedPod); err != nil {
+				klog.InfoS("Failed to finish reserving", "switchType", switchType, "subCluster", subCluster, "podKey", podKey, "unitKey", unitInfo.UnitKey, "err", err)
+			}
+
+			metrics.ObservePodSchedulingLatency(podProperty, getAttemptsLabel(runningUnitInfo.QueuedPodInfo), helper.SinceInSeconds(runningUnitInfo.QueuedPodInfo.InitialAttemptTimestamp))
+
+			klog.V(2).InfoS("Handed off this pod to the binder successfully",
+				"switchType", switchType, "subCluster", subCluster,
+				"pod", klog.KObj(runningUnitInfo.ClonedPod),
+				"unitKey", unitInfo.UnitKey)
+			// The binder is still authoritative, the placement is persisted through the pod annotations
+			// if the pod is neither bound nor rejected in time, e.g. the binder restarted.
+			gs.Reconciler.AddHandoffTask(reconciler.NewHandoffTask(framework.MakeCachePodInfoWrapper().Pod(runningUnitInfo.ClonedPod).Obj(), handoffToken), gs.handoffTimeout)
+			return
 		}
 
 		err := util.PatchPod(gs.client, runningUnitInfo.QueuedPodInfo.Pod, runningUnitInfo.ClonedPod)
@@ -841,3 +1009,34 @@ func (gs *unitScheduler) skipPodSchedule(pod *v1.Pod) bool {
 	return isCached || podutil.AssumedPod(pod) || podutil.BoundPod(pod)
 
 }
+
+// collectPlacements returns the resources requested by the successful pods without victims, key is node name.
+func collectPlacements(unitInfo *core.SchedulingUnitInfo, result *core.UnitResult) map[string]v1.ResourceList {
+	requests := make(map[string]*framework.Resource)
+	for _, podKey := range result.SuccessfulPods {
+		runningUnitInfo := unitInfo.DispatchedPods[podKey]
+		if runningUnitInfo == nil || len(runningUnitInfo.NodeToPlace) == 0 {
+			continue
+		}
+		// The resources are not released yet if there are victims.
+		if runningUnitInfo.Victims != nil && len(runningUnitInfo.Victims.Pods) > 0 {
+			continue
+		}
+		res, _, _ := framework.CalculateResource(runningUnitInfo.QueuedPodInfo.Pod)
+		if request, ok := requests[runningUnitInfo.NodeToPlace]; ok {
+			request.AddResource(&res)
+		} else {
+			requests[runningUnitInfo.NodeToPlace] = &res
+		}
+	}
+	placements := make(map[string]v1.ResourceList, len(requests))
+	for nodeName, request := range requests {
+		placements[nodeName] = request.ResourceList()
+	}
+	return placements
+}
+
+// reservationOwner returns the owner of the resources held back for the unit, in the format of `namespace/name`.
+func reservationOwner(unit framework.ScheduleUnit) string {
+	return unit.GetNamespace() + "/" + unit.GetName()
+}
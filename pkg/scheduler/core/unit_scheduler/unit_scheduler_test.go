@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,14 +36,19 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/events"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	schedulingconfig "github.com/kubewharf/godel-scheduler/pkg/framework/api/config"
 	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/unitqueuesort"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
@@ -164,6 +170,10 @@ func (ms mockScheduler) DisablePreemption() bool {
 	return false
 }
 
+func (ms mockScheduler) PreemptionDryRun() bool {
+	return false
+}
+
 func (ms mockScheduler) GetPreemptionFrameworkForPod(_ *v1.Pod) framework.SchedulerPreemptionFramework {
 	registry := framework.PluginMap{}
 	return fwkruntime.NewPreemptionFramework(registry, ms.basePlugins)
@@ -1066,6 +1076,7 @@ func TestScheduleUnitInNodeGroup_SinglePod(t *testing.T) {
 				snapshot,
 				globalClock,
 				tt.disablePreemption,
+				false,
 				config.CandidateSelectPolicyBest,
 				[]string{config.BetterPreemptionPolicyDichotomy},
 				100,
@@ -2144,6 +2155,7 @@ func TestScheduleUnitInNodeGroup_PodGroup(t *testing.T) {
 				snapshot,
 				globalClock,
 				false,
+				false,
 				config.CandidateSelectPolicyBest,
 				[]string{config.BetterPreemptionPolicyAscending},
 				100,
@@ -2245,3 +2257,266 @@ func TestScheduleUnitInNodeGroup_PodGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduleUnitInNodeGroup_PreemptionDryRun(t *testing.T) {
+	schedulerName := "scheduler"
+	stop := make(chan struct{})
+	defer close(stop)
+
+	client := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdClient := godelclientfake.NewSimpleClientset()
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	podInformer := informerFactory.Core().V1().Pods().Informer()
+	pcInformer := informerFactory.Scheduling().V1().PriorityClasses().Informer()
+	informerFactory.Start(stop)
+	informerFactory.WaitForCacheSync(stop)
+
+	podGroup := testing_helper.MakePodGroup().Namespace("default").Name("pg").MinMember(1).Obj()
+	pod := testing_helper.MakePod().Namespace("default").Name("foo").UID("foo").
+		Priority(100).PriorityClassName("pc").
+		Req(map[v1.ResourceName]string{"cpu": "5"}).
+		Annotation(podutil.PodGroupNameAnnotationKey, "pg").Obj()
+	nodes := []*v1.Node{
+		testing_helper.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{"cpu": "10"}).Obj(),
+		testing_helper.MakeNode().Name("n2").Capacity(map[v1.ResourceName]string{"cpu": "10"}).Obj(),
+	}
+	// The victim on n2 has a lower priority, so n2 is ranked ahead of n1.
+	existingPods := []*v1.Pod{
+		testing_helper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").
+			Priority(80).PriorityClassName("pc").Req(map[v1.ResourceName]string{"cpu": "10"}).Obj(),
+		testing_helper.MakePod().Namespace("default").Name("p2").UID("p2").Node("n2").
+			Priority(50).PriorityClassName("pc").Req(map[v1.ResourceName]string{"cpu": "10"}).Obj(),
+	}
+	podInformer.GetIndexer().Add(pod)
+	for _, p := range existingPods {
+		podInformer.GetIndexer().Add(p)
+	}
+	pcInformer.GetIndexer().Add(testing_helper.MakePriorityClass().Name("pc").Obj())
+
+	sCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("scheduler").SchedulerType(schedulerName).SubCluster(framework.DefaultSubCluster).
+		TTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore("PreemptionStore").
+		Obj())
+	snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		PodLister(informerFactory.Core().V1().Pods().Lister()).
+		EnableStore("PreemptionStore").
+		Obj())
+	for _, n := range nodes {
+		sCache.AddNode(n)
+	}
+	for _, p := range existingPods {
+		sCache.AddPod(p)
+	}
+	sCache.UpdateSnapshot(snapshot)
+
+	basePlugins := framework.PluginCollectionSet{
+		string(podutil.Kubelet): &framework.PluginCollection{
+			Filters: []*framework.PluginSpec{
+				framework.NewPluginSpec(noderesources.FitName),
+			},
+			Searchings: []*framework.VictimSearchingPluginCollectionSpec{
+				{
+					RejectNotSureVal: true,
+					Plugins: []*framework.PluginSpec{
+						{
+							Name: priorityvaluechecker.PriorityValueCheckerName,
+						},
+					},
+				},
+			},
+			Sortings: []*framework.PluginSpec{
+				framework.NewPluginSpec(priority.MinHighestPriorityName),
+			},
+		},
+	}
+	// The dry-run profile disables the preemption, but runs it to record what it would have done.
+	podScheduler := podscheduler.NewPodScheduler(
+		schedulerName,
+		framework.DisableScheduleSwitch,
+		"",
+		client,
+		crdClient,
+		informerFactory,
+		crdInformerFactory,
+		snapshot,
+		clock.RealClock{},
+		true,
+		true,
+		config.CandidateSelectPolicyBest,
+		[]string{config.BetterPreemptionPolicyAscending},
+		100,
+		100,
+		basePlugins,
+		nil,
+		map[string]*config.PluginConfig{},
+	)
+	recorder := events.NewFakeRecorder(10)
+	gs := &unitScheduler{
+		schedulerName:     testSchedulerName,
+		switchType:        framework.SwitchType(1),
+		disablePreemption: true,
+
+		podLister: testing_helper.NewFakePodLister(nil),
+		pgLister:  testing_helper.NewFakePodGroupLister(nil),
+
+		Cache:      sCache,
+		Snapshot:   snapshot,
+		Queue:      schedulingqueue.NewSchedulingQueue(sCache, nil, nil, nil, false),
+		Reconciler: reconciler.NewFailedTaskReconciler(nil, nil, sCache, ""),
+		Scheduler:  podScheduler,
+
+		Recorder: recorder,
+	}
+
+	unit := framework.NewPodGroupUnit(podGroup, 100)
+	unit.AddPod(&framework.QueuedPodInfo{Pod: pod})
+	queuedUnitInfo := &framework.QueuedUnitInfo{
+		UnitKey:            unit.GetKey(),
+		ScheduleUnit:       unit,
+		QueuePriorityScore: float64(unit.GetPriority()),
+	}
+	unitInfo, _ := gs.constructSchedulingUnitInfo(context.Background(), queuedUnitInfo)
+	unitFramework := unitruntime.NewUnitFramework(gs, gs, gs.PluginRegistry, nil, unitInfo.QueuedUnitInfo)
+	lister := framework.NewNodeInfoLister().(*framework.NodeInfoListerImpl)
+	for _, n := range nodes {
+		lister.AddNodeInfo(snapshot.GetNodeInfo(n.Name))
+	}
+	nodeGroup := snapshot.MakeBasicNodeGroup()
+	nodeGroup.SetNodeCircles([]framework.NodeCircle{framework.NewNodeCircle("", lister)})
+	unitResult := gs.scheduleUnitInNodeGroup(context.Background(), unitInfo, unitFramework, nodeGroup)
+
+	// The node, victims and sorted candidates are recorded.
+	var dryRunEvents []string
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.Contains(event, "PreemptionDryRun") {
+			dryRunEvents = append(dryRunEvents, event)
+		}
+	}
+	if len(dryRunEvents) != 1 {
+		t.Fatalf("expected 1 PreemptionDryRun event, got %v", dryRunEvents)
+	}
+	for _, want := range []string{"nominated node: n2", "p2", "sorted candidates: [n2 n1]"} {
+		if !strings.Contains(dryRunEvents[0], want) {
+			t.Errorf("expected the PreemptionDryRun event to contain %q, got %q", want, dryRunEvents[0])
+		}
+	}
+
+	// Nothing is nominated or persisted.
+	if len(unitResult.SuccessfulPods) != 0 || !reflect.DeepEqual(unitResult.FailedPods, []string{"default/foo"}) {
+		t.Errorf("expected the pod to stay unschedulable, got successful pods %v and failed pods %v", unitResult.SuccessfulPods, unitResult.FailedPods)
+	}
+	for key, runningUnitInfo := range unitInfo.DispatchedPods {
+		if len(runningUnitInfo.NodeToPlace) > 0 || runningUnitInfo.Victims != nil {
+			t.Errorf("expected pod %v not to be nominated, got node %v and victims %v", key, runningUnitInfo.NodeToPlace, runningUnitInfo.Victims)
+		}
+	}
+	for _, n := range nodes {
+		if pods := snapshot.GetNodeInfo(n.Name).GetPods(); len(pods) != 1 {
+			t.Errorf("expected the pods on node %v to be kept, got %v", n.Name, pods)
+		}
+	}
+	for _, action := range client.Actions() {
+		if !action.Matches("list", action.GetResource().Resource) && !action.Matches("watch", action.GetResource().Resource) {
+			t.Errorf("expected nothing to be persisted, got %v", action)
+		}
+	}
+}
+
+func TestHoldBackResources(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourceReservation, true)()
+
+	sCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		Obj())
+	starvationAware, err := unitqueuesort.NewStarvationAware(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClock := clock.NewFakeClock(time.Now())
+	gs := &unitScheduler{
+		Cache:           sCache,
+		Clock:           fakeClock,
+		starvationAware: starvationAware.(framework.StarvationAwareUnitQueueSortPlugin),
+	}
+
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: 4},
+	}
+	unitInfo := &core.SchedulingUnitInfo{
+		UnitKey:        "default/pg",
+		QueuedUnitInfo: &framework.QueuedUnitInfo{ScheduleUnit: framework.NewPodGroupUnit(pg, 100)},
+	}
+	cpu := func(quantity string) v1.ResourceList {
+		return v1.ResourceList{v1.ResourceCPU: resource.MustParse(quantity)}
+	}
+
+	gs.holdBackResources(unitInfo, map[string]v1.ResourceList{"n1": cpu("4"), "n2": cpu("2")})
+	reservations := sCache.GetUnitReservations("default/pg")
+	if len(reservations) != 2 || !reservations["n1"].Resources.Cpu().Equal(resource.MustParse("4")) {
+		t.Fatalf("expected the resources on n1 and n2 to be held back, got %v", reservations)
+	}
+	creationTime := reservations["n1"].CreationTime
+	if ttl := reservations["n1"].TTLSeconds; ttl != unitqueuesort.DefaultReservationTTLSeconds {
+		t.Errorf("expected ttl %v, got %v", unitqueuesort.DefaultReservationTTLSeconds, ttl)
+	}
+
+	// The previous reservations are kept if nothing is available in this attempt.
+	gs.holdBackResources(unitInfo, nil)
+	if got := sCache.GetUnitReservations("default/pg"); len(got) != 2 {
+		t.Errorf("expected the previous reservations to be kept, got %v", got)
+	}
+
+	// The reservations are replaced, inheriting the creation time so that they won't last forever.
+	fakeClock.Step(time.Minute)
+	gs.holdBackResources(unitInfo, map[string]v1.ResourceList{"n2": cpu("6")})
+	reservations = sCache.GetUnitReservations("default/pg")
+	if len(reservations) != 1 || reservations["n2"] == nil {
+		t.Fatalf("expected the resources to be held back on n2 only, got %v", reservations)
+	}
+	if !reservations["n2"].CreationTime.Equal(&creationTime) {
+		t.Errorf("expected creation time %v to be inherited, got %v", creationTime, reservations["n2"].CreationTime)
+	}
+
+	sCache.RemoveUnitReservations("default/pg")
+	if got := sCache.GetUnitReservations("default/pg"); len(got) != 0 {
+		t.Errorf("expected the reservations to be released, got %v", got)
+	}
+}
+
+func TestHoldBackResourcesWithoutResourceReservation(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourceReservation, false)()
+
+	sCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		Obj())
+	starvationAware, err := unitqueuesort.NewStarvationAware(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &unitScheduler{
+		Cache:           sCache,
+		Clock:           clock.NewFakeClock(time.Now()),
+		starvationAware: starvationAware.(framework.StarvationAwareUnitQueueSortPlugin),
+	}
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: 4},
+	}
+	unitInfo := &core.SchedulingUnitInfo{
+		UnitKey:        "default/pg",
+		QueuedUnitInfo: &framework.QueuedUnitInfo{ScheduleUnit: framework.NewPodGroupUnit(pg, 100)},
+	}
+
+	// Nothing is held back in cache since the ReservationStore is not enabled.
+	gs.holdBackResources(unitInfo, map[string]v1.ResourceList{"n1": {v1.ResourceCPU: resource.MustParse("4")}})
+	if got := sCache.GetUnitReservations("default/pg"); len(got) != 0 {
+		t.Errorf("expected nothing to be held back, got %v", got)
+	}
+	sCache.RemoveUnitReservations("default/pg")
+}
@@ -166,6 +166,26 @@ func (sched *Scheduler) onCSINodeUpdate(oldObj, newObj interface{}) {
 	)
 }
 
+func (sched *Scheduler) onCSIStorageCapacityAdd(obj interface{}) {
+	sched.ScheduleSwitch.Process(
+		// TODO: Parse SwitchType for CSI
+		framework.SwitchTypeAll,
+		func(dataSet ScheduleDataSet) {
+			dataSet.SchedulingQueue().MoveAllToActiveOrBackoffQueue(util.CSIStorageCapacityAdd)
+		},
+	)
+}
+
+func (sched *Scheduler) onCSIStorageCapacityUpdate(oldObj, newObj interface{}) {
+	sched.ScheduleSwitch.Process(
+		// TODO: Parse SwitchType for CSI
+		framework.SwitchTypeAll,
+		func(dataSet ScheduleDataSet) {
+			dataSet.SchedulingQueue().MoveAllToActiveOrBackoffQueue(util.CSIStorageCapacityUpdate)
+		},
+	)
+}
+
 // update nodes within scheduler api
 func (sched *Scheduler) onSchedulerUpdate(_, _ interface{}) {
 	sched.ScheduleSwitch.Process(
@@ -868,6 +888,19 @@ func addAllEventHandlers(
 		)
 	}
 
+	// Pods rejected for insufficient storage may fit once the CSI drivers publish more capacity.
+	if utilfeature.DefaultFeatureGate.Enabled(features.CSIStorageCapacity) {
+		// The volume binders of sub-cluster profiles may be created after the informers
+		// are started, so the CSIDriver informer is registered up front.
+		informerFactory.Storage().V1().CSIDrivers().Informer()
+		informerFactory.Storage().V1().CSIStorageCapacities().Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    sched.onCSIStorageCapacityAdd,
+				UpdateFunc: sched.onCSIStorageCapacityUpdate,
+			},
+		)
+	}
+
 	// On add and delete of PVs, it will affect equivalence cache items
 	// related to persistent volume
 	informerFactory.Core().V1().PersistentVolumes().Informer().AddEventHandler(
@@ -26,6 +26,7 @@ import (
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	fakecache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/fake"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 func TestNodeAllocatableChanged(t *testing.T) {
@@ -279,3 +280,76 @@ func TestSkipPodUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestForgetRejectedHandedOffPod(t *testing.T) {
+	dispatched := func(rejectedToken string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod-0",
+				Annotations: map[string]string{
+					podutil.PodStateAnnotationKey:  string(podutil.PodDispatched),
+					podutil.SchedulerAnnotationKey: "scheduler",
+				},
+			},
+		}
+		if len(rejectedToken) != 0 {
+			pod.Annotations[podutil.HandoffRejectedAnnotationKey] = rejectedToken
+		}
+		return pod
+	}
+	assumed := dispatched("")
+	assumed.Annotations[podutil.PodStateAnnotationKey] = string(podutil.PodAssumed)
+	assumed.Annotations[podutil.AssumedNodeAnnotationKey] = "node-0"
+
+	table := []struct {
+		name           string
+		oldPod, newPod *v1.Pod
+		expectRejected bool
+	}{
+		{
+			name:           "placement rejected",
+			oldPod:         dispatched(""),
+			newPod:         dispatched("token-1"),
+			expectRejected: true,
+		},
+		{
+			name:           "placement rejected again",
+			oldPod:         dispatched("token-1"),
+			newPod:         dispatched("token-2"),
+			expectRejected: true,
+		},
+		{
+			name:   "unrelated update after rejection",
+			oldPod: dispatched("token-1"),
+			newPod: dispatched("token-1"),
+		},
+		{
+			name:   "persisted placement rejected",
+			oldPod: assumed,
+			newPod: dispatched("token-1"),
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			if got := handoffRejected(test.oldPod, test.newPod); got != test.expectRejected {
+				t.Fatalf("handoffRejected() = %t, expected = %t", got, test.expectRejected)
+			}
+			if !test.expectRejected {
+				return
+			}
+
+			var forgotten *v1.Pod
+			c := &Scheduler{
+				commonCache: &fakecache.Cache{
+					IsAssumedPodFunc: func(*v1.Pod) bool { return true },
+					GetPodFunc:       func(*v1.Pod) *v1.Pod { return assumed },
+					ForgetFunc:       func(pod *v1.Pod) { forgotten = pod },
+				},
+			}
+			c.forgetHandedOffPod(test.newPod)
+			if forgotten != assumed {
+				t.Errorf("expected the assumed placement to be forgotten, got %v", forgotten)
+			}
+		})
+	}
+}
@@ -204,6 +204,10 @@ func renderBasePlugin(pluginCollection framework.PluginCollectionSet, baseKubele
 }
 
 func profileNeedPreemption(profile *config.GodelSchedulerProfile) bool {
+	if profile != nil && profile.PreemptionDryRun != nil && *profile.PreemptionDryRun {
+		// The dry-run preemption needs the preemption store as well.
+		return true
+	}
 	if profile != nil && profile.DisablePreemption != nil {
 		// If the DeisablePreemption has already been set, return it's value directly.
 		return !*profile.DisablePreemption
@@ -223,5 +227,11 @@ func parseProfilesBoolConfiguration(options schedulerOptions, checker func(*conf
 			return true
 		}
 	}
+	for k := range options.schedulerNameProfiles {
+		profile := options.schedulerNameProfiles[k]
+		if checker(&profile) {
+			return true
+		}
+	}
 	return false
 }
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpushare
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/gpushare"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	Name              = "GPUShare"
+	preFilterStateKey = "PreFilter" + Name
+)
+
+type preFilterState struct {
+	request gpushare.Request
+}
+
+// Clone the prefilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("error reading %q from cycleState: %v", preFilterStateKey, err)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to GPUShare.preFilterState error", c)
+	}
+	return s, nil
+}
+
+// GPUShare places the pods requesting a fraction of one GPU device by GPU memory and compute. It picks
+// a specific device on each node according to the policy, and the device of the selected node is written
+// to the pod annotation for the device plugin.
+type GPUShare struct {
+	handle framework.SchedulerFrameworkHandle
+	policy gpushare.Policy
+}
+
+var (
+	_ framework.PreFilterPlugin = &GPUShare{}
+	_ framework.FilterPlugin    = &GPUShare{}
+	_ framework.ScorePlugin     = &GPUShare{}
+)
+
+func New(plArgs runtime.Object, handle framework.SchedulerFrameworkHandle) (framework.Plugin, error) {
+	policy := gpushare.PolicyBinpack
+	if plArgs != nil {
+		args, ok := plArgs.(*config.GPUShareArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type GPUShareArgs, got %T", plArgs)
+		}
+		if len(args.Policy) > 0 {
+			policy = gpushare.Policy(args.Policy)
+		}
+	}
+	if policy != gpushare.PolicyBinpack && policy != gpushare.PolicySpread {
+		return nil, fmt.Errorf("invalid policy of %v: %v", Name, policy)
+	}
+	return &GPUShare{
+		handle: handle,
+		policy: policy,
+	}, nil
+}
+
+func (pl *GPUShare) Name() string {
+	return Name
+}
+
+func (pl *GPUShare) PreFilter(_ context.Context, cycleState *framework.CycleState, pod *v1.Pod) *framework.Status {
+	if !utilfeature.DefaultFeatureGate.Enabled(godelfeatures.GPUShareScheduling) {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("featuregate %s is disabled", godelfeatures.GPUShareScheduling))
+	}
+	request := gpushare.GetPodRequest(pod)
+	cycleState.Write(preFilterStateKey, &preFilterState{request: request})
+	if !request.IsEmpty() {
+		framework.InitPodAnnotationsOnNode(cycleState)
+	}
+	return nil
+}
+
+// PreFilterExtensions returns prefilter extensions, pod add and remove.
+func (pl *GPUShare) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+func (pl *GPUShare) Filter(_ context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if s.request.IsEmpty() {
+		return nil
+	}
+
+	device := gpushare.SelectDevice(nodeInfo.GetGPUShareDevices(), s.request, pl.policy)
+	if device == nil {
+		return framework.NewStatus(framework.Unschedulable, gpushare.ErrReasonInsufficientDevice)
+	}
+	// The device will be set on the pod if the node is selected finally.
+	if err := framework.SetPodAnnotationOnNode(cycleState, nodeInfo.GetNodeName(), podutil.GPUShareDeviceAnnotationKey, gpushare.FormatDevice(device)); err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	return nil
+}
+
+// Score favors the nodes whose selected device has the most GPU memory requested for Binpack policy,
+// and the least for Spread policy.
+func (pl *GPUShare) Score(_ context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, err.Error())
+	}
+	if s.request.IsEmpty() {
+		return 0, nil
+	}
+	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+
+	device := gpushare.SelectDevice(nodeInfo.GetGPUShareDevices(), s.request, pl.policy)
+	if device == nil {
+		return 0, nil
+	}
+	utilization := gpushare.Utilization(device, s.request)
+	if pl.policy == gpushare.PolicySpread {
+		utilization = 1 - utilization
+	}
+	return int64(utilization * float64(framework.MaxNodeScore)), nil
+}
+
+func (pl *GPUShare) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
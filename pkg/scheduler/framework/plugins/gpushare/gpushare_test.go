@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpushare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	st "github.com/kubewharf/godel-scheduler/pkg/scheduler/testing"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeNode(name, devices string) *v1.Node {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:         resource.MustParse("32"),
+				util.ResourceGPUMemory: resource.MustParse("32Gi"),
+				util.ResourceGPUCore:   resource.MustParse("200"),
+			},
+		},
+	}
+	if len(devices) > 0 {
+		node.Annotations[util.GPUShareDevicesAnnotationKey] = devices
+	}
+	return node
+}
+
+func makePod(name, nodeName, memory, core, device string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("uid-" + name),
+			Annotations: map[string]string{
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{}},
+			}},
+		},
+	}
+	if len(memory) > 0 {
+		pod.Spec.Containers[0].Resources.Requests[util.ResourceGPUMemory] = resource.MustParse(memory)
+	}
+	if len(core) > 0 {
+		pod.Spec.Containers[0].Resources.Requests[util.ResourceGPUCore] = resource.MustParse(core)
+	}
+	if len(device) > 0 {
+		pod.Annotations[podutil.GPUShareDeviceAnnotationKey] = device
+	}
+	return pod
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *config.GPUShareArgs
+		wantErr bool
+	}{
+		{name: "default policy", args: &config.GPUShareArgs{}},
+		{name: "spread policy", args: &config.GPUShareArgs{Policy: "Spread"}},
+		{name: "invalid policy", args: &config.GPUShareArgs{Policy: "Random"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.args, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGPUShare(t *testing.T) {
+	defer utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.GPUShareScheduling): false})
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.GPUShareScheduling): true})
+
+	twoDevices := "0:godel.bytedance.com/gpu-memory=16Gi,godel.bytedance.com/gpu-core=100;1:godel.bytedance.com/gpu-memory=16Gi,godel.bytedance.com/gpu-core=100"
+	oneDevice := "0:godel.bytedance.com/gpu-memory=16Gi,godel.bytedance.com/gpu-core=100"
+	nodes := []*v1.Node{
+		makeNode("node-1", twoDevices),
+		makeNode("node-2", oneDevice),
+		makeNode("node-3", ""),
+	}
+	existingPods := []*v1.Pod{
+		makePod("existing", "node-1", "8Gi", "50", "1"),
+	}
+
+	type nodeResult struct {
+		code   framework.Code
+		device string
+		score  int64
+	}
+	tests := []struct {
+		name     string
+		policy   string
+		pod      *v1.Pod
+		expected map[string]nodeResult
+	}{
+		{
+			name:   "binpack onto the device with least free memory",
+			policy: "Binpack",
+			pod:    makePod("p", "", "4Gi", "30", ""),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success, device: "1", score: 75},
+				"node-2": {code: framework.Success, device: "0", score: 25},
+				"node-3": {code: framework.Unschedulable},
+			},
+		},
+		{
+			name:   "spread onto the device with most free memory",
+			policy: "Spread",
+			pod:    makePod("p", "", "4Gi", "30", ""),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success, device: "0", score: 75},
+				"node-2": {code: framework.Success, device: "0", score: 75},
+				"node-3": {code: framework.Unschedulable},
+			},
+		},
+		{
+			name:   "skip the device without enough free core",
+			policy: "Binpack",
+			pod:    makePod("p", "", "4Gi", "60", ""),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success, device: "0", score: 25},
+				"node-2": {code: framework.Success, device: "0", score: 25},
+				"node-3": {code: framework.Unschedulable},
+			},
+		},
+		{
+			name:   "pod not requesting shared GPU",
+			policy: "Binpack",
+			pod:    makePod("p", "", "", "", ""),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success},
+				"node-2": {code: framework.Success},
+				"node-3": {code: framework.Success},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := godelcache.New(handler.MakeCacheHandlerWrapper().
+				SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+				TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+				EnableStore("PreemptionStore").
+				Obj())
+			snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+				SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+				EnableStore("PreemptionStore").
+				Obj())
+			for _, n := range nodes {
+				cache.AddNode(n)
+			}
+			for _, p := range existingPods {
+				cache.AddPod(p)
+			}
+			cache.UpdateSnapshot(snapshot)
+
+			fh, _ := st.NewSchedulerFrameworkHandle(nil, nil, nil, nil, nil, snapshot, nil, nil, nil, nil)
+			p, err := New(&config.GPUShareArgs{Policy: tt.policy}, fh)
+			if err != nil {
+				t.Fatalf("failed to create plugin: %v", err)
+			}
+			pl := p.(*GPUShare)
+
+			cycleState := framework.NewCycleState()
+			if status := pl.PreFilter(context.Background(), cycleState, tt.pod); !status.IsSuccess() {
+				t.Fatalf("unexpected prefilter status: %v", status)
+			}
+			for nodeName, expected := range tt.expected {
+				nodeInfo, err := snapshot.NodeInfos().Get(nodeName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				status := pl.Filter(context.Background(), cycleState, tt.pod, nodeInfo)
+				if status.Code() != expected.code {
+					t.Errorf("node %v: expected code %v, got %v", nodeName, expected.code, status.Code())
+				}
+				if got := framework.GetPodAnnotationsOnNode(cycleState, nodeName)[podutil.GPUShareDeviceAnnotationKey]; got != expected.device {
+					t.Errorf("node %v: expected device %q, got %q", nodeName, expected.device, got)
+				}
+				if !status.IsSuccess() {
+					continue
+				}
+				score, status := pl.Score(context.Background(), cycleState, tt.pod, nodeName)
+				if !status.IsSuccess() {
+					t.Errorf("node %v: unexpected score status: %v", nodeName, status)
+				}
+				if score != expected.score {
+					t.Errorf("node %v: expected score %v, got %v", nodeName, expected.score, score)
+				}
+			}
+		})
+	}
+}
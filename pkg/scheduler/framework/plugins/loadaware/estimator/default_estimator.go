@@ -42,7 +42,7 @@ type DefaultEstimator struct {
 	resources map[podutil.PodResourceType]sets.String
 }
 
-func NewDefaultEstimator(args *config.LoadAwareArgs, handle framework.SchedulerFrameworkHandle) (Estimator, error) {
+func NewDefaultEstimator(args *config.LoadAwareArgs, handle Handle) (Estimator, error) {
 	resources := make(map[podutil.PodResourceType]sets.String)
 	for _, res := range args.Resources {
 		resourceSet := resources[res.ResourceType]
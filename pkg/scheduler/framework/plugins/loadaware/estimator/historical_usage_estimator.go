@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	HistoricalUsageEstimatorName = "historicalUsageEstimator"
+
+	DefaultHistoricalUsagePercentile = 95
+)
+
+// HistoricalUsageEstimator estimates the usage of a pod by the historical usage of the workload it belongs to,
+// and falls back to NodeMetricEstimator for the pods of unknown workloads. Nodes are estimated the same as
+// NodeMetricEstimator.
+type HistoricalUsageEstimator struct {
+	*NodeMetricEstimator
+
+	percentile int64
+}
+
+func NewHistoricalUsageEstimator(args *config.LoadAwareArgs, handle Handle) (Estimator, error) {
+	nodeMetricEstimator, err := NewNodeMetricEstimator(args, handle)
+	if err != nil {
+		return nil, err
+	}
+	percentile := args.HistoricalUsagePercentile
+	if percentile == 0 {
+		percentile = DefaultHistoricalUsagePercentile
+	}
+	return &HistoricalUsageEstimator{
+		NodeMetricEstimator: nodeMetricEstimator.(*NodeMetricEstimator),
+		percentile:          percentile,
+	}, nil
+}
+
+func (e *HistoricalUsageEstimator) Name() string {
+	return HistoricalUsageEstimatorName
+}
+
+func (e *HistoricalUsageEstimator) EstimatePod(pod *v1.Pod) (*framework.Resource, error) {
+	resourceType, err := podutil.GetPodResourceType(pod)
+	if err != nil {
+		return nil, err
+	}
+	requests, _ := PodRequestsAndLimits(pod)
+
+	ownerInfo := podutil.GetPodOwnerInfo(pod)
+	if ownerInfo == nil {
+		return framework.NewResource(e.scalingResource(requests, resourceType)), nil
+	}
+	usage := e.handle.GetLoadAwareWorkloadUsage(GetWorkloadKey(ownerInfo), e.percentile)
+	if usage == nil {
+		return framework.NewResource(e.scalingResource(requests, resourceType)), nil
+	}
+
+	// Only cpu and memory usage are recorded in history, the other resources are still estimated by requests.
+	estimated := e.scalingResource(requests, resourceType)
+	if _, ok := estimated[v1.ResourceCPU]; ok {
+		estimated[v1.ResourceCPU] = *resource.NewMilliQuantity(usage.MilliCPU, resource.DecimalSI)
+	}
+	if _, ok := estimated[v1.ResourceMemory]; ok {
+		estimated[v1.ResourceMemory] = *resource.NewQuantity(usage.Memory, resource.BinarySI)
+	}
+	return framework.NewResource(estimated), nil
+}
+
+// GetWorkloadKey returns the key of the workload in the historical usage profiles, which is "<Type>/<Namespace>/<Name>".
+// The uid is not included so that the history is kept when the workload is recreated.
+func GetWorkloadKey(ownerInfo *podutil.OwnerInfo) string {
+	return ownerInfo.Type + podutil.KeySeperator + ownerInfo.Namespace + podutil.KeySeperator + ownerInfo.Name
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	st "github.com/kubewharf/godel-scheduler/pkg/scheduler/testing"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeCNRWithWorkloadUsage(nodeName, profiles string) *katalystv1alpha1.CustomNodeResource {
+	return &katalystv1alpha1.CustomNodeResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nodeName,
+			Annotations: map[string]string{util.WorkloadUsageProfileAnnotationKey: profiles},
+		},
+	}
+}
+
+func makeWorkloadPod(key, owner string) *v1.Pod {
+	wrapper := testinghelper.MakePod().Namespace("default").Name(key).UID(key).
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.BestEffortPod)).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "1000"})
+	if len(owner) > 0 {
+		wrapper.ControllerRef(metav1.OwnerReference{Kind: podutil.ReplicaSetKind, Name: owner, UID: "uid"})
+	}
+	return wrapper.Obj()
+}
+
+func TestHistoricalUsageEstimatorEstimatePod(t *testing.T) {
+	cnrs := []*katalystv1alpha1.CustomNodeResource{
+		makeCNRWithWorkloadUsage("n1", `{"ReplicaSet/default/rs1": {"50": {"cpu": "200m", "memory": "200"}, "95": {"cpu": "400m", "memory": "300"}}}`),
+		makeCNRWithWorkloadUsage("n2", `{"ReplicaSet/default/rs1": {"50": {"cpu": "300m", "memory": "100"}, "99": {"cpu": "500m", "memory": "600"}}}`),
+	}
+
+	tests := []struct {
+		name       string
+		percentile int64
+		pod        *v1.Pod
+		update     *katalystv1alpha1.CustomNodeResource
+		want       *framework.Resource
+	}{
+		{
+			name: "estimate by max usage of the default percentile among nodes",
+			pod:  makeWorkloadPod("p", "rs1"),
+			want: &framework.Resource{MilliCPU: 500, Memory: 600},
+		},
+		{
+			name:       "estimate by max usage of the given percentile among nodes",
+			percentile: 50,
+			pod:        makeWorkloadPod("p", "rs1"),
+			want:       &framework.Resource{MilliCPU: 300, Memory: 200},
+		},
+		{
+			name:   "usage history removed from the updated cnr",
+			pod:    makeWorkloadPod("p", "rs1"),
+			update: makeCNRWithWorkloadUsage("n2", `{}`),
+			want:   &framework.Resource{MilliCPU: 400, Memory: 300},
+		},
+		{
+			name: "fall back to scaled requests for unknown workload",
+			pod:  makeWorkloadPod("p", "rs2"),
+			want: &framework.Resource{MilliCPU: 600, Memory: 600},
+		},
+		{
+			name: "fall back to scaled requests for pod without owner",
+			pod:  makeWorkloadPod("p", ""),
+			want: &framework.Resource{MilliCPU: 600, Memory: 600},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadAwareSchedulingArgs := config.LoadAwareArgs{
+				Resources: []config.ResourceSpec{
+					{
+						Name:         string(v1.ResourceCPU),
+						Weight:       1,
+						ResourceType: podutil.BestEffortPod,
+					},
+					{
+						Name:         string(v1.ResourceMemory),
+						Weight:       1,
+						ResourceType: podutil.BestEffortPod,
+					},
+				},
+				EstimatedScalingFactors:   defaultEstimatedScalingFactors,
+				HistoricalUsagePercentile: tt.percentile,
+			}
+
+			schedulerCache := godelcache.New(handler.MakeCacheHandlerWrapper().
+				SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+				TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+				Obj())
+			snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+				SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+				Obj())
+			{
+				// Prepare cache and snapshot.
+				for _, cnr := range cnrs {
+					schedulerCache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: cnr.Name}})
+					schedulerCache.AddCNR(cnr)
+				}
+				schedulerCache.UpdateSnapshot(snapshot)
+				if tt.update != nil {
+					for _, cnr := range cnrs {
+						if cnr.Name == tt.update.Name {
+							schedulerCache.UpdateCNR(cnr, tt.update)
+						}
+					}
+					schedulerCache.UpdateSnapshot(snapshot)
+				}
+			}
+			fh, _ := st.NewSchedulerFrameworkHandle(nil, nil, nil, nil, schedulerCache, snapshot, nil, nil, nil, nil)
+
+			estimator, err := NewHistoricalUsageEstimator(&loadAwareSchedulingArgs, fh)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			got, err := estimator.EstimatePod(tt.pod)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want); len(diff) > 0 {
+				t.Errorf("Got diff: %v", diff)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/loadaware"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
@@ -49,10 +50,10 @@ type NodeMetricEstimator struct {
 	// Is CPU scaling factor is 80, estimated CPU = 80 / 100 * request.cpu
 	estimatedScalingFactors map[v1.ResourceName]int64
 
-	handle framework.SchedulerFrameworkHandle
+	handle Handle
 }
 
-func NewNodeMetricEstimator(args *config.LoadAwareArgs, handle framework.SchedulerFrameworkHandle) (Estimator, error) {
+func NewNodeMetricEstimator(args *config.LoadAwareArgs, handle Handle) (Estimator, error) {
 	resources := make(map[podutil.PodResourceType]sets.String)
 	for _, res := range args.Resources {
 		resourceSet := resources[res.ResourceType]
@@ -101,28 +102,10 @@ func (e *NodeMetricEstimator) ValidateNode(nodeInfo framework.NodeInfo, resource
 	}
 
 	if len(e.usageThresholds) > 0 {
-		allocatable := framework.NewResource(nil)
-		switch resourceType {
-		case podutil.GuaranteedPod:
-			allocatable = nodeInfo.GetGuaranteedAllocatable()
-		case podutil.BestEffortPod:
-			allocatable = nodeInfo.GetBestEffortAllocatable()
-		}
-		for resourceName, threshold := range e.usageThresholds {
-			// TODO: support more resources.
-			var usage, total int64
-			switch resourceName {
-			case v1.ResourceCPU:
-				usage = nodeMetricInfo.ProfileMilliCPUUsage
-				total = allocatable.MilliCPU
-			case v1.ResourceMemory:
-				usage = nodeMetricInfo.ProfileMEMUsage
-				total = allocatable.Memory
-			}
-			if float64(usage) > float64(threshold)/100.0*float64(total) {
-				// Because we only judge utilization based on metric information, preemption is useless.
-				return framework.NewStatus(framework.UnschedulableAndUnresolvable, "NodeMetricInfo usage exceeds threshold")
-			}
+		allocatable := loadaware.GetAllocatable(nodeInfo, resourceType)
+		if loadaware.ExceedsUsageThresholds(nodeMetricInfo, allocatable, e.usageThresholds) {
+			// Because we only judge utilization based on metric information, preemption is useless.
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, "NodeMetricInfo usage exceeds threshold")
 		}
 	}
 
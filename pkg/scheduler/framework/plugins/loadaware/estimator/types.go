@@ -24,11 +24,20 @@ import (
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
-type FactoryFn func(args *config.LoadAwareArgs, handle framework.SchedulerFrameworkHandle) (Estimator, error)
+// Handle provides the load aware information to the estimators. It is implemented by
+// framework.SchedulerFrameworkHandle, and can also be implemented out of the schedulers.
+type Handle interface {
+	GetLoadAwareNodeMetricInfo(nodeName string, resourceType podutil.PodResourceType) *framework.LoadAwareNodeMetricInfo
+	GetLoadAwareNodeUsage(nodeName string, resourceType podutil.PodResourceType) *framework.LoadAwareNodeUsage
+	GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *framework.LoadAwareWorkloadUsage
+}
+
+type FactoryFn func(args *config.LoadAwareArgs, handle Handle) (Estimator, error)
 
 var Estimators = map[string]FactoryFn{
-	DefaultEstimatorName:    NewDefaultEstimator,
-	NodeMetricEstimatorName: NewNodeMetricEstimator,
+	DefaultEstimatorName:         NewDefaultEstimator,
+	NodeMetricEstimatorName:      NewNodeMetricEstimator,
+	HistoricalUsageEstimatorName: NewHistoricalUsageEstimator,
 }
 
 type Estimator interface {
@@ -38,7 +47,7 @@ type Estimator interface {
 	EstimateNode(info framework.NodeInfo, resourceType podutil.PodResourceType) (*framework.Resource, error)
 }
 
-func NewEstimator(args *config.LoadAwareArgs, handle framework.SchedulerFrameworkHandle) (Estimator, error) {
+func NewEstimator(args *config.LoadAwareArgs, handle Handle) (Estimator, error) {
 	factoryFn := Estimators[args.Estimator]
 	if factoryFn == nil {
 		factoryFn = NewDefaultEstimator
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstoragepool
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/localstoragepool"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/validation"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	Name              = "LocalStoragePoolChecker"
+	preFilterStateKey = "PreFilter" + Name
+)
+
+var defaultLocalStoragePoolCheckerArgs = config.LocalStoragePoolCheckerArgs{
+	ScorePolicy: config.ScorePolicyMostAvailable,
+}
+
+type preFilterState struct {
+	requests *localstoragepool.Requests
+}
+
+// Clone the prefilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("error reading %q from cycleState: %v", preFilterStateKey, err)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to LocalStoragePoolChecker.preFilterState error", c)
+	}
+	return s, nil
+}
+
+// LocalStoragePoolChecker places the pods whose volumes are provisioned from the node-local storage pools,
+// e.g. LVM volume groups or NVMe disks, onto the nodes whose pools have enough free capacity.
+// The storage classes listed in StorageClassWeights are considered to be served by local pools. The requests
+// of the pod are written to the pod annotation so that they are accounted by NodeInfo once the pod is placed.
+type LocalStoragePoolChecker struct {
+	handle              framework.SchedulerFrameworkHandle
+	pvcLister           corelisters.PersistentVolumeClaimLister
+	scorePolicy         config.ScorePolicy
+	localStorageClasses sets.String
+	weights             map[string]int64
+}
+
+var (
+	_ framework.PreFilterPlugin = &LocalStoragePoolChecker{}
+	_ framework.FilterPlugin    = &LocalStoragePoolChecker{}
+	_ framework.ScorePlugin     = &LocalStoragePoolChecker{}
+)
+
+// New initializes a new plugin and returns it.
+func New(plArgs runtime.Object, handle framework.SchedulerFrameworkHandle) (framework.Plugin, error) {
+	args, ok := plArgs.(*config.LocalStoragePoolCheckerArgs)
+	if !ok {
+		klog.InfoS(fmt.Sprintf("WARN: want args to be of type LocalStoragePoolCheckerArgs, got %T", plArgs))
+		args = &defaultLocalStoragePoolCheckerArgs
+	}
+	if err := validation.ValidateLocalStoragePoolCheckerArgs(args); err != nil {
+		return nil, err
+	}
+
+	localStorageClasses := sets.NewString()
+	weights := make(map[string]int64, len(args.StorageClassWeights))
+	for _, spec := range args.StorageClassWeights {
+		localStorageClasses.Insert(spec.Name)
+		weights[spec.Name] = spec.Weight
+	}
+	return &LocalStoragePoolChecker{
+		handle:              handle,
+		pvcLister:           handle.SharedInformerFactory().Core().V1().PersistentVolumeClaims().Lister(),
+		scorePolicy:         args.ScorePolicy,
+		localStorageClasses: localStorageClasses,
+		weights:             weights,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *LocalStoragePoolChecker) Name() string {
+	return Name
+}
+
+func (pl *LocalStoragePoolChecker) PreFilter(_ context.Context, cycleState *framework.CycleState, pod *v1.Pod) *framework.Status {
+	if !utilfeature.DefaultFeatureGate.Enabled(godelfeatures.LocalStoragePoolScheduling) {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("featuregate %s is disabled", godelfeatures.LocalStoragePoolScheduling))
+	}
+	requests, err := localstoragepool.GetPodRequests(pod, pl.localStorageClasses, pl.pvcLister)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+	cycleState.Write(preFilterStateKey, &preFilterState{requests: requests})
+	if !requests.IsEmpty() {
+		framework.InitPodAnnotationsOnNode(cycleState)
+	}
+	return nil
+}
+
+// PreFilterExtensions returns prefilter extensions, pod add and remove.
+func (pl *LocalStoragePoolChecker) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+func (pl *LocalStoragePoolChecker) Filter(_ context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if s.requests.IsEmpty() {
+		return nil
+	}
+
+	if status := localstoragepool.Fits(s.requests.Pending, nodeInfo.GetLocalStoragePools()); status != nil {
+		return status
+	}
+	// The requests will be set on the pod if the node is selected finally.
+	if err := framework.SetPodAnnotationOnNode(cycleState, nodeInfo.GetNodeName(), podutil.LocalStorageRequestsAnnotationKey,
+		util.MarshalStorageQuantities(s.requests.All)); err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	return nil
+}
+
+// Score favors the nodes whose requested pools have the most free capacity left for MostAvailable policy,
+// and the least for LeastAvailable policy.
+func (pl *LocalStoragePoolChecker) Score(_ context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, err.Error())
+	}
+	if len(s.requests.Pending) == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+
+	utilization := localstoragepool.Utilization(s.requests.Pending, nodeInfo.GetLocalStoragePools(), pl.weights)
+	if pl.scorePolicy == config.ScorePolicyMostAvailable {
+		utilization = 1 - utilization
+	}
+	return int64(math.Round(utilization * float64(framework.MaxNodeScore))), nil
+}
+
+func (pl *LocalStoragePoolChecker) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
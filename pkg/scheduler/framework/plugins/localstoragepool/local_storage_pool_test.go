@@ -0,0 +1,288 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstoragepool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	st "github.com/kubewharf/godel-scheduler/pkg/scheduler/testing"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeNode(name, pools string) *v1.Node {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("32"),
+			},
+		},
+	}
+	if len(pools) > 0 {
+		node.Annotations[util.LocalStoragePoolsAnnotationKey] = pools
+	}
+	return node
+}
+
+func makePod(name, nodeName, requests string, claims ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("uid-" + name),
+			Annotations: map[string]string{
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []v1.Container{{}},
+		},
+	}
+	if len(requests) > 0 {
+		pod.Annotations[podutil.LocalStorageRequestsAnnotationKey] = requests
+	}
+	for _, claim := range claims {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name:         claim,
+			VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		})
+	}
+	return pod
+}
+
+func makePVC(name, storageClass, storage, volumeName string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			VolumeName:       volumeName,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(storage)},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *config.LocalStoragePoolCheckerArgs
+		wantErr bool
+	}{
+		{name: "most available policy", args: &config.LocalStoragePoolCheckerArgs{ScorePolicy: config.ScorePolicyMostAvailable}},
+		{name: "least available policy", args: &config.LocalStoragePoolCheckerArgs{ScorePolicy: config.ScorePolicyLeastAvailable}},
+		{name: "invalid policy", args: &config.LocalStoragePoolCheckerArgs{ScorePolicy: "Random"}, wantErr: true},
+		{
+			name: "invalid weight",
+			args: &config.LocalStoragePoolCheckerArgs{
+				ScorePolicy:         config.ScorePolicyMostAvailable,
+				StorageClassWeights: []config.ResourceSpec{{Name: "local-lvm", Weight: 0}},
+			},
+			wantErr: true,
+		},
+	}
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	fh, _ := st.NewSchedulerFrameworkHandle(nil, nil, informerFactory, nil, nil, nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.args, fh)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLocalStoragePoolChecker(t *testing.T) {
+	defer utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.LocalStoragePoolScheduling): false})
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.LocalStoragePoolScheduling): true})
+
+	nodes := []*v1.Node{
+		makeNode("node-1", "local-lvm=100Gi"),
+		makeNode("node-2", "local-lvm=100Gi,local-nvme=1Ti"),
+		makeNode("node-3", ""),
+	}
+	existingPods := []*v1.Pod{
+		makePod("existing", "node-1", "local-lvm=60Gi"),
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("small", "local-lvm", "30Gi", ""),
+		makePVC("large", "local-lvm", "50Gi", ""),
+		makePVC("bound", "local-lvm", "50Gi", "pv-bound"),
+		makePVC("remote", "ceph", "1Ti", ""),
+	}
+
+	type nodeResult struct {
+		code     framework.Code
+		requests string
+		score    int64
+	}
+	tests := []struct {
+		name     string
+		policy   config.ScorePolicy
+		pod      *v1.Pod
+		expected map[string]nodeResult
+	}{
+		{
+			name:   "most available",
+			policy: config.ScorePolicyMostAvailable,
+			pod:    makePod("p", "", "", "small"),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success, requests: "local-lvm=30Gi", score: 10},
+				"node-2": {code: framework.Success, requests: "local-lvm=30Gi", score: 70},
+				"node-3": {code: framework.Unschedulable},
+			},
+		},
+		{
+			name:   "least available",
+			policy: config.ScorePolicyLeastAvailable,
+			pod:    makePod("p", "", "", "small"),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success, requests: "local-lvm=30Gi", score: 90},
+				"node-2": {code: framework.Success, requests: "local-lvm=30Gi", score: 30},
+				"node-3": {code: framework.Unschedulable},
+			},
+		},
+		{
+			name:   "insufficient free capacity",
+			policy: config.ScorePolicyMostAvailable,
+			pod:    makePod("p", "", "", "large", "remote"),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Unschedulable},
+				"node-2": {code: framework.Success, requests: "local-lvm=50Gi", score: 50},
+				"node-3": {code: framework.Unschedulable},
+			},
+		},
+		{
+			name:   "provisioned volumes are accounted but don't need free capacity",
+			policy: config.ScorePolicyMostAvailable,
+			pod:    makePod("p", "", "", "bound"),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success, requests: "local-lvm=50Gi"},
+				"node-2": {code: framework.Success, requests: "local-lvm=50Gi"},
+				"node-3": {code: framework.Success, requests: "local-lvm=50Gi"},
+			},
+		},
+		{
+			name:   "pod without local volumes",
+			policy: config.ScorePolicyMostAvailable,
+			pod:    makePod("p", "", "", "remote"),
+			expected: map[string]nodeResult{
+				"node-1": {code: framework.Success},
+				"node-2": {code: framework.Success},
+				"node-3": {code: framework.Success},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := godelcache.New(handler.MakeCacheHandlerWrapper().
+				SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+				TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+				EnableStore("PreemptionStore").
+				Obj())
+			snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+				SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+				EnableStore("PreemptionStore").
+				Obj())
+			for _, n := range nodes {
+				cache.AddNode(n)
+			}
+			for _, p := range existingPods {
+				cache.AddPod(p)
+			}
+			cache.UpdateSnapshot(snapshot)
+
+			informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+			for _, pvc := range pvcs {
+				informerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+			}
+			fh, _ := st.NewSchedulerFrameworkHandle(nil, nil, informerFactory, nil, nil, snapshot, nil, nil, nil, nil)
+			p, err := New(&config.LocalStoragePoolCheckerArgs{
+				ScorePolicy:         tt.policy,
+				StorageClassWeights: []config.ResourceSpec{{Name: "local-lvm", Weight: 1}, {Name: "local-nvme", Weight: 2}},
+			}, fh)
+			if err != nil {
+				t.Fatalf("failed to create plugin: %v", err)
+			}
+			pl := p.(*LocalStoragePoolChecker)
+
+			cycleState := framework.NewCycleState()
+			if status := pl.PreFilter(context.Background(), cycleState, tt.pod); !status.IsSuccess() {
+				t.Fatalf("unexpected prefilter status: %v", status)
+			}
+			for nodeName, expected := range tt.expected {
+				nodeInfo, err := snapshot.NodeInfos().Get(nodeName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				status := pl.Filter(context.Background(), cycleState, tt.pod, nodeInfo)
+				if status.Code() != expected.code {
+					t.Errorf("node %v: expected code %v, got %v", nodeName, expected.code, status.Code())
+				}
+				if got := framework.GetPodAnnotationsOnNode(cycleState, nodeName)[podutil.LocalStorageRequestsAnnotationKey]; got != expected.requests {
+					t.Errorf("node %v: expected requests %q, got %q", nodeName, expected.requests, got)
+				}
+				if !status.IsSuccess() {
+					continue
+				}
+				score, status := pl.Score(context.Background(), cycleState, tt.pod, nodeName)
+				if !status.IsSuccess() {
+					t.Errorf("node %v: unexpected score status: %v", nodeName, status)
+				}
+				if score != expected.score {
+					t.Errorf("node %v: expected score %v, got %v", nodeName, expected.score, score)
+				}
+			}
+		})
+	}
+}
+
+func TestPreFilterMissingPVC(t *testing.T) {
+	defer utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.LocalStoragePoolScheduling): false})
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.LocalStoragePoolScheduling): true})
+
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	fh, _ := st.NewSchedulerFrameworkHandle(nil, nil, informerFactory, nil, nil, nil, nil, nil, nil, nil)
+	p, err := New(&config.LocalStoragePoolCheckerArgs{ScorePolicy: config.ScorePolicyMostAvailable}, fh)
+	if err != nil {
+		t.Fatalf("failed to create plugin: %v", err)
+	}
+	status := p.(*LocalStoragePoolChecker).PreFilter(context.Background(), framework.NewCycleState(), makePod("p", "", "", "missing"))
+	if status.Code() != framework.UnschedulableAndUnresolvable {
+		t.Errorf("expected code %v, got %v", framework.UnschedulableAndUnresolvable, status.Code())
+	}
+}
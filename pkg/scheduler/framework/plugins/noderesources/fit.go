@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -81,6 +82,7 @@ func newPreFilterState(state *utils.PodRequest) *preFilterState {
 
 // Fit is a plugin that checks if a node has sufficient resources.
 type Fit struct {
+	handle                framework.SchedulerFrameworkHandle
 	ignoredResources      sets.String
 	ignoredResourceGroups sets.String
 }
@@ -127,7 +129,7 @@ func validateLabelName(labelName string, fldPath *field.Path) field.ErrorList {
 }
 
 // NewFit initializes a new plugin and returns it.
-func NewFit(plArgs runtime.Object, _ framework.SchedulerFrameworkHandle) (framework.Plugin, error) {
+func NewFit(plArgs runtime.Object, handle framework.SchedulerFrameworkHandle) (framework.Plugin, error) {
 	args, err := getFitArgs(plArgs)
 	if err != nil {
 		return nil, err
@@ -138,6 +140,7 @@ func NewFit(plArgs runtime.Object, _ framework.SchedulerFrameworkHandle) (framew
 	}
 
 	return &Fit{
+		handle:                handle,
 		ignoredResources:      sets.NewString(args.IgnoredResources...),
 		ignoredResourceGroups: sets.NewString(args.IgnoredResourceGroups...),
 	}, nil
@@ -197,7 +200,14 @@ func (f *Fit) Filter(_ context.Context, cycleState *framework.CycleState, pod *v
 		return status
 	}
 
-	insufficientResources := utils.FitsRequest(s.getPodRequest(), nodeInfo, f.ignoredResources, f.ignoredResourceGroups)
+	// The resources reserved for other units are hidden from the pod.
+	var reserved *framework.Resource
+	if f.handle != nil {
+		if reservations := f.handle.GetNodeReservations(nodeInfo.GetNodeName()); len(reservations) > 0 {
+			reserved = framework.GetReservedResource(reservations, nodeInfo, pod, time.Now())
+		}
+	}
+	insufficientResources := utils.FitsRequestWithReserved(s.getPodRequest(), nodeInfo, reserved, f.ignoredResources, f.ignoredResourceGroups)
 
 	if len(insufficientResources) != 0 {
 		// We will keep all failure reasons.
@@ -22,15 +22,22 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ktypes "k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	utils "github.com/kubewharf/godel-scheduler/pkg/plugins/noderesources"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	testutil "github.com/kubewharf/godel-scheduler/pkg/scheduler/testing"
 	v1helper "github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
@@ -485,6 +492,92 @@ func TestEnoughRequests(t *testing.T) {
 	}
 }
 
+func TestFitWithReservations(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, godelfeatures.ResourceReservation, true)()
+
+	reservations, err := framework.FormatResourceReservations([]*framework.ResourceReservation{{
+		Name:         "r",
+		Owner:        "default/pg",
+		Resources:    v1.ResourceList{v1.ResourceCPU: *resource.NewMilliQuantity(6, resource.DecimalSI)},
+		CreationTime: metav1.Now(),
+		TTLSeconds:   60,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPodGroupPod := func(name, pgName string, usage framework.Resource) *v1.Pod {
+		pod := newResourcePod(usage)
+		pod.Namespace, pod.Name, pod.UID = "default", name, ktypes.UID(name)
+		if len(pgName) > 0 {
+			pod.Annotations[podutil.PodGroupNameAnnotationKey] = pgName
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name         string
+		existingPods []*v1.Pod
+		pod          *v1.Pod
+		wantStatus   *framework.Status
+	}{
+		{
+			name:       "reserved resources are hidden from the pods of other units",
+			pod:        newPodGroupPod("p", "", framework.Resource{MilliCPU: 5}),
+			wantStatus: framework.NewStatus(framework.Unschedulable, getErrReason(v1.ResourceCPU)),
+		},
+		{
+			name: "reserved resources are available to the pods of the owner",
+			pod:  newPodGroupPod("p", "pg", framework.Resource{MilliCPU: 5}),
+		},
+		{
+			name:         "reserved resources are released as the pods of the owner are placed",
+			existingPods: []*v1.Pod{newPodGroupPod("member", "pg", framework.Resource{MilliCPU: 4})},
+			pod:          newPodGroupPod("p", "", framework.Resource{MilliCPU: 4}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := godelcache.New(handler.MakeCacheHandlerWrapper().
+				SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+				TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+				EnableStore("PreemptionStore").
+				Obj())
+			snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+				SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+				EnableStore("PreemptionStore").
+				Obj())
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "n", Annotations: map[string]string{framework.ResourceReservationAnnotationKey: reservations}},
+				Status:     v1.NodeStatus{Capacity: makeResources(10, 20, 32, 5, 20, 5).Capacity, Allocatable: makeAllocatableResources(10, 20, 32, 5, 20, 5)},
+			}
+			cache.AddNode(node)
+			for _, pod := range test.existingPods {
+				pod.Spec.NodeName = node.Name
+				cache.AddPod(pod)
+			}
+			cache.UpdateSnapshot(snapshot)
+			if got := len(snapshot.GetNodeReservations(node.Name)); got != 1 {
+				t.Fatalf("expected 1 reservation on the node, got %v", got)
+			}
+
+			fh, _ := testutil.NewSchedulerFrameworkHandle(nil, nil, nil, nil, nil, snapshot, nil, nil, nil, nil)
+			p, err := NewFit(&config.NodeResourcesFitArgs{}, fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cycleState := framework.NewCycleState()
+			framework.SetPodResourceTypeState(podutil.GuaranteedPod, cycleState)
+			if status := p.(framework.PreFilterPlugin).PreFilter(context.Background(), cycleState, test.pod); !status.IsSuccess() {
+				t.Fatalf("prefilter failed with status: %v", status)
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(context.Background(), cycleState, test.pod, snapshot.GetNodeInfo(node.Name))
+			if !reflect.DeepEqual(gotStatus, test.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, test.wantStatus)
+			}
+		})
+	}
+}
+
 func TestPreFilterDisabled(t *testing.T) {
 	pod := &v1.Pod{}
 	nodeInfo := framework.NewNodeInfo()
@@ -92,10 +92,12 @@ func (pl *VolumeBinding) Filter(ctx context.Context, cs *framework.CycleState, p
 func New(_ runtime.Object, fh framework.SchedulerFrameworkHandle) (framework.Plugin, error) {
 	return &VolumeBinding{
 		binder: scheduling.NewBaseVolumeBinder(
+			fh.SharedInformerFactory().Core().V1().Nodes(),
 			fh.SharedInformerFactory().Storage().V1().CSINodes(),
 			fh.SharedInformerFactory().Core().V1().PersistentVolumeClaims(),
 			fh.SharedInformerFactory().Core().V1().PersistentVolumes(),
 			fh.SharedInformerFactory().Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(fh.SharedInformerFactory()),
 		),
 	}, nil
 }
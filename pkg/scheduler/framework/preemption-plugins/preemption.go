@@ -19,6 +19,7 @@ package preemptionplugins
 import (
 	"fmt"
 	"math"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -42,6 +43,13 @@ func HeuristicCheck(handle framework.SchedulerFrameworkHandle, pod *v1.Pod, node
 		resourceType, _ := podutil.GetPodResourceType(pod)
 		priority := GetPodPartitionPriority(pod)
 		podResource, _, _ := framework.CalculateResource(pod)
+		// The resources reserved for other units can't be occupied by preemption either.
+		if handle != nil && resourceType == podutil.GuaranteedPod {
+			reservations := handle.GetNodeReservations(node.GetNodeName())
+			if reserved := framework.GetReservedResource(reservations, node, pod, time.Now()); reserved != nil {
+				podResource.AddResource(reserved)
+			}
+		}
 		if !OccupiableResourcesCheck(priority, resourceType, podResource, node) {
 			return false
 		}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionbudgetchecker
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+)
+
+const (
+	PreemptionBudgetCheckerName    = "PreemptionBudgetChecker"
+	SearchingPreemptionBudgetKey   = "Searching-" + PreemptionBudgetCheckerName
+	preemptionBudgetExhaustedError = "preemption budget %v exhausted"
+)
+
+// PreemptionBudgetChecker stops nominating victims once the preemption budgets are exhausted by the victims
+// nominated by all the schedulers in the last minute, which are tracked by the preemption store. The budgets
+// are enforced by the binders before deleting the victims.
+type PreemptionBudgetChecker struct {
+	handle framework.SchedulerFrameworkHandle
+	budget *preemptionbudget.Budget
+}
+
+var (
+	_ framework.ClusterPrePreemptingPlugin = &PreemptionBudgetChecker{}
+	_ framework.NodePrePreemptingPlugin    = &PreemptionBudgetChecker{}
+	_ framework.VictimSearchingPlugin      = &PreemptionBudgetChecker{}
+)
+
+// NewPreemptionBudgetChecker initializes a new plugin and returns it.
+func NewPreemptionBudgetChecker(plArgs runtime.Object, handle framework.SchedulerFrameworkHandle) (framework.Plugin, error) {
+	args, err := getPreemptionBudgetArgs(plArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to new PreemptionBudgetChecker plugin: %v", err)
+	}
+	budget := &preemptionbudget.Budget{}
+	if args != nil {
+		budget = &args.Budget
+	}
+	if errs := budget.Validate(field.NewPath("args")); len(errs) > 0 {
+		return nil, fmt.Errorf("failed to new PreemptionBudgetChecker plugin: %v", errs.ToAggregate())
+	}
+	return &PreemptionBudgetChecker{
+		handle: handle,
+		budget: budget,
+	}, nil
+}
+
+func (c *PreemptionBudgetChecker) Name() string {
+	return PreemptionBudgetCheckerName
+}
+
+func (c *PreemptionBudgetChecker) ClusterPrePreempting(preemptor *v1.Pod, state, _ *framework.CycleState) *framework.Status {
+	usage := c.handle.SnapshotSharedLister().GetPreemptionBudgetUsage(c.budget)
+	if key, exhausted := c.budget.PriorityBandExhausted(usage, podutil.GetPodPriority(preemptor)); exhausted {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf(preemptionBudgetExhaustedError, key))
+	}
+	state.Write(SearchingPreemptionBudgetKey, &preemptionBudgetState{usage: usage})
+	return nil
+}
+
+func (c *PreemptionBudgetChecker) NodePrePreempting(_ *v1.Pod, nodeInfo framework.NodeInfo, state, _ *framework.CycleState) *framework.Status {
+	s, err := getPreemptionBudgetState(state)
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	if node := nodeInfo.GetNode(); node != nil {
+		if key, exhausted := c.budget.NodePoolExhausted(s.usage, node.Labels); exhausted {
+			return framework.NewStatus(framework.Unschedulable, fmt.Sprintf(preemptionBudgetExhaustedError, key))
+		}
+	}
+	return nil
+}
+
+func (c *PreemptionBudgetChecker) VictimSearching(preemptor *v1.Pod, podInfo *framework.PodInfo, state, _ *framework.CycleState, _ *framework.VictimState) (framework.Code, string) {
+	s, err := getPreemptionBudgetState(state)
+	if err != nil {
+		return framework.Error, err.Error()
+	}
+	victim := preemptionbudget.Victim{
+		Namespace:         podInfo.Pod.Namespace,
+		PreemptorPriority: podutil.GetPodPriority(preemptor),
+	}
+	if key, exhausted := c.budget.Exhausted(s.usage, victim); exhausted {
+		return framework.PreemptionFail, fmt.Sprintf(preemptionBudgetExhaustedError, key)
+	}
+	return framework.PreemptionNotSure, ""
+}
+
+// preemptionBudgetState is the usage of the budgets when the preemption of the preemptor starts.
+type preemptionBudgetState struct {
+	usage preemptionbudget.Usage
+}
+
+// Clone the state, the usage is read-only.
+func (s *preemptionBudgetState) Clone() framework.StateData {
+	return s
+}
+
+func getPreemptionBudgetState(state *framework.CycleState) (*preemptionBudgetState, error) {
+	c, err := state.Read(SearchingPreemptionBudgetKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q from cycleState: %v", SearchingPreemptionBudgetKey, err)
+	}
+	s, ok := c.(*preemptionBudgetState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to PreemptionBudgetChecker.preemptionBudgetState error", c)
+	}
+	return s, nil
+}
+
+func getPreemptionBudgetArgs(obj runtime.Object) (*config.PreemptionBudgetArgs, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	ptr, ok := obj.(*config.PreemptionBudgetArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type PreemptionBudgetArgs, got %T", obj)
+	}
+	return ptr, nil
+}
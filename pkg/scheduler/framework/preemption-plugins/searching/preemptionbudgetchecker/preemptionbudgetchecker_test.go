@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionbudgetchecker
+
+import (
+	"testing"
+	"time"
+
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	schedulertesting "github.com/kubewharf/godel-scheduler/pkg/scheduler/testing"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
+)
+
+func TestPreemptionBudgetChecker(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdClient := godelclientfake.NewSimpleClientset()
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	schedulerCache := cache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore("PreemptionStore").
+		Obj())
+	snapshot := cache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		EnableStore("PreemptionStore").
+		Obj())
+
+	node := testing_helper.MakeNode().Name("n1").Label("pool", "a").Obj()
+	if err := schedulerCache.AddNode(node); err != nil {
+		t.Fatal(err)
+	}
+	// Two victims in namespace p1 have been nominated by a preemptor in band 100.
+	preemptor := testing_helper.MakePod().Namespace("foo").Name("foo").UID("foo").Priority(100).
+		Annotation(podutil.PodStateAnnotationKey, string(podutil.PodAssumed)).
+		Annotation(podutil.NominatedNodeAnnotationKey, "{\"node\":\"n1\",\"victims\":[{\"name\":\"v1\",\"namespace\":\"p1\",\"uid\":\"v1\"},{\"name\":\"v2\",\"namespace\":\"p1\",\"uid\":\"v2\"}]}").Obj()
+	if err := schedulerCache.AssumePod(framework.MakeCachePodInfoWrapper().Pod(preemptor).Obj()); err != nil {
+		t.Fatal(err)
+	}
+	schedulerCache.UpdateSnapshot(snapshot)
+	fh, err := schedulertesting.NewSchedulerFrameworkHandle(client, crdClient, informerFactory, crdInformerFactory, schedulerCache, snapshot, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := &config.PreemptionBudgetArgs{
+		Budget: preemptionbudget.Budget{
+			PriorityBands: []preemptionbudget.PriorityBand{
+				{MinPriority: 100, MaxVictimsPerMinute: 2},
+				{MinPriority: 1000, MaxVictimsPerMinute: 10},
+			},
+			MaxVictimsPerMinutePerNamespace: 2,
+			NodePoolLabelKey:                "pool",
+			MaxVictimsPerMinutePerNodePool:  3,
+		},
+	}
+	pl, err := NewPreemptionBudgetChecker(args, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := pl.(*PreemptionBudgetChecker)
+
+	// The budget of band 100 is exhausted.
+	state := framework.NewCycleState()
+	lowPreemptor := testing_helper.MakePod().Namespace("bar").Name("bar").UID("bar").Priority(200).Obj()
+	if status := checker.ClusterPrePreempting(lowPreemptor, state, framework.NewCycleState()); status.Code() != framework.Unschedulable {
+		t.Errorf("expected preemption to be unschedulable, got %v", status)
+	}
+
+	state = framework.NewCycleState()
+	highPreemptor := testing_helper.MakePod().Namespace("bar").Name("bar").UID("bar").Priority(1000).Obj()
+	if status := checker.ClusterPrePreempting(highPreemptor, state, framework.NewCycleState()); status != nil {
+		t.Fatalf("unexpected status: %v", status)
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	if status := checker.NodePrePreempting(highPreemptor, nodeInfo, state, framework.NewCycleState()); status != nil {
+		t.Errorf("unexpected status: %v", status)
+	}
+
+	tests := []struct {
+		name         string
+		victim       string
+		expectedCode framework.Code
+	}{
+		{
+			name:         "namespace budget exhausted",
+			victim:       "p1",
+			expectedCode: framework.PreemptionFail,
+		},
+		{
+			name:         "namespace budget not exhausted",
+			victim:       "p2",
+			expectedCode: framework.PreemptionNotSure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podInfo := framework.NewPodInfo(testing_helper.MakePod().Namespace(tt.victim).Name("v").UID("v").Obj())
+			if code, msg := checker.VictimSearching(highPreemptor, podInfo, state, nil, nil); code != tt.expectedCode {
+				t.Errorf("expected code %v, got %v: %v", tt.expectedCode, code, msg)
+			}
+		})
+	}
+
+	// Exhaust the budget of node pool a.
+	args.MaxVictimsPerMinutePerNodePool = 2
+	if status := checker.NodePrePreempting(highPreemptor, nodeInfo, state, framework.NewCycleState()); status.Code() != framework.Unschedulable {
+		t.Errorf("expected node pool to be exhausted, got %v", status)
+	}
+}
@@ -24,8 +24,10 @@ import (
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/coscheduling"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/gpushare"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/imagelocality"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/loadaware"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/localstoragepool"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeaffinity"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodelabel"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeports"
@@ -41,6 +43,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/pdbchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/podlauncherchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/preemptibilitychecker"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/preemptionbudgetchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/priorityvaluechecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/sorting/priority"
 	starttime "github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/sorting/start_time"
@@ -81,6 +84,8 @@ func NewOrderedPluginRegistry() framework.PluginList {
 			nodevolumelimits.GCEPDName,
 			nodevolumelimits.EBSName,
 			nonnativeresource.NonNativeTopologyName,
+			gpushare.Name,
+			localstoragepool.Name,
 
 			// always Success
 			coscheduling.Name,
@@ -120,7 +125,9 @@ func NewInTreeRegistry() Registry {
 		noderesources.AdaptiveCpuToMemRatioName: noderesources.NewAdaptiveCpuToMemRatio,
 		noderesources.NodeResourcesAffinityName: noderesources.NewNodeResourcesAffinity,
 
-		loadaware.Name: loadaware.NewLoadAware,
+		loadaware.Name:        loadaware.NewLoadAware,
+		gpushare.Name:         gpushare.New,
+		localstoragepool.Name: localstoragepool.New,
 	}
 }
 
@@ -132,6 +139,7 @@ func NewInTreePreemptionRegistry() Registry {
 		pdbchecker.PDBCheckerName:                                       pdbchecker.NewPDBChecker,
 		priorityvaluechecker.PriorityValueCheckerName:                   priorityvaluechecker.NewPriorityValueChecker,
 		newlystartedprotectionchecker.NewlyStartedProtectionCheckerName: newlystartedprotectionchecker.NewNewlyStartedProtectionChecker,
+		preemptionbudgetchecker.PreemptionBudgetCheckerName:             preemptionbudgetchecker.NewPreemptionBudgetChecker,
 		// sorting plugins
 		priority.MinHighestPriorityName:       priority.NewMinHighestPriority,
 		priority.MinPrioritySumName:           priority.NewMinPrioritySum,
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networktopology
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	Name = "NetworkTopology"
+
+	// DefaultMaxDomainsPerLevel is the default max number of candidate topology domains returned for each level.
+	DefaultMaxDomainsPerLevel = 3
+)
+
+// NetworkTopology places the PodGroup units as tight as possible in the multi-level network topology
+// (node -> ToR -> spine -> superpod, etc.). It takes charge of the units whose affinity terms are all
+// defined on the configured topology levels, and searches from the smallest topology domain upward
+// for the domains that can fit the MinMember of the unit. The required affinity term caps the largest
+// level that can be used, and the preferred affinity terms allow to fall back to the whole cluster.
+type NetworkTopology struct {
+	handler            framework.SchedulerUnitFrameworkHandle
+	model              *topologyModel
+	maxDomainsPerLevel int
+}
+
+var (
+	_ framework.LocatingPlugin = &NetworkTopology{}
+	_ framework.GroupingPlugin = &NetworkTopology{}
+)
+
+func New(plArgs runtime.Object, handler framework.SchedulerUnitFrameworkHandle) (framework.Plugin, error) {
+	var levels []string
+	maxDomainsPerLevel := int32(DefaultMaxDomainsPerLevel)
+	if plArgs != nil {
+		args, ok := plArgs.(*config.NetworkTopologyArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type NetworkTopologyArgs, got %T", plArgs)
+		}
+		levels = args.TopologyLevels
+		if args.MaxDomainsPerLevel != nil {
+			maxDomainsPerLevel = *args.MaxDomainsPerLevel
+		}
+	}
+	if maxDomainsPerLevel <= 0 {
+		return nil, fmt.Errorf("invalid args of %v: maxDomainsPerLevel %v", Name, maxDomainsPerLevel)
+	}
+	model, err := newTopologyModel(levels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid args of %v: %v", Name, err)
+	}
+	return &NetworkTopology{
+		handler:            handler,
+		model:              model,
+		maxDomainsPerLevel: int(maxDomainsPerLevel),
+	}, nil
+}
+
+func (pl *NetworkTopology) Name() string {
+	return Name
+}
+
+// TopologyAware checks whether the unit should be placed by NetworkTopology, which means all the
+// affinity terms of the unit are defined on the configured topology levels.
+func (pl *NetworkTopology) TopologyAware(unit framework.ScheduleUnit) bool {
+	if unit == nil || unit.Type() == framework.SinglePodUnitType || pl.model.empty() {
+		return false
+	}
+	required, err := unit.GetRequiredAffinity()
+	if err != nil {
+		return false
+	}
+	preferred, err := unit.GetPreferredAffinity()
+	if err != nil {
+		return false
+	}
+	if len(required)+len(preferred) == 0 {
+		return false
+	}
+	for _, terms := range [][]framework.UnitAffinityTerm{required, preferred} {
+		for _, term := range terms {
+			if pl.model.levelOf(term.TopologyKey) < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// maxLevel returns the largest level the unit can be placed in, and whether the level is required.
+func (pl *NetworkTopology) maxLevel(unit framework.ScheduleUnit) (int, bool) {
+	required, _ := unit.GetRequiredAffinity()
+	if len(required) == 0 {
+		return pl.model.topLevel(), false
+	}
+	level := pl.model.topLevel()
+	for _, term := range required {
+		if l := pl.model.levelOf(term.TopologyKey); l < level {
+			level = l
+		}
+	}
+	return level, true
+}
+
+func (pl *NetworkTopology) Locating(ctx context.Context, unit framework.ScheduleUnit, unitCycleState *framework.CycleState, nodeGroup framework.NodeGroup) (framework.NodeGroup, *framework.Status) {
+	if !pl.TopologyAware(unit) {
+		return nodeGroup, nil
+	}
+	level, required := pl.maxLevel(unit)
+	if !required {
+		return nodeGroup, nil
+	}
+
+	podLauncher, err := podutil.GetPodLauncher(unit.GetPods()[0].Pod)
+	if err != nil {
+		return nil, framework.AsStatus(fmt.Errorf("pod launcher in unit %v is invalid: %v", unit.GetKey(), err))
+	}
+
+	// nodes without the label of the required level can never be placed in the required topology domain.
+	return framework.FilterNodeGroup(nodeGroup, func(ni framework.NodeInfo) bool {
+		_, ok := pl.model.domainOf(ni.GetNodeLabels(podLauncher), ni.GetNodeName(), level)
+		return ok
+	}), nil
+}
+
+func (pl *NetworkTopology) Grouping(ctx context.Context, unit framework.ScheduleUnit, unitCycleState *framework.CycleState, nodeGroup framework.NodeGroup) ([]framework.NodeGroup, *framework.Status) {
+	if !pl.TopologyAware(unit) {
+		return []framework.NodeGroup{nodeGroup}, nil
+	}
+	nodeCircles := nodeGroup.GetNodeCircles()
+	if len(nodeCircles) == 0 {
+		klog.InfoS("No available node circles found in NetworkTopology Grouping", "unitKey", unit.GetKey())
+		return []framework.NodeGroup{nodeGroup}, nil
+	}
+
+	pod := unit.GetPods()[0].Pod
+	podLauncher, err := podutil.GetPodLauncher(pod)
+	if err != nil {
+		return nil, framework.AsStatus(fmt.Errorf("pod launcher in unit %v is invalid: %v", unit.GetKey(), err))
+	}
+	resourceType, err := podutil.GetPodResourceType(pod)
+	if err != nil {
+		return nil, framework.AsStatus(fmt.Errorf("resource type of pod in unit %v is invalid: %v", unit.GetKey(), err))
+	}
+
+	runningPods := pl.getRunningPodsOfUnit(unit)
+	assignedNodes := sets.NewString()
+	for _, pod := range runningPods {
+		if len(pod.Spec.NodeName) != 0 {
+			assignedNodes.Insert(pod.Spec.NodeName)
+		}
+	}
+	needed := pl.getNeededMembers(unit, len(runningPods))
+	level, required := pl.maxLevel(unit)
+
+	nodes := nodeCircles[0].List()
+	assignedLabels := make(map[string]map[string]string, assignedNodes.Len())
+	for nodeName := range assignedNodes {
+		if nodeInfo, err := nodeCircles[0].Get(nodeName); err == nil {
+			assignedLabels[nodeName] = nodeInfo.GetNodeLabels(podLauncher)
+		} else if nodeInfo := pl.handler.GetNodeInfo(nodeName); nodeInfo != nil {
+			assignedLabels[nodeName] = nodeInfo.GetNodeLabels(podLauncher)
+		}
+	}
+
+	search := &domainSearch{
+		model:              pl.model,
+		podLauncher:        podLauncher,
+		request:            computePodRequest(pod),
+		resourceType:       resourceType,
+		assignedLabels:     assignedLabels,
+		needed:             needed,
+		maxLevel:           level,
+		maxDomainsPerLevel: pl.maxDomainsPerLevel,
+	}
+	domains := search.find(nodes, required)
+
+	nodeGroups := make([]framework.NodeGroup, 0, len(domains)+1)
+	for _, d := range domains {
+		nodeGroups = append(nodeGroups, d.nodeGroup(nodeGroup.GetPreferredNodes()))
+	}
+	if !required {
+		// the preferred affinity allows the unit to be placed anywhere as the last resort.
+		nodeGroups = append(nodeGroups, nodeGroup)
+	}
+	if len(nodeGroups) == 0 {
+		return nil, framework.AsStatus(fmt.Errorf("no topology domain found for unit %v", unit.GetKey()))
+	}
+
+	klog.InfoS("NetworkTopology Grouping for ScheduleUnit got nodeGroups", "unitKey", unit.GetKey(), "neededMembers", needed, "nodeGroups", printDomains(domains))
+	return nodeGroups, nil
+}
+
+// getRunningPodsOfUnit returns the running pods in the unit.
+func (pl *NetworkTopology) getRunningPodsOfUnit(unit framework.ScheduleUnit) []*v1.Pod {
+	unitStatus := pl.handler.GetUnitStatus(unit.GetKey())
+	if unitStatus == nil {
+		return nil
+	}
+	return unitStatus.GetRunningPods()
+}
+
+// getNeededMembers returns the number of pods that should be placed together in this scheduling attempt.
+func (pl *NetworkTopology) getNeededMembers(unit framework.ScheduleUnit, runningPods int) int {
+	minMember, err := unit.GetMinMember()
+	if err != nil || minMember-runningPods <= 0 {
+		return unit.NumPods()
+	}
+	if needed := minMember - runningPods; needed < unit.NumPods() {
+		return needed
+	}
+	return unit.NumPods()
+}
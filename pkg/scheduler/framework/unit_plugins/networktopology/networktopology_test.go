@@ -0,0 +1,336 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networktopology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/api/fake"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/testing/fakehandle"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeNode(name, tor, spine, cpu string) *v1.Node {
+	labels := map[string]string{}
+	if len(tor) > 0 {
+		labels["tor"] = tor
+	}
+	if len(spine) > 0 {
+		labels["spine"] = spine
+	}
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:  resource.MustParse(cpu),
+				v1.ResourcePods: resource.MustParse("110"),
+			},
+		},
+	}
+}
+
+func makeUnit(minMember, pods int32, required, preferred []string) *framework.QueuedUnitInfo {
+	pg := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"},
+		Spec: v1alpha1.PodGroupSpec{
+			MinMember: minMember,
+			Affinity: &v1alpha1.Affinity{
+				PodGroupAffinity: &v1alpha1.PodGroupAffinity{},
+			},
+		},
+	}
+	for _, key := range required {
+		pg.Spec.Affinity.PodGroupAffinity.Required = append(pg.Spec.Affinity.PodGroupAffinity.Required, v1alpha1.PodGroupAffinityTerm{TopologyKey: key})
+	}
+	for _, key := range preferred {
+		pg.Spec.Affinity.PodGroupAffinity.Preferred = append(pg.Spec.Affinity.PodGroupAffinity.Preferred, v1alpha1.PodGroupAffinityTerm{TopologyKey: key})
+	}
+	unit := &framework.QueuedUnitInfo{ScheduleUnit: framework.NewPodGroupUnit(pg, 100)}
+	for i := int32(0); i < pods; i++ {
+		unit.AddPod(&framework.QueuedPodInfo{
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      fmt.Sprintf("pod-%d", i),
+					UID:       types.UID(fmt.Sprintf("pod-%d", i)),
+					Annotations: map[string]string{
+						podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+						podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+						},
+					}},
+				},
+			},
+		})
+	}
+	return unit
+}
+
+func newTestPlugin(t *testing.T, levels ...string) *NetworkTopology {
+	cache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore("PreemptionStore").
+		Obj())
+	pl, err := New(&config.NetworkTopologyArgs{TopologyLevels: levels}, &fakehandle.MockUnitSchedulerHandle{Cache: cache})
+	if err != nil {
+		t.Fatalf("failed to create plugin: %v", err)
+	}
+	return pl.(*NetworkTopology)
+}
+
+func TestNew(t *testing.T) {
+	zero := int32(0)
+	tests := []struct {
+		name    string
+		args    runtime.Object
+		wantErr bool
+	}{
+		{name: "nil args", args: nil},
+		{name: "valid args", args: &config.NetworkTopologyArgs{TopologyLevels: []string{"tor", "spine"}}},
+		{name: "wrong args type", args: &config.NodeLabelArgs{}, wantErr: true},
+		{name: "empty level", args: &config.NetworkTopologyArgs{TopologyLevels: []string{"tor", ""}}, wantErr: true},
+		{name: "duplicated level", args: &config.NetworkTopologyArgs{TopologyLevels: []string{"tor", "tor"}}, wantErr: true},
+		{name: "invalid maxDomainsPerLevel", args: &config.NetworkTopologyArgs{MaxDomainsPerLevel: &zero}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.args, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTopologyAware(t *testing.T) {
+	pl := newTestPlugin(t, "tor", "spine")
+	tests := []struct {
+		name string
+		unit framework.ScheduleUnit
+		want bool
+	}{
+		{
+			name: "single pod unit",
+			unit: framework.NewSinglePodUnit(&framework.QueuedPodInfo{Pod: &v1.Pod{}}),
+			want: false,
+		},
+		{
+			name: "no affinity",
+			unit: makeUnit(1, 1, nil, nil),
+			want: false,
+		},
+		{
+			name: "all terms on topology levels",
+			unit: makeUnit(1, 1, []string{"spine"}, []string{"tor", v1.LabelHostname}),
+			want: true,
+		},
+		{
+			name: "term not on topology levels",
+			unit: makeUnit(1, 1, []string{"spine"}, []string{"zone"}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pl.TopologyAware(tt.unit); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if newTestPlugin(t).TopologyAware(makeUnit(1, 1, []string{v1.LabelHostname}, nil)) {
+		t.Errorf("expected unit not to be topology aware without configured levels")
+	}
+}
+
+func TestGrouping(t *testing.T) {
+	// Each pod requests 2 cpu.
+	nodes := []*v1.Node{
+		makeNode("node-1", "tor1", "spine1", "4"),
+		makeNode("node-2", "tor1", "spine1", "4"),
+		makeNode("node-3", "tor2", "spine1", "2"),
+		makeNode("node-4", "tor3", "spine2", "8"),
+		makeNode("node-5", "", "", "8"),
+		makeNode("node-6", "tor4", "spine2", "6"),
+	}
+
+	tests := []struct {
+		name     string
+		unit     *framework.QueuedUnitInfo
+		expected []string
+	}{
+		{
+			name: "search from node to spine, then fall back to the whole cluster",
+			unit: makeUnit(2, 2, nil, []string{"tor"}),
+			expected: []string{
+				v1.LabelHostname + ":node-1",
+				v1.LabelHostname + ":node-2",
+				v1.LabelHostname + ":node-6",
+				"tor:tor1",
+				"tor:tor3",
+				"spine:spine1",
+				"spine:spine2",
+				framework.DefaultNodeGroupName,
+			},
+		},
+		{
+			name: "domains with less leftover come first",
+			unit: makeUnit(3, 3, nil, []string{"spine"}),
+			expected: []string{
+				v1.LabelHostname + ":node-6",
+				v1.LabelHostname + ":node-4",
+				v1.LabelHostname + ":node-5",
+				"tor:tor1",
+				"spine:spine1",
+				"spine:spine2",
+				framework.DefaultNodeGroupName,
+			},
+		},
+		{
+			name: "required level caps the search",
+			unit: makeUnit(4, 4, []string{"tor"}, nil),
+			expected: []string{
+				v1.LabelHostname + ":node-4",
+				"tor:tor1",
+			},
+		},
+		{
+			name: "only domains fitting min member are candidates",
+			unit: makeUnit(5, 5, []string{"spine"}, nil),
+			expected: []string{
+				"spine:spine1",
+				"spine:spine2",
+			},
+		},
+		{
+			name: "largest domains of the required level if none fits",
+			unit: makeUnit(6, 6, []string{"tor"}, nil),
+			expected: []string{
+				"tor:tor1",
+				"tor:tor3",
+				"tor:tor4",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := newTestPlugin(t, "tor", "spine")
+			nodeGroup := framework.NewNodeGroup(framework.DefaultNodeGroupName, []framework.NodeCircle{framework.NewNodeCircle(framework.DefaultNodeCircleName, fake.NewNodeInfoLister(nodes))})
+
+			nodeGroup, status := pl.Locating(context.Background(), tt.unit, framework.NewCycleState(), nodeGroup)
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected locating status: %v", status)
+			}
+			nodeGroups, status := pl.Grouping(context.Background(), tt.unit, framework.NewCycleState(), nodeGroup)
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected grouping status: %v", status)
+			}
+			got := make([]string, 0, len(nodeGroups))
+			for _, nodeGroup := range nodeGroups {
+				got = append(got, nodeGroup.GetKey())
+			}
+			expected := make([]string, 0, len(tt.expected))
+			for _, key := range tt.expected {
+				expected = append(expected, framework.GenerateReadableKey(key))
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Errorf("expected node groups %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestFindWithAssignedNodes(t *testing.T) {
+	model, err := newTopologyModel([]string{"tor", "spine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := []*v1.Node{
+		makeNode("node-1", "tor1", "spine1", "4"),
+		makeNode("node-2", "tor1", "spine1", "4"),
+		makeNode("node-3", "tor2", "spine1", "2"),
+		makeNode("node-4", "tor3", "spine2", "8"),
+	}
+	nodeInfos := fake.NewNodeInfoLister(nodes).List()
+
+	tests := []struct {
+		name          string
+		assignedNodes []*v1.Node
+		expected      []string
+	}{
+		{
+			name:     "no assigned nodes",
+			expected: []string{v1.LabelHostname + ":node-1", v1.LabelHostname + ":node-2", v1.LabelHostname + ":node-4", "tor:tor1", "spine:spine1"},
+		},
+		{
+			name:          "assigned node constrains the domains",
+			assignedNodes: []*v1.Node{nodes[2]},
+			expected:      []string{"spine:spine1"},
+		},
+		{
+			name:          "assigned nodes across spines",
+			assignedNodes: []*v1.Node{nodes[0], nodes[3]},
+			expected:      []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignedLabels := map[string]map[string]string{}
+			for _, node := range tt.assignedNodes {
+				assignedLabels[node.Name] = node.Labels
+			}
+			search := &domainSearch{
+				model:              model,
+				podLauncher:        podutil.Kubelet,
+				request:            &framework.Resource{MilliCPU: 2000},
+				resourceType:       podutil.GuaranteedPod,
+				assignedLabels:     assignedLabels,
+				needed:             2,
+				maxLevel:           model.topLevel(),
+				maxDomainsPerLevel: DefaultMaxDomainsPerLevel,
+			}
+			got := []string{}
+			for _, d := range search.find(nodeInfos, false) {
+				got = append(got, d.key)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected domains %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
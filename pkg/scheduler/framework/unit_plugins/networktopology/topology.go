@@ -0,0 +1,313 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networktopology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// nodeLevel is the lowest topology level, where each node is a domain by itself.
+const nodeLevel = 0
+
+// topologyModel describes the levels of the network topology. Level 0 is the node itself,
+// and level i (i > 0) is the topology domain defined by the i-th configured node label key.
+type topologyModel struct {
+	levels []string
+	index  map[string]int
+}
+
+func newTopologyModel(labelKeys []string) (*topologyModel, error) {
+	m := &topologyModel{
+		levels: []string{v1.LabelHostname},
+		index:  map[string]int{v1.LabelHostname: nodeLevel},
+	}
+	for _, key := range labelKeys {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("empty topology level")
+		}
+		if _, ok := m.index[key]; ok {
+			return nil, fmt.Errorf("duplicated topology level %v", key)
+		}
+		m.index[key] = len(m.levels)
+		m.levels = append(m.levels, key)
+	}
+	return m, nil
+}
+
+func (m *topologyModel) empty() bool {
+	return len(m.levels) <= 1
+}
+
+func (m *topologyModel) topLevel() int {
+	return len(m.levels) - 1
+}
+
+// levelOf returns the level of the topology key, -1 means the key is not a topology level.
+func (m *topologyModel) levelOf(topologyKey string) int {
+	if level, ok := m.index[topologyKey]; ok {
+		return level
+	}
+	return -1
+}
+
+// domainOf returns the key of the topology domain the node belongs to at the given level.
+func (m *topologyModel) domainOf(labels map[string]string, nodeName string, level int) (string, bool) {
+	if level == nodeLevel {
+		return m.levels[nodeLevel] + ":" + nodeName, true
+	}
+	value, ok := labels[m.levels[level]]
+	if !ok {
+		return "", false
+	}
+	return m.levels[level] + ":" + value, true
+}
+
+// hopDistance returns the max number of network hops between two nodes in the same domain of the given level.
+func hopDistance(level int) int {
+	return 2 * level
+}
+
+// ------------------------------------------------------------------------------------------
+
+// domain is a set of nodes belonging to the same topology domain.
+type domain struct {
+	level int
+	key   string
+	nodes []framework.NodeInfo
+	// capacity is the number of unit members that can fit in the domain.
+	capacity int
+	// leftover is the capacity left after placing the needed members, the smaller the less fragmentation.
+	leftover int
+}
+
+func (d *domain) nodeGroup(preferredNodes framework.PreferredNodes) framework.NodeGroup {
+	lister := &framework.NodeInfoListerImpl{NodeInfoMap: make(map[string]framework.NodeInfo, len(d.nodes))}
+	for _, node := range d.nodes {
+		lister.AddNodeInfo(node)
+	}
+	nodeGroup := framework.NewNodeGroup(d.key, []framework.NodeCircle{framework.NewNodeCircle(d.key, lister)})
+	if preferredNodes != nil {
+		nodeGroup.SetPreferredNodes(framework.FilterPreferredNodes(preferredNodes, func(ni framework.NodeInfo) bool {
+			_, ok := lister.NodeInfoMap[ni.GetNodeName()]
+			return ok
+		}))
+	}
+	return nodeGroup
+}
+
+// sameNodes checks whether the two domains contain the same nodes, which happens when a domain
+// only has one child domain. Domains of different levels are either disjoint or nested.
+func (d *domain) sameNodes(other *domain) bool {
+	if len(d.nodes) != len(other.nodes) || len(d.nodes) == 0 {
+		return false
+	}
+	name := d.nodes[0].GetNodeName()
+	for _, node := range other.nodes {
+		if node.GetNodeName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func printDomains(domains []*domain) string {
+	var builder strings.Builder
+	builder.WriteByte('[')
+	for _, d := range domains {
+		builder.WriteString(fmt.Sprintf("%v(hops=%v,capacity=%v);", d.key, hopDistance(d.level), d.capacity))
+	}
+	builder.WriteByte(']')
+	return builder.String()
+}
+
+// ------------------------------------------------------------------------------------------
+
+// domainSearch searches the candidate topology domains for a unit.
+type domainSearch struct {
+	model        *topologyModel
+	podLauncher  podutil.PodLauncher
+	request      *framework.Resource
+	resourceType podutil.PodResourceType
+	// assignedLabels are the labels of the nodes where the running pods of the unit are.
+	assignedLabels map[string]map[string]string
+	// needed is the number of members that should be placed.
+	needed             int
+	maxLevel           int
+	maxDomainsPerLevel int
+}
+
+// find searches from the smallest topology domain upward for the domains that can fit the needed
+// members. Candidates are ordered by hop distance first, then by leftover fragmentation. If none
+// of the domains fits and the max level is required, the largest domains of the max level are
+// returned, since the unit may still be placed after preemption.
+func (s *domainSearch) find(nodes []framework.NodeInfo, required bool) []*domain {
+	slots := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		slots[node.GetNodeName()] = s.slotsOf(node)
+	}
+
+	var result, largest []*domain
+	for level := nodeLevel; level <= s.maxLevel; level++ {
+		domains := s.groupByLevel(nodes, slots, level)
+
+		fitting := make([]*domain, 0, len(domains))
+		for _, d := range domains {
+			if d.capacity >= s.needed {
+				d.leftover = d.capacity - s.needed
+				fitting = append(fitting, d)
+			}
+		}
+		sort.SliceStable(fitting, func(i, j int) bool {
+			if fitting[i].leftover != fitting[j].leftover {
+				return fitting[i].leftover < fitting[j].leftover
+			}
+			return fitting[i].key < fitting[j].key
+		})
+
+		count := 0
+		for _, d := range fitting {
+			if count >= s.maxDomainsPerLevel {
+				break
+			}
+			if containsSameNodes(result, d) {
+				continue
+			}
+			result = append(result, d)
+			count++
+		}
+
+		if level == s.maxLevel {
+			largest = domains
+		}
+	}
+
+	if len(result) == 0 && required {
+		sort.SliceStable(largest, func(i, j int) bool {
+			if largest[i].capacity != largest[j].capacity {
+				return largest[i].capacity > largest[j].capacity
+			}
+			return largest[i].key < largest[j].key
+		})
+		if len(largest) > s.maxDomainsPerLevel {
+			largest = largest[:s.maxDomainsPerLevel]
+		}
+		result = largest
+	}
+	return result
+}
+
+// groupByLevel groups the nodes into the domains of the given level. If the unit has running pods,
+// only the domain where all of them are can be used.
+func (s *domainSearch) groupByLevel(nodes []framework.NodeInfo, slots map[string]int, level int) []*domain {
+	assignedDomain := ""
+	for nodeName, labels := range s.assignedLabels {
+		key, ok := s.model.domainOf(labels, nodeName, level)
+		if !ok || (len(assignedDomain) > 0 && key != assignedDomain) {
+			return nil
+		}
+		assignedDomain = key
+	}
+
+	domainsByKey := make(map[string]*domain)
+	domains := make([]*domain, 0)
+	for _, node := range nodes {
+		key, ok := s.model.domainOf(node.GetNodeLabels(s.podLauncher), node.GetNodeName(), level)
+		if !ok || (len(assignedDomain) > 0 && key != assignedDomain) {
+			continue
+		}
+		d, ok := domainsByKey[key]
+		if !ok {
+			d = &domain{level: level, key: key}
+			domainsByKey[key] = d
+			domains = append(domains, d)
+		}
+		d.nodes = append(d.nodes, node)
+		d.capacity += slots[node.GetNodeName()]
+	}
+	return domains
+}
+
+// slotsOf returns the number of members that can fit in the node according to the free resources.
+// If none of the resources limits it, the needed members are taken.
+func (s *domainSearch) slotsOf(node framework.NodeInfo) int {
+	var allocatable, requested *framework.Resource
+	switch s.resourceType {
+	case podutil.GuaranteedPod:
+		allocatable, requested = node.GetGuaranteedAllocatable(), node.GetGuaranteedRequested()
+	case podutil.BestEffortPod:
+		allocatable, requested = node.GetBestEffortAllocatable(), node.GetBestEffortRequested()
+	}
+	if allocatable == nil {
+		return 0
+	}
+	if requested == nil {
+		requested = &framework.Resource{}
+	}
+
+	slots, limited := 0, false
+	fit := func(capacity, used, request int64) {
+		if request <= 0 {
+			return
+		}
+		if n := int((capacity - used) / request); !limited || n < slots {
+			slots, limited = n, true
+		}
+	}
+	fit(allocatable.MilliCPU, requested.MilliCPU, s.request.MilliCPU)
+	fit(allocatable.Memory, requested.Memory, s.request.Memory)
+	for name, request := range s.request.ScalarResources {
+		fit(allocatable.ScalarResources[name], requested.ScalarResources[name], request)
+	}
+	if allocatable.AllowedPodNumber > 0 {
+		fit(int64(allocatable.AllowedPodNumber), int64(node.NumPods()), 1)
+	}
+	if !limited {
+		return s.needed
+	}
+	if slots < 0 {
+		return 0
+	}
+	return slots
+}
+
+func containsSameNodes(domains []*domain, d *domain) bool {
+	for _, existing := range domains {
+		if existing.sameNodes(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// computePodRequest returns the resource request of the pod, the max of init containers are taken into account.
+func computePodRequest(pod *v1.Pod) *framework.Resource {
+	result := &framework.Resource{}
+	for _, container := range pod.Spec.Containers {
+		result.Add(container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		result.SetMaxResource(container.Resources.Requests)
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+)
+
+const Name = "Reservation"
+
+// Reservation prefers the nodes having resources reserved for the unit, either for its PodGroup or for
+// the controller of its pods, so that the unit consumes its own reservations before taking any other capacity.
+type Reservation struct {
+	handler framework.SchedulerUnitFrameworkHandle
+}
+
+var _ framework.LocatingPlugin = &Reservation{}
+
+func New(_ runtime.Object, handler framework.SchedulerUnitFrameworkHandle) (framework.Plugin, error) {
+	return &Reservation{handler: handler}, nil
+}
+
+func (i *Reservation) Name() string {
+	return Name
+}
+
+func (i *Reservation) Locating(ctx context.Context, unit framework.ScheduleUnit, unitCycleState *framework.CycleState, nodeGroup framework.NodeGroup) (framework.NodeGroup, *framework.Status) {
+	pods := unit.GetPods()
+	if len(pods) == 0 {
+		return nodeGroup, nil
+	}
+	// All the pods of a unit belong to the same owner, so any of them tells the reservations of the unit.
+	nodeNames := i.handler.GetReservedNodes(pods[0].Pod)
+	if len(nodeNames) == 0 {
+		return nodeGroup, nil
+	}
+
+	preferredNodes := nodeGroup.GetPreferredNodes()
+	if preferredNodes == nil {
+		preferredNodes = framework.NewPreferredNodes()
+		nodeGroup.SetPreferredNodes(preferredNodes)
+	}
+	for _, nodeName := range nodeNames {
+		for _, nodeCircle := range nodeGroup.GetNodeCircles() {
+			if nodeInfo, err := nodeCircle.Get(nodeName); err == nil && nodeInfo != nil {
+				preferredNodes.Add(nodeInfo)
+				break
+			}
+		}
+	}
+	klog.V(4).InfoS("Reservation Locating for ScheduleUnit", "unitKey", unit.GetKey(), "reservedNodes", nodeNames)
+	return nodeGroup, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/testing/fakehandle"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeReservation(owner string, ownerUID types.UID, creationTime metav1.Time) *framework.ResourceReservation {
+	return &framework.ResourceReservation{
+		Name:         owner,
+		Owner:        owner,
+		OwnerUID:     ownerUID,
+		Resources:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+		CreationTime: creationTime,
+		TTLSeconds:   60,
+	}
+}
+
+func makeNode(t *testing.T, name string, reservations ...*framework.ResourceReservation) *v1.Node {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("8"), v1.ResourcePods: resource.MustParse("110")},
+		},
+	}
+	if len(reservations) > 0 {
+		value, err := framework.FormatResourceReservations(reservations)
+		if err != nil {
+			t.Fatal(err)
+		}
+		node.Annotations = map[string]string{framework.ResourceReservationAnnotationKey: value}
+	}
+	return node
+}
+
+func makePodGroupUnit(t *testing.T, pgName string) framework.ScheduleUnit {
+	unit := framework.NewPodGroupUnit(&v1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: pgName}}, 100)
+	if err := unit.AddPod(&framework.QueuedPodInfo{Pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        pgName + "-0",
+		Annotations: map[string]string{podutil.PodGroupNameAnnotationKey: pgName},
+	}}}); err != nil {
+		t.Fatal(err)
+	}
+	return unit
+}
+
+// makeReplicaSetPodUnit makes the unit of a pod created by the ReplicaSet "rs", whose UID is rsUID.
+func makeReplicaSetPodUnit(rsUID string, creationTime metav1.Time) framework.ScheduleUnit {
+	return framework.NewSinglePodUnit(&framework.QueuedPodInfo{Pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         "default",
+		Name:              "rs-0",
+		CreationTimestamp: creationTime,
+		OwnerReferences: []metav1.OwnerReference{
+			{Kind: "ReplicaSet", Name: "rs", UID: types.UID(rsUID), Controller: pointer.Bool(true)},
+		},
+	}}})
+}
+
+func TestLocating(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourceReservation, true)()
+
+	cache := godelcache.New(handler.MakeCacheHandlerWrapper().
+		SchedulerName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		TTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore("PreemptionStore").
+		Obj())
+	snapshot := godelcache.NewEmptySnapshot(handler.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		EnableStore("PreemptionStore").
+		Obj())
+	now := metav1.Now()
+	for _, node := range []*v1.Node{
+		makeNode(t, "n1", makeReservation("default/pg", "", now)),
+		makeNode(t, "n2", makeReservation("default/other", "", now), makeReservation("default/rs", "rs", now)),
+		makeNode(t, "n3", makeReservation("default/pg", "", now), makeReservation("default/other", "", now)),
+		makeNode(t, "n4"),
+	} {
+		cache.AddNode(node)
+	}
+	cache.UpdateSnapshot(snapshot)
+
+	pl, err := New(nil, fakehandle.NewMockUnitSchedulerHandle(cache, snapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		unit framework.ScheduleUnit
+		want []string
+	}{
+		{
+			name: "nodes reserved for the PodGroup are preferred",
+			unit: makePodGroupUnit(t, "pg"),
+			want: []string{"n1", "n3"},
+		},
+		{
+			name: "nothing is preferred for the PodGroup without reservations",
+			unit: makePodGroupUnit(t, "none"),
+		},
+		{
+			name: "nodes reserved for the ReplicaSet are preferred for the recreated pod",
+			unit: makeReplicaSetPodUnit("rs", metav1.NewTime(now.Add(time.Second))),
+			want: []string{"n2"},
+		},
+		{
+			name: "nothing is preferred for the pod created before the reservation",
+			unit: makeReplicaSetPodUnit("rs", metav1.NewTime(now.Add(-time.Second))),
+		},
+		{
+			name: "nothing is preferred for the pod of another ReplicaSet with the same key",
+			unit: makeReplicaSetPodUnit("rs-other", metav1.NewTime(now.Add(time.Second))),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeGroup := framework.NewNodeGroup(framework.DefaultNodeGroupName, []framework.NodeCircle{framework.NewNodeCircle(framework.DefaultNodeCircleName, snapshot.NodeInfos())})
+			nodeGroup, status := pl.(framework.LocatingPlugin).Locating(context.Background(), tt.unit, framework.NewCycleState(), nodeGroup)
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected locating status: %v", status)
+			}
+			var got []string
+			if preferredNodes := nodeGroup.GetPreferredNodes(); preferredNodes != nil {
+				for _, nodeInfo := range preferredNodes.List() {
+					got = append(got, nodeInfo.GetNodeName())
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected preferred nodes %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/joblevelaffinity"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/networktopology"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/interpretabity"
@@ -122,6 +123,11 @@ func (f *UnitFramework) RunGroupingPlugin(ctx context.Context, unit framework.Sc
 	switch {
 	case framework.UnitRequireJobLevelAffinity(unit):
 		groupingPlugin, ok := f.groupingPlugins[joblevelaffinity.Name]
+		// Units whose affinity terms are all configured network topology levels are grouped by
+		// NetworkTopology, which searches the tightest domain across the whole topology hierarchy.
+		if pl, exist := f.groupingPlugins[networktopology.Name].(*networktopology.NetworkTopology); exist && pl.TopologyAware(unit) {
+			groupingPlugin, ok = pl, true
+		}
 		if ok && groupingPlugin != nil {
 			gotNodeGroups, status := groupingPlugin.Grouping(ctx, unit, unitCycleState, nodeGroup)
 			if !status.IsSuccess() {
@@ -375,7 +381,7 @@ func (f *UnitFramework) scheduleOneUnitInstance(ctx context.Context, scheduledIn
 		// add schedule result metric
 		if success {
 			metrics.PodScheduled(podProperty, helper.SinceInSeconds(start))
-		} else if godelScheduler.DisablePreemption() {
+		} else if godelScheduler.DisablePreemption() && !godelScheduler.PreemptionDryRun() {
 			metrics.PodUnschedulable(podProperty, helper.SinceInSeconds(start))
 		}
 	}()
@@ -427,6 +433,7 @@ func (f *UnitFramework) scheduleOneUnitInstance(ctx context.Context, scheduledIn
 	// reserve the requested resource and other information on the target node in advance before the API call
 	// so that we can do the update asynchronously
 	// TODO: Compared to updating scheduler cache directly, we'd better operate the snapshot to avoid data corruption.
+	setPodAnnotationsOnNode(state, clonedPod, scheduleResult.SuggestedHost)
 	NodeToPlace, err := f.schedulerHooks.ReservePod(ctx, clonedPod, scheduleResult)
 	if err != nil {
 		klog.ErrorS(err, "Failed to reserve pod", "switchType", switchType, "subCluster", subCluster, "podKey", podKey, "nodeGroup", nodeGroup.GetKey())
@@ -481,11 +488,16 @@ func (f *UnitFramework) preemptOneUnitInstance(ctx context.Context, scheduledInd
 	}
 
 	start := time.Now()
+	var dryRunVictims int
+	dryRunNominated := false
 	defer func() {
 		// add schedule & preemption result metric
 		if success {
 			metrics.PodNominated(podProperty, helper.SinceInSeconds(start))
 			metrics.PodScheduled(podProperty, helper.SinceInSeconds(start))
+		} else if dryRunNominated {
+			metrics.PodNominatedInDryRun(podProperty, helper.SinceInSeconds(start), dryRunVictims)
+			metrics.PodUnschedulable(podProperty, helper.SinceInSeconds(start))
 		} else {
 			metrics.PodNominatedFailure(podProperty, helper.SinceInSeconds(start))
 			metrics.PodUnschedulable(podProperty, helper.SinceInSeconds(start))
@@ -534,6 +546,28 @@ func (f *UnitFramework) preemptOneUnitInstance(ctx context.Context, scheduledInd
 		"victims", preemptionResult.NominatedNode.VictimPods,
 		"nodeGroup", nodeGroup.GetKey())
 
+	if godelScheduler.PreemptionDryRun() {
+		// In dry-run mode we only record what the preemption would have done, the victims are never
+		// nominated or persisted and the pod stays unschedulable.
+		dryRunNominated = true
+		if preemptionResult.Victims != nil {
+			dryRunVictims = len(preemptionResult.Victims.Pods)
+		}
+		klog.InfoS("Preemption dry-run would have nominated node for pod",
+			"switchType", switchType, "subCluster", subCluster,
+			"podKey", podKey,
+			"nominatedNode", preemptionResult.NominatedNode.NodeName,
+			"victims", preemptionResult.NominatedNode.VictimPods,
+			"sortedCandidates", preemptionResult.SortedCandidates,
+			"nodeGroup", nodeGroup.GetKey())
+		message := fmt.Sprintf("Preemption dry-run, nominated node: %v, victims: %+v, sorted candidates: %v, in node group: %v",
+			preemptionResult.NominatedNode.NodeName, preemptionResult.NominatedNode.VictimPods, preemptionResult.SortedCandidates, nodeGroup.GetKey())
+		preemptionTraceContext.WithFields(tracing.WithMessageField(message))
+		f.schedulerHooks.EventRecorder().Eventf(clonedPod, nil, v1.EventTypeNormal, "PreemptionDryRun", core.ReturnAction,
+			helper.TruncateMessage(message))
+		return false, fmt.Errorf("preemption is in dry-run mode, pod %v would have been placed on node %v", podKey, preemptionResult.NominatedNode.NodeName)
+	}
+
 	message := fmt.Sprintf("Pod can be placed by evicting some other pods, nominated node: %v, victims: %+v, in node group: %v", preemptionResult.NominatedNode.NodeName, preemptionResult.NominatedNode.VictimPods, nodeGroup.GetKey())
 	preemptionTraceContext.WithFields(tracing.WithMessageField(message))
 	f.schedulerHooks.EventRecorder().Eventf(clonedPod, nil, v1.EventTypeNormal, "PreemptForPodSuccessfully", core.ContinueAction,
@@ -542,6 +576,7 @@ func (f *UnitFramework) preemptOneUnitInstance(ctx context.Context, scheduledInd
 	// reserve the requested resource and other information on the target node in advance before the API call
 	// so that we can do the update asynchronously
 	// TODO: Compared to updating scheduler cache directly, we'd better operate the snapshot to avoid data corruption.
+	setPodAnnotationsOnNode(state, clonedPod, preemptionResult.NominatedNode.NodeName)
 	NodeToPlace, err := f.schedulerHooks.ReservePod(ctx, clonedPod, preemptionResult)
 	if err != nil {
 		klog.ErrorS(err, "Failed to reserve pod", "switchType", switchType, "subCluster", subCluster,
@@ -600,3 +635,18 @@ func errorStr(err error) string {
 	}
 	return ""
 }
+
+// setPodAnnotationsOnNode sets the annotations decided by plugins for the node where the pod will be placed,
+// e.g. the shared GPU device.
+func setPodAnnotationsOnNode(state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	annotations := framework.GetPodAnnotationsOnNode(state, nodeName)
+	if len(annotations) == 0 {
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	for key, value := range annotations {
+		pod.Annotations[key] = value
+	}
+}
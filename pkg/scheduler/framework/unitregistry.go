@@ -26,7 +26,9 @@ import (
 	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/daemonset"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/joblevelaffinity"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/networktopology"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/noop"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/reservation"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_plugins/virtualkubelet"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/util"
 )
@@ -48,6 +50,8 @@ func NewOrderedUnitPluginRegistry() framework.PluginOrder {
 			daemonset.Name,
 			virtualkubelet.Name,
 			joblevelaffinity.Name,
+			networktopology.Name,
+			reservation.Name,
 			noop.Name,
 		},
 	}
@@ -60,12 +64,14 @@ func NewUnitInTreeRegistry() UnitRegistry {
 		joblevelaffinity.Name: joblevelaffinity.New,
 		daemonset.Name:        daemonset.New,
 		virtualkubelet.Name:   virtualkubelet.New,
+		reservation.Name:      reservation.New,
+		networktopology.Name:  networktopology.New,
 	}
 }
 
 func NewUnitPluginsRegistry(
 	registry UnitRegistry,
-	pluginArgs map[string]*schedulerconfig.PluginConfig, // TODO: Do we need UnitPlugin args in future?
+	pluginArgs map[string]*schedulerconfig.PluginConfig,
 	handler framework.SchedulerUnitFrameworkHandle,
 ) framework.PluginMap {
 	pluginMap := framework.PluginMap{}
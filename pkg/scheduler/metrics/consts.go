@@ -67,4 +67,5 @@ const (
 	UnitScheduleSucceed    = "succeed"
 	UnitScheduleFailed     = "schedule_failed"
 	UnitApplyToCacheFailed = "apply_to_cache_failed"
+	UnitScheduleConflicted = "schedule_conflicted"
 )
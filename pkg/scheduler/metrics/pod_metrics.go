@@ -200,8 +200,9 @@ var (
 			Subsystem: SchedulerSubsystem,
 			Name:      "pod_preempting_attempts",
 			Help: "Number of attempts to preempt pods, by the result. 'nominated' means a pod is nominated to preempt " +
-				"others, 'nominatedScheduling' means a pod is scheduled in preempting and 'nominatedFailure' means a pod " +
-				"failed to schedule and nominate preemption.",
+				"others, 'nominatedScheduling' means a pod is scheduled in preempting, 'nominatedFailure' means a pod " +
+				"failed to schedule and nominate preemption and 'nominatedDryRun' means a pod would have been nominated " +
+				"in the dry-run preemption.",
 			StabilityLevel: metrics.ALPHA,
 		}, []string{pkgmetrics.ResultLabel, pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.SchedulerLabel})
 
@@ -214,6 +215,15 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		}, []string{pkgmetrics.SchedulerLabel})
 
+	preemptingDryRunVictims = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "preempting_dry_run_victims",
+			Help:           "Number of victims the dry-run preemption would have preempted",
+			Buckets:        metrics.LinearBuckets(5, 5, 10),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.SchedulerLabel})
+
 	preemptingStageLatency = metrics.NewHistogramVec(
 		&metrics.HistogramOpts{
 			Subsystem:      SchedulerSubsystem,
@@ -250,7 +260,7 @@ var (
 // setScheduler sets pkgmetrics.SchedulerLabel with scheduler instance name.
 func setScheduler(labels metrics.Labels) {
 	if _, ok := labels[pkgmetrics.SchedulerLabel]; !ok {
-		labels[pkgmetrics.SchedulerLabel] = SchedulerName
+		labels[pkgmetrics.SchedulerLabel] = SchedulerName()
 	}
 }
 
@@ -310,6 +320,19 @@ func PreemptingAttemptsInc(podProperty *api.PodProperty, result string) {
 	newPreemptingAttemptsCounterMetric(podLabels).Inc()
 }
 
+// newPreemptingDryRunVictimsObserverMetric returns the ObserverMetric for given labels by PreemptingDryRunVictims
+func newPreemptingDryRunVictimsObserverMetric(labels metrics.Labels) metrics.ObserverMetric {
+	setScheduler(labels)
+	return preemptingDryRunVictims.With(labels)
+}
+
+// PreemptingDryRunVictimsObserve Invoke Observe method
+// podLabels contains basic object property labels
+func PreemptingDryRunVictimsObserve(podProperty *api.PodProperty, victims float64) {
+	podLabels := podProperty.ConvertToMetricsLabels()
+	newPreemptingDryRunVictimsObserverMetric(podLabels).Observe(victims)
+}
+
 // newPreemptingStageLatencyObserverMetric returns the ObserverMetric for given labels by PreemptingStageLatency
 func newPreemptingStageLatencyObserverMetric(labels metrics.Labels) metrics.ObserverMetric {
 	setScheduler(labels)
@@ -41,6 +41,9 @@ var (
 	// NominatedFailureResult is marked as uns
 	NominatedFailureResult ScheduleResult = "nominatedFailure"
 
+	// NominatedDryRunResult is marked as successful preempting nomination in dry-run, nothing is nominated
+	NominatedDryRunResult ScheduleResult = "nominatedDryRun"
+
 	// ErrorResult is marked as internal error, including scheduling and preempting nomination
 	ErrorResult ScheduleResult = "error"
 )
@@ -81,6 +84,13 @@ func PodNominatedFailure(podProperty *api.PodProperty, duration float64) {
 	observePreemptingAttemptAndLatency(podProperty, string(NominatedFailureResult), duration)
 }
 
+// PodNominatedInDryRun can record a successful dry-run preempting nomination attempt, the duration
+// since `start` and the number of victims it would have preempted.
+func PodNominatedInDryRun(podProperty *api.PodProperty, duration float64, victims int) {
+	observePreemptingAttemptAndLatency(podProperty, string(NominatedDryRunResult), duration)
+	PreemptingDryRunVictimsObserve(podProperty, float64(victims))
+}
+
 func observeScheduleAttemptAndLatency(podProperty *api.PodProperty, result string, duration float64) {
 	SchedulingAlgorithmDurationObserve(podProperty, result, duration)
 	ScheduleAttemptsInc(podProperty, result)
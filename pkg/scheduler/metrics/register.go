@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -41,6 +42,7 @@ var metricsList = []metrics.Registerable{
 	preemptingEvaluationDuration,
 	preemptingEvaluationQuantile,
 	preemptingNominatorVictims,
+	preemptingDryRunVictims,
 	podUpdatingLatency,
 	podPendingLatency,
 	unitPendingLatency,
@@ -64,12 +66,19 @@ var metricsList = []metrics.Registerable{
 	unitScheduleResult,
 }
 
-// SchedulerName name of scheduler to produce metrics
-var SchedulerName string
+// schedulerName is the name of scheduler to produce metrics, it's stored atomically since the schedulers
+// running in the same process (e.g. integration tests) register their names concurrently.
+var schedulerName atomic.Value
 
 // RegisterSchedulerName registers scheduler name, which is used as a value of ScheduleLabel
 func RegisterSchedulerName(name string) {
-	SchedulerName = name
+	schedulerName.Store(name)
+}
+
+// SchedulerName returns the registered scheduler name.
+func SchedulerName() string {
+	name, _ := schedulerName.Load().(string)
+	return name
 }
 
 var registerMetrics sync.Once
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 
+	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
@@ -29,9 +30,14 @@ import (
 type schedulerOptions struct {
 	defaultProfile     *config.GodelSchedulerProfile
 	subClusterProfiles map[string]config.GodelSchedulerProfile
-
-	renewInterval int64
-	subClusterKey string
+	// schedulerNameProfiles are keyed by the spec.schedulerName of the pods they serve.
+	schedulerNameProfiles map[string]config.GodelSchedulerProfile
+
+	renewInterval     int64
+	subClusterKey     string
+	nodePartitionType string
+	// binderHandoff is nil if the scheduled pods are not handed off to the binder directly.
+	binderHandoff *config.BinderHandoffConfiguration
 }
 
 // Option configures a Scheduler
@@ -53,6 +59,17 @@ func WithSubClusterProfiles(profiles []config.GodelSchedulerProfile) Option {
 	}
 }
 
+// WithSchedulerNameProfiles sets the profiles for the pods whose spec.schedulerName is not the primary one.
+func WithSchedulerNameProfiles(profiles []config.GodelSchedulerProfile) Option {
+	return func(o *schedulerOptions) {
+		schedulerNameProfiles := make(map[string]config.GodelSchedulerProfile, len(profiles))
+		for _, profile := range profiles {
+			schedulerNameProfiles[profile.SchedulerName] = profile
+		}
+		o.schedulerNameProfiles = schedulerNameProfiles
+	}
+}
+
 // WithRenewInterval sets renew interval for Scheduler in seconds, the default value is 30
 func WithRenewInterval(renewInterval int64) Option {
 	return func(o *schedulerOptions) {
@@ -66,9 +83,24 @@ func WithSubClusterKey(key string) Option {
 	}
 }
 
+// WithNodePartitionType sets the node partition type for Scheduler, the default value is Logical
+func WithNodePartitionType(nodePartitionType string) Option {
+	return func(o *schedulerOptions) {
+		o.nodePartitionType = nodePartitionType
+	}
+}
+
+// WithBinderHandoff sets the configuration of handing off the scheduled pods to the binder directly.
+func WithBinderHandoff(binderHandoff *config.BinderHandoffConfiguration) Option {
+	return func(o *schedulerOptions) {
+		o.binderHandoff = binderHandoff
+	}
+}
+
 var defaultSchedulerOptions = schedulerOptions{
-	renewInterval: config.DefaultRenewIntervalInSeconds,
-	subClusterKey: config.DefaultSubClusterKey,
+	renewInterval:     config.DefaultRenewIntervalInSeconds,
+	subClusterKey:     config.DefaultSubClusterKey,
+	nodePartitionType: defaultsconfig.DefaultNodePartitionType,
 }
 
 func renderOptions(opts ...Option) schedulerOptions {
@@ -88,6 +120,8 @@ type subClusterConfig struct {
 	UnitMaxBackoffSeconds         int64
 	AttemptImpactFactorOnPriority float64
 
+	ParallelUnitWorkers int32
+
 	BasePlugins             framework.PluginCollectionSet
 	PluginConfigs           []config.PluginConfig
 	PreemptionPluginConfigs []config.PluginConfig
@@ -96,6 +130,7 @@ type subClusterConfig struct {
 	DisablePreemption      bool
 	CandidatesSelectPolicy string
 	BetterSelectPolicies   []string
+	PreemptionDryRun       bool
 
 	EnableStore map[string]bool
 }
@@ -123,6 +158,9 @@ func (c *subClusterConfig) complete(profile *config.GodelSchedulerProfile) {
 	if profile.AttemptImpactFactorOnPriority != nil {
 		c.AttemptImpactFactorOnPriority = *profile.AttemptImpactFactorOnPriority
 	}
+	if profile.ParallelUnitWorkers != nil {
+		c.ParallelUnitWorkers = *profile.ParallelUnitWorkers
+	}
 	if profile.BasePluginsForKubelet != nil || profile.BasePluginsForNM != nil {
 		c.BasePlugins = renderBasePlugin(NewBasePlugins(), profile.BasePluginsForKubelet, profile.BasePluginsForNM)
 	}
@@ -146,6 +184,13 @@ func (c *subClusterConfig) complete(profile *config.GodelSchedulerProfile) {
 	if profile.BetterSelectPolicies != nil {
 		c.BetterSelectPolicies = *profile.BetterSelectPolicies
 	}
+	if profile.PreemptionDryRun != nil {
+		c.PreemptionDryRun = *profile.PreemptionDryRun
+	}
+	// The dry-run preemption searches victims in the preemption store as the real one does.
+	if c.PreemptionDryRun {
+		c.EnableStore[string(preemptionstore.Name)] = true
+	}
 }
 
 // String by JSON format. This content can be identified on `https://jsonformatter.curiousconcept.com/#`
@@ -168,6 +213,8 @@ func newDefaultSubClusterConfig(profile *config.GodelSchedulerProfile) *subClust
 		UnitMaxBackoffSeconds:         config.DefaultUnitMaxBackoffInSeconds,
 		AttemptImpactFactorOnPriority: config.DefaultAttemptImpactFactorOnPriority,
 
+		ParallelUnitWorkers: config.DefaultParallelUnitWorkers,
+
 		BasePlugins:             NewBasePlugins(),
 		PluginConfigs:           []config.PluginConfig{},
 		PreemptionPluginConfigs: []config.PluginConfig{},
@@ -196,6 +243,8 @@ func newSubClusterConfigFromDefaultConfig(profile *config.GodelSchedulerProfile,
 		UnitMaxBackoffSeconds:         defaultConfig.UnitMaxBackoffSeconds,
 		AttemptImpactFactorOnPriority: defaultConfig.AttemptImpactFactorOnPriority,
 
+		ParallelUnitWorkers: defaultConfig.ParallelUnitWorkers,
+
 		BasePlugins:             defaultConfig.BasePlugins,
 		PluginConfigs:           defaultConfig.PluginConfigs,
 		PreemptionPluginConfigs: defaultConfig.PreemptionPluginConfigs,
@@ -204,6 +253,7 @@ func newSubClusterConfigFromDefaultConfig(profile *config.GodelSchedulerProfile,
 		DisablePreemption:      defaultConfig.DisablePreemption,
 		CandidatesSelectPolicy: defaultConfig.CandidatesSelectPolicy,
 		BetterSelectPolicies:   defaultConfig.BetterSelectPolicies,
+		PreemptionDryRun:       defaultConfig.PreemptionDryRun,
 	}
 	c.EnableStore = make(map[string]bool, len(defaultConfig.EnableStore))
 	for k, v := range defaultConfig.EnableStore {
@@ -29,6 +29,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/cmd/scheduler/app/options"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilpointer "k8s.io/utils/pointer"
@@ -202,6 +203,7 @@ func TestLoadAndRenderFileV1beta1(t *testing.T) {
 			UnitInitialBackoffSeconds:     1,
 			UnitMaxBackoffSeconds:         100,
 			AttemptImpactFactorOnPriority: 3.0,
+			ParallelUnitWorkers:           1,
 
 			BasePlugins:             renderBasePlugin(NewBasePlugins(), testBasePluginsForKubelet, testBasePluginsForNM),
 			PluginConfigs:           testPluginConfigs,
@@ -234,6 +236,7 @@ func TestLoadAndRenderFileV1beta1(t *testing.T) {
 			UnitInitialBackoffSeconds:     1,
 			UnitMaxBackoffSeconds:         100,
 			AttemptImpactFactorOnPriority: 3.0,
+			ParallelUnitWorkers:           1,
 
 			BasePlugins:             renderBasePlugin(NewBasePlugins(), testBasePluginsForKubelet, testBasePluginsForNM),
 			PluginConfigs:           testPluginConfigs,
@@ -266,6 +269,7 @@ func TestLoadAndRenderFileV1beta1(t *testing.T) {
 			UnitInitialBackoffSeconds:     1,
 			UnitMaxBackoffSeconds:         100,
 			AttemptImpactFactorOnPriority: 3.0,
+			ParallelUnitWorkers:           1,
 
 			BasePlugins:             renderBasePlugin(NewBasePlugins(), testBasePluginsForKubelet, testBasePluginsForNM),
 			PluginConfigs:           testPluginConfigs,
@@ -297,6 +301,7 @@ func TestLoadAndRenderFileV1beta1(t *testing.T) {
 			UnitInitialBackoffSeconds:     2,
 			UnitMaxBackoffSeconds:         256,
 			AttemptImpactFactorOnPriority: 3.0,
+			ParallelUnitWorkers:           1,
 
 			BasePlugins:             renderBasePlugin(NewBasePlugins(), testBasePluginsForKubelet, testBasePluginsForNM),
 			PluginConfigs:           testPluginConfigs,
@@ -387,6 +392,7 @@ func TestLoadAndRenderFileV1beta1(t *testing.T) {
 			UnitInitialBackoffSeconds:     1,
 			UnitMaxBackoffSeconds:         100,
 			AttemptImpactFactorOnPriority: 3.0,
+			ParallelUnitWorkers:           1,
 
 			BasePlugins:             renderBasePlugin(NewBasePlugins(), setBasePluginsForKubelet, nil),
 			PluginConfigs:           setPluginConfigs,
@@ -412,6 +418,7 @@ func TestLoadAndRenderFileV1beta1(t *testing.T) {
 			UnitInitialBackoffSeconds:     1,
 			UnitMaxBackoffSeconds:         100,
 			AttemptImpactFactorOnPriority: 3.0,
+			ParallelUnitWorkers:           1,
 
 			BasePlugins:             renderBasePlugin(NewBasePlugins(), testBasePluginsForKubelet, testBasePluginsForNM),
 			PluginConfigs:           testPluginConfigs,
@@ -605,3 +612,30 @@ func TestLoadAndRenderFileV1beta1PreemptionProfileConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestSubClusterConfigPreemptionDryRun(t *testing.T) {
+	defaultProfile := newDefaultSubClusterConfig(&schedulerconfig.GodelSchedulerProfile{
+		DisablePreemption: utilpointer.Bool(true),
+	})
+	if defaultProfile.PreemptionDryRun || defaultProfile.EnableStore[string(preemptionstore.Name)] {
+		t.Errorf("expected dry-run and preemption store to be disabled by default, got %v", defaultProfile)
+	}
+	if profileNeedPreemption(&schedulerconfig.GodelSchedulerProfile{DisablePreemption: utilpointer.Bool(true)}) {
+		t.Errorf("expected profile without dry-run not to need preemption")
+	}
+
+	dryRunProfile := &schedulerconfig.GodelSchedulerProfile{
+		DisablePreemption: utilpointer.Bool(true),
+		PreemptionDryRun:  utilpointer.Bool(true),
+	}
+	profile := newSubClusterConfigFromDefaultConfig(dryRunProfile, defaultProfile)
+	assert.True(t, profile.DisablePreemption)
+	assert.True(t, profile.PreemptionDryRun)
+	assert.True(t, profile.EnableStore[string(preemptionstore.Name)])
+	assert.True(t, profileNeedPreemption(dryRunProfile))
+
+	// The dry-run is inherited from the default profile.
+	inherited := newSubClusterConfigFromDefaultConfig(nil, profile)
+	assert.True(t, inherited.PreemptionDryRun)
+	assert.True(t, inherited.EnableStore[string(preemptionstore.Name)])
+}
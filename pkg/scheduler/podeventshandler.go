@@ -29,11 +29,11 @@ import (
 )
 
 func (sched *Scheduler) assumedOrBoundPod(pod *v1.Pod) bool {
-	return podutil.BoundPod(pod) || podutil.AssumedPodOfGodel(pod, *sched.SchedulerName)
+	return podutil.BoundPod(pod) || podutil.AssumedPodOfGodel(pod, sched.schedulerNames...)
 }
 
 func (sched *Scheduler) dispatchedPodOfThisScheduler(pod *v1.Pod) bool {
-	return podutil.DispatchedPodOfGodel(pod, *sched.SchedulerName) && podutil.DispatchedPodOfThisScheduler(pod, sched.Name)
+	return podutil.DispatchedPodOfGodel(pod, sched.schedulerNames...) && podutil.DispatchedPodOfThisScheduler(pod, sched.Name)
 }
 
 func (sched *Scheduler) triggerQueueOnAssumedOrBoundPodAdd(pod *v1.Pod) error {
@@ -81,7 +81,9 @@ func (sched *Scheduler) addDispatchedPodToQueue(pod *v1.Pod) error {
 		go traceContext.Finish()
 	}()
 
-	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
+	// The pods of the schedulerName profiles are scheduled by the workflows created at startup.
+	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) &&
+		framework.ParseSwitchTypeFromSchedulerName(pod.Spec.SchedulerName) == 0 {
 		subCluster := pod.Spec.NodeSelector[framework.GetGlobalSubClusterKey()]
 		idx, exist := framework.GetOrCreateClusterIndex(subCluster)
 		if !exist {
@@ -136,11 +138,13 @@ func (sched *Scheduler) updateDispatchedPodInQueue(oldPod, newPod *v1.Pod) error
 			}
 		}
 
-		subCluster := newPod.Spec.NodeSelector[framework.GetGlobalSubClusterKey()]
-		idx, exist := framework.GetOrCreateClusterIndex(subCluster)
-		if !exist {
-			// ATTENTION: It is possible to be called before `sched.Run`, so we don't run workflow immediately.
-			sched.createSubClusterWorkflow(idx, subCluster)
+		if framework.ParseSwitchTypeFromSchedulerName(newPod.Spec.SchedulerName) == 0 {
+			subCluster := newPod.Spec.NodeSelector[framework.GetGlobalSubClusterKey()]
+			idx, exist := framework.GetOrCreateClusterIndex(subCluster)
+			if !exist {
+				// ATTENTION: It is possible to be called before `sched.Run`, so we don't run workflow immediately.
+				sched.createSubClusterWorkflow(idx, subCluster)
+			}
 		}
 	}
 
@@ -216,6 +220,12 @@ func (sched *Scheduler) updatePod(oldObj, newObj interface{}) {
 
 	klog.V(3).InfoS("Detected an Update event for pod", "pod", klog.KObj(newPod))
 
+	// A handed off pod is still dispatched when the binder rejects it, forget its placement before
+	// the pod is updated in the queue, so that it is scheduled again.
+	if handoffRejected(oldPod, newPod) {
+		sched.forgetHandedOffPod(newPod)
+	}
+
 	// Corresponding cache operations
 	if err := sched.updatePodInCache(oldPod, newPod); err != nil {
 		klog.InfoS("Failed to update pod in scheduler cache", "err", err)
@@ -228,6 +238,30 @@ func (sched *Scheduler) updatePod(oldObj, newObj interface{}) {
 	}
 }
 
+// handoffRejected checks if the binder has just rejected the placement handed off for the dispatched pod.
+func handoffRejected(oldPod, newPod *v1.Pod) bool {
+	token := newPod.Annotations[podutil.HandoffRejectedAnnotationKey]
+	return len(token) != 0 && token != oldPod.Annotations[podutil.HandoffRejectedAnnotationKey] &&
+		podutil.DispatchedPod(oldPod) && podutil.DispatchedPod(newPod)
+}
+
+// forgetHandedOffPod forgets the placement of the handed off pod rejected by the binder.
+func (sched *Scheduler) forgetHandedOffPod(pod *v1.Pod) {
+	if assumed, err := sched.commonCache.IsAssumedPod(pod); err != nil || !assumed {
+		return
+	}
+	cachedPod, err := sched.commonCache.GetPod(pod)
+	if err != nil {
+		klog.InfoS("Failed to get the handed off pod from scheduler cache", "pod", klog.KObj(pod), "err", err)
+		return
+	}
+	if err := sched.commonCache.ForgetPod(framework.MakeCachePodInfoWrapper().Pod(cachedPod).Obj()); err != nil {
+		klog.InfoS("Failed to forget the handed off pod rejected by the binder", "pod", klog.KObj(pod), "err", err)
+		return
+	}
+	klog.V(3).InfoS("Forgot the handed off pod rejected by the binder", "pod", klog.KObj(pod))
+}
+
 func (sched *Scheduler) updatePodInCache(oldPod *v1.Pod, newPod *v1.Pod) error {
 	// First, updating pod information in the scheduler cache
 	if err := sched.commonCache.UpdatePod(oldPod, newPod); err != nil {
@@ -66,6 +66,9 @@ type BlockQueue struct {
 	// readyQ is a heap structure that scheduler actively looks at to find units to
 	// schedule. Head of heap is the highest priority unit.
 	readyQ SubQueue
+	// unitSnapshotInterval is how often the units in readyQ are re-snapshotted and re-sorted, it is zero if
+	// the order of the units doesn't change over time.
+	unitSnapshotInterval time.Duration
 
 	// 2. WaitingQueue
 	// waitingQ holds the units that doesn't have enough pods (such as podgroup MimMember).
@@ -119,10 +122,11 @@ func NewBlockQueue(
 		metricsRecorder: newMetricsRecorder(),
 
 		waitingPodsList: newWaitingPodsList(qos, options.subCluster, options.owner, options.clock),
-		readyQ:          heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready")),
+		readyQ:          newReadyQueue(comp, options.unitSnapshot),
 		waitingQ:        heap.NewWithRecorder("waiting", unitInfoKeyFunc, alwaysFalse, metrics.NewPendingUnitsRecorder("waiting")),
 
-		moveRequestCycle: -1,
+		moveRequestCycle:     -1,
+		unitSnapshotInterval: options.unitSnapshotInterval,
 	}
 	pq.cond.L = &pq.lock
 	pq.latestOperationTimestamp = pq.clock.Now()
@@ -136,6 +140,16 @@ func (p *BlockQueue) Run() {
 	// TODO: discuss remove flushScheduledUnitsInWaitingQ & flushWaitingPodsList
 	go wait.Until(p.flushScheduledUnitsInWaitingQ, 2.0*time.Second, p.stop)
 	go wait.Until(p.flushWaitingPodsList, 20.0*time.Second, p.stop)
+	if p.unitSnapshotInterval > 0 {
+		go wait.Until(p.resortReadyQ, p.unitSnapshotInterval, p.stop)
+	}
+}
+
+// resortReadyQ re-snapshots the units in readyQ and re-sorts them.
+func (p *BlockQueue) resortReadyQ() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	resnapshotReadyQueue(p.readyQ)
 }
 
 // triggerBroadcastIfNeeded
@@ -32,6 +32,10 @@ type schedulingQueueOptions struct {
 	unitMaxBackoffDuration        time.Duration
 	owner                         string
 	attemptImpactFactorOnPriority float64
+	// unitSnapshot snapshots the state the units are sorted by, it's nil if the order of the units
+	// doesn't change over time.
+	unitSnapshot         func(*framework.QueuedUnitInfo)
+	unitSnapshotInterval time.Duration
 }
 
 // Option configures a PriorityQueue
@@ -86,6 +90,15 @@ func WithAttemptImpactFactorOnPriority(attemptImpactFactorOnPriority float64) Op
 	}
 }
 
+// WithUnitSnapshot sets the function snapshotting the state the units are sorted by, along with how often
+// the units are re-snapshotted and re-sorted.
+func WithUnitSnapshot(snapshot func(*framework.QueuedUnitInfo), interval time.Duration) Option {
+	return func(o *schedulingQueueOptions) {
+		o.unitSnapshot = snapshot
+		o.unitSnapshotInterval = interval
+	}
+}
+
 var defaultPriorityQueueOptions = schedulingQueueOptions{
 	clock:                         util.RealClock{},
 	unitInitialBackoffDuration:    config.DefaultUnitInitialBackoffInSeconds * time.Second,
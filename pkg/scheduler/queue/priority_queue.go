@@ -74,6 +74,9 @@ type PriorityQueue struct {
 	// readyQ is a heap structure that scheduler actively looks at to find units to
 	// schedule. Head of heap is the highest priority unit.
 	readyQ SubQueue
+	// unitSnapshotInterval is how often the units in readyQ are re-snapshotted and re-sorted, it is zero if
+	// the order of the units doesn't change over time.
+	unitSnapshotInterval time.Duration
 	// 2. BackoffQueue
 	// backoffQ is a heap ordered by backoff expiry. Units which have completed backoff
 	// are popped from this heap before the scheduler looks at readyQ.
@@ -147,13 +150,14 @@ func NewPriorityQueue(
 		metricsRecorder: newMetricsRecorder(),
 
 		waitingPodsList: newWaitingPodsList(qos, options.subCluster, options.owner, options.clock),
-		readyQ:          heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready")),
+		readyQ:          newReadyQueue(comp, options.unitSnapshot),
 		backoffQ:        heap.NewWithRecorder("backoff", unitInfoKeyFunc, boHandler.unitsCompareBackoffCompleted, metrics.NewPendingUnitsRecorder("backoff")),
 		waitingQ:        heap.NewWithRecorder("waiting", unitInfoKeyFunc, alwaysFalse, metrics.NewPendingUnitsRecorder("waiting")),
 		unschedulableQ:  heap.NewWithRecorder("unschedulable", unitInfoKeyFunc, alwaysFalse, metrics.NewPendingUnitsRecorder("unschedulable")),
 
 		moveRequestCycle:              -1,
 		attemptImpactFactorOnPriority: options.attemptImpactFactorOnPriority,
+		unitSnapshotInterval:          options.unitSnapshotInterval,
 		priorityHeap: heap.New("priority", unitInfoKeyFunc, func(unitInfo1, unitInfo2 interface{}) bool {
 			u1 := unitInfo1.(*framework.QueuedUnitInfo)
 			u2 := unitInfo2.(*framework.QueuedUnitInfo)
@@ -173,6 +177,16 @@ func (p *PriorityQueue) Run() {
 	// TODO: discuss remove flushScheduledUnitsInWaitingQ & flushWaitingPodsList
 	go wait.Until(p.flushScheduledUnitsInWaitingQ, 2.0*time.Second, p.stop)
 	go wait.Until(p.flushWaitingPodsList, 20.0*time.Second, p.stop)
+	if p.unitSnapshotInterval > 0 {
+		go wait.Until(p.resortReadyQ, p.unitSnapshotInterval, p.stop)
+	}
+}
+
+// resortReadyQ re-snapshots the units in readyQ and re-sorts them.
+func (p *PriorityQueue) resortReadyQ() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	resnapshotReadyQueue(p.readyQ)
 }
 
 // flushBackoffQCompleted Moves all units from backoffQ which have completed backoff in to readyQ
@@ -167,6 +167,39 @@ func TestPriorityQueue_AddWithReversePriorityLessFunc(t *testing.T) {
 	}
 }
 
+func TestPriorityQueue_ResortReadyQ(t *testing.T) {
+	starving := map[string]bool{}
+	snapshot := func(u *framework.QueuedUnitInfo) {
+		u.Starving = starving[u.UnitKey]
+	}
+	less := func(u1, u2 *framework.QueuedUnitInfo) bool {
+		if u1.Starving != u2.Starving {
+			return u1.Starving
+		}
+		return newDefaultUnitQueueSort()(u1, u2)
+	}
+	q := NewPriorityQueue(nil, nil, nil, less, WithUnitSnapshot(snapshot, time.Second))
+	if err := q.Add(&medPriorityPod); err != nil {
+		t.Errorf("add failed: %v", err)
+	}
+	if err := q.Add(&highPriorityPod); err != nil {
+		t.Errorf("add failed: %v", err)
+	}
+
+	// The order is kept until the units are re-snapshotted.
+	starving[utils.GetUnitIdentifier(&medPriorityPod)] = true
+	if u := q.readyQ.Peek().(*framework.QueuedUnitInfo); getOnePodInfo(u).Pod != &highPriorityPod {
+		t.Errorf("Expected: %v before resorting, but got: %v", highPriorityPod.Name, getOnePodInfo(u).Pod.Name)
+	}
+	q.resortReadyQ()
+	if u, err := q.Pop(); err != nil || getOnePodInfo(u).Pod != &medPriorityPod {
+		t.Errorf("Expected: %v after Pop, but got: %v", medPriorityPod.Name, getOnePodInfo(u).Pod.Name)
+	}
+	if u, err := q.Pop(); err != nil || getOnePodInfo(u).Pod != &highPriorityPod {
+		t.Errorf("Expected: %v after Pop, but got: %v", highPriorityPod.Name, getOnePodInfo(u).Pod.Name)
+	}
+}
+
 func TestPriorityQueue_AddUnschedulableIfNotPresent(t *testing.T) {
 	q := NewPriorityQueue(nil, nil, nil, newDefaultUnitQueueSort())
 	q.Add(&highPriNominatedPod)
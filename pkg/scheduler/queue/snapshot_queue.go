@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/util/heap"
+)
+
+// snapshotQueue is a heap of units sorted by the state changing over time. The state is snapshotted into
+// a unit when it's added, and only re-snapshotted for all the units at once along with re-sorting them,
+// so that the order of the units never changes under the heap.
+type snapshotQueue struct {
+	*heap.Heap
+	snapshot func(*framework.QueuedUnitInfo)
+}
+
+// newReadyQueue returns the heap of the units ready to be scheduled, the units are snapshotted by
+// snapshot if it is not nil.
+func newReadyQueue(comp func(interface{}, interface{}) bool, snapshot func(*framework.QueuedUnitInfo)) SubQueue {
+	h := heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready"))
+	if snapshot == nil {
+		return h
+	}
+	return &snapshotQueue{Heap: h, snapshot: snapshot}
+}
+
+func (q *snapshotQueue) Add(obj interface{}) error {
+	q.snapshot(obj.(*framework.QueuedUnitInfo))
+	return q.Heap.Add(obj)
+}
+
+func (q *snapshotQueue) Update(oldObj, newObj interface{}) error {
+	q.snapshot(newObj.(*framework.QueuedUnitInfo))
+	return q.Heap.Update(oldObj, newObj)
+}
+
+// resnapshot re-snapshots all the units and re-sorts them.
+func (q *snapshotQueue) resnapshot() {
+	q.Heap.Refresh(func(obj interface{}) {
+		q.snapshot(obj.(*framework.QueuedUnitInfo))
+	})
+}
+
+// resnapshotReadyQueue re-sorts the units in readyQ if they're sorted by the state changing over time.
+// It must be called with the lock of the queue held.
+func resnapshotReadyQueue(readyQ SubQueue) {
+	if q, ok := readyQ.(*snapshotQueue); ok {
+		q.resnapshot()
+	}
+}
@@ -126,6 +126,8 @@ func InitUnitQueueSortPlugin(spec *framework.PluginSpec, pluginArgs map[string]*
 type SortPluginFactory = func(runtime.Object) (framework.UnitQueueSortPlugin, error)
 
 var UnitSortPluginRegistry = map[string]SortPluginFactory{
-	unitqueuesort.FCFSName: unitqueuesort.NewFCFS,
-	unitqueuesort.Name:     unitqueuesort.New,
+	unitqueuesort.FCFSName:            unitqueuesort.NewFCFS,
+	unitqueuesort.Name:                unitqueuesort.New,
+	unitqueuesort.StarvationAwareName: unitqueuesort.NewStarvationAware,
+	unitqueuesort.FairShareName:       unitqueuesort.NewFairShare,
 }
@@ -49,12 +49,20 @@ type FailedTaskReconciler struct {
 
 type FailedPatchTask struct {
 	podInfo *framework.CachePodInfo
+	// handoffToken is the token of the placement handed off to the binder, it is empty if the pod
+	// was not handed off.
+	handoffToken string
 }
 
 func NewFailedPatchTask(podInfo *framework.CachePodInfo) *FailedPatchTask {
 	return &FailedPatchTask{podInfo: podInfo}
 }
 
+// NewHandoffTask returns the task persisting the placement handed off to the binder with token.
+func NewHandoffTask(podInfo *framework.CachePodInfo, token string) *FailedPatchTask {
+	return &FailedPatchTask{podInfo: podInfo, handoffToken: token}
+}
+
 func NewFailedTaskReconciler(client clientset.Interface, podLister corelisters.PodLister,
 	schedulerCache godelcache.SchedulerCache, schedulerName string,
 ) *FailedTaskReconciler {
@@ -72,6 +80,13 @@ func (re *FailedTaskReconciler) AddFailedTask(fpt *FailedPatchTask) {
 	re.failedPatchTaskQueue.Add(fpt)
 }
 
+// AddHandoffTask adds the task of a pod handed off to the binder directly. Its placement is persisted
+// through the pod annotations after delay if the pod is still dispatched by then and the binder hasn't
+// rejected the placement, so that it is not lost if the binder restarts.
+func (re *FailedTaskReconciler) AddHandoffTask(fpt *FailedPatchTask, delay time.Duration) {
+	re.failedPatchTaskQueue.AddAfter(fpt, delay)
+}
+
 func (re *FailedTaskReconciler) Run() {
 	go wait.Until(re.failedPatchTaskWorker, time.Second, re.stop)
 }
@@ -95,6 +110,12 @@ func (re *FailedTaskReconciler) failedPatchTaskWorker() {
 		}
 
 		latestPod, err := re.podLister.Pods(fpt.podInfo.Pod.Namespace).Get(fpt.podInfo.Pod.Name)
+		if err == nil && handoffRejected(latestPod, fpt) {
+			// The scheduler has forgotten the placement and scheduled the pod again when it was rejected,
+			// the pod may even carry a newer placement by now.
+			klog.V(4).InfoS("Dropped the handed off placement rejected by the binder", "pod", klog.KObj(latestPod))
+			return false
+		}
 		if goOn := re.checkPodState(latestPod, err, fpt); !goOn {
 			return false
 		}
@@ -161,3 +182,8 @@ func (re *FailedTaskReconciler) checkPodState(latestPod *v1.Pod, err error, fpt
 
 	return true
 }
+
+// handoffRejected checks if the placement handed off by the task has been rejected by the binder.
+func handoffRejected(latestPod *v1.Pod, fpt *FailedPatchTask) bool {
+	return len(fpt.handoffToken) != 0 && latestPod.Annotations[podutil.HandoffRejectedAnnotationKey] == fpt.handoffToken
+}
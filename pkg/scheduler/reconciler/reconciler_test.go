@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	fakecache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/fake"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestHandoffTask(t *testing.T) {
+	dispatchedPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "p",
+			Namespace: "default",
+			UID:       "uid",
+			Annotations: map[string]string{
+				podutil.PodStateAnnotationKey:        string(podutil.PodDispatched),
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+				podutil.SchedulerAnnotationKey:       "scheduler",
+			},
+		},
+		Spec: v1.PodSpec{SchedulerName: "godel-scheduler"},
+	}
+	assumedPod := dispatchedPod.DeepCopy()
+	assumedPod.Annotations[podutil.PodStateAnnotationKey] = string(podutil.PodAssumed)
+	assumedPod.Annotations[podutil.AssumedNodeAnnotationKey] = "node"
+
+	tests := []struct {
+		name          string
+		rejectedToken string
+		expectPatched bool
+	}{
+		{
+			name:          "placement not rejected",
+			expectPatched: true,
+		},
+		{
+			name:          "placement rejected",
+			rejectedToken: "token",
+		},
+		{
+			name:          "earlier placement rejected",
+			rejectedToken: "earlier-token",
+			expectPatched: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latestPod := dispatchedPod.DeepCopy()
+			if len(tt.rejectedToken) != 0 {
+				latestPod.Annotations[podutil.HandoffRejectedAnnotationKey] = tt.rejectedToken
+			}
+			client := clientsetfake.NewSimpleClientset(latestPod)
+			podInformer := informers.NewSharedInformerFactory(client, 0).Core().V1().Pods()
+			podInformer.Informer().GetStore().Add(latestPod)
+
+			re := NewFailedTaskReconciler(client, podInformer.Lister(), &fakecache.Cache{}, "godel-scheduler")
+			re.AddFailedTask(NewHandoffTask(framework.MakeCachePodInfoWrapper().Pod(assumedPod).Obj(), "token"))
+			// the worker returns once the queued tasks are drained
+			re.failedPatchTaskQueue.ShutDown()
+			re.failedPatchTaskWorker()
+
+			got, err := client.CoreV1().Pods(latestPod.Namespace).Get(context.TODO(), latestPod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get pod: %v", err)
+			}
+			if patched := podutil.AssumedPod(got); patched != tt.expectPatched {
+				t.Errorf("expected placement patched %v, got %v", tt.expectPatched, patched)
+			}
+		})
+	}
+}
@@ -18,7 +18,10 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
@@ -42,14 +45,20 @@ import (
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
 	cachedebugger "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/debugger"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/handler"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
 	podscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler/core/pod_scheduler"
 	unitscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler/core/unit_scheduler"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
 	godelqueue "github.com/kubewharf/godel-scheduler/pkg/scheduler/queue"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/reconciler"
 	schedulerutil "github.com/kubewharf/godel-scheduler/pkg/scheduler/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
 )
 
+// handoffRequestTimeout is the timeout of a single handoff request to the binder. The scheduler
+// falls back to persisting the placements through the pod annotations when it is exceeded.
+const handoffRequestTimeout = 2 * time.Second
+
 // Scheduler watches for new unscheduled pods. It attempts to find
 // nodes that they fit on and writes bindings back to the api server. Scheduler is the wrapper that watches for all
 // pod and node event, the actual scheduling process is handled by schedule framework.
@@ -59,6 +68,9 @@ type Scheduler struct {
 	// SchedulerName here is the higher level scheduler name, which is used to select pods
 	// that godel schedulers should be responsible for and filter out irrelevant pods.
 	SchedulerName *string
+	// schedulerNames are all the scheduler names served by the scheduler, the first one is SchedulerName
+	// and the others are the ones of the schedulerName profiles.
+	schedulerNames []string
 
 	// Close this to shut down the scheduler.
 	StopEverything         <-chan struct{}
@@ -82,9 +94,15 @@ type Scheduler struct {
 	mayHasPreemption        bool
 	defaultSubClusterConfig *subClusterConfig
 
+	// handoffClient hands off the scheduled pods to the binder directly, it is nil if disabled.
+	handoffClient *handoff.Client
+
 	schedulerMaintainer StatusMaintainer
 	recorder            events.EventRecorder
 	metricsRecorder     *godelcache.ClusterCollectable
+
+	// inFlight tracks the units being scheduled and the pods being persisted for them.
+	inFlight unitscheduler.InFlightTracker
 }
 
 // New returns a Scheduler
@@ -111,13 +129,22 @@ func New(
 	options := renderOptions(opts...)
 	globalClock := clock.RealClock{}
 
+	if len(options.schedulerNameProfiles) > 0 && !utilfeature.DefaultFeatureGate.Enabled(features.SchedulerConcurrentScheduling) {
+		return nil, fmt.Errorf("schedulerName profiles require the feature gate %v", features.SchedulerConcurrentScheduling)
+	}
+	schedulerNames := []string{*schedulerName}
+	for name := range options.schedulerNameProfiles {
+		schedulerNames = append(schedulerNames, name)
+	}
+	sort.Strings(schedulerNames[1:])
+
 	podLister := informerFactory.Core().V1().Pods().Lister()
 	podInformer := informerFactory.Core().V1().Pods()
 
 	mayHasPreemption := parseProfilesBoolConfiguration(options, profileNeedPreemption)
 
 	handlerWrapper := handler.MakeCacheHandlerWrapper().
-		SchedulerName(godelSchedulerName).SchedulerType(*schedulerName).SubCluster(framework.DefaultSubCluster).
+		SchedulerName(godelSchedulerName).SchedulerType(schedulerNames...).SubCluster(framework.DefaultSubCluster).
 		TTL(15 * time.Minute).Period(10 * time.Second).StopCh(stopEverything).
 		PodLister(podLister).PodInformer(podInformer)
 	if mayHasPreemption {
@@ -128,6 +155,7 @@ func New(
 	sched := &Scheduler{
 		Name:                   godelSchedulerName,
 		SchedulerName:          schedulerName,
+		schedulerNames:         schedulerNames,
 		StopEverything:         stopEverything,
 		scheduledPodsHasSynced: informerFactory.Core().V1().Pods().Informer().HasSynced,
 		clock:                  globalClock,
@@ -150,6 +178,14 @@ func New(
 		metricsRecorder:     godelcache.NewEmptyClusterCollectable(godelSchedulerName),
 	}
 
+	if binderHandoff := options.binderHandoff; binderHandoff != nil && binderHandoff.Enable {
+		tlsConfig, err := handoff.NewClientTLSConfig(binderHandoff.CertFile, binderHandoff.KeyFile, binderHandoff.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create binder handoff client: %v", err)
+		}
+		sched.handoffClient = handoff.NewClient(binderHandoff.BinderAddress, handoffRequestTimeout, tlsConfig)
+	}
+
 	// 3. Create sub-cluster workflows.
 	framework.SetGlobalSubClusterKey(options.subClusterKey)
 	framework.CleanClusterIndex()
@@ -164,6 +200,10 @@ func New(
 				subClusters = append(subClusters, options.subClusterProfiles[i].SubClusterName)
 			}
 		}
+		// The workflows of the schedulerName profiles are created at startup and never recycled.
+		for _, name := range schedulerNames[1:] {
+			subClusters = append(subClusters, framework.SchedulerNameProfileKey(name))
+		}
 		for i := range subClusters {
 			subCluster := subClusters[i]
 			idx := framework.AllocClusterIndex(subCluster)
@@ -196,12 +236,52 @@ func (sched *Scheduler) Run(ctx context.Context) {
 
 	rand.Seed(time.Now().Unix())
 
-	sched.ScheduleSwitch.Run(ctx)
+	sched.ScheduleSwitch.Run(unitscheduler.WithInFlightTracker(ctx, &sched.inFlight))
+}
+
+// Drain hands over the responsibility of the scheduler gracefully, it should be called while the scheduler is
+// still running. The scheduler is marked as draining first so that the dispatcher stops dispatching new pods
+// to it and hands its nodes over to other schedulers, and no more units are scheduled. Then the in-flight units
+// and the pods being persisted for them are waited to be finished at most timeout, the workflows are stopped by
+// stopWorkflows, and finally the scheduler leaves so that the pending pods are returned.
+func (sched *Scheduler) Drain(timeout time.Duration, stopWorkflows func()) {
+	klog.InfoS("Started draining scheduler", "schedulerName", sched.Name, "timeout", timeout)
+	if err := sched.schedulerMaintainer.Drain(); err != nil {
+		klog.InfoS("Failed to mark scheduler as draining", "schedulerName", sched.Name, "err", err)
+	}
+	sched.inFlight.StartDraining()
+
+	finished := make(chan struct{})
+	go func() {
+		sched.inFlight.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		klog.InfoS("In-flight units have been finished", "schedulerName", sched.Name)
+	case <-time.After(timeout):
+		klog.InfoS("Timed out waiting for in-flight units", "schedulerName", sched.Name, "timeout", timeout)
+	}
+	stopWorkflows()
+
+	if err := sched.schedulerMaintainer.Leave(); err != nil {
+		klog.InfoS("Failed to remove scheduler", "schedulerName", sched.Name, "err", err)
+		return
+	}
+	klog.InfoS("Finished draining scheduler", "schedulerName", sched.Name)
 }
 
 func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType framework.SwitchType) ScheduleDataSet {
 	var subClusterConfig *subClusterConfig
-	if profile, ok := sched.options.subClusterProfiles[subCluster]; ok {
+	// cacheSubCluster decides the nodes visible to the workflow, and schedulerName decides the pods
+	// the reconciler is responsible for.
+	cacheSubCluster, schedulerName := subCluster, *sched.SchedulerName
+	if framework.IsSchedulerNameProfileKey(subCluster) {
+		schedulerName = strings.TrimPrefix(subCluster, framework.SchedulerNameProfilePrefix)
+		profile := sched.options.schedulerNameProfiles[schedulerName]
+		subClusterConfig = newSubClusterConfigFromDefaultConfig(&profile, sched.defaultSubClusterConfig)
+		cacheSubCluster = framework.DefaultSubCluster
+	} else if profile, ok := sched.options.subClusterProfiles[subCluster]; ok {
 		subClusterConfig = newSubClusterConfigFromDefaultConfig(&profile, sched.defaultSubClusterConfig)
 	} else {
 		subClusterConfig = sched.defaultSubClusterConfig
@@ -217,6 +297,13 @@ func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType fra
 	if err != nil {
 		panic(err)
 	}
+	if fairShare, ok := unitQueueSortPlugin.(framework.FairShareUnitQueueSortPlugin); ok {
+		fairShare.SetQueueShareLister(sched.commonCache.GetQueueShares)
+	}
+	if _, ok := unitQueueSortPlugin.(framework.StarvationAwareUnitQueueSortPlugin); ok && !utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation) {
+		klog.InfoS("WARN: resources are not held back for the starving units since the ResourceReservation feature is disabled",
+			"subCluster", subCluster, "unitQueueSortPlugin", unitQueueSortPlugin.Name())
+	}
 	preemptionPluginArgs := make(map[string]*config.PluginConfig)
 	for index := range subClusterConfig.PreemptionPluginConfigs {
 		pluginArgs := subClusterConfig.PreemptionPluginConfigs[index]
@@ -224,61 +311,84 @@ func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType fra
 	}
 
 	handler := handler.MakeCacheHandlerWrapper().
-		SubCluster(subCluster).SwitchType(switchType).
+		SubCluster(cacheSubCluster).SwitchType(switchType).NodePartitionType(sched.options.nodePartitionType).
 		EnableStore(schedulerutil.FilterTrueKeys(subClusterConfig.EnableStore)...).
 		PodLister(sched.podLister).
 		Obj()
-	snapshot := godelcache.NewEmptySnapshot(handler)
-	podScheduler := podscheduler.NewPodScheduler(
-		sched.Name,
-		switchType,
-		subCluster,
-		sched.client,
-		sched.crdClient,
-		sched.informerFactory,
-		sched.crdInformerFactory,
-		snapshot,
-		sched.clock,
-		subClusterConfig.DisablePreemption,
-		subClusterConfig.CandidatesSelectPolicy,
-		subClusterConfig.BetterSelectPolicies,
-		subClusterConfig.PercentageOfNodesToScore,
-		subClusterConfig.IncreasedPercentageOfNodesToScore,
-		subClusterConfig.BasePlugins,
-		pluginArgs,
-		preemptionPluginArgs,
-	)
+	queueOptions := []godelqueue.Option{
+		godelqueue.WithUnitInitialBackoffDuration(time.Duration(subClusterConfig.UnitInitialBackoffSeconds) * time.Second),
+		godelqueue.WithPodMaxBackoffDuration(time.Duration(subClusterConfig.UnitMaxBackoffSeconds) * time.Second),
+		godelqueue.WithOwner(sched.Name),
+		godelqueue.WithSwitchType(switchType),
+		godelqueue.WithSubCluster(subCluster),
+		godelqueue.WithClock(sched.clock),
+	}
+	if snapshotPlugin, ok := unitQueueSortPlugin.(framework.SnapshotUnitQueueSortPlugin); ok {
+		queueOptions = append(queueOptions, godelqueue.WithUnitSnapshot(snapshotPlugin.SnapshotUnit, snapshotPlugin.SnapshotInterval()))
+	}
 	schedulingQueue := godelqueue.NewSchedulingQueue(
 		sched.commonCache,
 		sched.informerFactory.Scheduling().V1().PriorityClasses().Lister(),
 		sched.crdInformerFactory.Scheduling().V1alpha1().PodGroups().Lister(),
 		unitQueueSortPlugin.Less,
 		subClusterConfig.UseBlockQueue,
-		godelqueue.WithUnitInitialBackoffDuration(time.Duration(subClusterConfig.UnitInitialBackoffSeconds)*time.Second),
-		godelqueue.WithPodMaxBackoffDuration(time.Duration(subClusterConfig.UnitMaxBackoffSeconds)*time.Second),
-		godelqueue.WithOwner(sched.Name),
-		godelqueue.WithSwitchType(switchType),
-		godelqueue.WithSubCluster(subCluster),
-		godelqueue.WithClock(sched.clock),
-	)
-	reconciler := reconciler.NewFailedTaskReconciler(sched.client, sched.informerFactory.Core().V1().Pods().Lister(), sched.commonCache, *sched.SchedulerName)
-	unitScheduler := unitscheduler.NewUnitScheduler(
-		sched.Name,
-		switchType,
-		subCluster,
-		subClusterConfig.DisablePreemption,
-		sched.client,
-		sched.crdClient,
-		sched.podLister,
-		sched.pgLister,
-		sched.commonCache,
-		snapshot,
-		schedulingQueue,
-		reconciler,
-		podScheduler,
-		sched.clock,
-		sched.recorder,
+		queueOptions...,
 	)
+	reconciler := reconciler.NewFailedTaskReconciler(sched.client, sched.informerFactory.Core().V1().Pods().Lister(), sched.commonCache, schedulerName)
+	// newUnitScheduler creates a unit scheduler with its own snapshot and pod scheduler.
+	newUnitScheduler := func() (*godelcache.Snapshot, core.UnitScheduler) {
+		snapshot := godelcache.NewEmptySnapshot(handler)
+		podScheduler := podscheduler.NewPodScheduler(
+			sched.Name,
+			switchType,
+			subCluster,
+			sched.client,
+			sched.crdClient,
+			sched.informerFactory,
+			sched.crdInformerFactory,
+			snapshot,
+			sched.clock,
+			subClusterConfig.DisablePreemption,
+			subClusterConfig.PreemptionDryRun,
+			subClusterConfig.CandidatesSelectPolicy,
+			subClusterConfig.BetterSelectPolicies,
+			subClusterConfig.PercentageOfNodesToScore,
+			subClusterConfig.IncreasedPercentageOfNodesToScore,
+			subClusterConfig.BasePlugins,
+			pluginArgs,
+			preemptionPluginArgs,
+		)
+		return snapshot, unitscheduler.NewUnitScheduler(
+			sched.Name,
+			switchType,
+			subCluster,
+			subClusterConfig.DisablePreemption,
+			sched.client,
+			sched.crdClient,
+			sched.podLister,
+			sched.pgLister,
+			sched.commonCache,
+			snapshot,
+			schedulingQueue,
+			reconciler,
+			sched.handoffClient,
+			sched.handoffTimeout(),
+			podScheduler,
+			unitQueueSortPlugin,
+			pluginArgs,
+			sched.clock,
+			sched.recorder,
+		)
+	}
+	snapshot, unitScheduler := newUnitScheduler()
+	if subClusterConfig.ParallelUnitWorkers > 1 {
+		workers := []core.UnitScheduler{unitScheduler}
+		for i := 1; i < int(subClusterConfig.ParallelUnitWorkers); i++ {
+			_, worker := newUnitScheduler()
+			workers = append(workers, worker)
+		}
+		unitScheduler = unitscheduler.NewParallelUnitScheduler(unitscheduler.DefaultMaxConflictRetries, workers...)
+	}
 	debugger := cachedebugger.New(
 		sched.informerFactory.Core().V1().Nodes().Lister(),
 		sched.informerFactory.Core().V1().Pods().Lister(),
@@ -306,3 +416,11 @@ func (sched *Scheduler) createSubClusterWorkflow(idx int, subCluster string) (Sc
 	}
 	return sched.ScheduleSwitch.Get(gt), sched.ScheduleSwitch.Get(be)
 }
+
+// handoffTimeout returns the longest duration to wait for the binder to bind a handed off pod.
+func (sched *Scheduler) handoffTimeout() time.Duration {
+	if sched.options.binderHandoff == nil || sched.options.binderHandoff.TimeoutSeconds <= 0 {
+		return config.DefaultBinderHandoffTimeoutInSeconds * time.Second
+	}
+	return time.Duration(sched.options.binderHandoff.TimeoutSeconds) * time.Second
+}
@@ -18,6 +18,7 @@ package scheduler
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
@@ -43,6 +44,11 @@ const (
 // StatusMaintainer manages creating and renewing the status for this Scheduler
 type StatusMaintainer interface {
 	Run(stopCh <-chan struct{})
+	// Drain marks the Scheduler as draining, so that the dispatcher stops dispatching new pods to it
+	// and hands its nodes over to other schedulers.
+	Drain() error
+	// Leave removes the Scheduler, so that the pods which are not scheduled yet are returned to the dispatcher.
+	Leave() error
 }
 
 type maintainer struct {
@@ -50,6 +56,10 @@ type maintainer struct {
 	schedulerName string
 	renewInterval time.Duration
 	clock         clock.Clock
+
+	// mu protects left, the status should not be renewed any more after the Scheduler left.
+	mu   sync.Mutex
+	left bool
 }
 
 // NewSchedulerStatusMaintainer constructs and returns a maintainer
@@ -69,12 +79,50 @@ func (c *maintainer) Run(stopCh <-chan struct{}) {
 		klog.ErrorS(nil, "Exited the scheduler status maintainer because the CRD client was nil", "schedulerName", c.schedulerName)
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
+	// the draining annotation may be left by the previous process with the same name
+	if err := c.retry(func() error { return setSchedulerDraining(c.crdClient, c.schedulerName, false) }); err != nil {
+		klog.InfoS("Failed to clear the draining state of scheduler", "schedulerName", c.schedulerName, "err", err)
+	}
 	wait.Until(c.sync, c.renewInterval, stopCh)
 }
 
+// Drain marks the Scheduler as draining
+func (c *maintainer) Drain() error {
+	return c.retry(func() error { return setSchedulerDraining(c.crdClient, c.schedulerName, true) })
+}
+
+// Leave deletes the Scheduler and stops renewing its status
+func (c *maintainer) Leave() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.left = true
+	return c.retry(func() error {
+		if err := util.DeleteScheduler(c.crdClient, c.schedulerName); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+func (c *maintainer) retry(f func() error) error {
+	var err error
+	for i := 0; i < maxUpdateRetries; i++ {
+		if err = f(); err == nil {
+			return nil
+		}
+		c.clock.Sleep(sleep)
+	}
+	return err
+}
+
 // sync attempts to update the status for Scheduler
 // update Status.LastUpdateTime at the moment
 func (c *maintainer) sync() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.left {
+		return
+	}
 	if err := ensureSchedulerUpToDate(c.crdClient, c.clock, c.schedulerName); err != nil {
 		klog.InfoS("Failed to update scheduler status, will retry later", "schedulerName", c.schedulerName, "renewInterval", c.renewInterval)
 	}
@@ -94,6 +142,31 @@ func ensureSchedulerUpToDate(client godelclient.Interface, clock clock.Clock, sc
 	return fmt.Errorf("failed %d attempts to update scheduler status", maxUpdateRetries)
 }
 
+// setSchedulerDraining adds or removes the draining annotation of the Scheduler
+func setSchedulerDraining(client godelclient.Interface, schedulerName string, draining bool) error {
+	existed, err := util.GetScheduler(client, schedulerName)
+	if err != nil {
+		if apierrors.IsNotFound(err) && !draining {
+			return nil
+		}
+		return err
+	}
+	if util.IsSchedulerDraining(existed) == draining {
+		return nil
+	}
+	updated := existed.DeepCopy()
+	if draining {
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[util.SchedulerDrainingAnnotationKey] = metav1.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(updated.Annotations, util.SchedulerDrainingAnnotationKey)
+	}
+	_, err = util.UpdateScheduler(client, updated)
+	return err
+}
+
 // updateSchedulerStatus tries to update Scheduler status to apiserver, if Scheduler not exists, add new Scheduler to apiserver
 func updateSchedulerStatus(client godelclient.Interface, schedulerName string) error {
 	existed, err := util.GetScheduler(client, schedulerName)
@@ -33,17 +33,22 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/events"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
@@ -56,13 +61,24 @@ import (
 
 func TestSchedulerCreation(t *testing.T) {
 	cases := []struct {
-		name    string
-		opts    []Option
-		wantErr string
+		name                        string
+		opts                        []Option
+		disableConcurrentScheduling bool
+		wantErr                     string
 	}{
 		{
 			name: "default scheduler",
 		},
+		{
+			name: "schedulerName profiles",
+			opts: []Option{WithSchedulerNameProfiles([]config.GodelSchedulerProfile{{SchedulerName: "godel-batch"}})},
+		},
+		{
+			name:                        "schedulerName profiles without concurrent scheduling",
+			opts:                        []Option{WithSchedulerNameProfiles([]config.GodelSchedulerProfile{{SchedulerName: "godel-batch"}})},
+			disableConcurrentScheduling: true,
+			wantErr:                     "schedulerName profiles require the feature gate",
+		},
 	}
 
 	for _, tc := range cases {
@@ -76,6 +92,9 @@ func TestSchedulerCreation(t *testing.T) {
 
 			eventBroadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
 			eventRecorder := eventBroadcaster.NewRecorder(scheme.Scheme, testSchedulerName)
+			if tc.disableConcurrentScheduling {
+				defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.SchedulerConcurrentScheduling, false)()
+			}
 
 			stopCh := make(chan struct{})
 			defer close(stopCh)
@@ -89,6 +108,7 @@ func TestSchedulerCreation(t *testing.T) {
 				katalystInformerFactory,
 				stopCh,
 				eventRecorder,
+				tc.opts...,
 			)
 			if len(tc.wantErr) != 0 {
 				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
@@ -105,13 +125,24 @@ func TestSchedulerCreation(t *testing.T) {
 
 func TestInitSchedulerCRD(t *testing.T) {
 	cases := []struct {
-		name    string
-		opts    []Option
-		wantErr string
+		name                        string
+		opts                        []Option
+		disableConcurrentScheduling bool
+		wantErr                     string
 	}{
 		{
 			name: "default scheduler",
 		},
+		{
+			name: "schedulerName profiles",
+			opts: []Option{WithSchedulerNameProfiles([]config.GodelSchedulerProfile{{SchedulerName: "godel-batch"}})},
+		},
+		{
+			name:                        "schedulerName profiles without concurrent scheduling",
+			opts:                        []Option{WithSchedulerNameProfiles([]config.GodelSchedulerProfile{{SchedulerName: "godel-batch"}})},
+			disableConcurrentScheduling: true,
+			wantErr:                     "schedulerName profiles require the feature gate",
+		},
 	}
 
 	for _, tc := range cases {
@@ -212,6 +243,78 @@ func TestSchedulerStatusErrors(t *testing.T) {
 	assert.Error(t, err, "unexpected error %v", err)
 }
 
+func TestSchedulerDrain(t *testing.T) {
+	client := clientsetfake.NewSimpleClientset()
+	crdClient := godelclientfake.NewSimpleClientset()
+	katalystCrdClient := katalystclientfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	katalystInformerFactory := katalystinformers.NewSharedInformerFactory(katalystCrdClient, 0)
+
+	eventBroadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
+	eventRecorder := eventBroadcaster.NewRecorder(scheme.Scheme, testSchedulerName)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	testingScheduler, err := New(
+		testSchedulerName,
+		&testSchedulerSysName,
+		client,
+		crdClient,
+		informerFactory,
+		crdInformerFactory,
+		katalystInformerFactory,
+		stopCh,
+		eventRecorder,
+	)
+	assert.Nil(t, err)
+	assert.NoError(t, ensureSchedulerUpToDate(testingScheduler.crdClient, testingScheduler.clock, testingScheduler.Name))
+
+	// simulate an in-flight unit
+	assert.True(t, testingScheduler.inFlight.Enter())
+	drained, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		testingScheduler.Drain(wait.ForeverTestTimeout, func() { close(stopped) })
+		close(drained)
+	}()
+
+	err = wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		scheduler, err := crdClient.SchedulingV1alpha1().Schedulers().Get(context.TODO(), testSchedulerName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return util.IsSchedulerDraining(scheduler), nil
+	})
+	assert.NoError(t, err, "scheduler was not marked as draining")
+	assert.False(t, testingScheduler.inFlight.Enter(), "no more units should be scheduled after draining started")
+	select {
+	case <-stopped:
+		t.Fatal("workflows stopped before the in-flight unit was finished")
+	case <-drained:
+		t.Fatal("scheduler left before the in-flight unit was finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	testingScheduler.inFlight.Exit()
+	select {
+	case <-drained:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the scheduler to drain")
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("workflows should be stopped before the scheduler left")
+	}
+	_, err = crdClient.SchedulingV1alpha1().Schedulers().Get(context.TODO(), testSchedulerName, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "scheduler should be deleted after draining, got %v", err)
+
+	// the status should not be renewed after the scheduler left
+	testingScheduler.schedulerMaintainer.(*maintainer).sync()
+	_, err = crdClient.SchedulingV1alpha1().Schedulers().Get(context.TODO(), testSchedulerName, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "scheduler should not be recreated after leaving, got %v", err)
+}
+
 func podWithAnnotation(pod *v1.Pod, annotations map[string]string) *v1.Pod {
 	pod.Annotations = annotations
 	return pod
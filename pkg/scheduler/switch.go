@@ -163,6 +163,10 @@ func (s *ScheduleDataSetImpl) ScheduleFunc() func(context.Context) {
 
 // TODO: revisit this rule.
 func (s *ScheduleDataSetImpl) CanBeRecycle() bool {
+	// The workflows of the schedulerName profiles live as long as the scheduler.
+	if framework.IsSchedulerNameProfileKey(s.subCluster) {
+		return false
+	}
 	return s.schedulingQueue.CanBeRecycle() && s.unitScheduler.CanBeRecycle()
 }
 
@@ -287,6 +291,11 @@ func (s *ScheduleSwitchImpl) workflowRecycle(force int) {
 				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 			}
 			freedClusterIndex := framework.FreeClusterIndex(gtDataSet.SubCluster())
+			// The cluster indexes are shared by the schedulers running in the same process (e.g. integration tests),
+			// so the index may have been freed by another scheduler when all workflows are recycled on exit.
+			if force == 1 && freedClusterIndex == -1 {
+				continue
+			}
 			if freedClusterIndex != i {
 				// TODO: revisit this message.
 				klog.ErrorS(nil, "WorkflowRecycle was invalid, freed cluster index did not match the subcluster, which should not happen", "freedClusterIndex", freedClusterIndex, "index", i, "subCluster", gtDataSet.SubCluster())
@@ -365,7 +374,7 @@ func (s *ScheduleSwitchImpl) Process(state framework.SwitchType, f ProcessFunc)
 }
 
 func ParseSwitchTypeForNode(node *v1.Node) framework.SwitchType {
-	st := framework.DefaultSubClusterSwitchType
+	st := framework.DefaultSubClusterSwitchType | framework.ParseSwitchTypeFromSchedulerNameProfiles()
 	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
 		return st |
 			framework.ParseSwitchTypeFromSubCluster(node.Labels[framework.GetGlobalSubClusterKey()])
@@ -374,7 +383,7 @@ func ParseSwitchTypeForNode(node *v1.Node) framework.SwitchType {
 }
 
 func ParseSwitchTypeForNMNode(nmNode *nodev1alpha1.NMNode) framework.SwitchType {
-	st := framework.DefaultSubClusterSwitchType
+	st := framework.DefaultSubClusterSwitchType | framework.ParseSwitchTypeFromSchedulerNameProfiles()
 	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
 		return st |
 			framework.ParseSwitchTypeFromSubCluster(nmNode.Labels[framework.GetGlobalSubClusterKey()])
@@ -383,7 +392,7 @@ func ParseSwitchTypeForNMNode(nmNode *nodev1alpha1.NMNode) framework.SwitchType
 }
 
 func ParseSwitchTypeForCNR(cnr *katalystv1alpha1.CustomNodeResource) framework.SwitchType {
-	st := framework.DefaultSubClusterSwitchType
+	st := framework.DefaultSubClusterSwitchType | framework.ParseSwitchTypeFromSchedulerNameProfiles()
 	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
 		return st |
 			framework.ParseSwitchTypeFromSubCluster(cnr.Labels[framework.GetGlobalSubClusterKey()])
@@ -393,7 +402,10 @@ func ParseSwitchTypeForCNR(cnr *katalystv1alpha1.CustomNodeResource) framework.S
 
 func ParseSwitchTypeForPod(pod *v1.Pod) framework.SwitchType {
 	var st framework.SwitchType
-	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
+	if profileSt := framework.ParseSwitchTypeFromSchedulerName(pod.Spec.SchedulerName); profileSt != 0 {
+		// The pods of a schedulerName profile are always scheduled by the workflows of the profile.
+		st = profileSt
+	} else if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
 		st = framework.ParseSwitchTypeFromSubCluster(pod.Spec.NodeSelector[framework.GetGlobalSubClusterKey()])
 	} else {
 		st = framework.DefaultSubClusterSwitchType
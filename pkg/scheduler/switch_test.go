@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestParseSwitchTypeWithSchedulerNameProfiles(t *testing.T) {
+	framework.CleanClusterIndex()
+	defer framework.CleanClusterIndex()
+
+	defaultIdx := framework.AllocClusterIndex(framework.DefaultSubCluster)
+	batchIdx := framework.AllocClusterIndex(framework.SchedulerNameProfileKey("godel-batch"))
+	batchGT, batchBE := framework.ClusterIndexToSwitchType(batchIdx)
+	defaultGT, defaultBE := framework.ClusterIndexToSwitchType(defaultIdx)
+
+	makePod := func(schedulerName string, resourceType podutil.PodResourceType) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "p",
+				Namespace:   "default",
+				Annotations: map[string]string{podutil.PodResourceTypeAnnotationKey: string(resourceType)},
+			},
+			Spec: v1.PodSpec{SchedulerName: schedulerName},
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		pod      *v1.Pod
+		expected framework.SwitchType
+	}{
+		{
+			name:     "GT pod of the primary scheduler name",
+			pod:      makePod(testSchedulerSysName, podutil.GuaranteedPod),
+			expected: defaultGT,
+		},
+		{
+			name:     "BE pod of the primary scheduler name",
+			pod:      makePod(testSchedulerSysName, podutil.BestEffortPod),
+			expected: defaultBE,
+		},
+		{
+			name:     "GT pod of the schedulerName profile",
+			pod:      makePod("godel-batch", podutil.GuaranteedPod),
+			expected: batchGT,
+		},
+		{
+			name:     "BE pod of the schedulerName profile",
+			pod:      makePod("godel-batch", podutil.BestEffortPod),
+			expected: batchBE,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSwitchTypeForPod(tt.pod); got != tt.expected {
+				t.Errorf("expected switch type %b, got %b", tt.expected, got)
+			}
+		})
+	}
+
+	// The workflows of the schedulerName profiles see all the nodes.
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n"}}
+	if got, expected := ParseSwitchTypeForNode(node), defaultGT|defaultBE|batchGT|batchBE; got != expected {
+		t.Errorf("expected node switch type %b, got %b", expected, got)
+	}
+}
@@ -76,3 +76,7 @@ func (gs *MockUnitSchedulerHandle) IsCachedPod(pod *v1.Pod) (bool, error) {
 func (gs *MockUnitSchedulerHandle) GetNodeInfo(nodeName string) framework.NodeInfo {
 	return gs.Snapshot.GetNodeInfo(nodeName)
 }
+
+func (gs *MockUnitSchedulerHandle) GetReservedNodes(pod *v1.Pod) []string {
+	return gs.Snapshot.GetReservedNodes(pod)
+}
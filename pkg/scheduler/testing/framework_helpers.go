@@ -104,6 +104,14 @@ func (mfh *MockSchedulerFrameworkHandle) GetLoadAwareNodeUsage(nodeName string,
 	return mfh.nodeInfoSnapshot.GetLoadAwareNodeUsage(nodeName, resourceType)
 }
 
+func (mfh *MockSchedulerFrameworkHandle) GetLoadAwareWorkloadUsage(workloadKey string, percentile int64) *framework.LoadAwareWorkloadUsage {
+	return mfh.nodeInfoSnapshot.GetLoadAwareWorkloadUsage(workloadKey, percentile)
+}
+
+func (mfh *MockSchedulerFrameworkHandle) GetNodeReservations(nodeName string) []*framework.ResourceReservation {
+	return mfh.nodeInfoSnapshot.GetNodeReservations(nodeName)
+}
+
 func (mfh *MockSchedulerFrameworkHandle) retrievePluginsFromPodConstraints(pod *v1.Pod, constraintAnnotationKey string) (*framework.PluginCollection, error) {
 	podConstraints, err := frameworkconfig.GetConstraints(pod, constraintAnnotationKey)
 	if err != nil {
@@ -37,6 +37,7 @@ import (
 	storagelisters "k8s.io/client-go/listers/storage/v1"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util/preemptionbudget"
 )
 
 var _ framework.SharedLister = &fakeSharedLister{}
@@ -116,6 +117,10 @@ func (f *fakeSharedLister) GetPreemptorsByVictim(node, victim string) []string {
 	return nil
 }
 
+func (f *fakeSharedLister) GetPreemptionBudgetUsage(budget *preemptionbudget.Budget) preemptionbudget.Usage {
+	return nil
+}
+
 type fakeDeploymentNamespaceLister struct {
 	dpMap map[string]*appsv1.Deployment
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handoff
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Path is the path of the handoff endpoint served by binders.
+const Path = "/handoff"
+
+// Placement is the assumed placement of a pod handed off from a scheduler to a binder directly,
+// instead of being persisted in the pod annotations first.
+type Placement struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+	// Annotations are the annotations the scheduler would have patched on the pod, including the pod
+	// state, the assumed or nominated node along with the victims, and the trace context.
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Request is the body of a handoff request.
+type Request struct {
+	// Scheduler is the name of the scheduler handing off the placements.
+	Scheduler  string      `json:"scheduler"`
+	Placements []Placement `json:"placements"`
+}
+
+// Response is the body of a handoff response.
+type Response struct {
+	// Accepted are the UIDs of the pods accepted by the binder. The placements of the other pods
+	// must be persisted by the scheduler through the pod annotations.
+	Accepted []types.UID `json:"accepted"`
+}
+
+// Client hands off placements to a binder.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client handing off placements to the binder listening on address through mutual TLS.
+func NewClient(address string, timeout time.Duration, tlsConfig *tls.Config) *Client {
+	address = strings.TrimPrefix(address, "https://")
+	return &Client{
+		url: "https://" + strings.TrimSuffix(address, "/") + Path,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}
+}
+
+// HandOff sends the placements to the binder and returns the UIDs of the accepted pods.
+func (c *Client) HandOff(ctx context.Context, request *Request) (map[types.UID]bool, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, c.url)
+	}
+
+	var response Response
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	accepted := make(map[types.UID]bool, len(response.Accepted))
+	for _, uid := range response.Accepted {
+		accepted[uid] = true
+	}
+	return accepted, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handoff
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubewharf/godel-scheduler/test/utils"
+)
+
+func TestClientHandOff(t *testing.T) {
+	var got Request
+	certs := utils.NewTestCertificates(t)
+	server := newTestServer(t, certs, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != Path || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(&Response{Accepted: []types.UID{got.Placements[0].UID}})
+	}))
+	defer server.Close()
+
+	request := &Request{
+		Scheduler: "scheduler",
+		Placements: []Placement{
+			{Namespace: "default", Name: "p1", UID: "uid1", Annotations: map[string]string{"k": "v"}},
+			{Namespace: "default", Name: "p2", UID: "uid2"},
+		},
+	}
+	accepted, err := NewClient(server.URL, time.Second, clientTLSConfig(t, certs, "scheduler")).HandOff(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(accepted, map[types.UID]bool{"uid1": true}) {
+		t.Errorf("unexpected accepted pods: %v", accepted)
+	}
+	if !reflect.DeepEqual(&got, request) {
+		t.Errorf("unexpected request received: %+v", got)
+	}
+}
+
+func TestClientHandOffError(t *testing.T) {
+	certs := utils.NewTestCertificates(t)
+	server := newTestServer(t, certs, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL, time.Second, clientTLSConfig(t, certs, "scheduler")).HandOff(context.Background(), &Request{}); err == nil {
+		t.Errorf("expected error for unavailable binder")
+	}
+}
+
+func TestClientHandOffUnauthorized(t *testing.T) {
+	certs := utils.NewTestCertificates(t)
+	server := newTestServer(t, certs, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Response{})
+	}))
+	defer server.Close()
+
+	// the client certificate is not allowed
+	if _, err := NewClient(server.URL, time.Second, clientTLSConfig(t, certs, "intruder")).HandOff(context.Background(), &Request{}); err == nil {
+		t.Errorf("expected error for the client not allowed")
+	}
+	// no client certificate is presented
+	noClientCert := &tls.Config{RootCAs: clientTLSConfig(t, certs, "scheduler").RootCAs}
+	if _, err := NewClient(server.URL, time.Second, noClientCert).HandOff(context.Background(), &Request{}); err == nil {
+		t.Errorf("expected error for the client without certificate")
+	}
+	// the request is not sent through TLS at all
+	recorder := httptest.NewRecorder()
+	WithAuthorization(http.NotFoundHandler(), sets.NewString("scheduler")).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, Path, nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("unexpected status code %d", recorder.Code)
+	}
+}
+
+func clientTLSConfig(t *testing.T, certs *utils.TestCertificates, commonName string) *tls.Config {
+	certFile, keyFile := certs.Issue(t, commonName, x509.ExtKeyUsageClientAuth)
+	config, err := NewClientTLSConfig(certFile, keyFile, certs.CAFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// newTestServer starts a handoff server only allowing the client named "scheduler".
+func newTestServer(t *testing.T, certs *utils.TestCertificates, handler http.Handler) *httptest.Server {
+	certFile, keyFile := certs.Issue(t, "binder", x509.ExtKeyUsageServerAuth)
+	config, err := NewServerTLSConfig(certFile, keyFile, certs.CAFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(WithAuthorization(handler, sets.NewString("scheduler")))
+	server.TLS = config
+	server.StartTLS()
+	return server
+}
@@ -262,6 +262,15 @@ func (h *Heap) String() string {
 	return h.name
 }
 
+// Refresh calls f on every item, which may change the order of the items, and then restores the heap
+// invariant. It's the only way the order of the items already in the heap may change.
+func (h *Heap) Refresh(f func(obj interface{})) {
+	for _, item := range h.data.items {
+		f(item.obj)
+	}
+	heap.Init(h.data)
+}
+
 // `f` must be a read-only function
 func (h *Heap) Process(f ProcessFunc) {
 	parallelize.Until(context.Background(), len(h.data.queue), func(i int) {
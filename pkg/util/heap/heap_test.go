@@ -269,3 +269,36 @@ func TestHeap_List(t *testing.T) {
 		}
 	}
 }
+
+// TestHeap_Refresh tests that the heap invariant is restored after the items are changed.
+func TestHeap_Refresh(t *testing.T) {
+	comparePointedInts := func(val1 interface{}, val2 interface{}) bool {
+		return *val1.(testHeapObject).val.(*int) < *val2.(testHeapObject).val.(*int)
+	}
+	h := New("", testHeapObjectKeyFunc, comparePointedInts)
+	for i := 0; i < 50; i++ {
+		val := i
+		h.Add(mkHeapObj(string([]rune{'a', rune(i)}), &val))
+	}
+
+	// Reverse the order of all the items.
+	h.Refresh(func(obj interface{}) {
+		val := obj.(testHeapObject).val.(*int)
+		*val = -*val
+	})
+	prevNum := -50
+	for h.Len() > 0 {
+		obj, err := h.Pop()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		num := *obj.(testHeapObject).val.(*int)
+		if num <= prevNum {
+			t.Fatalf("got %v out of order, last was %v", num, prevNum)
+		}
+		prevNum = num
+	}
+	if prevNum != 0 {
+		t.Errorf("expected the last item to be 0, got %v", prevNum)
+	}
+}
@@ -66,6 +66,14 @@ const (
 	// TODO: figure out if we can return multiple nodes ? if so how to deal with scheduler cache ?
 	NominatedNodeAnnotationKey = "godel.bytedance.com/nominated-node"
 
+	// HandoffTokenAnnotationKey is a pod annotation key, value is the token identifying a placement handed off
+	// by a scheduler to the binder directly. It is persisted along with the placement.
+	HandoffTokenAnnotationKey = "godel.bytedance.com/handoff-token"
+
+	// HandoffRejectedAnnotationKey is a pod annotation key, value is the token of the handed off placement
+	// rejected by the binder, so that the scheduler never persists the rejected placement afterwards.
+	HandoffRejectedAnnotationKey = "godel.bytedance.com/handoff-rejected"
+
 	// ATTENTION: This annotation key will be DEPRECATED in the future and REPLACED by `QoSLevelKey=katalyst.kubewharf.io/qos_level`.
 	// PodResourceTypeAnnotationKey is a pod annotation key, value is the pod resource type (guaranteed or best-effort)
 	PodResourceTypeAnnotationKey = "godel.bytedance.com/pod-resource-type"
@@ -79,6 +87,14 @@ const (
 	// MicroTopologyKey is an annotation key for pod micro topology assigned by scheduler&binder
 	MicroTopologyKey = "godel.bytedance.com/micro-topology"
 
+	// GPUShareDeviceAnnotationKey is a pod annotation key, value is the index of the shared GPU device assigned by scheduler,
+	// the device plugin allocates the device according to it.
+	GPUShareDeviceAnnotationKey = "godel.bytedance.com/gpu-share-device"
+
+	// LocalStorageRequestsAnnotationKey is a pod annotation key, value is the storage requested by the pod from the
+	// node-local storage pools keyed by storage class, e.g. "local-lvm=100Gi". It's set by scheduler.
+	LocalStorageRequestsAnnotationKey = "godel.bytedance.com/local-storage-requests"
+
 	IgnorePodsLimitAnnotationKey = "godel.bytedance.com/ignore-pods-limit"
 
 	ProtectionDurationFromPreemptionKey = "godel.bytedance.com/protection-duration-from-preemption"
@@ -91,6 +107,14 @@ const (
 	IncreasePercentageOfNodesToScoreAnnotationKey = "godel.bytedance.com/increase-percentage-of-nodes-to-score"
 
 	IncreasePercentageOfNodesToScore = "true"
+
+	// SkipTenantAdmissionAnnotationKey is a pod annotation key, pods with this annotation set to "true" are
+	// dispatched without being limited by the tenant admission rate, it's used by system workloads.
+	SkipTenantAdmissionAnnotationKey = "godel.bytedance.com/skip-tenant-admission"
+
+	// FairShareQueueAnnotationKey is a pod and PodGroup annotation key, value is the fair-share queue the unit is
+	// accounted to by the FairShare unit queue sort plugin. The namespace is used if it's not set.
+	FairShareQueueAnnotationKey = "godel.bytedance.com/fair-share-queue"
 )
 
 type PodState string
@@ -170,27 +194,27 @@ func DispatchedPod(pod *v1.Pod) bool {
 	return false
 }
 
-func DispatchedPodOfGodel(pod *v1.Pod, schedulerName string) bool {
+func DispatchedPodOfGodel(pod *v1.Pod, schedulerNames ...string) bool {
 	if LegalPodResourceTypeAndLauncher(pod) &&
-		responsibleForPod(pod, schedulerName) &&
+		responsibleForPod(pod, schedulerNames...) &&
 		DispatchedPod(pod) {
 		return true
 	}
 	return false
 }
 
-func AssumedPodOfGodel(pod *v1.Pod, schedulerName string) bool {
+func AssumedPodOfGodel(pod *v1.Pod, schedulerNames ...string) bool {
 	if LegalPodResourceTypeAndLauncher(pod) &&
-		responsibleForPod(pod, schedulerName) &&
+		responsibleForPod(pod, schedulerNames...) &&
 		AssumedPod(pod) {
 		return true
 	}
 	return false
 }
 
-func PendingPodOfGodel(pod *v1.Pod, schedulerName string) bool {
+func PendingPodOfGodel(pod *v1.Pod, schedulerNames ...string) bool {
 	if LegalPodResourceTypeAndLauncher(pod) &&
-		responsibleForPod(pod, schedulerName) &&
+		responsibleForPod(pod, schedulerNames...) &&
 		PendingPod(pod) {
 		return true
 	}
@@ -277,8 +301,8 @@ func AbnormalPodState(pod *v1.Pod) bool {
 	}
 }
 
-func AbnormalPodStateOfGodel(pod *v1.Pod, schedulerName string) bool {
-	if AbnormalPodState(pod) && responsibleForPod(pod, schedulerName) {
+func AbnormalPodStateOfGodel(pod *v1.Pod, schedulerNames ...string) bool {
+	if AbnormalPodState(pod) && responsibleForPod(pod, schedulerNames...) {
 		return true
 	}
 	return false
@@ -433,6 +433,34 @@ func GetPodLauncher(pod *v1.Pod) (PodLauncher, error) {
 	return Kubelet, nil
 }
 
+// GetPodGPUShareDevice returns the index of the shared GPU device assigned to the pod, false means not assigned.
+func GetPodGPUShareDevice(pod *v1.Pod) (int, bool) {
+	value, ok := pod.Annotations[GPUShareDeviceAnnotationKey]
+	if !ok {
+		return -1, false
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		klog.InfoS("WARN: Invalid shared GPU device of pod", "pod", klog.KObj(pod), "device", value)
+		return -1, false
+	}
+	return index, true
+}
+
+// GetPodLocalStorageRequests returns the storage requested by the pod from the node-local storage pools.
+func GetPodLocalStorageRequests(pod *v1.Pod) map[string]int64 {
+	value, ok := pod.Annotations[LocalStorageRequestsAnnotationKey]
+	if !ok {
+		return nil
+	}
+	requests, err := util.UnmarshalStorageQuantities(value)
+	if err != nil {
+		klog.InfoS("WARN: Invalid local storage requests of pod", "pod", klog.KObj(pod), "err", err)
+		return nil
+	}
+	return requests
+}
+
 // IsLongRunningTask checks if this pod is long-running task
 func IsLongRunningTask(pod *v1.Pod) bool {
 	if pod.Annotations == nil {
@@ -565,6 +593,15 @@ func GetPodKey(pod *v1.Pod) string {
 	return pod.Namespace + "/" + pod.Name
 }
 
+// GetFairShareQueue returns the fair-share queue of the object with the given namespace and annotations,
+// which is the namespace unless the FairShareQueueAnnotationKey annotation is set.
+func GetFairShareQueue(namespace string, annotations map[string]string) string {
+	if queue := annotations[FairShareQueueAnnotationKey]; len(queue) > 0 {
+		return queue
+	}
+	return namespace
+}
+
 func LegalPodResourceTypeAndLauncher(pod *v1.Pod) bool {
 	if _, err := GetPodLauncher(pod); err != nil {
 		return false
@@ -576,10 +613,18 @@ func LegalPodResourceTypeAndLauncher(pod *v1.Pod) bool {
 	return true
 }
 
-func responsibleForPod(pod *v1.Pod, schedulerName string) bool {
-	return pod.Spec.SchedulerName == schedulerName ||
-		pod.Spec.SchedulerName == v1.DefaultSchedulerName ||
-		pod.Spec.SchedulerName == ""
+// responsibleForPod returns true if the pod is expected to be handled by one of the
+// given scheduler names, i.e. the primary name and the names of the schedulerName profiles.
+func responsibleForPod(pod *v1.Pod, schedulerNames ...string) bool {
+	if pod.Spec.SchedulerName == v1.DefaultSchedulerName || pod.Spec.SchedulerName == "" {
+		return true
+	}
+	for _, name := range schedulerNames {
+		if pod.Spec.SchedulerName == name {
+			return true
+		}
+	}
+	return false
 }
 
 const RSKind = "ReplicaSet"
@@ -854,6 +899,8 @@ func CleanupPodAnnotations(client clientset.Interface, pod *v1.Pod) error {
 	delete(podCopy.Annotations, NominatedNodeAnnotationKey)
 	delete(podCopy.Annotations, FailedSchedulersAnnotationKey)
 	delete(podCopy.Annotations, MicroTopologyKey)
+	delete(podCopy.Annotations, GPUShareDeviceAnnotationKey)
+	delete(podCopy.Annotations, LocalStorageRequestsAnnotationKey)
 
 	// reset pod state to dispatched
 	podCopy.Annotations[PodStateAnnotationKey] = string(PodDispatched)
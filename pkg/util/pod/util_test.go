@@ -59,3 +59,52 @@ func TestGetOwnerInfo(t *testing.T) {
 		t.Errorf("got owner error for p2")
 	}
 }
+
+func TestResponsibleForPod(t *testing.T) {
+	makePod := func(schedulerName string) *v1.Pod {
+		return &v1.Pod{Spec: v1.PodSpec{SchedulerName: schedulerName}}
+	}
+	for _, tt := range []struct {
+		name           string
+		pod            *v1.Pod
+		schedulerNames []string
+		expected       bool
+	}{
+		{
+			name:           "empty scheduler name",
+			pod:            makePod(""),
+			schedulerNames: []string{"godel-scheduler"},
+			expected:       true,
+		},
+		{
+			name:           "default scheduler name",
+			pod:            makePod(v1.DefaultSchedulerName),
+			schedulerNames: []string{"godel-scheduler"},
+			expected:       true,
+		},
+		{
+			name:           "primary scheduler name",
+			pod:            makePod("godel-scheduler"),
+			schedulerNames: []string{"godel-scheduler", "godel-batch"},
+			expected:       true,
+		},
+		{
+			name:           "scheduler name of a profile",
+			pod:            makePod("godel-batch"),
+			schedulerNames: []string{"godel-scheduler", "godel-batch"},
+			expected:       true,
+		},
+		{
+			name:           "irrelevant scheduler name",
+			pod:            makePod("other-scheduler"),
+			schedulerNames: []string{"godel-scheduler", "godel-batch"},
+			expected:       false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := responsibleForPod(tt.pod, tt.schedulerNames...); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
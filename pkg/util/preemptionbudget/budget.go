@@ -0,0 +1,315 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionbudget
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// Window is the sliding window in which the victims are accounted in the budgets.
+const Window = time.Minute
+
+const (
+	// PriorityBandBudget limits the victims preempted by the preemptors in a priority band.
+	PriorityBandBudget = "priority_band"
+	// NamespaceBudget limits the victims preempted in a namespace.
+	NamespaceBudget = "namespace"
+	// NodePoolBudget limits the victims preempted on the nodes of a node pool.
+	NodePoolBudget = "node_pool"
+)
+
+// Budget is the cluster-wide max number of victims preempted per minute, it is shared by the binders
+// which enforce it and the schedulers which stop nominating victims once it is exhausted.
+type Budget struct {
+	// PriorityBands limit the victims of the preemptors in the bands, a preemptor belongs to the band with
+	// the highest MinPriority not greater than its priority.
+	PriorityBands []PriorityBand `json:"priorityBands,omitempty"`
+	// MaxVictimsPerMinutePerNamespace limits the victims in each namespace, 0 means no limit.
+	MaxVictimsPerMinutePerNamespace int32 `json:"maxVictimsPerMinutePerNamespace,omitempty"`
+	// NodePoolLabelKey is the node label whose value is the node pool of the node.
+	NodePoolLabelKey string `json:"nodePoolLabelKey,omitempty"`
+	// MaxVictimsPerMinutePerNodePool limits the victims on the nodes of each node pool, 0 means no limit.
+	MaxVictimsPerMinutePerNodePool int32 `json:"maxVictimsPerMinutePerNodePool,omitempty"`
+}
+
+// PriorityBand is the budget of the preemptors in one priority band.
+type PriorityBand struct {
+	// MinPriority is the lower bound of the priority of the preemptors in the band.
+	MinPriority int32 `json:"minPriority"`
+	// MaxVictimsPerMinute limits the victims of the preemptors in the band, 0 means no limit.
+	MaxVictimsPerMinute int32 `json:"maxVictimsPerMinute,omitempty"`
+}
+
+// Validate validates the budget.
+func (b *Budget) Validate(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if b == nil {
+		return errs
+	}
+	minPriorities := make(map[int32]bool, len(b.PriorityBands))
+	for i, band := range b.PriorityBands {
+		if minPriorities[band.MinPriority] {
+			errs = append(errs, field.Duplicate(fldPath.Child("priorityBands").Index(i).Child("minPriority"), band.MinPriority))
+		}
+		minPriorities[band.MinPriority] = true
+		if band.MaxVictimsPerMinute < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("priorityBands").Index(i).Child("maxVictimsPerMinute"), band.MaxVictimsPerMinute, "must be non-negative"))
+		}
+	}
+	if b.MaxVictimsPerMinutePerNamespace < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxVictimsPerMinutePerNamespace"), b.MaxVictimsPerMinutePerNamespace, "must be non-negative"))
+	}
+	if b.MaxVictimsPerMinutePerNodePool < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("maxVictimsPerMinutePerNodePool"), b.MaxVictimsPerMinutePerNodePool, "must be non-negative"))
+	}
+	if b.MaxVictimsPerMinutePerNodePool > 0 {
+		if len(b.NodePoolLabelKey) == 0 {
+			errs = append(errs, field.Required(fldPath.Child("nodePoolLabelKey"), "required when the node pool budget is set"))
+		} else {
+			for _, msg := range validation.IsQualifiedName(b.NodePoolLabelKey) {
+				errs = append(errs, field.Invalid(fldPath.Child("nodePoolLabelKey"), b.NodePoolLabelKey, msg))
+			}
+		}
+	}
+	return errs
+}
+
+// DeepCopyInto copies the receiver into out.
+func (b *Budget) DeepCopyInto(out *Budget) {
+	*out = *b
+	if b.PriorityBands != nil {
+		out.PriorityBands = make([]PriorityBand, len(b.PriorityBands))
+		copy(out.PriorityBands, b.PriorityBands)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (b *Budget) DeepCopy() *Budget {
+	if b == nil {
+		return nil
+	}
+	out := new(Budget)
+	b.DeepCopyInto(out)
+	return out
+}
+
+// Key identifies one budget, e.g. the budget of the namespace "default".
+type Key struct {
+	Type  string
+	Value string
+}
+
+func (k Key) String() string {
+	return k.Type + "/" + k.Value
+}
+
+// Victim is a preemption victim accounted in the budgets.
+type Victim struct {
+	// Key is the unique key of the victim pod, a victim nominated by several preemptors is accounted once.
+	Key       string
+	Namespace string
+	// NodeLabels are the labels of the node the victim is running on.
+	NodeLabels        map[string]string
+	PreemptorPriority int32
+}
+
+// NewVictim returns the victim pod preempted by the preemptor, running on the node with the given labels.
+func NewVictim(preemptor *v1.Pod, namespace, name, uid string, nodeLabels map[string]string) Victim {
+	return Victim{
+		Key:               podutil.GetPodFullKey(namespace, name, uid),
+		Namespace:         namespace,
+		NodeLabels:        nodeLabels,
+		PreemptorPriority: podutil.GetPodPriority(preemptor),
+	}
+}
+
+// ExhaustedError is returned when admitting the victims exceeds a budget.
+type ExhaustedError struct {
+	Key   Key
+	Limit int32
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("preemption budget %v exhausted, at most %d victims per minute", e.Key, e.Limit)
+}
+
+// Keys returns the keys of the budgets limiting the victim.
+func (b *Budget) Keys(v Victim) []Key {
+	keys := make([]Key, 0, 3)
+	if key, ok := b.priorityBandKey(v.PreemptorPriority); ok {
+		keys = append(keys, key)
+	}
+	if b.MaxVictimsPerMinutePerNamespace > 0 {
+		keys = append(keys, Key{Type: NamespaceBudget, Value: v.Namespace})
+	}
+	if nodePool := v.NodeLabels[b.NodePoolLabelKey]; b.MaxVictimsPerMinutePerNodePool > 0 && len(nodePool) > 0 {
+		keys = append(keys, Key{Type: NodePoolBudget, Value: nodePool})
+	}
+	return keys
+}
+
+// Limit returns the max number of victims per minute of the budget, 0 means no limit.
+func (b *Budget) Limit(key Key) int32 {
+	switch key.Type {
+	case PriorityBandBudget:
+		for _, band := range b.PriorityBands {
+			if strconv.Itoa(int(band.MinPriority)) == key.Value {
+				return band.MaxVictimsPerMinute
+			}
+		}
+	case NamespaceBudget:
+		return b.MaxVictimsPerMinutePerNamespace
+	case NodePoolBudget:
+		return b.MaxVictimsPerMinutePerNodePool
+	}
+	return 0
+}
+
+// Exhausted returns the first budget limiting the victim which has no room left in the usage.
+func (b *Budget) Exhausted(usage Usage, v Victim) (Key, bool) {
+	for _, key := range b.Keys(v) {
+		if usage[key] >= b.Limit(key) {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// PriorityBandExhausted returns whether the budget of the priority band of the preemptor has no room left.
+func (b *Budget) PriorityBandExhausted(usage Usage, preemptorPriority int32) (Key, bool) {
+	key, ok := b.priorityBandKey(preemptorPriority)
+	if !ok {
+		return Key{}, false
+	}
+	return key, usage[key] >= b.Limit(key)
+}
+
+// NodePoolExhausted returns whether the budget of the node pool of the node has no room left.
+func (b *Budget) NodePoolExhausted(usage Usage, nodeLabels map[string]string) (Key, bool) {
+	nodePool := nodeLabels[b.NodePoolLabelKey]
+	if b.MaxVictimsPerMinutePerNodePool <= 0 || len(nodePool) == 0 {
+		return Key{}, false
+	}
+	key := Key{Type: NodePoolBudget, Value: nodePool}
+	return key, usage[key] >= b.MaxVictimsPerMinutePerNodePool
+}
+
+// priorityBandKey returns the key of the priority band of the preemptor if the band is limited.
+func (b *Budget) priorityBandKey(priority int32) (Key, bool) {
+	var (
+		matched PriorityBand
+		found   bool
+	)
+	for _, band := range b.PriorityBands {
+		if priority >= band.MinPriority && (!found || band.MinPriority > matched.MinPriority) {
+			matched, found = band, true
+		}
+	}
+	if !found || matched.MaxVictimsPerMinute <= 0 {
+		return Key{}, false
+	}
+	return Key{Type: PriorityBandBudget, Value: strconv.Itoa(int(matched.MinPriority))}, true
+}
+
+// Usage is the number of victims accounted in each budget in the window.
+type Usage map[Key]int32
+
+type record struct {
+	victim    Victim
+	timestamp time.Time
+}
+
+// Tracker keeps the victims preempted in the last Window. It is safe for concurrent use.
+type Tracker struct {
+	lock    sync.Mutex
+	records map[string]record
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{records: make(map[string]record)}
+}
+
+// Record accounts the victims at now, the victims already accounted in the window are skipped.
+func (t *Tracker) Record(victims []Victim, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.record(victims, now)
+}
+
+// Usage returns the usage of the budget in the window ending at now.
+func (t *Tracker) Usage(b *Budget, now time.Time) Usage {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.usage(b, now)
+}
+
+// Admit accounts the victims at now if none of the budgets is exceeded by them, otherwise nothing is
+// accounted and an ExhaustedError is returned.
+func (t *Tracker) Admit(b *Budget, victims []Victim, now time.Time) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	usage := t.usage(b, now)
+	admitted := make(map[string]bool, len(victims))
+	for _, v := range victims {
+		if _, ok := t.records[v.Key]; ok || admitted[v.Key] {
+			continue
+		}
+		admitted[v.Key] = true
+		for _, key := range b.Keys(v) {
+			usage[key]++
+			if limit := b.Limit(key); usage[key] > limit {
+				return &ExhaustedError{Key: key, Limit: limit}
+			}
+		}
+	}
+	t.record(victims, now)
+	return nil
+}
+
+func (t *Tracker) record(victims []Victim, now time.Time) {
+	for _, v := range victims {
+		if _, ok := t.records[v.Key]; !ok {
+			t.records[v.Key] = record{victim: v, timestamp: now}
+		}
+	}
+}
+
+// usage also drops the records out of the window.
+func (t *Tracker) usage(b *Budget, now time.Time) Usage {
+	usage := make(Usage)
+	for key, r := range t.records {
+		if now.Sub(r.timestamp) >= Window {
+			delete(t.records, key)
+			continue
+		}
+		for _, k := range b.Keys(r.victim) {
+			usage[k]++
+		}
+	}
+	return usage
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionbudget
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testBudget() *Budget {
+	return &Budget{
+		PriorityBands: []PriorityBand{
+			{MinPriority: 100, MaxVictimsPerMinute: 3},
+			{MinPriority: 1000, MaxVictimsPerMinute: 0},
+		},
+		MaxVictimsPerMinutePerNamespace: 2,
+		NodePoolLabelKey:                "pool",
+		MaxVictimsPerMinutePerNodePool:  5,
+	}
+}
+
+func TestBudgetKeys(t *testing.T) {
+	b := testBudget()
+	tests := []struct {
+		name   string
+		victim Victim
+		want   []Key
+	}{
+		{
+			name:   "all budgets",
+			victim: Victim{Key: "v", Namespace: "ns", NodeLabels: map[string]string{"pool": "a"}, PreemptorPriority: 200},
+			want: []Key{
+				{Type: PriorityBandBudget, Value: "100"},
+				{Type: NamespaceBudget, Value: "ns"},
+				{Type: NodePoolBudget, Value: "a"},
+			},
+		},
+		{
+			name:   "unlimited band and no node pool",
+			victim: Victim{Key: "v", Namespace: "ns", PreemptorPriority: 2000},
+			want:   []Key{{Type: NamespaceBudget, Value: "ns"}},
+		},
+		{
+			name:   "below all bands",
+			victim: Victim{Key: "v", Namespace: "ns", PreemptorPriority: 10},
+			want:   []Key{{Type: NamespaceBudget, Value: "ns"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Keys(tt.victim); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected keys %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTrackerAdmit(t *testing.T) {
+	b := testBudget()
+	tracker := NewTracker()
+	now := time.Now()
+
+	victims := []Victim{
+		{Key: "ns1/v1", Namespace: "ns1", PreemptorPriority: 200},
+		{Key: "ns1/v2", Namespace: "ns1", PreemptorPriority: 200},
+	}
+	if err := tracker.Admit(b, victims, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The victims already accounted are not accounted again.
+	if err := tracker.Admit(b, victims[:1], now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := tracker.Admit(b, []Victim{{Key: "ns1/v3", Namespace: "ns1", PreemptorPriority: 200}}, now)
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Key != (Key{Type: NamespaceBudget, Value: "ns1"}) {
+		t.Fatalf("expected namespace budget to be exhausted, got %v", err)
+	}
+
+	if err := tracker.Admit(b, []Victim{{Key: "ns2/v1", Namespace: "ns2", PreemptorPriority: 200}}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = tracker.Admit(b, []Victim{{Key: "ns3/v1", Namespace: "ns3", PreemptorPriority: 200}}, now)
+	if !errors.As(err, &exhausted) || exhausted.Key != (Key{Type: PriorityBandBudget, Value: "100"}) {
+		t.Fatalf("expected priority band budget to be exhausted, got %v", err)
+	}
+
+	usage := tracker.Usage(b, now)
+	if key, ok := b.PriorityBandExhausted(usage, 150); !ok || key.Value != "100" {
+		t.Errorf("expected priority band 100 to be exhausted")
+	}
+	if _, ok := b.PriorityBandExhausted(usage, 1500); ok {
+		t.Errorf("expected priority band 1000 not to be limited")
+	}
+	if _, ok := b.NodePoolExhausted(usage, map[string]string{"pool": "a"}); ok {
+		t.Errorf("expected node pool a not to be exhausted")
+	}
+
+	// The budgets are refilled once the victims are out of the window.
+	if err := tracker.Admit(b, []Victim{{Key: "ns1/v3", Namespace: "ns1", PreemptorPriority: 200}}, now.Add(Window)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tracker.Usage(b, now.Add(Window)); got[Key{Type: NamespaceBudget, Value: "ns1"}] != 1 {
+		t.Errorf("expected 1 victim in namespace ns1, got %v", got)
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Config configures the membership leases of a group of active instances sharing the work.
+type Config struct {
+	// LeaseNamespace is the namespace of the membership leases.
+	LeaseNamespace string
+	// LeaseGroupLabelKey is the label of the membership leases, whose value is the group of the instances.
+	LeaseGroupLabelKey string
+	// LeaseDuration is the duration after which an instance not renewing its lease leaves the group.
+	LeaseDuration time.Duration
+	// RenewInterval is the interval of renewing the lease and refreshing the membership.
+	RenewInterval time.Duration
+}
+
+// Members is an immutable view of the shard membership from the perspective of one instance.
+type Members struct {
+	identity string
+	members  []string
+}
+
+// NewMembers returns the membership of the given instances, which always includes the instance itself.
+func NewMembers(identity string, members ...string) *Members {
+	set := map[string]bool{identity: true}
+	for _, member := range members {
+		set[member] = true
+	}
+	list := make([]string, 0, len(set))
+	for member := range set {
+		list = append(list, member)
+	}
+	sort.Strings(list)
+	return &Members{identity: identity, members: list}
+}
+
+// Owner returns the instance owning the key. Rendezvous hashing is used so that only the keys
+// owned by the joining or leaving instance move when the membership changes.
+func (m *Members) Owner(key string) string {
+	var owner string
+	var max uint64
+	for _, member := range m.members {
+		if weight := rendezvousWeight(member, key); len(owner) == 0 || weight > max {
+			owner, max = member, weight
+		}
+	}
+	return owner
+}
+
+// Owns returns whether the key is owned by the instance itself.
+func (m *Members) Owns(key string) bool {
+	return m.Owner(key) == m.identity
+}
+
+// List returns the sorted identities of the instances.
+func (m *Members) List() []string {
+	return append([]string{}, m.members...)
+}
+
+// Equal returns whether the two views contain the same instances.
+func (m *Members) Equal(other *Members) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	if m.identity != other.identity || len(m.members) != len(other.members) {
+		return false
+	}
+	for i := range m.members {
+		if m.members[i] != other.members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func rendezvousWeight(member, key string) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(member))
+	hasher.Write([]byte{0})
+	hasher.Write([]byte(key))
+	// fnv doesn't mix the last bytes well, finalize it with the mixer of splitmix64.
+	h := hasher.Sum64()
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// MembershipChangedHandler is called with the previous and the current membership once the membership changes.
+type MembershipChangedHandler func(previous, current *Members)
+
+// Manager maintains the membership lease of the instance itself and refreshes the membership of all the instances of the group.
+type Manager struct {
+	client        clientset.Interface
+	clock         clock.Clock
+	namespace     string
+	labelKey      string
+	group         string
+	identity      string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+
+	lock        sync.RWMutex
+	members     *Members
+	lastRenewed time.Time
+	handlers    []MembershipChangedHandler
+}
+
+// NewManager returns a Manager of the instance identified by identity.
+func NewManager(client clientset.Interface, clock clock.Clock, group, identity string, cfg Config) *Manager {
+	return &Manager{
+		client:        client,
+		clock:         clock,
+		namespace:     cfg.LeaseNamespace,
+		labelKey:      cfg.LeaseGroupLabelKey,
+		group:         group,
+		identity:      identity,
+		leaseDuration: cfg.LeaseDuration,
+		renewInterval: cfg.RenewInterval,
+		members:       NewMembers(identity),
+	}
+}
+
+// Identity returns the identity of the instance itself.
+func (m *Manager) Identity() string {
+	return m.identity
+}
+
+// Members returns the current membership.
+func (m *Manager) Members() *Members {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.members
+}
+
+// AddMembershipChangedHandler registers the handler, it must be called before Run.
+func (m *Manager) AddMembershipChangedHandler(handler MembershipChangedHandler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Join renews the lease and refreshes the membership for the first time, the instance should not work on any key before it succeeds.
+func (m *Manager) Join(ctx context.Context) error {
+	if err := m.renew(ctx); err != nil {
+		return err
+	}
+	return m.refresh(ctx)
+}
+
+// Run renews the lease and refreshes the membership periodically. It returns an error once the lease
+// has not been renewed for the lease duration, since the shard may have been taken over by the others.
+// The lease is released when the context is done.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := m.clock.NewTicker(m.renewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			m.leave()
+			return nil
+		case <-ticker.C():
+			if err := m.renew(ctx); err != nil {
+				klog.InfoS("Failed to renew the shard lease", "group", m.group, "identity", m.identity, "err", err)
+				if m.expired() {
+					return fmt.Errorf("shard lease of %v was not renewed within %v", m.identity, m.leaseDuration)
+				}
+				continue
+			}
+			if err := m.refresh(ctx); err != nil {
+				klog.InfoS("Failed to refresh the shard membership", "group", m.group, "identity", m.identity, "err", err)
+			}
+		}
+	}
+}
+
+func (m *Manager) expired() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.clock.Since(m.lastRenewed) > m.leaseDuration
+}
+
+func (m *Manager) leaseName() string {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(m.identity))
+	return fmt.Sprintf("%s-shard-%x", m.group, hasher.Sum64())
+}
+
+func (m *Manager) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(m.clock.Now())
+	leaseDurationSeconds := int32(m.leaseDuration / time.Second)
+	leases := m.client.CoordinationV1().Leases(m.namespace)
+
+	lease, err := leases.Get(ctx, m.leaseName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.leaseName(),
+				Namespace: m.namespace,
+				Labels:    map[string]string{m.labelKey: m.group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &m.identity,
+				LeaseDurationSeconds: &leaseDurationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if _, err := leases.Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		lease = lease.DeepCopy()
+		lease.Spec.HolderIdentity = &m.identity
+		lease.Spec.LeaseDurationSeconds = &leaseDurationSeconds
+		lease.Spec.RenewTime = &now
+		if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.lastRenewed = now.Time
+	return nil
+}
+
+func (m *Manager) refresh(ctx context.Context) error {
+	leases, err := m.client.CoordinationV1().Leases(m.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{m.labelKey: m.group}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	now := m.clock.Now()
+	var alive []string
+	for i := range leases.Items {
+		spec := leases.Items[i].Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		if spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second).Before(now) {
+			continue
+		}
+		alive = append(alive, *spec.HolderIdentity)
+	}
+	m.setMembers(NewMembers(m.identity, alive...))
+	return nil
+}
+
+func (m *Manager) setMembers(members *Members) {
+	m.lock.Lock()
+	previous := m.members
+	if previous.Equal(members) {
+		m.lock.Unlock()
+		return
+	}
+	m.members = members
+	handlers := m.handlers
+	m.lock.Unlock()
+
+	klog.InfoS("Shard membership changed", "group", m.group, "identity", m.identity, "previous", previous.List(), "current", members.List())
+	for _, handler := range handlers {
+		handler(previous, members)
+	}
+}
+
+// leave deletes the lease, so the others take over the shard without waiting for the lease to expire.
+func (m *Manager) leave() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.renewInterval)
+	defer cancel()
+	if err := m.client.CoordinationV1().Leases(m.namespace).Delete(ctx, m.leaseName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		klog.InfoS("Failed to release the shard lease", "group", m.group, "identity", m.identity, "err", err)
+	}
+}
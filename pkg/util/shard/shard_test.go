@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMembersOwner(t *testing.T) {
+	binders := []string{"binder-0", "binder-1", "binder-2"}
+	views := make([]*Members, len(binders))
+	for i, identity := range binders {
+		views[i] = NewMembers(identity, binders...)
+	}
+
+	owned := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		owners := 0
+		for _, view := range views {
+			if view.Owns(key) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("expected key %v to be owned by exactly one binder, got %d", key, owners)
+		}
+		owned[views[0].Owner(key)]++
+	}
+	for _, identity := range binders {
+		if owned[identity] < 800 || owned[identity] > 1200 {
+			t.Errorf("expected keys to be spread evenly, binder %v owns %d of 3000", identity, owned[identity])
+		}
+	}
+}
+
+func TestMembersOwnerStability(t *testing.T) {
+	before := NewMembers("binder-0", "binder-1", "binder-2")
+	after := NewMembers("binder-0", "binder-1", "binder-2", "binder-3")
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if owner := after.Owner(key); owner != "binder-3" && owner != before.Owner(key) {
+			t.Errorf("expected key %v to stay on %v or move to the joining binder, got %v", key, before.Owner(key), owner)
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	fakeClock := clock.NewFakeClock(time.Now())
+	cfg := Config{
+		LeaseNamespace:     "godel-system",
+		LeaseGroupLabelKey: "godel.bytedance.com/binder-shard-group",
+		LeaseDuration:      15 * time.Second,
+		RenewInterval:      5 * time.Second,
+	}
+
+	m0 := NewManager(client, fakeClock, "godel-scheduler", "binder-0", cfg)
+	m1 := NewManager(client, fakeClock, "godel-scheduler", "binder-1", cfg)
+	// binders of another group are not members
+	other := NewManager(client, fakeClock, "other-scheduler", "binder-2", cfg)
+
+	var changes []*Members
+	m0.AddMembershipChangedHandler(func(previous, current *Members) {
+		changes = append(changes, current)
+	})
+
+	for _, m := range []*Manager{m0, m1, other} {
+		if err := m.Join(ctx); err != nil {
+			t.Fatalf("failed to join: %v", err)
+		}
+	}
+	if err := m0.refresh(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	expected := NewMembers("binder-0", "binder-0", "binder-1")
+	if !m0.Members().Equal(expected) {
+		t.Errorf("expected members %v, got %v", expected.List(), m0.Members().List())
+	}
+	if len(changes) != 1 || !changes[0].Equal(expected) {
+		t.Errorf("expected handler to be called once with %v, got %v", expected.List(), changes)
+	}
+
+	// binder-1 stops renewing its lease.
+	fakeClock.Step(10 * time.Second)
+	if err := m0.renew(ctx); err != nil {
+		t.Fatalf("failed to renew: %v", err)
+	}
+	fakeClock.Step(10 * time.Second)
+	if err := m0.refresh(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	expected = NewMembers("binder-0")
+	if !m0.Members().Equal(expected) {
+		t.Errorf("expected members %v after binder-1 expired, got %v", expected.List(), m0.Members().List())
+	}
+	if !m1.expired() {
+		t.Errorf("expected the lease of binder-1 to be expired")
+	}
+
+	// binder-0 leaves, its lease is deleted.
+	m0.leave()
+	if err := m1.Join(ctx); err != nil {
+		t.Fatalf("failed to join: %v", err)
+	}
+	expected = NewMembers("binder-1")
+	if !m1.Members().Equal(expected) {
+		t.Errorf("expected members %v after binder-0 left, got %v", expected.List(), m1.Members().List())
+	}
+	if leases, _ := client.CoordinationV1().Leases(cfg.LeaseNamespace).List(ctx, metav1.ListOptions{}); len(leases.Items) != 2 {
+		t.Errorf("expected 2 leases, got %d", len(leases.Items))
+	}
+}
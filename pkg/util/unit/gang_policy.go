@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+)
+
+const (
+	// GangTimeoutPolicyAnnotationKey is a PodGroup annotation key, value is the GangTimeoutPolicy applied
+	// once the PodGroup times out.
+	GangTimeoutPolicyAnnotationKey = "godel.bytedance.com/gang-timeout-policy"
+	// GangFailurePolicyAnnotationKey is a PodGroup annotation key, value is the GangFailurePolicy applied
+	// once any member of the scheduled PodGroup fails.
+	GangFailurePolicyAnnotationKey = "godel.bytedance.com/gang-failure-policy"
+	// GangBackoffLimitAnnotationKey is a PodGroup annotation key, value is the number of restarts allowed by
+	// the RestartGang policy before the whole gang is failed.
+	GangBackoffLimitAnnotationKey = "godel.bytedance.com/gang-backoff-limit"
+	// GangRestartCountAnnotationKey is maintained by the PodGroup controller, value is the number of times
+	// the gang has been restarted.
+	GangRestartCountAnnotationKey = "godel.bytedance.com/gang-restart-count"
+	// GangRestartTimestampAnnotationKey is maintained by the PodGroup controller, value is the RFC3339 time of
+	// the latest restart, the schedule timeout of a restarted gang is counted from it.
+	GangRestartTimestampAnnotationKey = "godel.bytedance.com/gang-restart-timestamp"
+
+	DefaultGangBackoffLimit int32 = 6
+)
+
+// GangTimeoutPolicy decides what happens to the bound members of a timed out PodGroup.
+type GangTimeoutPolicy string
+
+const (
+	// GangTimeoutIgnore only records the Timeout phase, the bound members keep running.
+	GangTimeoutIgnore GangTimeoutPolicy = "Ignore"
+	// GangTimeoutReleaseBound deletes the bound members so that they stop holding resources.
+	GangTimeoutReleaseBound GangTimeoutPolicy = "ReleaseBound"
+)
+
+// GangFailurePolicy decides what happens to a scheduled PodGroup once any of its members fails.
+type GangFailurePolicy string
+
+const (
+	// GangFailureIgnore leaves the other members untouched.
+	GangFailureIgnore GangFailurePolicy = "Ignore"
+	// GangFailureRestartGang deletes all members and schedules the gang again, at most backoff limit times.
+	GangFailureRestartGang GangFailurePolicy = "RestartGang"
+	// GangFailureDeleteGang deletes all members and fails the PodGroup.
+	GangFailureDeleteGang GangFailurePolicy = "DeleteGang"
+)
+
+// GetGangTimeoutPolicy returns the timeout policy of the PodGroup, GangTimeoutIgnore by default.
+func GetGangTimeoutPolicy(pg *v1alpha1.PodGroup) (GangTimeoutPolicy, error) {
+	value, ok := pg.GetAnnotations()[GangTimeoutPolicyAnnotationKey]
+	if !ok {
+		return GangTimeoutIgnore, nil
+	}
+	switch policy := GangTimeoutPolicy(value); policy {
+	case GangTimeoutIgnore, GangTimeoutReleaseBound:
+		return policy, nil
+	}
+	return GangTimeoutIgnore, fmt.Errorf("unsupported gang timeout policy %q", value)
+}
+
+// GetGangFailurePolicy returns the failure policy of the PodGroup, GangFailureIgnore by default.
+func GetGangFailurePolicy(pg *v1alpha1.PodGroup) (GangFailurePolicy, error) {
+	value, ok := pg.GetAnnotations()[GangFailurePolicyAnnotationKey]
+	if !ok {
+		return GangFailureIgnore, nil
+	}
+	switch policy := GangFailurePolicy(value); policy {
+	case GangFailureIgnore, GangFailureRestartGang, GangFailureDeleteGang:
+		return policy, nil
+	}
+	return GangFailureIgnore, fmt.Errorf("unsupported gang failure policy %q", value)
+}
+
+// GetGangBackoffLimit returns the backoff limit of the PodGroup, DefaultGangBackoffLimit by default.
+func GetGangBackoffLimit(pg *v1alpha1.PodGroup) (int32, error) {
+	value, ok := pg.GetAnnotations()[GangBackoffLimitAnnotationKey]
+	if !ok {
+		return DefaultGangBackoffLimit, nil
+	}
+	limit, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return DefaultGangBackoffLimit, fmt.Errorf("invalid gang backoff limit %q: %v", value, err)
+	}
+	if limit < 0 {
+		return DefaultGangBackoffLimit, fmt.Errorf("invalid gang backoff limit %q: must be non-negative", value)
+	}
+	return int32(limit), nil
+}
+
+// GetGangRestartCount returns the number of times the gang has been restarted.
+func GetGangRestartCount(pg *v1alpha1.PodGroup) int32 {
+	count, err := strconv.ParseInt(pg.GetAnnotations()[GangRestartCountAnnotationKey], 10, 32)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return int32(count)
+}
+
+// GetGangStartTime returns the time the schedule timeout of the PodGroup is counted from, which is
+// the latest restart if the gang has been restarted, the creation time otherwise.
+func GetGangStartTime(pg *v1alpha1.PodGroup) time.Time {
+	if value, ok := pg.GetAnnotations()[GangRestartTimestampAnnotationKey]; ok {
+		if restartTime, err := time.Parse(time.RFC3339, value); err == nil {
+			return restartTime
+		}
+	}
+	return pg.CreationTimestamp.Time
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeAnnotatedPodGroup(annotations map[string]string) *v1alpha1.PodGroup {
+	return &v1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default", Annotations: annotations}}
+}
+
+func TestGetGangTimeoutPolicy(t *testing.T) {
+	policy, err := GetGangTimeoutPolicy(makeAnnotatedPodGroup(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, GangTimeoutIgnore, policy)
+
+	policy, err = GetGangTimeoutPolicy(makeAnnotatedPodGroup(map[string]string{GangTimeoutPolicyAnnotationKey: "ReleaseBound"}))
+	assert.NoError(t, err)
+	assert.Equal(t, GangTimeoutReleaseBound, policy)
+
+	policy, err = GetGangTimeoutPolicy(makeAnnotatedPodGroup(map[string]string{GangTimeoutPolicyAnnotationKey: "Unknown"}))
+	assert.Error(t, err)
+	assert.Equal(t, GangTimeoutIgnore, policy)
+}
+
+func TestGetGangFailurePolicy(t *testing.T) {
+	policy, err := GetGangFailurePolicy(makeAnnotatedPodGroup(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, GangFailureIgnore, policy)
+
+	for _, want := range []GangFailurePolicy{GangFailureIgnore, GangFailureRestartGang, GangFailureDeleteGang} {
+		policy, err = GetGangFailurePolicy(makeAnnotatedPodGroup(map[string]string{GangFailurePolicyAnnotationKey: string(want)}))
+		assert.NoError(t, err)
+		assert.Equal(t, want, policy)
+	}
+
+	policy, err = GetGangFailurePolicy(makeAnnotatedPodGroup(map[string]string{GangFailurePolicyAnnotationKey: "restartgang"}))
+	assert.Error(t, err)
+	assert.Equal(t, GangFailureIgnore, policy)
+}
+
+func TestGetGangBackoffLimit(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantLimit int32
+		wantErr   bool
+	}{
+		{value: "", wantLimit: DefaultGangBackoffLimit, wantErr: true},
+		{value: "0", wantLimit: 0},
+		{value: "3", wantLimit: 3},
+		{value: "-1", wantLimit: DefaultGangBackoffLimit, wantErr: true},
+		{value: "three", wantLimit: DefaultGangBackoffLimit, wantErr: true},
+	}
+	for _, tt := range tests {
+		limit, err := GetGangBackoffLimit(makeAnnotatedPodGroup(map[string]string{GangBackoffLimitAnnotationKey: tt.value}))
+		assert.Equal(t, tt.wantErr, err != nil, tt.value)
+		assert.Equal(t, tt.wantLimit, limit, tt.value)
+	}
+
+	limit, err := GetGangBackoffLimit(makeAnnotatedPodGroup(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultGangBackoffLimit, limit)
+}
+
+func TestGetGangStartTime(t *testing.T) {
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+	pg := makeAnnotatedPodGroup(nil)
+	pg.CreationTimestamp = metav1.Time{Time: created}
+	assert.Equal(t, created, GetGangStartTime(pg))
+	assert.Equal(t, int32(0), GetGangRestartCount(pg))
+
+	restarted := time.Now().Truncate(time.Second)
+	pg.Annotations = map[string]string{
+		GangRestartTimestampAnnotationKey: restarted.Format(time.RFC3339),
+		GangRestartCountAnnotationKey:     "2",
+	}
+	assert.True(t, restarted.Equal(GetGangStartTime(pg)))
+	assert.Equal(t, int32(2), GetGangRestartCount(pg))
+}
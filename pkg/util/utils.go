@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
@@ -49,7 +50,9 @@ const (
 	CanBePreempted              = "true"
 	CannotBePreempted           = "false"
 	PreemptionPolicyKey         = "godel.bytedance.com/preemption-policy"
-	ObjectNameField             = "metadata.name"
+	// SchedulerDrainingAnnotationKey is set on the Scheduler CRD by a terminating scheduler, whose value is the time the drain started.
+	SchedulerDrainingAnnotationKey = "godel.bytedance.com/scheduler-draining"
+	ObjectNameField                = "metadata.name"
 	// hardcode GPU name here
 	// TODO: support more GPU names
 	ResourceGPU  v1.ResourceName = "nvidia.com/gpu"
@@ -65,6 +68,22 @@ const (
 
 	ResourceSriov v1.ResourceName = "bytedance.com/sriov.nic"
 
+	// ResourceGPUMemory and ResourceGPUCore are requested by the pods sharing one GPU device,
+	// ResourceGPUCore is the percentage of the compute of one GPU device.
+	ResourceGPUMemory v1.ResourceName = "godel.bytedance.com/gpu-memory"
+	ResourceGPUCore   v1.ResourceName = "godel.bytedance.com/gpu-core"
+	// GPUShareDevicesAnnotationKey is a node annotation key, value is the shared GPU resources of each device,
+	// e.g. "0:godel.bytedance.com/gpu-memory=16Gi,godel.bytedance.com/gpu-core=100;1:...".
+	// It's used only when the devices are not reported by CNR.
+	GPUShareDevicesAnnotationKey = "godel.bytedance.com/gpu-share-devices"
+	// WorkloadUsageProfileAnnotationKey is a CNR annotation key, value is the historical per-pod usage percentiles of
+	// the workloads running on the node, e.g. {"ReplicaSet/default/nginx": {"95": {"cpu": "500m", "memory": "1Gi"}}}.
+	WorkloadUsageProfileAnnotationKey = "godel.bytedance.com/workload-usage-profile"
+	// LocalStoragePoolsAnnotationKey is a CNR or node annotation key, value is the capacity of the node-local storage
+	// pools keyed by the storage class they serve, e.g. "local-lvm=1Ti,local-nvme=3200Gi".
+	// The node annotation is used only when CNR doesn't report any pool.
+	LocalStoragePoolsAnnotationKey = "godel.bytedance.com/local-storage-pools"
+
 	SemicolonSeperator = ";"
 	CommaSeperator     = ","
 	ColonSeperator     = ":"
@@ -262,6 +281,22 @@ func UpdateSchedulerStatus(cs crdclientset.Interface, scheduler *v1alpha1.Schedu
 	return cs.SchedulingV1alpha1().Schedulers().UpdateStatus(context.TODO(), scheduler, metav1.UpdateOptions{})
 }
 
+// UpdateScheduler updates v1alpha1.Scheduler in API server
+func UpdateScheduler(cs crdclientset.Interface, scheduler *v1alpha1.Scheduler) (*v1alpha1.Scheduler, error) {
+	return cs.SchedulingV1alpha1().Schedulers().Update(context.TODO(), scheduler, metav1.UpdateOptions{})
+}
+
+// DeleteScheduler deletes v1alpha1.Scheduler from API server
+func DeleteScheduler(cs crdclientset.Interface, schedulerName string) error {
+	return cs.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), schedulerName, metav1.DeleteOptions{})
+}
+
+// IsSchedulerDraining checks whether the scheduler is draining before it exits
+func IsSchedulerDraining(scheduler *v1alpha1.Scheduler) bool {
+	_, ok := scheduler.Annotations[SchedulerDrainingAnnotationKey]
+	return ok
+}
+
 // GetScheduler returns the latest <scheduler> from API server
 func GetScheduler(cs crdclientset.Interface, schedulerName string) (*v1alpha1.Scheduler, error) {
 	return cs.SchedulingV1alpha1().Schedulers().Get(context.TODO(), schedulerName, metav1.GetOptions{})
@@ -348,6 +383,41 @@ func MarshalMicroTopology(topology map[int]*v1.ResourceList) string {
 	return str
 }
 
+// UnmarshalStorageQuantities parses the storage quantities keyed by storage class, e.g. "local-lvm=1Ti,local-nvme=3200Gi".
+func UnmarshalStorageQuantities(str string) (map[string]int64, error) {
+	if str == "" {
+		return nil, nil
+	}
+	res := make(map[string]int64)
+	for _, item := range strings.Split(str, CommaSeperator) {
+		kv := strings.Split(item, EqualSignSeperator)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("failed to parse storage quantity %s", item)
+		}
+		quantity, err := resource.ParseQuantity(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse storage quantity %s: %v", kv[1], err)
+		}
+		res[kv[0]] += quantity.Value()
+	}
+	return res, nil
+}
+
+// MarshalStorageQuantities formats the storage quantities keyed by storage class, sorted by storage class.
+func MarshalStorageQuantities(quantities map[string]int64) string {
+	storageClasses := make([]string, 0, len(quantities))
+	for storageClass := range quantities {
+		storageClasses = append(storageClasses, storageClass)
+	}
+	sort.Strings(storageClasses)
+	items := make([]string, 0, len(storageClasses))
+	for _, storageClass := range storageClasses {
+		quantity := resource.NewQuantity(quantities[storageClass], resource.BinarySI)
+		items = append(items, storageClass+EqualSignSeperator+quantity.String())
+	}
+	return strings.Join(items, CommaSeperator)
+}
+
 func GetPodDebugModeOnNode(pod *v1.Pod, node *v1.Node, nmNode *nodev1alpha1.NMNode) bool {
 	if GetPodDebugMode(pod) != DebugModeOn {
 		return false
@@ -432,7 +502,9 @@ func EqualMap(m1, m2 map[string]string) bool {
 	return true
 }
 
-func ParallelizeUntil(stop *bool, workers int, pieces int, doWorkPiece func(piece int)) {
+// ParallelizeUntil runs doWorkPiece for all pieces with the given number of workers, and stops once stop is set
+// by any of the pieces.
+func ParallelizeUntil(stop *atomic.Bool, workers int, pieces int, doWorkPiece func(piece int)) {
 	chunk := (pieces + workers - 1) / workers
 	wg := sync.WaitGroup{}
 	wg.Add(workers)
@@ -440,7 +512,7 @@ func ParallelizeUntil(stop *bool, workers int, pieces int, doWorkPiece func(piec
 		go func(i int) {
 			defer wg.Done()
 			for j := 0; j < chunk; j++ {
-				if *stop {
+				if stop.Load() {
 					return
 				}
 				index := i*chunk + j
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -283,7 +284,7 @@ func TestParallelizeUntil(t *testing.T) {
 				s[i] = i
 			}
 
-			stop := false
+			var stop atomic.Bool
 			ParallelizeUntil(&stop, worker, length, doWorkPiece)
 
 			for i := 0; i < length; i++ {
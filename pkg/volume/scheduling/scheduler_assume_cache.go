@@ -25,6 +25,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	pvutil "github.com/kubewharf/godel-scheduler/pkg/volume/persistentvolume/util"
 )
 
 // AssumeCache is a cache on top of the informer that allows for updating
@@ -126,6 +129,42 @@ func (c *assumeCache) objInfoIndexFunc(obj interface{}) ([]string, error) {
 	return c.indexFunc(objInfo.latestObj)
 }
 
+// addIndex adds an extra index on the latest version of the objects. It must be called
+// before any object is added to the cache.
+func (c *assumeCache) addIndex(indexName string, indexFunc cache.IndexFunc) error {
+	return c.store.AddIndexers(cache.Indexers{indexName: func(obj interface{}) ([]string, error) {
+		objInfo, ok := obj.(*objInfo)
+		if !ok {
+			return nil, &errWrongType{"objInfo", obj}
+		}
+		return indexFunc(objInfo.latestObj)
+	}})
+}
+
+// byIndex returns the latest version of the objects whose indexed value of the given
+// index matches indexedValue.
+func (c *assumeCache) byIndex(indexName, indexedValue string) []interface{} {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	objs, err := c.store.ByIndex(indexName, indexedValue)
+	if err != nil {
+		klog.InfoS("Failed to list index", "indexName", indexName, "err", err)
+		return nil
+	}
+
+	allObjs := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		objInfo, ok := obj.(*objInfo)
+		if !ok {
+			klog.InfoS("Failed to list object", "err", &errWrongType{"objInfo", obj})
+			continue
+		}
+		allObjs = append(allObjs, objInfo.latestObj)
+	}
+	return allObjs
+}
+
 // NewAssumeCache creates an assume cache for general objects.
 func NewAssumeCache(informer cache.SharedIndexInformer, description, indexName string, indexFunc cache.IndexFunc) AssumeCache {
 	c := &assumeCache{
@@ -299,7 +338,7 @@ func (c *assumeCache) Assume(obj interface{}) error {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
 
-	objInfo, err := c.getObjInfo(name)
+	stored, err := c.getObjInfo(name)
 	if err != nil {
 		return err
 	}
@@ -309,7 +348,7 @@ func (c *assumeCache) Assume(obj interface{}) error {
 		return err
 	}
 
-	storedVersion, err := c.getObjVersion(name, objInfo.latestObj)
+	storedVersion, err := c.getObjVersion(name, stored.latestObj)
 	if err != nil {
 		return err
 	}
@@ -318,8 +357,11 @@ func (c *assumeCache) Assume(obj interface{}) error {
 		return fmt.Errorf("%v %q is out of sync (stored: %d, assume: %d)", c.description, name, storedVersion, newVersion)
 	}
 
-	// Only update the cached object
-	objInfo.latestObj = obj
+	// Only update the cached object. The objInfo is replaced instead of being modified
+	// in place so that the indexes are computed against the assumed object.
+	if err := c.store.Update(&objInfo{name: name, latestObj: obj, apiObj: stored.apiObj}); err != nil {
+		return err
+	}
 	klog.V(4).InfoS("Assumed object", "entryDescription", c.description, "objectName", name, "objectVersion", newVersion)
 	return nil
 }
@@ -328,12 +370,13 @@ func (c *assumeCache) Restore(objName string) {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
 
-	objInfo, err := c.getObjInfo(objName)
+	stored, err := c.getObjInfo(objName)
 	if err != nil {
 		// This could be expected if object got deleted
 		klog.InfoS("Failed to restore object", "entryDescription", c.description, "objectName", objName, "err", err)
+	} else if err := c.store.Update(&objInfo{name: objName, latestObj: stored.apiObj, apiObj: stored.apiObj}); err != nil {
+		klog.InfoS("Failed to restore object", "entryDescription", c.description, "objectName", objName, "err", err)
 	} else {
-		objInfo.latestObj = objInfo.apiObj
 		klog.V(4).InfoS("Restored object", "entryDescription", c.description, "objectName", objName)
 	}
 }
@@ -415,15 +458,36 @@ type PVCAssumeCache interface {
 	// pvcKey is the result of MetaNamespaceKeyFunc on PVC obj
 	GetPVC(pvcKey string) (*v1.PersistentVolumeClaim, error)
 	GetAPIPVC(pvcKey string) (*v1.PersistentVolumeClaim, error)
+	// ListPendingProvisioningPVCs returns the PVCs of the given storage class that have been
+	// handed over for provisioning on a selected node, assumed or not, but are not bound yet.
+	ListPendingProvisioningPVCs(storageClassName string) []*v1.PersistentVolumeClaim
 }
 
 type pvcAssumeCache struct {
 	AssumeCache
 }
 
+const pvcPendingProvisioningIndex = "pendingprovisioning"
+
+// pvcPendingProvisioningIndexFunc indexes the unbound PVCs with a selected node by their storage class.
+func pvcPendingProvisioningIndexFunc(obj interface{}) ([]string, error) {
+	pvc, ok := obj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		return nil, fmt.Errorf("object is not a v1.PersistentVolumeClaim: %v", obj)
+	}
+	if pvc.Spec.VolumeName != "" || pvc.Annotations[pvutil.AnnSelectedNode] == "" {
+		return nil, nil
+	}
+	return []string{helper.GetPersistentVolumeClaimClass(pvc)}, nil
+}
+
 // NewPVCAssumeCache creates a PVC assume cache.
 func NewPVCAssumeCache(informer cache.SharedIndexInformer) PVCAssumeCache {
-	return &pvcAssumeCache{NewAssumeCache(informer, "v1.PersistentVolumeClaim", "namespace", cache.MetaNamespaceIndexFunc)}
+	c := NewAssumeCache(informer, "v1.PersistentVolumeClaim", "namespace", cache.MetaNamespaceIndexFunc)
+	if err := c.(*assumeCache).addIndex(pvcPendingProvisioningIndex, pvcPendingProvisioningIndexFunc); err != nil {
+		klog.InfoS("Failed to add the pending provisioning index to the PVC assume cache", "err", err)
+	}
+	return &pvcAssumeCache{c}
 }
 
 func (c *pvcAssumeCache) GetPVC(pvcKey string) (*v1.PersistentVolumeClaim, error) {
@@ -450,3 +514,22 @@ func (c *pvcAssumeCache) GetAPIPVC(pvcKey string) (*v1.PersistentVolumeClaim, er
 	}
 	return pvc, nil
 }
+
+func (c *pvcAssumeCache) ListPendingProvisioningPVCs(storageClassName string) []*v1.PersistentVolumeClaim {
+	internalCache, ok := c.AssumeCache.(*assumeCache)
+	if !ok {
+		return nil
+	}
+
+	objs := internalCache.byIndex(pvcPendingProvisioningIndex, storageClassName)
+	pvcs := make([]*v1.PersistentVolumeClaim, 0, len(objs))
+	for _, obj := range objs {
+		pvc, ok := obj.(*v1.PersistentVolumeClaim)
+		if !ok {
+			klog.InfoS("Failed to list PVCs", "err", &errWrongType{"v1.PersistentVolumeClaim", obj})
+			continue
+		}
+		pvcs = append(pvcs, pvc)
+	}
+	return pvcs
+}
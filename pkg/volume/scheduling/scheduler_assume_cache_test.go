@@ -472,3 +472,54 @@ func TestAssumeUpdatePVCCache(t *testing.T) {
 		t.Fatalf("failed to get PVC after old PVC added: %v", err)
 	}
 }
+
+func TestListPendingProvisioningPVCs(t *testing.T) {
+	cache := NewPVCAssumeCache(nil)
+	internalCache, ok := cache.(*pvcAssumeCache).AssumeCache.(*assumeCache)
+	if !ok {
+		t.Fatalf("Failed to get internal cache")
+	}
+
+	className := "class1"
+	pvc := makeClaim("pvc1", "1", "ns1")
+	pvc.Spec.StorageClassName = &className
+	internalCache.add(pvc)
+	if pvcs := cache.ListPendingProvisioningPVCs(className); len(pvcs) != 0 {
+		t.Fatalf("ListPendingProvisioningPVCs() returned %v PVCs, expected 0", len(pvcs))
+	}
+
+	// Assume the PVC provisioned on a node
+	assumedPVC := pvc.DeepCopy()
+	assumedPVC.Annotations[pvutil.AnnSelectedNode] = "node1"
+	if err := cache.Assume(assumedPVC); err != nil {
+		t.Fatalf("Assume() returned error %v", err)
+	}
+	if pvcs := cache.ListPendingProvisioningPVCs(className); len(pvcs) != 1 || pvcs[0] != assumedPVC {
+		t.Fatalf("ListPendingProvisioningPVCs() returned %v, expected the assumed PVC", pvcs)
+	}
+	if pvcs := cache.ListPendingProvisioningPVCs("class2"); len(pvcs) != 0 {
+		t.Fatalf("ListPendingProvisioningPVCs() returned %v PVCs of another class, expected 0", len(pvcs))
+	}
+
+	// Restore PVC
+	cache.Restore(getPVCName(pvc))
+	if pvcs := cache.ListPendingProvisioningPVCs(className); len(pvcs) != 0 {
+		t.Fatalf("ListPendingProvisioningPVCs() returned %v PVCs after restore, expected 0", len(pvcs))
+	}
+
+	// The informer reports the PVC with a selected node, and then bound
+	pendingPVC := makeClaim("pvc1", "2", "ns1")
+	pendingPVC.Spec.StorageClassName = &className
+	pendingPVC.Annotations[pvutil.AnnSelectedNode] = "node1"
+	internalCache.add(pendingPVC)
+	if pvcs := cache.ListPendingProvisioningPVCs(className); len(pvcs) != 1 || pvcs[0] != pendingPVC {
+		t.Fatalf("ListPendingProvisioningPVCs() returned %v, expected the pending PVC", pvcs)
+	}
+	boundPVC := pendingPVC.DeepCopy()
+	boundPVC.ResourceVersion = "3"
+	boundPVC.Spec.VolumeName = "pv1"
+	internalCache.add(boundPVC)
+	if pvcs := cache.ListPendingProvisioningPVCs(className); len(pvcs) != 0 {
+		t.Fatalf("ListPendingProvisioningPVCs() returned %v PVCs after binding, expected 0", len(pvcs))
+	}
+}
@@ -34,6 +34,7 @@ import (
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	storageinformers "k8s.io/client-go/informers/storage/v1"
 	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	storagelisters "k8s.io/client-go/listers/storage/v1"
 	csitrans "k8s.io/csi-translation-lib"
 	csiplugins "k8s.io/csi-translation-lib/plugins"
@@ -65,6 +66,8 @@ const (
 	ErrReasonNodeConflict ConflictReason = "node(s) had volume node affinity conflict"
 	// ErrUnboundImmediatePVC is used when the pod has an unbound PVC in immedate binding mode.
 	ErrUnboundImmediatePVC ConflictReason = "pod has unbound immediate PersistentVolumeClaims"
+	// ErrReasonNotEnoughSpace is used when a pod cannot start on a node because not enough storage space is available.
+	ErrReasonNotEnoughSpace ConflictReason = "node(s) did not have enough free storage"
 )
 
 // InTreeToCSITranslator contains methods required to check migratable status
@@ -166,34 +169,49 @@ type volumeBinder struct {
 
 type baseVolumeBinder struct {
 	classLister     storagelisters.StorageClassLister
+	nodeLister      corelisters.NodeLister
 	csiNodeInformer storageinformers.CSINodeInformer
 	pvcCache        PVCAssumeCache
 	pvCache         PVAssumeCache
 	translator      InTreeToCSITranslator
+
+	// capacity is nil if storage capacity checking is disabled.
+	capacity *capacityTracker
 }
 
-func newBaseVolumeBinder(csiNodeInformer storageinformers.CSINodeInformer,
+func newBaseVolumeBinder(
+	nodeInformer coreinformers.NodeInformer,
+	csiNodeInformer storageinformers.CSINodeInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
+	capacityCheck *CapacityCheck,
 ) *baseVolumeBinder {
 	b := &baseVolumeBinder{
 		classLister:     storageClassInformer.Lister(),
+		nodeLister:      nodeInformer.Lister(),
 		csiNodeInformer: csiNodeInformer,
 		pvcCache:        NewPVCAssumeCache(pvcInformer.Informer()),
 		pvCache:         NewPVAssumeCache(pvInformer.Informer()),
 		translator:      csitrans.New(),
 	}
+	if capacityCheck != nil {
+		b.capacity = newCapacityTracker(capacityCheck)
+	}
 	return b
 }
 
+// NewBaseVolumeBinder sets up all the caches needed for the scheduler to check volume binding
+// feasibility. Storage capacity is only checked if capacityCheck is not nil.
 func NewBaseVolumeBinder(
+	nodeInformer coreinformers.NodeInformer,
 	csiNodeInformer storageinformers.CSINodeInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
+	capacityCheck *CapacityCheck,
 ) BaseVolumeBinder {
-	b := newBaseVolumeBinder(csiNodeInformer, pvcInformer, pvInformer, storageClassInformer)
+	b := newBaseVolumeBinder(nodeInformer, csiNodeInformer, pvcInformer, pvInformer, storageClassInformer, capacityCheck)
 	return b
 }
 
@@ -206,6 +224,7 @@ func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 	// returns without an error.
 	unboundVolumesSatisfied := true
 	boundVolumesSatisfied := true
+	sufficientStorage := true
 	defer func() {
 		if err != nil {
 			return
@@ -216,6 +235,9 @@ func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 		if !unboundVolumesSatisfied {
 			reasons = append(reasons, ErrReasonBindConflict)
 		}
+		if !sufficientStorage {
+			reasons = append(reasons, ErrReasonNotEnoughSpace)
+		}
 	}()
 
 	start := time.Now()
@@ -229,14 +251,17 @@ func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 	podVolumes := podVolumesInfo{
 		boundVolumesSatisfied:   boundVolumesSatisfied,
 		unboundVolumesSatisfied: unboundVolumesSatisfied,
+		sufficientStorage:       sufficientStorage,
 	}
 	podVolumes, reasons, err = b.findPodVolumes(pod, nodeName, nodeLabels, podVolumes)
 	boundVolumesSatisfied = podVolumes.boundVolumesSatisfied
 	unboundVolumesSatisfied = podVolumes.unboundVolumesSatisfied
+	sufficientStorage = podVolumes.sufficientStorage
 	return
 }
 
 // NewVolumeBinder sets up all the caches needed for the scheduler to make volume binding decisions.
+// Storage capacity is only checked if capacityCheck is not nil.
 func NewVolumeBinder(
 	kubeClient clientset.Interface,
 	nodeInformer coreinformers.NodeInformer,
@@ -244,10 +269,11 @@ func NewVolumeBinder(
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
+	capacityCheck *CapacityCheck,
 	bindTimeout time.Duration,
 ) GodelVolumeBinder {
 	b := &volumeBinder{
-		baseVolumeBinder: newBaseVolumeBinder(csiNodeInformer, pvcInformer, pvInformer, storageClassInformer),
+		baseVolumeBinder: newBaseVolumeBinder(nodeInformer, csiNodeInformer, pvcInformer, pvInformer, storageClassInformer, capacityCheck),
 		kubeClient:       kubeClient,
 		nodeInformer:     nodeInformer,
 		podBindingCache:  NewPodBindingCache(),
@@ -281,6 +307,7 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels m
 	// returns without an error.
 	unboundVolumesSatisfied := true
 	boundVolumesSatisfied := true
+	sufficientStorage := true
 	defer func() {
 		if err != nil {
 			return
@@ -291,6 +318,9 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels m
 		if !unboundVolumesSatisfied {
 			reasons = append(reasons, ErrReasonBindConflict)
 		}
+		if !sufficientStorage {
+			reasons = append(reasons, ErrReasonNotEnoughSpace)
+		}
 	}()
 
 	start := time.Now()
@@ -327,12 +357,14 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels m
 	podVolumes := podVolumesInfo{
 		boundVolumesSatisfied:   boundVolumesSatisfied,
 		unboundVolumesSatisfied: unboundVolumesSatisfied,
+		sufficientStorage:       sufficientStorage,
 		matchedBindings:         matchedBindings,
 		provisionedClaims:       provisionedClaims,
 	}
 	podVolumes, reasons, err = b.findPodVolumes(pod, nodeName, nodeLabels, podVolumes)
 	boundVolumesSatisfied = podVolumes.boundVolumesSatisfied
 	unboundVolumesSatisfied = podVolumes.unboundVolumesSatisfied
+	sufficientStorage = podVolumes.sufficientStorage
 	matchedBindings = podVolumes.matchedBindings
 	provisionedClaims = podVolumes.provisionedClaims
 	return
@@ -818,42 +850,53 @@ func (b *baseVolumeBinder) findMatchingVolumes(pod *v1.Pod, claimsToBind []*v1.P
 
 // checkVolumeProvisions checks given unbound claims (the claims have gone through func
 // findMatchingVolumes, and do not have matching volumes for binding), and return true
-// if all of the claims are eligible for dynamic provision.
-func (b *baseVolumeBinder) checkVolumeProvisions(pod *v1.Pod, claimsToProvision []*v1.PersistentVolumeClaim, nodeName string, nodeLabels map[string]string) (provisionSatisfied bool, provisionedClaims []*v1.PersistentVolumeClaim, err error) {
+// if all of the claims are eligible for dynamic provision. sufficientStorage is false
+// if the storage capacity reachable from the node cannot hold the claims.
+func (b *baseVolumeBinder) checkVolumeProvisions(pod *v1.Pod, claimsToProvision []*v1.PersistentVolumeClaim, nodeName string, nodeLabels map[string]string) (provisionSatisfied, sufficientStorage bool, provisionedClaims []*v1.PersistentVolumeClaim, err error) {
 	provisionedClaims = []*v1.PersistentVolumeClaim{}
+	provisioners := make(map[string]string, len(claimsToProvision))
 
 	for _, claim := range claimsToProvision {
 		pvcName := getPVCName(claim)
 		className := helper.GetPersistentVolumeClaimClass(claim)
 		if className == "" {
-			return false, nil, fmt.Errorf("no class for claim %q", pvcName)
+			return false, false, nil, fmt.Errorf("no class for claim %q", pvcName)
 		}
 
 		class, err := b.classLister.Get(className)
 		if err != nil {
-			return false, nil, fmt.Errorf("failed to find storage class %q", className)
+			return false, false, nil, fmt.Errorf("failed to find storage class %q", className)
 		}
 		provisioner := class.Provisioner
 		if provisioner == "" || provisioner == pvutil.NotSupportedProvisioner {
 			klog.V(4).InfoS("StorageClass of PVC did not support dynamic provisioning", "storageClass", klog.KObj(class), "PVC", klog.KObj(claim))
-			return false, nil, nil
+			return false, true, nil, nil
 		}
 
 		// Check if the node can satisfy the topology requirement in the class
 		if !helper.MatchTopologySelectorTerms(class.AllowedTopologies, labels.Set(nodeLabels)) {
 			klog.V(4).InfoS("Node failed to satisfy provisioning topology requirements of claim", "nodeName", nodeName, "PVC", klog.KObj(claim))
-			return false, nil, nil
+			return false, true, nil, nil
 		}
 
-		// TODO: Check if capacity of the node domain in the storage class
-		// can satisfy resource requirement of given claim
-
+		provisioners[className] = provisioner
 		provisionedClaims = append(provisionedClaims, claim)
 
 	}
+
+	// Check if capacity of the node domain in the storage class
+	// can satisfy resource requirement of given claims
+	sufficient, err := b.hasEnoughCapacity(provisionedClaims, provisioners, nodeName, nodeLabels)
+	if err != nil {
+		return false, false, nil, err
+	}
+	if !sufficient {
+		// hasEnoughCapacity logs an explanation.
+		return true, false, nil, nil
+	}
 	klog.V(4).InfoS("Provisioning for claims of pod that has no matching volumes on node", "pod", klog.KObj(pod), "nodeName", nodeName)
 
-	return true, provisionedClaims, nil
+	return true, true, provisionedClaims, nil
 }
 
 func (b *volumeBinder) revertAssumedPVs(bindings []*bindingInfo) {
@@ -969,6 +1012,7 @@ func (b *baseVolumeBinder) tryTranslatePVToCSI(pv *v1.PersistentVolume, csiNode
 type podVolumesInfo struct {
 	boundVolumesSatisfied   bool
 	unboundVolumesSatisfied bool
+	sufficientStorage       bool
 	matchedBindings         []*bindingInfo
 	provisionedClaims       []*v1.PersistentVolumeClaim
 }
@@ -1028,7 +1072,7 @@ func (b *baseVolumeBinder) findPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 
 		// Check for claims to provision
 		if len(claimsToProvision) > 0 {
-			podVolumes.unboundVolumesSatisfied, podVolumes.provisionedClaims, err = b.checkVolumeProvisions(pod, claimsToProvision, nodeName, nodeLabels)
+			podVolumes.unboundVolumesSatisfied, podVolumes.sufficientStorage, podVolumes.provisionedClaims, err = b.checkVolumeProvisions(pod, claimsToProvision, nodeName, nodeLabels)
 			if err != nil {
 				return podVolumes, nil, err
 			}
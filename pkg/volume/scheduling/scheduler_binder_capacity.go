@@ -0,0 +1,287 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	pvutil "github.com/kubewharf/godel-scheduler/pkg/volume/persistentvolume/util"
+)
+
+// CapacityCheck contains additional parameters for the volume binders that are only
+// needed when checking volume sizes against the storage capacity published by CSI drivers.
+type CapacityCheck struct {
+	CSIDriverInformer          storageinformers.CSIDriverInformer
+	CSIStorageCapacityInformer storageinformers.CSIStorageCapacityInformer
+}
+
+// NewCapacityCheck returns the CapacityCheck backed by the informer factory, or nil if the
+// CSIStorageCapacity feature is disabled.
+func NewCapacityCheck(informerFactory informers.SharedInformerFactory) *CapacityCheck {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.CSIStorageCapacity) {
+		return nil
+	}
+	return &CapacityCheck{
+		CSIDriverInformer:          informerFactory.Storage().V1().CSIDrivers(),
+		CSIStorageCapacityInformer: informerFactory.Storage().V1().CSIStorageCapacities(),
+	}
+}
+
+const csiStorageCapacityStorageClassIndex = "godel.storageclass"
+
+func csiStorageCapacityStorageClassIndexFunc(obj interface{}) ([]string, error) {
+	if capacity, ok := obj.(*storagev1.CSIStorageCapacity); ok {
+		return []string{capacity.StorageClassName}, nil
+	}
+	return nil, nil
+}
+
+// capacityTracker looks up the CSIStorageCapacity objects per storage class and
+// topology segment.
+type capacityTracker struct {
+	csiDriverLister storagelisters.CSIDriverLister
+	capacityLister  storagelisters.CSIStorageCapacityLister
+	// capacityIndexer is nil if the storage class index could not be added to the
+	// informer, all the objects are listed and filtered in that case.
+	capacityIndexer cache.Indexer
+}
+
+func newCapacityTracker(capacityCheck *CapacityCheck) *capacityTracker {
+	t := &capacityTracker{
+		csiDriverLister: capacityCheck.CSIDriverInformer.Lister(),
+		capacityLister:  capacityCheck.CSIStorageCapacityInformer.Lister(),
+	}
+
+	informer := capacityCheck.CSIStorageCapacityInformer.Informer()
+	// The informer is shared by the binders of all the profiles, only the first one adds the index.
+	if _, ok := informer.GetIndexer().GetIndexers()[csiStorageCapacityStorageClassIndex]; !ok {
+		if err := informer.AddIndexers(cache.Indexers{csiStorageCapacityStorageClassIndex: csiStorageCapacityStorageClassIndexFunc}); err != nil {
+			klog.InfoS("Failed to index CSIStorageCapacity objects by storage class, all of them will be listed instead", "err", err)
+			return t
+		}
+	}
+	t.capacityIndexer = informer.GetIndexer()
+	return t
+}
+
+// tracksCapacity returns true if the CSI driver publishes its storage capacity.
+func (t *capacityTracker) tracksCapacity(provisioner string) (bool, error) {
+	driver, err := t.csiDriverLister.Get(provisioner)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Either the provisioner is not a CSI driver or the driver does not
+			// publish a CSIDriver object. Either way, no capacity is tracked.
+			return false, nil
+		}
+		return false, err
+	}
+	return driver.Spec.StorageCapacity != nil && *driver.Spec.StorageCapacity, nil
+}
+
+// listCapacities returns the CSIStorageCapacity objects of the storage class which are
+// reachable from a node with the given labels.
+func (t *capacityTracker) listCapacities(className string, nodeLabels map[string]string) ([]*storagev1.CSIStorageCapacity, error) {
+	var capacities []*storagev1.CSIStorageCapacity
+	if t.capacityIndexer != nil {
+		objs, err := t.capacityIndexer.ByIndex(csiStorageCapacityStorageClassIndex, className)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			if capacity, ok := obj.(*storagev1.CSIStorageCapacity); ok {
+				capacities = append(capacities, capacity)
+			}
+		}
+	} else {
+		all, err := t.capacityLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, capacity := range all {
+			if capacity.StorageClassName == className {
+				capacities = append(capacities, capacity)
+			}
+		}
+	}
+
+	reachable := capacities[:0]
+	for _, capacity := range capacities {
+		if nodeHasAccess(capacity, nodeLabels) {
+			reachable = append(reachable, capacity)
+		}
+	}
+	// Keep the first-fit placement deterministic.
+	sort.Slice(reachable, func(i, j int) bool {
+		return capacityKey(reachable[i]) < capacityKey(reachable[j])
+	})
+	return reachable, nil
+}
+
+func capacityKey(capacity *storagev1.CSIStorageCapacity) string {
+	return capacity.Namespace + "/" + capacity.Name
+}
+
+// nodeHasAccess returns true if the topology segment of the capacity contains the node.
+func nodeHasAccess(capacity *storagev1.CSIStorageCapacity, nodeLabels map[string]string) bool {
+	if capacity.NodeTopology == nil {
+		// Capacity without topology is not reachable from any node.
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(capacity.NodeTopology)
+	if err != nil {
+		klog.InfoS("Failed to parse the node topology of CSIStorageCapacity", "capacity", klog.KObj(capacity), "err", err)
+		return false
+	}
+	return selector.Matches(labels.Set(nodeLabels))
+}
+
+// capacitySufficient returns true if a volume of sizeInBytes can be provisioned from the
+// capacity which already has requestedInBytes pending.
+func capacitySufficient(capacity *storagev1.CSIStorageCapacity, requestedInBytes, sizeInBytes int64) bool {
+	if capacity.MaximumVolumeSize != nil && capacity.MaximumVolumeSize.Value() < sizeInBytes {
+		return false
+	}
+	if capacity.Capacity == nil {
+		return capacity.MaximumVolumeSize != nil
+	}
+	return capacity.Capacity.Value()-requestedInBytes >= sizeInBytes
+}
+
+func claimSize(claim *v1.PersistentVolumeClaim) (int64, bool) {
+	quantity, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return 0, false
+	}
+	return quantity.Value(), true
+}
+
+// hasEnoughCapacity checks whether the claims to provision fit into the storage capacity
+// reachable from the node. The claims of other pods which have been handed over to the
+// provisioners but are not bound yet, including the ones only assumed in the PVC cache,
+// are subtracted from the published capacity, since the provisioners do not refresh the
+// CSIStorageCapacity objects until the volumes are created.
+func (b *baseVolumeBinder) hasEnoughCapacity(claims []*v1.PersistentVolumeClaim, provisioners map[string]string, nodeName string, nodeLabels map[string]string) (bool, error) {
+	if b.capacity == nil || len(claims) == 0 {
+		return true, nil
+	}
+
+	podClaims := sets.NewString()
+	for _, claim := range claims {
+		podClaims.Insert(getPVCName(claim))
+	}
+
+	// Place the largest claims first so that the first-fit placement is less likely to fail.
+	sortedClaims := make([]*v1.PersistentVolumeClaim, len(claims))
+	copy(sortedClaims, claims)
+	sort.Stable(sort.Reverse(byPVCSize(sortedClaims)))
+
+	// requested records the bytes taken from each capacity by pending provisioning and
+	// by the claims placed so far, keyed by capacity namespace/name.
+	requested := map[string]int64{}
+	// capacitiesByClass has no entry for the storage classes whose driver does not track capacity.
+	capacitiesByClass := map[string][]*storagev1.CSIStorageCapacity{}
+	checkedClasses := sets.NewString()
+	for _, claim := range sortedClaims {
+		size, ok := claimSize(claim)
+		if !ok {
+			continue
+		}
+		className := helper.GetPersistentVolumeClaimClass(claim)
+		if !checkedClasses.Has(className) {
+			checkedClasses.Insert(className)
+			tracked, err := b.capacity.tracksCapacity(provisioners[className])
+			if err != nil {
+				return false, err
+			}
+			if tracked {
+				capacities, err := b.capacity.listCapacities(className, nodeLabels)
+				if err != nil {
+					return false, err
+				}
+				b.addPendingProvisioning(className, capacities, podClaims, nodeName, nodeLabels, requested)
+				capacitiesByClass[className] = capacities
+			}
+		}
+		capacities, tracked := capacitiesByClass[className]
+		if !tracked {
+			continue
+		}
+
+		placed := false
+		for _, capacity := range capacities {
+			key := capacityKey(capacity)
+			if capacitySufficient(capacity, requested[key], size) {
+				requested[key] += size
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			klog.V(4).InfoS("Node has no accessible CSIStorageCapacity with enough capacity for PVC",
+				"nodeName", nodeName, "PVC", klog.KObj(claim), "size", size, "storageClass", className)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// addPendingProvisioning adds the sizes of the unbound claims of the storage class with a
+// selected node, except the claims of the pod itself, to the capacities they are drawn from.
+func (b *baseVolumeBinder) addPendingProvisioning(className string, capacities []*storagev1.CSIStorageCapacity, podClaims sets.String, nodeName string, nodeLabels map[string]string, requested map[string]int64) {
+	if len(capacities) == 0 {
+		return
+	}
+	for _, pvc := range b.pvcCache.ListPendingProvisioningPVCs(className) {
+		if podClaims.Has(getPVCName(pvc)) {
+			continue
+		}
+		size, ok := claimSize(pvc)
+		if !ok {
+			continue
+		}
+
+		selectedNodeLabels := nodeLabels
+		if selectedNode := pvc.Annotations[pvutil.AnnSelectedNode]; selectedNode != nodeName {
+			node, err := b.nodeLister.Get(selectedNode)
+			if err != nil {
+				continue
+			}
+			selectedNodeLabels = node.Labels
+		}
+		for _, capacity := range capacities {
+			if nodeHasAccess(capacity, selectedNodeLabels) {
+				requested[capacityKey(capacity)] += size
+				break
+			}
+		}
+	}
+}
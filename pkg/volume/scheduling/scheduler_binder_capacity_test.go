@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/controller"
+)
+
+func makeCSIDriver(name string, storageCapacity bool) *storagev1.CSIDriver {
+	return &storagev1.CSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: storagev1.CSIDriverSpec{
+			StorageCapacity: &storageCapacity,
+		},
+	}
+}
+
+func makeCapacity(name, className string, nodeLabels map[string]string, capacityStr, maximumVolumeSizeStr string) *storagev1.CSIStorageCapacity {
+	capacity := &storagev1.CSIStorageCapacity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+		},
+		StorageClassName: className,
+	}
+	if nodeLabels != nil {
+		capacity.NodeTopology = &metav1.LabelSelector{MatchLabels: nodeLabels}
+	}
+	if capacityStr != "" {
+		quantity := resource.MustParse(capacityStr)
+		capacity.Capacity = &quantity
+	}
+	if maximumVolumeSizeStr != "" {
+		quantity := resource.MustParse(maximumVolumeSizeStr)
+		capacity.MaximumVolumeSize = &quantity
+	}
+	return capacity
+}
+
+func TestFindPodVolumesWithCSIStorageCapacity(t *testing.T) {
+	const provisioner = "test-provisioner"
+	node1Topology := map[string]string{nodeLabelKey: "node1"}
+	node2Topology := map[string]string{nodeLabelKey: "node2"}
+	pendingPVCNode1 := makeTestPVC("pending-pvc-node1", "1Gi", "node1", pvcSelectedNode, "", "1", &waitClassWithProvisioner)
+	pendingPVCNode2 := makeTestPVC("pending-pvc-node2", "1Gi", "node2", pvcSelectedNode, "", "1", &waitClassWithProvisioner)
+
+	type scenarioType struct {
+		// Inputs
+		podPVCs    []*v1.PersistentVolumeClaim
+		otherPVCs  []*v1.PersistentVolumeClaim
+		driver     *storagev1.CSIDriver
+		capacities []*storagev1.CSIStorageCapacity
+
+		// Expected results
+		expectedProvisions []*v1.PersistentVolumeClaim
+		reasons            ConflictReasons
+	}
+	scenarios := map[string]scenarioType{
+		"no-csi-driver": {
+			podPVCs:            []*v1.PersistentVolumeClaim{provisionedPVC},
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC},
+		},
+		"csi-driver-without-storage-capacity": {
+			podPVCs:            []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:             makeCSIDriver(provisioner, false),
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC},
+		},
+		"no-capacity": {
+			podPVCs: []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:  makeCSIDriver(provisioner, true),
+			reasons: ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"enough-capacity": {
+			podPVCs:            []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:             makeCSIDriver(provisioner, true),
+			capacities:         []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "1Gi", "")},
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC},
+		},
+		"capacity-of-other-storage-class": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClass, node1Topology, "1Gi", "")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"capacity-of-other-topology": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("node2", waitClassWithProvisioner, node2Topology, "1Gi", "")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"capacity-without-topology": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("global", waitClassWithProvisioner, nil, "1Gi", "")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"insufficient-capacity": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "1Mi", "")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"insufficient-maximum-volume-size": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "10Gi", "1Mi")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"enough-maximum-volume-size-without-capacity": {
+			podPVCs:            []*v1.PersistentVolumeClaim{provisionedPVC},
+			driver:             makeCSIDriver(provisioner, true),
+			capacities:         []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "", "1Gi")},
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC},
+		},
+		"two-claims-insufficient-capacity": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC, provisionedPVC2},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "1Gi", "")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"two-claims-enough-capacity": {
+			podPVCs:            []*v1.PersistentVolumeClaim{provisionedPVC, provisionedPVC2},
+			driver:             makeCSIDriver(provisioner, true),
+			capacities:         []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "2Gi", "")},
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC, provisionedPVC2},
+		},
+		"two-claims-spread-over-capacities": {
+			podPVCs: []*v1.PersistentVolumeClaim{provisionedPVC, provisionedPVC2},
+			driver:  makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{
+				makeCapacity("node1-a", waitClassWithProvisioner, node1Topology, "1Gi", ""),
+				makeCapacity("node1-b", waitClassWithProvisioner, node1Topology, "1Gi", ""),
+			},
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC, provisionedPVC2},
+		},
+		"pending-provisioning-on-same-node": {
+			podPVCs:    []*v1.PersistentVolumeClaim{provisionedPVC},
+			otherPVCs:  []*v1.PersistentVolumeClaim{pendingPVCNode1},
+			driver:     makeCSIDriver(provisioner, true),
+			capacities: []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "1Gi", "")},
+			reasons:    ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"pending-provisioning-on-other-node": {
+			podPVCs:            []*v1.PersistentVolumeClaim{provisionedPVC},
+			otherPVCs:          []*v1.PersistentVolumeClaim{pendingPVCNode2},
+			driver:             makeCSIDriver(provisioner, true),
+			capacities:         []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "1Gi", "")},
+			expectedProvisions: []*v1.PersistentVolumeClaim{provisionedPVC},
+		},
+		"pending-provisioning-of-pod-itself": {
+			podPVCs:            []*v1.PersistentVolumeClaim{selectedNodePVC},
+			driver:             makeCSIDriver(provisioner, true),
+			capacities:         []*storagev1.CSIStorageCapacity{makeCapacity("node1", waitClassWithProvisioner, node1Topology, "1Gi", "")},
+			expectedProvisions: []*v1.PersistentVolumeClaim{selectedNodePVC},
+		},
+	}
+
+	run := func(t *testing.T, scenario scenarioType) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Setup
+		testEnv := newTestBinder(t, ctx.Done())
+		testEnv.initNodes([]*v1.Node{node1, node2})
+		testEnv.initClaims(append(scenario.podPVCs, scenario.otherPVCs...), nil)
+
+		informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), controller.NoResyncPeriodFunc())
+		capacityCheck := &CapacityCheck{
+			CSIDriverInformer:          informerFactory.Storage().V1().CSIDrivers(),
+			CSIStorageCapacityInformer: informerFactory.Storage().V1().CSIStorageCapacities(),
+		}
+		testEnv.internalBinder.capacity = newCapacityTracker(capacityCheck)
+		if scenario.driver != nil {
+			capacityCheck.CSIDriverInformer.Informer().GetIndexer().Add(scenario.driver)
+		}
+		for _, capacity := range scenario.capacities {
+			capacityCheck.CSIStorageCapacityInformer.Informer().GetIndexer().Add(capacity)
+		}
+
+		// Execute
+		pod := makePod(scenario.podPVCs)
+		reasons, err := testEnv.binder.FindPodVolumes(pod, node1.Name, node1.Labels)
+
+		// Validate
+		if err != nil {
+			t.Errorf("returned error: %v", err)
+		}
+		checkReasons(t, reasons, scenario.reasons)
+		testEnv.validatePodCache(t, node1.Name, pod, nil, scenario.expectedProvisions)
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) { run(t, scenario) })
+	}
+}
+
+func TestFindPodVolumesWithAssumedProvisioning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := newTestBinder(t, ctx.Done())
+	testEnv.initNodes([]*v1.Node{node1})
+	testEnv.initClaims([]*v1.PersistentVolumeClaim{provisionedPVC, provisionedPVC2}, nil)
+
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), controller.NoResyncPeriodFunc())
+	capacityCheck := &CapacityCheck{
+		CSIDriverInformer:          informerFactory.Storage().V1().CSIDrivers(),
+		CSIStorageCapacityInformer: informerFactory.Storage().V1().CSIStorageCapacities(),
+	}
+	testEnv.internalBinder.capacity = newCapacityTracker(capacityCheck)
+	capacityCheck.CSIDriverInformer.Informer().GetIndexer().Add(makeCSIDriver("test-provisioner", true))
+	capacityCheck.CSIStorageCapacityInformer.Informer().GetIndexer().Add(
+		makeCapacity("node1", waitClassWithProvisioner, map[string]string{nodeLabelKey: "node1"}, "1Gi", ""))
+
+	pod1 := makePod([]*v1.PersistentVolumeClaim{provisionedPVC})
+	pod1.Name = "pod1"
+	pod2 := makePod([]*v1.PersistentVolumeClaim{provisionedPVC2})
+	pod2.Name = "pod2"
+
+	// Both pods fit as long as nothing has been assumed.
+	for _, pod := range []*v1.Pod{pod1, pod2} {
+		reasons, err := testEnv.binder.FindPodVolumes(pod, node1.Name, node1.Labels)
+		if err != nil {
+			t.Fatalf("FindPodVolumes returned error: %v", err)
+		}
+		checkReasons(t, reasons, nil)
+	}
+
+	// The provisioning assumed for pod1 takes up the whole capacity.
+	if _, err := testEnv.binder.AssumePodVolumes(pod1, node1.Name); err != nil {
+		t.Fatalf("AssumePodVolumes returned error: %v", err)
+	}
+	reasons, err := testEnv.binder.FindPodVolumes(pod2, node1.Name, node1.Labels)
+	if err != nil {
+		t.Fatalf("FindPodVolumes returned error: %v", err)
+	}
+	checkReasons(t, reasons, ConflictReasons{ErrReasonNotEnoughSpace})
+
+	// The capacity is given back once the assumed provisioning is reverted.
+	testEnv.internalBinder.revertAssumedPVCs([]*v1.PersistentVolumeClaim{provisionedPVC})
+	reasons, err = testEnv.binder.FindPodVolumes(pod2, node1.Name, node1.Labels)
+	if err != nil {
+		t.Fatalf("FindPodVolumes returned error: %v", err)
+	}
+	checkReasons(t, reasons, nil)
+}
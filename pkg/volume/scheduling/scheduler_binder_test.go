@@ -161,6 +161,7 @@ func newTestBinder(t *testing.T, stopCh <-chan struct{}) *testEnv {
 		pvcInformer,
 		informerFactory.Core().V1().PersistentVolumes(),
 		classInformer,
+		nil,
 		3*time.Second)
 
 	// Wait for informers cache sync
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kubewharf/godel-scheduler/pkg/framework/api/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/constraints"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+var annotationsPath = field.NewPath("metadata", "annotations")
+
+// ValidatePod validates the Godel annotations of a pod with the same parsing code used by the scheduling
+// components, so that misconfigured pods are rejected at admission instead of failing in scheduling.
+// The PodGroup the pod belongs to must exist if pgLister is not nil.
+func ValidatePod(pod *v1.Pod, pgLister v1alpha1.PodGroupLister) field.ErrorList {
+	errs := field.ErrorList{}
+	annotations := pod.GetAnnotations()
+
+	if value, ok := annotations[podutil.PodLauncherAnnotationKey]; ok {
+		if _, err := podutil.GetPodLauncher(pod); err != nil {
+			errs = append(errs, field.NotSupported(annotationsPath.Key(podutil.PodLauncherAnnotationKey), value,
+				[]string{string(podutil.Kubelet), string(podutil.NodeManager)}))
+		}
+	}
+	if value, ok := annotations[podutil.PodResourceTypeAnnotationKey]; ok {
+		if _, err := podutil.GetPodResourceType(pod); err != nil {
+			errs = append(errs, field.NotSupported(annotationsPath.Key(podutil.PodResourceTypeAnnotationKey), value,
+				[]string{string(podutil.GuaranteedPod), string(podutil.BestEffortPod)}))
+		}
+	}
+	for _, key := range []string{constraints.HardConstraintsAnnotationKey, constraints.SoftConstraintsAnnotationKey} {
+		if value, ok := annotations[key]; ok {
+			if _, err := config.GetConstraints(pod, key); err != nil {
+				errs = append(errs, field.Invalid(annotationsPath.Key(key), value, err.Error()))
+			}
+		}
+	}
+	if value, ok := annotations[podutil.MicroTopologyKey]; ok && len(value) > 0 {
+		if _, err := util.UnmarshalMicroTopology(value); err != nil {
+			errs = append(errs, field.Invalid(annotationsPath.Key(podutil.MicroTopologyKey), value, err.Error()))
+		}
+	}
+	if pgName := podutil.GetPodGroupName(pod); len(pgName) > 0 && pgLister != nil {
+		if _, err := pgLister.PodGroups(pod.Namespace).Get(pgName); err != nil {
+			if apierrors.IsNotFound(err) {
+				errs = append(errs, field.NotFound(annotationsPath.Key(podutil.PodGroupNameAnnotationKey), pgName))
+			} else {
+				errs = append(errs, field.InternalError(annotationsPath.Key(podutil.PodGroupNameAnnotationKey),
+					fmt.Errorf("failed to get PodGroup %s/%s: %v", pod.Namespace, pgName, err)))
+			}
+		}
+	}
+	return errs
+}
+
+// ValidatePodGroup validates the spec of a PodGroup.
+func ValidatePodGroup(pg *schedulingv1a1.PodGroup) field.ErrorList {
+	errs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	if pg.Spec.MinMember <= 0 {
+		errs = append(errs, field.Invalid(specPath.Child("minMember"), pg.Spec.MinMember, "must be greater than 0"))
+	}
+	if pg.Spec.ScheduleTimeoutSeconds != nil && *pg.Spec.ScheduleTimeoutSeconds <= 0 {
+		errs = append(errs, field.Invalid(specPath.Child("scheduleTimeoutSeconds"), *pg.Spec.ScheduleTimeoutSeconds, "must be greater than 0"))
+	}
+	if _, err := unitutil.GetGangTimeoutPolicy(pg); err != nil {
+		errs = append(errs, field.Invalid(annotationsPath.Key(unitutil.GangTimeoutPolicyAnnotationKey), pg.Annotations[unitutil.GangTimeoutPolicyAnnotationKey], err.Error()))
+	}
+	if _, err := unitutil.GetGangFailurePolicy(pg); err != nil {
+		errs = append(errs, field.Invalid(annotationsPath.Key(unitutil.GangFailurePolicyAnnotationKey), pg.Annotations[unitutil.GangFailurePolicyAnnotationKey], err.Error()))
+	}
+	if _, err := unitutil.GetGangBackoffLimit(pg); err != nil {
+		errs = append(errs, field.Invalid(annotationsPath.Key(unitutil.GangBackoffLimitAnnotationKey), pg.Annotations[unitutil.GangBackoffLimitAnnotationKey], err.Error()))
+	}
+	return errs
+}
+
+// DefaultPodAnnotations returns the resource type and launcher annotations which are not set on the pod,
+// with the values the scheduling components would use for it.
+func DefaultPodAnnotations(pod *v1.Pod) map[string]string {
+	defaults := make(map[string]string)
+	if _, ok := pod.GetAnnotations()[podutil.PodResourceTypeAnnotationKey]; !ok {
+		if resourceType, err := podutil.GetPodResourceType(pod); err == nil {
+			defaults[podutil.PodResourceTypeAnnotationKey] = string(resourceType)
+		}
+	}
+	if _, ok := pod.GetAnnotations()[podutil.PodLauncherAnnotationKey]; !ok {
+		if launcher, err := podutil.GetPodLauncher(pod); err == nil {
+			defaults[podutil.PodLauncherAnnotationKey] = string(launcher)
+		}
+	}
+	return defaults
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ValidatePath is the path of the validating webhook, serving pods and PodGroups.
+	ValidatePath = "/validate"
+	// MutatePath is the path of the mutating webhook, serving pods.
+	MutatePath = "/mutate"
+)
+
+var (
+	podResource      = metav1.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	podGroupResource = metav1.GroupVersionResource{Group: schedulingv1a1.SchemeGroupVersion.Group, Version: schedulingv1a1.SchemeGroupVersion.Version, Resource: "podgroups"}
+)
+
+// Webhook admits the pods and PodGroups of the Godel scheduling system.
+type Webhook struct {
+	// schedulerNames are the spec.schedulerName of the pods served, the other pods are always admitted.
+	schedulerNames sets.String
+	pgLister       v1alpha1.PodGroupLister
+	// defaultPodAnnotations indicates whether the resource type and launcher annotations should be defaulted.
+	defaultPodAnnotations bool
+}
+
+// New returns a Webhook. The PodGroups referred by pods are not checked if pgLister is nil.
+func New(schedulerNames []string, pgLister v1alpha1.PodGroupLister, defaultPodAnnotations bool) *Webhook {
+	return &Webhook{
+		schedulerNames:        sets.NewString(schedulerNames...),
+		pgLister:              pgLister,
+		defaultPodAnnotations: defaultPodAnnotations,
+	}
+}
+
+// Handler returns the handler serving the validating and mutating webhooks.
+func (wh *Webhook) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, wh.validate)
+	})
+	mux.HandleFunc(MutatePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, wh.mutate)
+	})
+	return mux
+}
+
+type admitFunc func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+func serve(w http.ResponseWriter, r *http.Request, admit admitFunc) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := admit(review.Request)
+	response.UID = review.Request.UID
+	review.Response = response
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.InfoS("Failed to write admission response", "err", err)
+	}
+}
+
+func (wh *Webhook) validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	switch req.Resource {
+	case podResource:
+		// Only the pods being created are validated, the updates made by the scheduling components,
+		// e.g. the placements, must never be blocked.
+		if req.Operation != admissionv1.Create {
+			return allowedResponse()
+		}
+		pod := &v1.Pod{}
+		if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+			return errorResponse(http.StatusBadRequest, err)
+		}
+		if !wh.schedulerNames.Has(pod.Spec.SchedulerName) {
+			return allowedResponse()
+		}
+		return validationResponse("Pod", req.Name, ValidatePod(pod, wh.pgLister))
+	case podGroupResource:
+		// Only the PodGroups being created and the updates of their specs are validated, the deletions and
+		// the status updates made by the scheduling components must never be blocked.
+		if (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) || len(req.SubResource) != 0 {
+			return allowedResponse()
+		}
+		pg := &schedulingv1a1.PodGroup{}
+		if err := json.Unmarshal(req.Object.Raw, pg); err != nil {
+			return errorResponse(http.StatusBadRequest, err)
+		}
+		return validationResponse("PodGroup", req.Name, ValidatePodGroup(pg))
+	default:
+		return allowedResponse()
+	}
+}
+
+// jsonPatchOperation is an operation of RFC 6902 JSON patch.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+func (wh *Webhook) mutate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if !wh.defaultPodAnnotations || req.Resource != podResource || req.Operation != admissionv1.Create {
+		return allowedResponse()
+	}
+	pod := &v1.Pod{}
+	if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+		return errorResponse(http.StatusBadRequest, err)
+	}
+	if !wh.schedulerNames.Has(pod.Spec.SchedulerName) {
+		return allowedResponse()
+	}
+	defaults := DefaultPodAnnotations(pod)
+	if len(defaults) == 0 {
+		return allowedResponse()
+	}
+
+	var patch []jsonPatchOperation
+	if pod.Annotations == nil {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/metadata/annotations", Value: defaults})
+	} else {
+		for key, value := range defaults {
+			patch = append(patch, jsonPatchOperation{Op: "add", Path: "/metadata/annotations/" + escapeJSONPointer(key), Value: value})
+		}
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, err)
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	response := allowedResponse()
+	response.Patch = patchBytes
+	response.PatchType = &patchType
+	return response
+}
+
+func escapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func allowedResponse() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func errorResponse(code int32, err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Result: &metav1.Status{Status: metav1.StatusFailure, Code: code, Message: err.Error()},
+	}
+}
+
+func validationResponse(kind, name string, errs field.ErrorList) *admissionv1.AdmissionResponse {
+	if len(errs) == 0 {
+		return allowedResponse()
+	}
+	return &admissionv1.AdmissionResponse{
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: fmt.Sprintf("%s %q is invalid for Godel: %s", kind, name, errs.ToAggregate().Error()),
+		},
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/constraints"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+const testSchedulerName = "godel-scheduler"
+
+func makePod(annotations map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "p", Annotations: annotations},
+		Spec:       v1.PodSpec{SchedulerName: testSchedulerName},
+	}
+}
+
+func newTestServer(t *testing.T, defaultPodAnnotations bool) *httptest.Server {
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(godelclientfake.NewSimpleClientset(), 0)
+	pgInformer := crdInformerFactory.Scheduling().V1alpha1().PodGroups()
+	pgInformer.Informer().GetIndexer().Add(&schedulingv1a1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"},
+		Spec:       schedulingv1a1.PodGroupSpec{MinMember: 1},
+	})
+	return httptest.NewTLSServer(New([]string{testSchedulerName}, pgInformer.Lister(), defaultPodAnnotations).Handler())
+}
+
+func review(t *testing.T, server *httptest.Server, path string, resource metav1.GroupVersionResource, operation admissionv1.Operation, obj runtime.Object) *admissionv1.AdmissionResponse {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reviewRequest(t, server, path, &admissionv1.AdmissionRequest{
+		Resource:  resource,
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+}
+
+func reviewRequest(t *testing.T, server *httptest.Server, path string, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	req.UID = types.UID("uid")
+	body, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  req,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Post(server.URL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to send admission review: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d", resp.StatusCode)
+	}
+	result := &admissionv1.AdmissionReview{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		t.Fatalf("failed to decode admission review: %v", err)
+	}
+	if result.Response == nil || result.Response.UID != "uid" {
+		t.Fatalf("unexpected admission response: %+v", result.Response)
+	}
+	return result.Response
+}
+
+func TestValidatePods(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		pod         *v1.Pod
+		operation   admissionv1.Operation
+		wantAllowed bool
+		wantMessage string
+	}{
+		{
+			name: "valid pod",
+			pod: makePod(map[string]string{
+				podutil.PodLauncherAnnotationKey:         string(podutil.Kubelet),
+				podutil.PodResourceTypeAnnotationKey:     string(podutil.GuaranteedPod),
+				podutil.PodGroupNameAnnotationKey:        "pg",
+				constraints.HardConstraintsAnnotationKey: "NodeAffinity",
+				constraints.SoftConstraintsAnnotationKey: "NodeAffinity:2",
+				podutil.MicroTopologyKey:                 "0:cpu=1",
+			}),
+			operation:   admissionv1.Create,
+			wantAllowed: true,
+		},
+		{
+			name:        "invalid launcher",
+			pod:         makePod(map[string]string{podutil.PodLauncherAnnotationKey: "docker"}),
+			operation:   admissionv1.Create,
+			wantMessage: podutil.PodLauncherAnnotationKey,
+		},
+		{
+			name:        "invalid resource type",
+			pod:         makePod(map[string]string{podutil.PodResourceTypeAnnotationKey: "burstable"}),
+			operation:   admissionv1.Create,
+			wantMessage: podutil.PodResourceTypeAnnotationKey,
+		},
+		{
+			name:        "malformed soft constraints",
+			pod:         makePod(map[string]string{constraints.SoftConstraintsAnnotationKey: "NodeAffinity:x"}),
+			operation:   admissionv1.Create,
+			wantMessage: constraints.SoftConstraintsAnnotationKey,
+		},
+		{
+			name:        "missing pod group",
+			pod:         makePod(map[string]string{podutil.PodGroupNameAnnotationKey: "missing"}),
+			operation:   admissionv1.Create,
+			wantMessage: "missing",
+		},
+		{
+			name:        "malformed micro topology",
+			pod:         makePod(map[string]string{podutil.MicroTopologyKey: "bad"}),
+			operation:   admissionv1.Create,
+			wantMessage: podutil.MicroTopologyKey,
+		},
+		{
+			name:        "updates are not validated",
+			pod:         makePod(map[string]string{podutil.PodLauncherAnnotationKey: "docker"}),
+			operation:   admissionv1.Update,
+			wantAllowed: true,
+		},
+		{
+			name: "pods of other schedulers are ignored",
+			pod: func() *v1.Pod {
+				pod := makePod(map[string]string{podutil.PodLauncherAnnotationKey: "docker"})
+				pod.Spec.SchedulerName = "default-scheduler"
+				return pod
+			}(),
+			operation:   admissionv1.Create,
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := review(t, server, ValidatePath, podResource, tt.operation, tt.pod)
+			if resp.Allowed != tt.wantAllowed {
+				t.Fatalf("expected allowed %v, got %+v", tt.wantAllowed, resp)
+			}
+			if !tt.wantAllowed && !strings.Contains(resp.Result.Message, tt.wantMessage) {
+				t.Errorf("expected message containing %q, got %q", tt.wantMessage, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestValidatePodGroups(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	timeout := int32(0)
+	for _, tt := range []struct {
+		name        string
+		spec        schedulingv1a1.PodGroupSpec
+		annotations map[string]string
+		wantAllowed bool
+	}{
+		{name: "valid", spec: schedulingv1a1.PodGroupSpec{MinMember: 2}, wantAllowed: true},
+		{name: "zero min member", spec: schedulingv1a1.PodGroupSpec{MinMember: 0}},
+		{name: "zero schedule timeout", spec: schedulingv1a1.PodGroupSpec{MinMember: 1, ScheduleTimeoutSeconds: &timeout}},
+		{
+			name: "valid gang policies",
+			spec: schedulingv1a1.PodGroupSpec{MinMember: 2},
+			annotations: map[string]string{
+				unitutil.GangTimeoutPolicyAnnotationKey: string(unitutil.GangTimeoutReleaseBound),
+				unitutil.GangFailurePolicyAnnotationKey: string(unitutil.GangFailureRestartGang),
+				unitutil.GangBackoffLimitAnnotationKey:  "3",
+			},
+			wantAllowed: true,
+		},
+		{name: "invalid gang failure policy", spec: schedulingv1a1.PodGroupSpec{MinMember: 2}, annotations: map[string]string{unitutil.GangFailurePolicyAnnotationKey: "Restart"}},
+		{name: "negative gang backoff limit", spec: schedulingv1a1.PodGroupSpec{MinMember: 2}, annotations: map[string]string{unitutil.GangBackoffLimitAnnotationKey: "-1"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := &schedulingv1a1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg", Annotations: tt.annotations}, Spec: tt.spec}
+			if resp := review(t, server, ValidatePath, podGroupResource, admissionv1.Create, pg); resp.Allowed != tt.wantAllowed {
+				t.Errorf("expected allowed %v, got %+v", tt.wantAllowed, resp)
+			}
+		})
+	}
+}
+
+func TestValidatePodGroupOperations(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	invalid, err := json.Marshal(&schedulingv1a1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name        string
+		operation   admissionv1.Operation
+		subResource string
+		object      []byte
+		wantAllowed bool
+	}{
+		{name: "spec updates are validated", operation: admissionv1.Update, object: invalid},
+		{name: "status updates are not validated", operation: admissionv1.Update, subResource: "status", object: invalid, wantAllowed: true},
+		{name: "deletions are not validated", operation: admissionv1.Delete, wantAllowed: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := reviewRequest(t, server, ValidatePath, &admissionv1.AdmissionRequest{
+				Name:        "pg",
+				Resource:    podGroupResource,
+				SubResource: tt.subResource,
+				Operation:   tt.operation,
+				Object:      runtime.RawExtension{Raw: tt.object},
+			})
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("expected allowed %v, got %+v", tt.wantAllowed, resp)
+			}
+		})
+	}
+}
+
+func TestMutatePods(t *testing.T) {
+	server := newTestServer(t, true)
+	defer server.Close()
+
+	resp := review(t, server, MutatePath, podResource, admissionv1.Create, makePod(map[string]string{
+		podutil.PodLauncherAnnotationKey: string(podutil.NodeManager),
+	}))
+	if !resp.Allowed || resp.PatchType == nil || *resp.PatchType != admissionv1.PatchTypeJSONPatch {
+		t.Fatalf("expected an allowed response with JSON patch, got %+v", resp)
+	}
+	var patch []jsonPatchOperation
+	if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+	want := jsonPatchOperation{Op: "add", Path: "/metadata/annotations/godel.bytedance.com~1pod-resource-type", Value: string(podutil.GuaranteedPod)}
+	if len(patch) != 1 || patch[0] != want {
+		t.Errorf("expected patch %v, got %v", want, patch)
+	}
+
+	resp = review(t, server, MutatePath, podResource, admissionv1.Create, makePod(nil))
+	if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+	if len(patch) != 1 || patch[0].Path != "/metadata/annotations" {
+		t.Errorf("expected the annotations to be added as a whole, got %v", patch)
+	}
+}
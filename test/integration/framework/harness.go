@@ -0,0 +1,455 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	katalystfake "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned/fake"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	godelbinder "github.com/kubewharf/godel-scheduler/pkg/binder"
+	binderconfig "github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/controller"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher"
+	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+	godelscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler"
+	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
+	"github.com/kubewharf/godel-scheduler/pkg/util/handoff"
+)
+
+const (
+	// DefaultTimeout is the default timeout of waiting for the expected states.
+	DefaultTimeout = 30 * time.Second
+	// DefaultInterval is the default interval of polling the states.
+	DefaultInterval = 100 * time.Millisecond
+)
+
+// Config describes the components started by the harness.
+type Config struct {
+	// SchedulerNames are the names of the godel scheduler instances, one scheduler is started for each name.
+	// Defaults to a single scheduler named `godel-scheduler`.
+	SchedulerNames []string
+	// SchedulerConfiguration is the configuration shared by all schedulers, the defaults will be applied.
+	SchedulerConfiguration *schedulerconfig.GodelSchedulerConfiguration
+	// BinderConfiguration is the configuration of binder, the defaults will be applied.
+	BinderConfiguration *binderconfig.GodelBinderConfiguration
+	// DispatcherConfiguration is the configuration of dispatcher, the defaults will be applied.
+	DispatcherConfiguration *dispatcherconfig.GodelDispatcherConfiguration
+}
+
+// TestContext holds the in-process dispatcher, schedulers and binder, which are wired as `cmd/*/app/server.go`
+// does but talk to the fake clientsets instead of a real API server.
+type TestContext struct {
+	t      testing.TB
+	ctx    context.Context
+	cancel context.CancelFunc
+	// running tracks the components running in background, which are waited for when the TestContext is closed.
+	running sync.WaitGroup
+
+	Client         *fake.Clientset
+	GodelCrdClient *godelfake.Clientset
+	KatalystClient *katalystfake.Clientset
+
+	Dispatcher *dispatcher.Dispatcher
+	Schedulers []*godelscheduler.Scheduler
+	Binder     *godelbinder.Binder
+}
+
+// componentInformers holds the informer factories of one component, each component owns its informers like
+// running in a separate process.
+type componentInformers struct {
+	informerFactory            informers.SharedInformerFactory
+	godelCrdInformerFactory    crdinformers.SharedInformerFactory
+	katalystCrdInformerFactory katalystinformers.SharedInformerFactory
+}
+
+func (tc *TestContext) newComponentInformers() *componentInformers {
+	return &componentInformers{
+		informerFactory:            cmdutil.NewInformerFactory(tc.Client, 0),
+		godelCrdInformerFactory:    crdinformers.NewSharedInformerFactory(tc.GodelCrdClient, 0),
+		katalystCrdInformerFactory: katalystinformers.NewSharedInformerFactory(tc.KatalystClient, 0),
+	}
+}
+
+func (ci *componentInformers) startAndWaitForCacheSync(stopCh <-chan struct{}) {
+	ci.informerFactory.Start(stopCh)
+	ci.godelCrdInformerFactory.Start(stopCh)
+	ci.katalystCrdInformerFactory.Start(stopCh)
+	ci.informerFactory.WaitForCacheSync(stopCh)
+	ci.godelCrdInformerFactory.WaitForCacheSync(stopCh)
+	ci.katalystCrdInformerFactory.WaitForCacheSync(stopCh)
+}
+
+// StartTestContext starts the dispatcher, schedulers and binder, and registers the cleanup to the test.
+func StartTestContext(t testing.TB, cfg Config) *TestContext {
+	ctx, cancel := context.WithCancel(context.Background())
+	tc := &TestContext{
+		t:              t,
+		ctx:            ctx,
+		cancel:         cancel,
+		Client:         fake.NewSimpleClientset(),
+		GodelCrdClient: godelfake.NewSimpleClientset(),
+		KatalystClient: katalystfake.NewSimpleClientset(),
+	}
+	tc.Client.PrependReactor("create", "pods", tc.bindPod)
+	t.Cleanup(tc.Close)
+
+	if err := tc.startSchedulers(cfg); err != nil {
+		t.Fatalf("failed to start schedulers: %v", err)
+	}
+	if err := tc.startBinder(cfg); err != nil {
+		t.Fatalf("failed to start binder: %v", err)
+	}
+	tc.startDispatcher(cfg)
+
+	// The dispatcher only dispatches pods to the active schedulers.
+	if err := wait.PollImmediate(DefaultInterval, DefaultTimeout, func() (bool, error) {
+		schedulers, err := tc.GodelCrdClient.SchedulingV1alpha1().Schedulers().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		return len(schedulers.Items) == len(tc.Schedulers), nil
+	}); err != nil {
+		t.Fatalf("failed to wait for schedulers to be registered: %v", err)
+	}
+	return tc
+}
+
+func (tc *TestContext) startSchedulers(cfg Config) error {
+	componentConfig := cfg.SchedulerConfiguration
+	if componentConfig == nil {
+		componentConfig = &schedulerconfig.GodelSchedulerConfiguration{}
+	}
+	schedulerconfig.SetDefaults_GodelSchedulerConfiguration(componentConfig)
+
+	names := cfg.SchedulerNames
+	if len(names) == 0 {
+		names = []string{componentConfig.GodelSchedulerName}
+	}
+	for _, name := range names {
+		ci := tc.newComponentInformers()
+		sched, err := godelscheduler.New(
+			name,
+			componentConfig.SchedulerName,
+			tc.Client,
+			tc.GodelCrdClient,
+			ci.informerFactory,
+			ci.godelCrdInformerFactory,
+			ci.katalystCrdInformerFactory,
+			tc.ctx.Done(),
+			&events.FakeRecorder{},
+			godelscheduler.WithDefaultProfile(componentConfig.DefaultProfile),
+			godelscheduler.WithSubClusterProfiles(componentConfig.SubClusterProfiles),
+			godelscheduler.WithSchedulerNameProfiles(componentConfig.SchedulerNameProfiles),
+			godelscheduler.WithRenewInterval(componentConfig.SchedulerRenewIntervalSeconds),
+			godelscheduler.WithSubClusterKey(*componentConfig.SubClusterKey),
+			godelscheduler.WithNodePartitionType(componentConfig.NodePartitionType),
+			godelscheduler.WithBinderHandoff(componentConfig.BinderHandoff),
+		)
+		if err != nil {
+			return err
+		}
+		ci.startAndWaitForCacheSync(tc.ctx.Done())
+		tc.goRun(sched.Run)
+		tc.Schedulers = append(tc.Schedulers, sched)
+	}
+	return nil
+}
+
+func (tc *TestContext) startBinder(cfg Config) error {
+	componentConfig := cfg.BinderConfiguration
+	if componentConfig == nil {
+		componentConfig = &binderconfig.GodelBinderConfiguration{}
+	}
+	binderconfig.SetDefaults_GodelBinderConfiguration(componentConfig)
+
+	ci := tc.newComponentInformers()
+	binder, err := godelbinder.New(
+		tc.Client,
+		tc.GodelCrdClient,
+		ci.informerFactory,
+		ci.godelCrdInformerFactory,
+		ci.katalystCrdInformerFactory,
+		tc.ctx.Done(),
+		&events.FakeRecorder{},
+		componentConfig.SchedulerName,
+		componentConfig.VolumeBindingTimeoutSeconds,
+		godelbinder.WithPluginsAndConfigs(componentConfig.Profile),
+		godelbinder.WithSchedulerNameProfiles(componentConfig.SchedulerNameProfiles),
+		godelbinder.WithPreemptionBudget(componentConfig.PreemptionBudget),
+	)
+	if err != nil {
+		return err
+	}
+	pgInformer := ci.godelCrdInformerFactory.Scheduling().V1alpha1().PodGroups()
+	nodeLister := ci.informerFactory.Core().V1().Nodes().Lister()
+	ci.startAndWaitForCacheSync(tc.ctx.Done())
+	cache.WaitForCacheSync(tc.ctx.Done(), pgInformer.Informer().HasSynced)
+
+	if handoffConfig := componentConfig.Handoff; handoffConfig != nil && handoffConfig.Enable {
+		if err := tc.startHandoffServer(binder, handoffConfig); err != nil {
+			return err
+		}
+	}
+
+	controller.SetupPodGroupController(tc.ctx, tc.Client, tc.GodelCrdClient, pgInformer)
+	if gpuDefragmentation := componentConfig.GPUDefragmentation; gpuDefragmentation != nil && gpuDefragmentation.Enable {
+		controller.SetupGPUDefragmentationController(tc.ctx, tc.Client, binder.BinderCache, nodeLister, gpuDefragmentation)
+	}
+	if hotspotAvoidance := componentConfig.HotspotAvoidance; hotspotAvoidance != nil && hotspotAvoidance.Enable {
+		controller.SetupHotspotAvoidanceController(tc.ctx, tc.Client, binder.BinderCache, nodeLister, hotspotAvoidance)
+	}
+	tc.goRun(binder.Run)
+	tc.Binder = binder
+	return nil
+}
+
+// startHandoffServer serves the placements handed off by the schedulers as `cmd/binder/app/server.go` does.
+func (tc *TestContext) startHandoffServer(binder *godelbinder.Binder, handoffConfig *binderconfig.HandoffConfiguration) error {
+	tlsConfig, err := handoff.NewServerTLSConfig(handoffConfig.CertFile, handoffConfig.KeyFile, handoffConfig.ClientCAFile)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", handoffConfig.BindAddress)
+	if err != nil {
+		return err
+	}
+	handoffMux := http.NewServeMux()
+	handoffMux.Handle(handoff.Path, handoff.WithAuthorization(binder.HandoffHandler(), sets.NewString(handoffConfig.AllowedClientNames...)))
+	handoffServer := &http.Server{Handler: handoffMux, TLSConfig: tlsConfig}
+	tc.goRun(func(ctx context.Context) {
+		<-ctx.Done()
+		handoffServer.Close()
+	})
+	tc.goRun(func(ctx context.Context) {
+		if err := handoffServer.ServeTLS(listener, "", ""); err != nil && err != http.ErrServerClosed {
+			tc.t.Errorf("failed to serve handoff server: %v", err)
+		}
+	})
+	return nil
+}
+
+func (tc *TestContext) startDispatcher(cfg Config) {
+	componentConfig := cfg.DispatcherConfiguration
+	if componentConfig == nil {
+		componentConfig = &dispatcherconfig.GodelDispatcherConfiguration{}
+	}
+	dispatcherconfig.SetDefaults(componentConfig)
+
+	ci := tc.newComponentInformers()
+	tc.Dispatcher = dispatcher.New(
+		tc.ctx.Done(),
+		tc.Client,
+		tc.GodelCrdClient,
+		ci.informerFactory.Core().V1().Pods(),
+		ci.informerFactory.Core().V1().Nodes(),
+		ci.godelCrdInformerFactory.Scheduling().V1alpha1().Schedulers(),
+		ci.godelCrdInformerFactory.Node().V1alpha1().NMNodes(),
+		ci.godelCrdInformerFactory.Scheduling().V1alpha1().PodGroups(),
+		ci.informerFactory.Scheduling().V1().PriorityClasses(),
+		*componentConfig.SchedulerName,
+		componentConfig.ProfileSchedulerNames,
+		componentConfig.NodePartitionType,
+		componentConfig.TenantAdmission,
+		nil,
+		nil,
+		&events.FakeRecorder{},
+	)
+	ci.startAndWaitForCacheSync(tc.ctx.Done())
+	tc.Dispatcher.Run(tc.ctx)
+}
+
+// goRun runs the component in background until the TestContext is closed.
+func (tc *TestContext) goRun(run func(ctx context.Context)) {
+	tc.running.Add(1)
+	go func() {
+		defer tc.running.Done()
+		run(tc.ctx)
+	}()
+}
+
+// Close stops all components and waits for them to exit. The schedulers share the sub-cluster indexes of the
+// process and release them on exit, so they must be gone before the schedulers of the next test are started.
+func (tc *TestContext) Close() {
+	tc.cancel()
+	tc.running.Wait()
+}
+
+// Ctx returns the context of the components, which is done once the TestContext is closed.
+func (tc *TestContext) Ctx() context.Context {
+	return tc.ctx
+}
+
+// bindPod simulates the `pods/binding` subresource of API server, which is not supported by the fake clientset.
+func (tc *TestContext) bindPod(action clienttesting.Action) (bool, runtime.Object, error) {
+	createAction, ok := action.(clienttesting.CreateAction)
+	if !ok || createAction.GetSubresource() != "binding" {
+		return false, nil, nil
+	}
+	binding := createAction.GetObject().(*v1.Binding)
+	obj, err := tc.Client.Tracker().Get(v1.SchemeGroupVersion.WithResource("pods"), binding.Namespace, binding.Name)
+	if err != nil {
+		return true, nil, err
+	}
+	pod := obj.(*v1.Pod).DeepCopy()
+	if len(pod.Spec.NodeName) != 0 {
+		return true, nil, apierrors.NewConflict(v1.Resource("pods/binding"), binding.Name,
+			fmt.Errorf("pod %v is already assigned to node %q", binding.Name, pod.Spec.NodeName))
+	}
+	pod.Spec.NodeName = binding.Target.Name
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	for k, v := range binding.Annotations {
+		pod.Annotations[k] = v
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{Type: v1.PodScheduled, Status: v1.ConditionTrue})
+	return true, nil, tc.Client.Tracker().Update(v1.SchemeGroupVersion.WithResource("pods"), pod, pod.Namespace)
+}
+
+// CreateNodes creates the nodes.
+func (tc *TestContext) CreateNodes(nodes ...*v1.Node) {
+	for _, node := range nodes {
+		if _, err := tc.Client.CoreV1().Nodes().Create(tc.ctx, node, metav1.CreateOptions{}); err != nil {
+			tc.t.Fatalf("failed to create node %v: %v", node.Name, err)
+		}
+	}
+}
+
+// CreatePods creates the pods.
+func (tc *TestContext) CreatePods(pods ...*v1.Pod) {
+	for _, pod := range pods {
+		if _, err := tc.Client.CoreV1().Pods(pod.Namespace).Create(tc.ctx, pod, metav1.CreateOptions{}); err != nil {
+			tc.t.Fatalf("failed to create pod %v/%v: %v", pod.Namespace, pod.Name, err)
+		}
+	}
+}
+
+// CreatePodGroups creates the PodGroups.
+func (tc *TestContext) CreatePodGroups(podGroups ...*schedulingv1a1.PodGroup) {
+	for _, pg := range podGroups {
+		// The fake clientset does not set the creation timestamp like apiserver does,
+		// and the PodGroup controller times out the PodGroups without it.
+		if pg.CreationTimestamp.IsZero() {
+			pg.CreationTimestamp = metav1.Now()
+		}
+		if _, err := tc.GodelCrdClient.SchedulingV1alpha1().PodGroups(pg.Namespace).Create(tc.ctx, pg, metav1.CreateOptions{}); err != nil {
+			tc.t.Fatalf("failed to create PodGroup %v/%v: %v", pg.Namespace, pg.Name, err)
+		}
+	}
+}
+
+// CreatePriorityClasses creates the PriorityClasses.
+func (tc *TestContext) CreatePriorityClasses(priorityClasses ...*schedulingv1.PriorityClass) {
+	for _, pc := range priorityClasses {
+		if _, err := tc.Client.SchedulingV1().PriorityClasses().Create(tc.ctx, pc, metav1.CreateOptions{}); err != nil {
+			tc.t.Fatalf("failed to create PriorityClass %v: %v", pc.Name, err)
+		}
+	}
+}
+
+// GetPod returns the latest pod.
+func (tc *TestContext) GetPod(namespace, name string) *v1.Pod {
+	pod, err := tc.Client.CoreV1().Pods(namespace).Get(tc.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		tc.t.Fatalf("failed to get pod %v/%v: %v", namespace, name, err)
+	}
+	return pod
+}
+
+// WaitForPodsBound waits until all the pods are bound, and returns the nodes they are bound to, keyed by pod name.
+func (tc *TestContext) WaitForPodsBound(namespace string, names []string, timeout time.Duration) map[string]string {
+	bindings := make(map[string]string, len(names))
+	err := wait.PollImmediate(DefaultInterval, timeout, func() (bool, error) {
+		for _, name := range names {
+			pod, err := tc.Client.CoreV1().Pods(namespace).Get(tc.ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if len(pod.Spec.NodeName) == 0 {
+				return false, nil
+			}
+			bindings[name] = pod.Spec.NodeName
+		}
+		return true, nil
+	})
+	if err != nil {
+		tc.t.Fatalf("failed to wait for pods %v to be bound, bound pods: %v, err: %v", names, bindings, err)
+	}
+	return bindings
+}
+
+// ExpectPodsNotBound checks that none of the pods gets bound during the duration.
+func (tc *TestContext) ExpectPodsNotBound(namespace string, names []string, duration time.Duration) {
+	err := wait.PollImmediate(DefaultInterval, duration, func() (bool, error) {
+		for _, name := range names {
+			pod, err := tc.Client.CoreV1().Pods(namespace).Get(tc.ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if len(pod.Spec.NodeName) != 0 {
+				return false, fmt.Errorf("pod %v was bound to node %v unexpectedly", name, pod.Spec.NodeName)
+			}
+		}
+		return false, nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		tc.t.Fatal(err)
+	}
+}
+
+// WaitForPodsDeleted waits until all the pods are deleted.
+func (tc *TestContext) WaitForPodsDeleted(namespace string, names []string, timeout time.Duration) {
+	err := wait.PollImmediate(DefaultInterval, timeout, func() (bool, error) {
+		for _, name := range names {
+			_, err := tc.Client.CoreV1().Pods(namespace).Get(tc.ctx, name, metav1.GetOptions{})
+			if err == nil {
+				return false, nil
+			}
+			if !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		tc.t.Fatalf("failed to wait for pods %v to be deleted, err: %v", names, err)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/klog/v2"
+
+	pkgmetrics "github.com/kubewharf/godel-scheduler/pkg/common/metrics"
+	"github.com/kubewharf/godel-scheduler/test/e2e/perftype"
+)
+
+const (
+	// dataItemsVersion is the version of the generated data items, it should be bumped up for incompatible changes.
+	dataItemsVersion = "v1"
+
+	throughputSampleInterval = time.Second
+
+	e2eSchedulingLatencyMetric   = "scheduler_e2e_scheduling_duration_seconds"
+	schedulingStageLatencyMetric = "scheduler_scheduling_stage_duration_seconds"
+)
+
+// throughputCollector samples the number of the measured pods bound in every interval.
+type throughputCollector struct {
+	client   kubernetes.Interface
+	selector labels.Selector
+	// samples are the throughputs of every interval, in pods/second.
+	samples []float64
+}
+
+func newThroughputCollector(client kubernetes.Interface) *throughputCollector {
+	return &throughputCollector{
+		client:   client,
+		selector: labels.SelectorFromSet(labels.Set{measuredLabelKey: "true"}),
+	}
+}
+
+// run samples the throughput from now on until all the expected pods are bound or the context is done,
+// it returns whether all the pods are bound. The pods already bound are not counted in any sample.
+func (c *throughputCollector) run(ctx context.Context, expected int) bool {
+	ticker := time.NewTicker(throughputSampleInterval)
+	defer ticker.Stop()
+
+	lastBound, lastTime := 0, time.Now()
+	if bound, err := c.countBoundPods(ctx); err != nil {
+		klog.InfoS("Failed to count the bound pods", "err", err)
+	} else if bound >= expected {
+		return true
+	} else {
+		lastBound = bound
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case now := <-ticker.C:
+			bound, err := c.countBoundPods(ctx)
+			if err != nil {
+				klog.InfoS("Failed to count the bound pods", "err", err)
+				continue
+			}
+			c.samples = append(c.samples, float64(bound-lastBound)/now.Sub(lastTime).Seconds())
+			lastBound, lastTime = bound, now
+			if bound >= expected {
+				return true
+			}
+		}
+	}
+}
+
+func (c *throughputCollector) countBoundPods(ctx context.Context) (int, error) {
+	pods, err := c.client.CoreV1().Pods(testNamespace).List(ctx, metav1.ListOptions{LabelSelector: c.selector.String()})
+	if err != nil {
+		return 0, err
+	}
+	bound := 0
+	for i := range pods.Items {
+		if isBound(&pods.Items[i]) {
+			bound++
+		}
+	}
+	return bound, nil
+}
+
+func isBound(pod *v1.Pod) bool {
+	return len(pod.Spec.NodeName) > 0
+}
+
+func (c *throughputCollector) dataItem(labels map[string]string) perftype.DataItem {
+	samples := append([]float64{}, c.samples...)
+	sort.Float64s(samples)
+	data := map[string]float64{}
+	if len(samples) > 0 {
+		sum := 0.0
+		for _, s := range samples {
+			sum += s
+		}
+		data["Average"] = sum / float64(len(samples))
+		data["Perc50"] = percentile(samples, 0.5)
+		data["Perc90"] = percentile(samples, 0.9)
+		data["Perc99"] = percentile(samples, 0.99)
+	}
+	return perftype.DataItem{
+		Data:   data,
+		Unit:   "pods/s",
+		Labels: withMetric(labels, "SchedulingThroughput"),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted samples.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// resetMetrics clears the metrics recorded by the previous runs.
+func resetMetrics() {
+	legacyregistry.Reset()
+}
+
+// collectHistograms returns the data items of the e2e scheduling latency and the latency of every scheduling stage.
+func collectHistograms(labels map[string]string) []perftype.DataItem {
+	var items []perftype.DataItem
+	if item, ok := histogramDataItem(e2eSchedulingLatencyMetric, nil, labels); ok {
+		items = append(items, item)
+	}
+	for _, operation := range labelValues(schedulingStageLatencyMetric, pkgmetrics.OperationLabel) {
+		lvMap := map[string]string{pkgmetrics.OperationLabel: operation}
+		if item, ok := histogramDataItem(schedulingStageLatencyMetric, lvMap, labels); ok {
+			item.Labels[pkgmetrics.OperationLabel] = operation
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func histogramDataItem(metric string, lvMap map[string]string, labels map[string]string) (perftype.DataItem, bool) {
+	hist, err := testutil.GetHistogramVecFromGatherer(legacyregistry.DefaultGatherer, metric, lvMap)
+	if err != nil {
+		klog.InfoS("Failed to get the histogram", "metric", metric, "err", err)
+		return perftype.DataItem{}, false
+	}
+	if hist.GetAggregatedSampleCount() == 0 {
+		return perftype.DataItem{}, false
+	}
+	// Seconds -> milliseconds.
+	return perftype.DataItem{
+		Data: map[string]float64{
+			"Average": hist.Average() * 1000,
+			"Perc50":  hist.Quantile(0.5) * 1000,
+			"Perc90":  hist.Quantile(0.9) * 1000,
+			"Perc99":  hist.Quantile(0.99) * 1000,
+		},
+		Unit:   "ms",
+		Labels: withMetric(labels, metric),
+	}, true
+}
+
+// labelValues returns the distinct values of the label in the metric.
+func labelValues(metric, label string) []string {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		klog.InfoS("Failed to gather the metrics", "err", err)
+		return nil
+	}
+	seen := map[string]bool{}
+	var values []string
+	for _, family := range families {
+		if family.GetName() != metric {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if pair.GetName() == label && !seen[pair.GetValue()] {
+					seen[pair.GetValue()] = true
+					values = append(values, pair.GetValue())
+				}
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func withMetric(labels map[string]string, metric string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result["Metric"] = metric
+	return result
+}
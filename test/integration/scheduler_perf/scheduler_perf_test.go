@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/kubewharf/godel-scheduler/test/e2e/perftype"
+	"github.com/kubewharf/godel-scheduler/test/integration/framework"
+)
+
+var (
+	configFile   = flag.String("perf-scheduling-config", "config/performance-config.yaml", "The file of the workloads to run.")
+	dataItemsDir = flag.String("data-items-dir", "", "The directory to write the data items of the benchmarks to, the data items are only logged if it's empty.")
+	timeout      = flag.Duration("perf-scheduling-timeout", 10*time.Minute, "The timeout of scheduling all the pods of a workload.")
+	// The watchers of the fake clientset panic once their buffers are full, so the objects can't be created at full speed.
+	createQPS = flag.Float64("perf-scheduling-create-qps", 500, "The QPS of creating the pods and PodGroups.")
+)
+
+func TestPerformanceConfig(t *testing.T) {
+	workloads, err := LoadWorkloads(*configFile)
+	if err != nil {
+		t.Fatalf("failed to load the workloads: %v", err)
+	}
+	for _, w := range workloads {
+		pods, podGroups := w.makePods("pod", w.Pods, true)
+		expected := 0
+		for _, p := range w.Pods {
+			expected += p.Count
+		}
+		if len(pods) != expected {
+			t.Errorf("workload %v: expected %d pods, got %d", w.Name, expected, len(pods))
+		}
+		for _, pg := range podGroups {
+			if pg.Spec.MinMember <= 0 {
+				t.Errorf("workload %v: PodGroup %v has no members", w.Name, pg.Name)
+			}
+		}
+	}
+}
+
+// BenchmarkPerfScheduling runs every workload once and reports the scheduling throughput and latencies.
+func BenchmarkPerfScheduling(b *testing.B) {
+	workloads, err := LoadWorkloads(*configFile)
+	if err != nil {
+		b.Fatalf("failed to load the workloads: %v", err)
+	}
+	var dataItems []perftype.DataItem
+	for _, w := range workloads {
+		w := w
+		b.Run(w.Name, func(b *testing.B) {
+			dataItems = append(dataItems, runWorkload(b, w)...)
+		})
+	}
+	if err := writeDataItems(b, dataItems); err != nil {
+		b.Fatalf("failed to write the data items: %v", err)
+	}
+}
+
+func runWorkload(b *testing.B, w *Workload) []perftype.DataItem {
+	tc := framework.StartTestContext(b, framework.Config{SchedulerConfiguration: w.schedulerConfiguration()})
+	defer tc.Close()
+
+	tc.CreateNodes(w.makeNodes()...)
+	tc.CreatePriorityClasses(makePriorityClasses(append(append([]PodTemplate{}, w.InitPods...), w.Pods...))...)
+
+	limiter := flowcontrol.NewTokenBucketRateLimiter(float32(*createQPS), 1)
+	defer limiter.Stop()
+
+	initPods, initPodGroups := w.makePods("init", w.InitPods, false)
+	createPods(tc, limiter, initPods, initPodGroups)
+	initPodNames := make([]string, 0, len(initPods))
+	for _, pod := range initPods {
+		initPodNames = append(initPodNames, pod.Name)
+	}
+	tc.WaitForPodsBound(testNamespace, initPodNames, *timeout)
+
+	pods, podGroups := w.makePods("pod", w.Pods, true)
+	resetMetrics()
+	b.ResetTimer()
+
+	ctx, cancel := context.WithTimeout(tc.Ctx(), *timeout)
+	defer cancel()
+
+	start := time.Now()
+	createPods(tc, limiter, pods, podGroups)
+	// The throughput is sampled once all the pods are created, so that it is not limited by the creation QPS.
+	collector := newThroughputCollector(tc.Client)
+	if !collector.run(ctx, len(pods)) {
+		b.Fatalf("timed out waiting for the %d pods to be bound", len(pods))
+	}
+	b.StopTimer()
+
+	elapsed := time.Since(start)
+	b.ReportMetric(float64(len(pods))/elapsed.Seconds(), "pods/s")
+
+	labels := map[string]string{"Name": b.Name()}
+	return append([]perftype.DataItem{collector.dataItem(labels)}, collectHistograms(labels)...)
+}
+
+func createPods(tc *framework.TestContext, limiter flowcontrol.RateLimiter, pods []*v1.Pod, podGroups []*schedulingv1a1.PodGroup) {
+	for _, pg := range podGroups {
+		limiter.Accept()
+		tc.CreatePodGroups(pg)
+	}
+	for _, pod := range pods {
+		limiter.Accept()
+		tc.CreatePods(pod)
+	}
+}
+
+func writeDataItems(b *testing.B, dataItems []perftype.DataItem) error {
+	data, err := json.MarshalIndent(perftype.PerfData{Version: dataItemsVersion, DataItems: dataItems}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if len(*dataItemsDir) == 0 {
+		b.Logf("%s\n%s\n%s", perftype.PerfResultTag, data, perftype.PerfResultEnd)
+		return nil
+	}
+	path := filepath.Join(*dataItemsDir, fmt.Sprintf("%s_%s.json", b.Name(), time.Now().Format(time.RFC3339)))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	b.Logf("data items are written to %v", path)
+	return nil
+}
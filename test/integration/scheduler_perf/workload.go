@@ -0,0 +1,235 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"fmt"
+	"os"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	testNamespace = "default"
+	// subClusterKey is the node label and pod node selector key of the sub-clusters.
+	subClusterKey = "godel.bytedance.com/benchmark-subcluster"
+	// measuredLabelKey marks the pods whose scheduling is measured.
+	measuredLabelKey = "godel.bytedance.com/benchmark-measured"
+)
+
+// Workload describes the cluster and the pods of a benchmark declaratively.
+type Workload struct {
+	// Name is the name of the workload, which is used as the name of the sub-benchmark.
+	Name string `json:"name"`
+	// SubClusters spreads the nodes and the pods evenly over the given number of sub-clusters.
+	SubClusters int `json:"subClusters,omitempty"`
+	// Nodes are the node shapes of the cluster.
+	Nodes []NodeTemplate `json:"nodes"`
+	// InitPods are scheduled before the measurement starts, e.g. the victims of preemption.
+	InitPods []PodTemplate `json:"initPods,omitempty"`
+	// Pods are the pods whose scheduling is measured.
+	Pods []PodTemplate `json:"pods"`
+}
+
+// NodeTemplate describes a group of nodes with the same shape.
+type NodeTemplate struct {
+	Count  int    `json:"count"`
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+}
+
+// PodTemplate describes a group of pods with the same shape.
+type PodTemplate struct {
+	Count  int    `json:"count"`
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+	// Priority is the priority of the pods, a PriorityClass with the value is created for them.
+	Priority *int32 `json:"priority,omitempty"`
+	// CanBePreempted marks the pods as preemptible, which requires Priority to be set.
+	CanBePreempted bool `json:"canBePreempted,omitempty"`
+	// PodGroupSize groups every PodGroupSize pods into a PodGroup with the same MinMember.
+	PodGroupSize int `json:"podGroupSize,omitempty"`
+}
+
+// LoadWorkloads reads the workloads from the config file.
+func LoadWorkloads(path string) ([]*Workload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var workloads []*Workload
+	if err := yaml.UnmarshalStrict(data, &workloads); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(workloads))
+	for _, w := range workloads {
+		if err := w.validate(); err != nil {
+			return nil, fmt.Errorf("invalid workload %q: %v", w.Name, err)
+		}
+		if names[w.Name] {
+			return nil, fmt.Errorf("duplicate workload %q", w.Name)
+		}
+		names[w.Name] = true
+	}
+	return workloads, nil
+}
+
+func (w *Workload) validate() error {
+	if len(w.Name) == 0 {
+		return fmt.Errorf("name is required")
+	}
+	if w.SubClusters < 0 {
+		return fmt.Errorf("subClusters must not be negative")
+	}
+	if len(w.Nodes) == 0 || len(w.Pods) == 0 {
+		return fmt.Errorf("nodes and pods are required")
+	}
+	for _, n := range w.Nodes {
+		if n.Count <= 0 {
+			return fmt.Errorf("node count must be positive")
+		}
+	}
+	for _, p := range append(append([]PodTemplate{}, w.InitPods...), w.Pods...) {
+		if p.Count <= 0 {
+			return fmt.Errorf("pod count must be positive")
+		}
+		if p.PodGroupSize < 0 || p.PodGroupSize > 0 && p.Count%p.PodGroupSize != 0 {
+			return fmt.Errorf("pod count %d must be a multiple of podGroupSize %d", p.Count, p.PodGroupSize)
+		}
+		if p.CanBePreempted && p.Priority == nil {
+			return fmt.Errorf("preemptible pods require priority")
+		}
+	}
+	return nil
+}
+
+// preemption returns whether any of the pods has priority, so preemption needs to be enabled.
+func (w *Workload) preemption() bool {
+	for _, p := range append(append([]PodTemplate{}, w.InitPods...), w.Pods...) {
+		if p.Priority != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulerConfiguration returns the scheduler configuration required by the workload.
+func (w *Workload) schedulerConfiguration() *schedulerconfig.GodelSchedulerConfiguration {
+	disablePreemption := !w.preemption()
+	cfg := &schedulerconfig.GodelSchedulerConfiguration{
+		DefaultProfile: &schedulerconfig.GodelSchedulerProfile{
+			DisablePreemption: &disablePreemption,
+		},
+	}
+	if w.SubClusters > 0 {
+		key := subClusterKey
+		cfg.SubClusterKey = &key
+	}
+	return cfg
+}
+
+func (w *Workload) subCluster(i int) string {
+	return fmt.Sprintf("subcluster-%d", i%w.SubClusters)
+}
+
+func (w *Workload) makeNodes() []*v1.Node {
+	var nodes []*v1.Node
+	for _, t := range w.Nodes {
+		for i := 0; i < t.Count; i++ {
+			wrapper := testinghelper.MakeNode().Name(fmt.Sprintf("node-%d", len(nodes))).
+				Capacity(map[v1.ResourceName]string{v1.ResourceCPU: t.CPU, v1.ResourceMemory: t.Memory})
+			if w.SubClusters > 0 {
+				wrapper.Label(subClusterKey, w.subCluster(len(nodes)))
+			}
+			nodes = append(nodes, wrapper.Obj())
+		}
+	}
+	return nodes
+}
+
+func makePriorityClasses(templates []PodTemplate) []*schedulingv1.PriorityClass {
+	var priorityClasses []*schedulingv1.PriorityClass
+	seen := map[int32]bool{}
+	for _, t := range templates {
+		if t.Priority == nil || seen[*t.Priority] {
+			continue
+		}
+		seen[*t.Priority] = true
+		priorityClasses = append(priorityClasses, &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{Name: priorityClassName(*t.Priority)},
+			Value:      *t.Priority,
+		})
+	}
+	return priorityClasses
+}
+
+func priorityClassName(priority int32) string {
+	return fmt.Sprintf("priority-%d", priority)
+}
+
+// makePods generates the pods and PodGroups of the templates, the names of the objects are prefixed by prefix.
+func (w *Workload) makePods(prefix string, templates []PodTemplate, measured bool) ([]*v1.Pod, []*schedulingv1a1.PodGroup) {
+	var pods []*v1.Pod
+	var podGroups []*schedulingv1a1.PodGroup
+	for _, t := range templates {
+		for i := 0; i < t.Count; i++ {
+			name := fmt.Sprintf("%s-%d", prefix, len(pods))
+			wrapper := testinghelper.MakePod().Namespace(testNamespace).Name(name).UID(name).
+				SchedulerName(schedulerconfig.DefaultSchedulerName).
+				Req(map[v1.ResourceName]string{v1.ResourceCPU: t.CPU, v1.ResourceMemory: t.Memory}).
+				Label(measuredLabelKey, fmt.Sprint(measured)).
+				Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+				Annotation(podutil.PodLauncherAnnotationKey, string(podutil.Kubelet))
+			if t.Priority != nil {
+				wrapper.PriorityClassName(priorityClassName(*t.Priority)).Priority(*t.Priority)
+			}
+			if t.CanBePreempted {
+				wrapper.Annotation(util.CanBePreemptedAnnotationKey, util.CanBePreempted)
+			}
+			if t.PodGroupSize > 0 {
+				if i%t.PodGroupSize == 0 {
+					pg := testinghelper.MakePodGroup().Namespace(testNamespace).
+						Name(fmt.Sprintf("%s-pg-%d", prefix, len(podGroups))).MinMember(uint(t.PodGroupSize))
+					if t.Priority != nil {
+						pg.ProrityClassName(priorityClassName(*t.Priority))
+					}
+					podGroups = append(podGroups, pg.Obj())
+				}
+				wrapper.Annotation(podutil.PodGroupNameAnnotationKey, podGroups[len(podGroups)-1].Name)
+			}
+			if w.SubClusters > 0 {
+				// All members of a PodGroup are placed in the same sub-cluster.
+				index := len(pods)
+				if t.PodGroupSize > 0 {
+					index = len(podGroups)
+				}
+				wrapper.NodeSelector(map[string]string{subClusterKey: w.subCluster(index)})
+			}
+			pods = append(pods, wrapper.Obj())
+		}
+	}
+	return pods, podGroups
+}
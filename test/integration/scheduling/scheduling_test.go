@@ -0,0 +1,241 @@
+/*
+Copyright 2023 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	clienttesting "k8s.io/client-go/testing"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	utilpointer "k8s.io/utils/pointer"
+
+	binderconfig "github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	godelframework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/test/integration/framework"
+	"github.com/kubewharf/godel-scheduler/test/utils"
+)
+
+const testNamespace = "default"
+
+func makeNodes(num int, cpu string) []*v1.Node {
+	nodes := make([]*v1.Node, 0, num)
+	for i := 0; i < num; i++ {
+		nodes = append(nodes, testinghelper.MakeNode().Name(fmt.Sprintf("node-%d", i)).
+			Capacity(map[v1.ResourceName]string{v1.ResourceCPU: cpu, v1.ResourceMemory: "16Gi"}).Obj())
+	}
+	return nodes
+}
+
+func makePod(name, cpu string) *testinghelper.PodWrapper {
+	return testinghelper.MakePod().Namespace(testNamespace).Name(name).UID(name).
+		SchedulerName(schedulerconfig.DefaultSchedulerName).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu, v1.ResourceMemory: "1Gi"}).
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+		Annotation(podutil.PodLauncherAnnotationKey, string(podutil.Kubelet))
+}
+
+func makePodNames(prefix string, num int) []string {
+	names := make([]string, 0, num)
+	for i := 0; i < num; i++ {
+		names = append(names, fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return names
+}
+
+func TestSchedulePods(t *testing.T) {
+	tc := framework.StartTestContext(t, framework.Config{})
+	tc.CreateNodes(makeNodes(2, "4")...)
+
+	names := makePodNames("pod", 4)
+	for _, name := range names {
+		tc.CreatePods(makePod(name, "2").Obj())
+	}
+	bindings := tc.WaitForPodsBound(testNamespace, names, framework.DefaultTimeout)
+
+	podsPerNode := map[string]int{}
+	for _, nodeName := range bindings {
+		podsPerNode[nodeName]++
+	}
+	for nodeName, num := range podsPerNode {
+		if num > 2 {
+			t.Errorf("node %v was overcommitted with %d pods", nodeName, num)
+		}
+	}
+}
+
+func TestGangScheduling(t *testing.T) {
+	tc := framework.StartTestContext(t, framework.Config{})
+	tc.CreateNodes(makeNodes(2, "4")...)
+
+	// The resources are only enough for 4 pods, so the gang requiring 5 pods can not be scheduled.
+	tc.CreatePodGroups(testinghelper.MakePodGroup().Namespace(testNamespace).Name("pg-large").MinMember(5).Obj())
+	largeGang := makePodNames("large", 5)
+	for _, name := range largeGang {
+		tc.CreatePods(makePod(name, "2").Annotation(podutil.PodGroupNameAnnotationKey, "pg-large").Obj())
+	}
+	tc.ExpectPodsNotBound(testNamespace, largeGang, 5*time.Second)
+
+	tc.CreatePodGroups(testinghelper.MakePodGroup().Namespace(testNamespace).Name("pg-small").MinMember(2).Obj())
+	smallGang := makePodNames("small", 2)
+	for _, name := range smallGang {
+		tc.CreatePods(makePod(name, "2").Annotation(podutil.PodGroupNameAnnotationKey, "pg-small").Obj())
+	}
+	tc.WaitForPodsBound(testNamespace, smallGang, framework.DefaultTimeout)
+}
+
+func TestPreemption(t *testing.T) {
+	schedulerConfig := &schedulerconfig.GodelSchedulerConfiguration{
+		DefaultProfile: &schedulerconfig.GodelSchedulerProfile{
+			DisablePreemption: utilpointer.BoolPtr(false),
+		},
+	}
+	tc := framework.StartTestContext(t, framework.Config{SchedulerConfiguration: schedulerConfig})
+	tc.CreateNodes(makeNodes(1, "4")...)
+	tc.CreatePriorityClasses(
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "low"}, Value: 100},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Value: 1000},
+	)
+
+	tc.CreatePods(makePod("victim", "4").PriorityClassName("low").Priority(100).
+		Annotation(util.CanBePreemptedAnnotationKey, util.CanBePreempted).Obj())
+	tc.WaitForPodsBound(testNamespace, []string{"victim"}, framework.DefaultTimeout)
+
+	tc.CreatePods(makePod("preemptor", "4").PriorityClassName("high").Priority(1000).Obj())
+	tc.WaitForPodsDeleted(testNamespace, []string{"victim"}, framework.DefaultTimeout)
+	tc.WaitForPodsBound(testNamespace, []string{"preemptor"}, framework.DefaultTimeout)
+}
+
+func TestMultipleSchedulers(t *testing.T) {
+	tc := framework.StartTestContext(t, framework.Config{
+		SchedulerNames: []string{"godel-scheduler-0", "godel-scheduler-1"},
+	})
+	tc.CreateNodes(makeNodes(4, "4")...)
+
+	// Both schedulers race for all the nodes, the binder has to resolve the conflicts.
+	names := makePodNames("pod", 8)
+	for _, name := range names {
+		tc.CreatePods(makePod(name, "2").Obj())
+	}
+	bindings := tc.WaitForPodsBound(testNamespace, names, framework.DefaultTimeout)
+
+	podsPerNode := map[string]int{}
+	for _, nodeName := range bindings {
+		podsPerNode[nodeName]++
+	}
+	for nodeName, num := range podsPerNode {
+		if num > 2 {
+			t.Errorf("node %v was overcommitted with %d pods", nodeName, num)
+		}
+	}
+}
+
+func TestBinderHandoff(t *testing.T) {
+	certs := utils.NewTestCertificates(t)
+	serverCert, serverKey := certs.Issue(t, "binder", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := certs.Issue(t, "scheduler", x509.ExtKeyUsageClientAuth)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	tc := framework.StartTestContext(t, framework.Config{
+		SchedulerConfiguration: &schedulerconfig.GodelSchedulerConfiguration{
+			BinderHandoff: &schedulerconfig.BinderHandoffConfiguration{
+				Enable:        true,
+				BinderAddress: address,
+				CertFile:      clientCert,
+				KeyFile:       clientKey,
+				CAFile:        certs.CAFile,
+			},
+		},
+		BinderConfiguration: &binderconfig.GodelBinderConfiguration{
+			Handoff: &binderconfig.HandoffConfiguration{
+				Enable:             true,
+				BindAddress:        address,
+				CertFile:           serverCert,
+				KeyFile:            serverKey,
+				ClientCAFile:       certs.CAFile,
+				AllowedClientNames: []string{"scheduler"},
+			},
+		},
+	})
+	tc.CreateNodes(makeNodes(2, "4")...)
+
+	names := makePodNames("pod", 4)
+	for _, name := range names {
+		tc.CreatePods(makePod(name, "2").Obj())
+	}
+	tc.WaitForPodsBound(testNamespace, names, framework.DefaultTimeout)
+
+	// The placements are handed off to the binder instead of being persisted through the pod annotations.
+	for _, action := range tc.Client.Actions() {
+		patch, ok := action.(clienttesting.PatchAction)
+		if ok && patch.GetResource().Resource == "pods" && strings.Contains(string(patch.GetPatch()), podutil.AssumedNodeAnnotationKey) {
+			t.Errorf("pod %v was patched with the assumed node: %s", patch.GetName(), patch.GetPatch())
+		}
+	}
+}
+
+func TestRecreatedPodPlacedOnReservedNode(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourceReservation, true)()
+
+	tc := framework.StartTestContext(t, framework.Config{})
+	now := time.Now()
+	reservations, err := godelframework.FormatResourceReservations([]*godelframework.ResourceReservation{{
+		Name:         "migration",
+		Owner:        testNamespace + "/rs",
+		OwnerUID:     "rs",
+		Resources:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+		CreationTime: metav1.NewTime(now.Add(-time.Minute)),
+		TTLSeconds:   3600,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The reserved node is the least preferred one by scores, since the others have much more resources.
+	reserved := testinghelper.MakeNode().Name("reserved").
+		Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4", v1.ResourceMemory: "16Gi"}).Obj()
+	reserved.Annotations = map[string]string{godelframework.ResourceReservationAnnotationKey: reservations}
+	tc.CreateNodes(append(makeNodes(2, "64"), reserved)...)
+
+	// The pod recreated by the ReplicaSet after its previous pod was migrated away.
+	pod := makePod("rs-0", "2").
+		ControllerRef(metav1.OwnerReference{Kind: util.OwnerTypeReplicaSet, Name: "rs", UID: "rs", APIVersion: "apps/v1", Controller: utilpointer.Bool(true)}).Obj()
+	pod.CreationTimestamp = metav1.NewTime(now)
+	tc.CreatePods(pod)
+	bindings := tc.WaitForPodsBound(testNamespace, []string{"rs-0"}, framework.DefaultTimeout)
+	if bindings["rs-0"] != "reserved" {
+		t.Errorf("expected the recreated pod to be placed on its reserved node, got %v", bindings["rs-0"])
+	}
+}
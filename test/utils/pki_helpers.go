@@ -25,10 +25,15 @@ import (
 	"encoding/pem"
 	"math"
 	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
 	"time"
 
 	"github.com/pkg/errors"
 	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 )
 
 const (
@@ -83,3 +88,58 @@ func NewSignedCert(cfg *certutil.Config, key crypto.Signer, caCert *x509.Certifi
 	}
 	return x509.ParseCertificate(certDERBytes)
 }
+
+// TestCertificates is a CA issuing the certificates of 127.0.0.1 for tests, the files are written
+// to a temporary directory of the test.
+type TestCertificates struct {
+	dir    string
+	ca     *x509.Certificate
+	caKey  crypto.Signer
+	CAFile string
+}
+
+// NewTestCertificates creates a self-signed CA and writes it to CAFile.
+func NewTestCertificates(t testing.TB) *TestCertificates {
+	caKey, err := NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "test-ca"}, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs := &TestCertificates{dir: t.TempDir(), ca: ca, caKey: caKey}
+	certs.CAFile = filepath.Join(certs.dir, "ca.crt")
+	if err := os.WriteFile(certs.CAFile, EncodeCertPEM(ca), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certs
+}
+
+// Issue writes a certificate signed by the CA along with its key, and returns the paths of them.
+func (c *TestCertificates) Issue(t testing.TB, commonName string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := NewSignedCert(&certutil.Config{
+		CommonName: commonName,
+		AltNames:   certutil.AltNames{IPs: []net.IP{net.ParseIP("127.0.0.1")}},
+		Usages:     []x509.ExtKeyUsage{usage},
+	}, key, c.ca, c.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(c.dir, commonName+".crt"), filepath.Join(c.dir, commonName+".key")
+	if err := os.WriteFile(certFile, EncodeCertPEM(cert), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}